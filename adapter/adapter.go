@@ -1,15 +1,23 @@
 package adapter
 
 import (
+	"context"
+
 	"github.com/khinshankhan/logstox"
 )
 
 // Adapter bridges a Logger[Base] so callers can log with fields of type App.
+//
+// Adapter's level methods add a stack frame; when Base reports callers, wrap it as
+// logstox.WithOptions(base, logstox.AddCallerSkip(1)) so file:line points at the call site.
 type Adapter[Base, App any] struct {
 	// Base is the underlying logger we ultimately call.
 	Base logstox.Logger[Base]
 	// ToBase converts an application field into the Base field.
 	ToBase func(App) Base
+
+	// context keeps the App fields added via With, for Fields.
+	context []App
 }
 
 // mapSlice applies f to all items of in, returning a freshly allocated slice.
@@ -36,6 +44,15 @@ func (a Adapter[Base, App]) Info(msg string, fields ...App) {
 	a.Base.Info(msg, mapSlice(a.ToBase, fields)...)
 }
 
+// Notice logs a notice entry, or an Info one if Base isn't a logstox.Noticer.
+func (a Adapter[Base, App]) Notice(msg string, fields ...App) {
+	if n, ok := a.Base.(logstox.Noticer[Base]); ok {
+		n.Notice(msg, mapSlice(a.ToBase, fields)...)
+	} else {
+		a.Base.Info(msg, mapSlice(a.ToBase, fields)...)
+	}
+}
+
 // WARN (1): for recording messages indicating something unusual happened that may need attention before it escalates to a more severe issue.
 func (a Adapter[Base, App]) Warn(msg string, fields ...App) {
 	a.Base.Warn(msg, mapSlice(a.ToBase, fields)...)
@@ -64,16 +81,27 @@ func (a Adapter[Base, App]) Fatal(msg string, fields ...App) {
 // With returns a new Adapter with Base.With(...) applied and the same converter.
 func (a Adapter[Base, App]) With(fields ...App) logstox.Logger[App] {
 	return Adapter[Base, App]{
-		Base:   a.Base.With(mapSlice(a.ToBase, fields)...),
-		ToBase: a.ToBase,
+		Base:    a.Base.With(mapSlice(a.ToBase, fields)...),
+		ToBase:  a.ToBase,
+		context: append(a.context[:len(a.context):len(a.context)], fields...),
+	}
+}
+
+// WithLazy returns a new Adapter with the converted fields deferred on the underlying logger via logstox.WithLazy.
+func (a Adapter[Base, App]) WithLazy(fields ...App) logstox.Logger[App] {
+	return Adapter[Base, App]{
+		Base:    logstox.WithLazy(a.Base, mapSlice(a.ToBase, fields)...),
+		ToBase:  a.ToBase,
+		context: append(a.context[:len(a.context):len(a.context)], fields...),
 	}
 }
 
 // Named returns a new Adapter with Base.Named(name) and the same converter.
 func (a Adapter[Base, App]) Named(name string) logstox.Logger[App] {
 	return Adapter[Base, App]{
-		Base:   a.Base.Named(name),
-		ToBase: a.ToBase,
+		Base:    a.Base.Named(name),
+		ToBase:  a.ToBase,
+		context: a.context,
 	}
 }
 
@@ -81,3 +109,55 @@ func (a Adapter[Base, App]) Named(name string) logstox.Logger[App] {
 func (a Adapter[Base, App]) Sync() error {
 	return a.Base.Sync()
 }
+
+// Enabled reports whether the underlying logger records lvl.
+func (a Adapter[Base, App]) Enabled(lvl logstox.Level) bool {
+	return logstox.Enabled(a.Base, lvl)
+}
+
+// WithOptions returns a new Adapter with the options applied to the underlying logger and the same converter.
+func (a Adapter[Base, App]) WithOptions(opts ...logstox.Option) logstox.Logger[App] {
+	return Adapter[Base, App]{
+		Base:    logstox.WithOptions(a.Base, opts...),
+		ToBase:  a.ToBase,
+		context: a.context,
+	}
+}
+
+// WithLevel returns a new Adapter with the underlying logger's minimum level set to lvl.
+func (a Adapter[Base, App]) WithLevel(lvl logstox.Level) logstox.Logger[App] {
+	return Adapter[Base, App]{
+		Base:    logstox.WithLevel(a.Base, lvl),
+		ToBase:  a.ToBase,
+		context: a.context,
+	}
+}
+
+// Name returns the underlying logger's name.
+func (a Adapter[Base, App]) Name() string {
+	return logstox.NameOf(a.Base)
+}
+
+// WithContext returns a new Adapter with Base bound to ctx.
+func (a Adapter[Base, App]) WithContext(ctx context.Context) logstox.Logger[App] {
+	return Adapter[Base, App]{
+		Base:    logstox.WithContext(a.Base, ctx),
+		ToBase:  a.ToBase,
+		context: a.context,
+	}
+}
+
+// LevelVar returns Base's LevelVar.
+func (a Adapter[Base, App]) LevelVar() *logstox.LevelVar {
+	return logstox.LevelVarOf(a.Base)
+}
+
+// Close closes Base.
+func (a Adapter[Base, App]) Close() error {
+	return logstox.Close(a.Base)
+}
+
+// Fields returns the App fields added via With.
+func (a Adapter[Base, App]) Fields() []App {
+	return a.context
+}
@@ -0,0 +1,149 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/khinshankhan/logstox"
+	"github.com/khinshankhan/logstox/backend/streamx"
+	"github.com/khinshankhan/logstox/fields"
+)
+
+// Keys of the fixed audit schema.
+const (
+	ActorKey   = "actor"
+	ActionKey  = "action"
+	TargetKey  = "target"
+	OutcomeKey = "outcome"
+	DetailsKey = "details"
+)
+
+// ContextPrefix is prepended to the key of a context field (see Logger.With) that would shadow a schema key, eg
+// "actor" becomes "context_actor".
+const ContextPrefix = "context_"
+
+// Message is the message every audit entry is logged with.
+const Message = "audit"
+
+// Outcome is the result of an audited action.
+type Outcome string
+
+// Conventional outcomes. Others are allowed as long as they're non-empty.
+const (
+	Success Outcome = "success"
+	Failure Outcome = "failure"
+	Denied  Outcome = "denied"
+)
+
+// ErrMissingField is returned (wrapped) when an Event lacks a required field.
+var ErrMissingField = errors.New("audit: missing required field")
+
+// Event is a single audit record. Actor, Action, Target and Outcome are required.
+type Event struct {
+	Actor   string  // who did it, eg a user or service ID
+	Action  string  // what they did, eg "user.delete"
+	Target  string  // what they did it to
+	Outcome Outcome // how it went
+	// Details are extra fields, grouped under DetailsKey ("details") so they can never shadow the schema.
+	Details []fields.Field
+}
+
+// validate reports every required field e is missing.
+func (e Event) validate() error {
+	var errs []error
+	for _, kv := range [...]struct{ k, v string }{
+		{ActorKey, e.Actor},
+		{ActionKey, e.Action},
+		{TargetKey, e.Target},
+		{OutcomeKey, string(e.Outcome)},
+	} {
+		if kv.v == "" {
+			errs = append(errs, fmt.Errorf("%w %q", ErrMissingField, kv.k))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Logger emits audit events with a fixed schema.
+//
+// Use NewWriter, or New on a logger of its own writing to a dedicated sink, not on a child of the application logger:
+// audit entries must not be sampled, filtered, or mixed in with regular logs. Entries are always logged at InfoLevel,
+// so the underlying logger's level must allow it.
+type Logger struct {
+	l logstox.Logger[fields.Field]
+}
+
+// New returns an audit Logger writing through l. l's own context fields aren't checked against the schema, give them
+// keys of their own.
+func New(l logstox.Logger[fields.Field]) Logger {
+	return Logger{l: l}
+}
+
+// NewWriter returns an audit Logger writing JSON lines to w, which should be a sink dedicated to audit entries. The
+// logger is its own, with no sampling or middleware, so every valid event is written.
+func NewWriter(w io.Writer) Logger {
+	return New(streamx.Backend{}.New(logstox.Options[fields.Field]{Writer: w, Level: logstox.InfoLevel}))
+}
+
+// Log validates e and emits it. If any required field is missing nothing is emitted and the returned error wraps
+// ErrMissingField once per missing field.
+func (a Logger) Log(e Event) error {
+	if err := e.validate(); err != nil {
+		return err
+	}
+
+	fs := []fields.Field{
+		fields.String(ActorKey, e.Actor),
+		fields.String(ActionKey, e.Action),
+		fields.String(TargetKey, e.Target),
+		fields.String(OutcomeKey, string(e.Outcome)),
+	}
+	if len(e.Details) > 0 {
+		fs = append(fs, fields.Dict(DetailsKey, e.Details...))
+	}
+	a.l.Info(Message, fs...)
+	return nil
+}
+
+// With returns a child audit Logger whose entries carry fs as context, eg a request ID. Fields that would shadow a
+// schema key are renamed with ContextPrefix, including the ones lazy fields and precomputed bundles hold.
+func (a Logger) With(fs ...fields.Field) Logger {
+	return Logger{l: a.l.With(unshadow(fs)...)}
+}
+
+// reserved reports whether k is one of the schema's keys.
+func reserved(k string) bool {
+	switch k {
+	case ActorKey, ActionKey, TargetKey, OutcomeKey, DetailsKey:
+		return true
+	}
+	return false
+}
+
+// unshadow returns fs with the keys shadowing the schema renamed.
+func unshadow(fs []fields.Field) []fields.Field {
+	out := make([]fields.Field, 0, len(fs))
+	for _, f := range fs {
+		switch f.Kind() {
+		case fields.FieldKindLazyFields, fields.FieldKindLazyValue:
+			fn := f.LazyFunc()
+			f = fields.LazyFields(func(ctx context.Context) []fields.Field { return unshadow(fn(ctx)) })
+		case fields.FieldKindPrecomputed:
+			out = append(out, unshadow(f.Precomputed().Fields())...)
+			continue
+		default:
+			if reserved(f.Key) {
+				f.Key = ContextPrefix + f.Key
+			}
+		}
+		out = append(out, f)
+	}
+	return out
+}
+
+// Sync flushes the underlying logger.
+func (a Logger) Sync() error {
+	return a.l.Sync()
+}
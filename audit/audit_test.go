@@ -0,0 +1,68 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/khinshankhan/logstox"
+	"github.com/khinshankhan/logstox/fields"
+)
+
+func TestWithRenamesShadowingFields(t *testing.T) {
+	tests := []struct {
+		name string
+		ctx  fields.Field
+		want map[string]string
+	}{
+		{
+			name: "plain",
+			ctx:  fields.String(ActorKey, "spoofed"),
+			want: map[string]string{ActorKey: "alice", ContextPrefix + ActorKey: "spoofed"},
+		},
+		{
+			name: "lazy",
+			ctx:  fields.Lazy(func() []fields.Field { return []fields.Field{fields.String(OutcomeKey, "spoofed")} }),
+			want: map[string]string{OutcomeKey: "success", ContextPrefix + OutcomeKey: "spoofed"},
+		},
+		{
+			name: "precomputed",
+			ctx:  fields.Precompute(fields.String(TargetKey, "spoofed"), fields.String("request_id", "r1")),
+			want: map[string]string{TargetKey: "doc", ContextPrefix + TargetKey: "spoofed", "request_id": "r1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := NewWriter(&buf).With(tt.ctx).Log(Event{Actor: "alice", Action: "doc.read", Target: "doc", Outcome: Success})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var e logstox.Entry
+			if err := json.Unmarshal(buf.Bytes(), &e); err != nil {
+				t.Fatalf("decoding %q: %v", buf.Bytes(), err)
+			}
+			got := make(map[string]string)
+			for _, f := range e.Fields {
+				got[f.Key] = f.Str()
+			}
+			for k, want := range tt.want {
+				if got[k] != want {
+					t.Errorf("%s = %v, want %v", k, got[k], want)
+				}
+			}
+		})
+	}
+}
+
+func TestLogMissingFields(t *testing.T) {
+	var buf bytes.Buffer
+	err := NewWriter(&buf).Log(Event{Actor: "alice", Outcome: Failure})
+	if !errors.Is(err, ErrMissingField) {
+		t.Fatalf("err = %v, want ErrMissingField", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("wrote %q for an invalid event", buf.Bytes())
+	}
+}
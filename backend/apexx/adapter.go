@@ -0,0 +1,99 @@
+package apexx
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/apex/log"
+
+	"github.com/khinshankhan/logstox/fields"
+)
+
+// ToApex converts logstox fields into apex Fields, Dicts becoming nested Fields.
+// LazyFields functions are handed context.Background(); use ToApexContext to supply a request context.
+func ToApex(fs ...fields.Field) log.Fields {
+	return ToApexContext(context.Background(), fs...)
+}
+
+// ToApexContext converts logstox fields into apex Fields, handing ctx to LazyFields functions (including those nested
+// in Dicts). Lazy fields are evaluated right away, and precomputed bundles are expanded. A repeated key is written
+// again suffixed with "_2", "_3" and so on, so no value is lost.
+func ToApexContext(ctx context.Context, fs ...fields.Field) log.Fields {
+	out := make(log.Fields, len(fs))
+	add(ctx, out, fs, nil)
+	return out
+}
+
+// add converts fs into dst, appending the keys dst already had to repeated if it's not nil.
+func add(ctx context.Context, dst log.Fields, fs []fields.Field, repeated *[]string) {
+	for _, f := range fs {
+		switch f.Kind() {
+		case fields.FieldKindInvalid:
+		case fields.FieldKindLazyFields, fields.FieldKindLazyValue:
+			add(ctx, dst, f.LazyFunc()(ctx), repeated)
+		case fields.FieldKindPrecomputed:
+			add(ctx, dst, f.Precomputed().Fields(), repeated)
+		default:
+			put(dst, f.Key, value(ctx, f), repeated)
+		}
+	}
+}
+
+// put writes v under k in dst, or under the first free suffixed key if k is taken, appending k to repeated if it's not
+// nil.
+func put(dst log.Fields, k string, v any, repeated *[]string) {
+	if !has(dst, k) {
+		dst[k] = v
+		return
+	}
+	if repeated != nil {
+		*repeated = append(*repeated, k)
+	}
+	for n := 2; ; n++ {
+		if sk := k + "_" + strconv.Itoa(n); !has(dst, sk) {
+			dst[sk] = v
+			return
+		}
+	}
+}
+
+// value converts f's value into one apex's handlers encode sensibly.
+func value(ctx context.Context, f fields.Field) any {
+	switch f.Kind() {
+	case fields.FieldKindError:
+		return f.Err().Error()
+	case fields.FieldKindErrors:
+		errs := f.Interface().([]error)
+		msgs := make([]string, len(errs))
+		for i, err := range errs {
+			if err != nil {
+				msgs[i] = err.Error()
+			} else {
+				msgs[i] = "<nil>"
+			}
+		}
+		return msgs
+	case fields.FieldKindDict:
+		return ToApexContext(ctx, f.Fields()...)
+	case fields.FieldKindRawJSON:
+		return json.RawMessage(f.Bytes())
+	case fields.FieldKindHexBytes:
+		return hex.EncodeToString(f.Bytes())
+	case fields.FieldKindTimestamp:
+		if t := f.Time(); !t.IsZero() {
+			return t
+		}
+		return time.Now()
+	default:
+		return f.Boxed()
+	}
+}
+
+// has reports whether dst has the key k.
+func has(dst log.Fields, k string) bool {
+	_, ok := dst[k]
+	return ok
+}
@@ -0,0 +1,462 @@
+package apexx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/apex/log"
+	"github.com/apex/log/handlers/json"
+	"github.com/apex/log/handlers/multi"
+	"github.com/apex/log/handlers/text"
+
+	"github.com/khinshankhan/logstox"
+	"github.com/khinshankhan/logstox/fields"
+)
+
+// Encodings supported by Backend.Encoding.
+const (
+	EncodingJSON = "json"
+	EncodingText = "text"
+)
+
+// NameKey is the field the logger name is written under, apex loggers being unnamed.
+const NameKey = "logger"
+
+// Backend builds loggers writing through apex/log, for teams on apex that want the logstox field vocabulary before
+// fully migrating. Fields are converted into apex Fields (see ToApex) and the logger name is written under NameKey.
+//
+// Options.Level, LevelVar, Name, Fields, Writer, Writers, SplitStdStreams, Development, Multiline, Limits and
+// ContextExtractors are honored; apex has no caller, stacktrace or time layout settings, so the other options don't apply.
+//
+// apex keeps fields in a map, so a key repeated across context and call-site fields can't be written twice: the
+// later fields are written under the key suffixed with "_2", "_3" and so on, and Development reports the repeat like
+// logstox.ValidateKeys does.
+type Backend struct {
+	// Development is the same as Options.Development: it defaults Encoding to text, makes DPanic panic after logging
+	// and validates field keys (see logstox.ValidateKeys).
+	Development bool
+	// Encoding selects the handler, either EncodingJSON or EncodingText.
+	// If empty, defaults to text in Development and JSON otherwise.
+	Encoding string
+	// Handler, if set, is used as is instead of building one, so existing handlers can be kept.
+	// Writer and encoding settings are then the handler's business and are ignored.
+	Handler log.Handler
+}
+
+// Interface satisfaction (compile-time assertions).
+var _ logstox.Backend[fields.Field] = Backend{}
+
+// New constructs an apex-backed Logger[fields.Field].
+func (b Backend) New(o logstox.Options[fields.Field]) logstox.Logger[fields.Field] {
+	o.Development = b.Development || o.Development
+	if o.SplitStdStreams {
+		o.Writers = append(o.Writers[:len(o.Writers):len(o.Writers)], logstox.StdStreams()...)
+	}
+
+	h := b.Handler
+	var writers []io.Writer // closed by Close
+	if h == nil {
+		var hs []log.Handler
+		if o.Writer != nil || len(o.Writers) == 0 {
+			w := o.Writer
+			if w == nil {
+				w = os.Stderr
+			}
+			hs = append(hs, b.handler(w, o))
+			writers = append(writers, w)
+		}
+		for _, w := range o.Writers {
+			hs = append(hs, levelHandler{b.handler(w.Writer, o), w.Enabled})
+			writers = append(writers, w.Writer)
+		}
+		h = multi.New(hs...)
+	}
+
+	// the logger does the filtering, so LevelVar and WithLevel can go below Options.Level
+	lg := fromApex(&log.Logger{Handler: h, Level: log.DebugLevel}, o)
+	lg.writers = writers
+	lg.console = b.Handler == nil && b.encoding(o) == EncodingText
+	return lg
+}
+
+// handler builds the JSON/ text handler writing to w.
+func (b Backend) handler(w io.Writer, o logstox.Options[fields.Field]) log.Handler {
+	if b.encoding(o) == EncodingText {
+		return text.New(w)
+	}
+	return json.New(w)
+}
+
+// encoding returns the encoding of the handlers New builds.
+func (b Backend) encoding(o logstox.Options[fields.Field]) string {
+	switch {
+	case b.Encoding != "":
+		return b.Encoding
+	case o.Development:
+		return EncodingText
+	default:
+		return EncodingJSON
+	}
+}
+
+// FromApex wraps an existing apex logger (eg the package level log.Log, or an entry carrying fields already),
+// applying Options.Level, LevelVar, Name, Fields, Development and ContextExtractors on top of its own level.
+func FromApex(base log.Interface, o logstox.Options[fields.Field]) logstox.Logger[fields.Field] {
+	return fromApex(base, o)
+}
+
+func fromApex(base log.Interface, o logstox.Options[fields.Field]) logger {
+	lg := logger{
+		e:           base.WithFields(log.Fields{}), // an entry of base, whatever its type
+		level:       o.Level,
+		min:         o.Level,
+		levelVar:    o.LevelVar,
+		development: o.Development,
+		name:        o.Name,
+		extractors:  o.ContextExtractors,
+		multiline:   o.Multiline,
+		limits:      o.Limits,
+	}
+	if o.LevelVar != nil {
+		// the LevelVar is the floor instead, WithLevel can only raise it
+		lg.level, lg.min = math.MinInt8, math.MinInt8
+	}
+	if len(o.Fields) > 0 {
+		return lg.with(o.Fields)
+	}
+	return lg
+}
+
+// logger is the apex implementation of logstox.Logger[fields.Field].
+type logger struct {
+	e           *log.Entry        // the apex entry written through, carrying the fields of the one passed to FromApex
+	eager       log.Fields        // the eager context fields, converted; shared, copied on write
+	pending     func() log.Fields // set by WithLazy, builds eager on first use
+	level       logstox.Level     // Options.Level, the floor for WithLevel
+	min         logstox.Level     // entries below are dropped
+	development bool
+	name        string
+	context     []fields.Field    // every With field, eager or lazy, for Fields
+	lazy        []fields.Field    // lazy context fields, evaluated per entry
+	levelVar    *logstox.LevelVar // checked on every entry if set
+	writers     []io.Writer       // Options writers, synced by Sync and closed by Close
+	multiline   logstox.Multiline // Options.Multiline
+	limits      logstox.Limits    // Options.Limits
+	onError     func(error)       // handler failures, written to stderr by apex if nil
+	hooks       []func(logstox.Entry) error
+	console     bool // whether the handler is apex's text one, which writes messages as they are
+	// ctx is the context bound by WithContext, handed to lazy fields; extractors (Options.ContextExtractors) derive
+	// fields from it per entry.
+	ctx        context.Context
+	extractors []func(context.Context) []fields.Field
+}
+
+// Interface satisfaction (compile-time assertions).
+var (
+	_ logstox.Logger[fields.Field]         = logger{}
+	_ logstox.LevelCheck                   = logger{}
+	_ logstox.LevelSetter[fields.Field]    = logger{}
+	_ logstox.OptionsApplier[fields.Field] = logger{}
+	_ logstox.LazyWither[fields.Field]     = logger{}
+	_ logstox.Noticer[fields.Field]        = logger{}
+	_ logstox.Introspector[fields.Field]   = logger{}
+	_ logstox.LevelVarHolder               = logger{}
+	_ logstox.Closer                       = logger{}
+	_ logstox.ContextBinder[fields.Field]  = logger{}
+)
+
+// log writes an entry through apex if lvl is enabled.
+func (lg logger) log(lvl logstox.Level, msg string, fs []fields.Field) {
+	if !lg.Enabled(lvl) {
+		return
+	}
+	msg, detail := lg.multiline.Apply(msg, lg.console)
+	if detail != "" {
+		fs = append(fs[:len(fs):len(fs)], fields.String(logstox.DetailKey, detail))
+	}
+	var invalid error
+	if lg.development && lvl.Compare(logstox.DPanicLevel) < 0 {
+		if invalid = validate(fs); invalid != nil {
+			lvl = logstox.DPanicLevel
+			fs = append(fs[:len(fs):len(fs)], fields.String(logstox.FieldErrorsKey, invalid.Error()), fields.PanicStack())
+		}
+	}
+
+	ctx := lg.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	eager := lg.eagerFields()
+	fm := make(log.Fields, len(eager)+len(lg.lazy)+len(fs)+1)
+	for k, v := range eager {
+		fm[k] = v
+	}
+	var repeated []string
+	if lg.name != "" {
+		put(fm, NameKey, lg.name, &repeated)
+	}
+	if lvl == logstox.NoticeLevel {
+		put(fm, logstox.NoticeKey, true, &repeated)
+	}
+	add(ctx, fm, lg.lazy, &repeated)
+	if lg.limits.Enabled() {
+		// context fields aren't bounded, see logstox.Limits
+		var all []fields.Field
+		if lg.ctx != nil {
+			all = fields.Resolve(ctx, all, logstox.ExtractContext(ctx, lg.extractors))
+		}
+		msg, all = lg.limits.Apply(msg, fields.Resolve(ctx, all, fs))
+		add(ctx, fm, all, &repeated)
+	} else {
+		if lg.ctx != nil {
+			add(ctx, fm, logstox.ExtractContext(ctx, lg.extractors), &repeated)
+		}
+		add(ctx, fm, fs, &repeated)
+	}
+	if len(repeated) > 0 && lg.development && lvl.Compare(logstox.DPanicLevel) < 0 && invalid == nil {
+		invalid = repeatedKeys(repeated)
+		lvl = logstox.DPanicLevel
+		fm[logstox.FieldErrorsKey] = invalid.Error()
+		add(ctx, fm, []fields.Field{fields.PanicStack()}, nil)
+	}
+
+	e := lg.e.WithFields(fm)
+	switch lvl {
+	case logstox.DebugLevel:
+		e.Debug(msg)
+	case logstox.InfoLevel, logstox.NoticeLevel:
+		e.Info(msg)
+	case logstox.WarnLevel:
+		e.Warn(msg)
+	case logstox.FatalLevel:
+		e.Fatal(msg) // exits
+	default:
+		e.Error(msg)
+	}
+	lg.hook(lvl, msg)
+	if invalid != nil {
+		panic(msg)
+	}
+}
+
+// eagerFields returns the eager context fields, converting them first if WithLazy deferred it.
+func (lg logger) eagerFields() log.Fields {
+	if lg.pending != nil {
+		return lg.pending()
+	}
+	return lg.eager
+}
+
+// hook calls the hooks added by WithOptions with the entry just written.
+func (lg logger) hook(lvl logstox.Level, msg string) {
+	if len(lg.hooks) == 0 {
+		return
+	}
+	e := logstox.Entry{Time: time.Now(), Level: lvl, Name: lg.name, Message: msg, Context: lg.ctx}
+	for _, hook := range lg.hooks {
+		if err := hook(e); err != nil && lg.onError != nil {
+			lg.onError(err)
+		}
+	}
+}
+
+// repeatedKeys reports the keys written more than once in an entry, for Development.
+func repeatedKeys(keys []string) error {
+	errs := make([]error, len(keys))
+	for i, k := range keys {
+		errs[i] = fmt.Errorf("duplicate field key %q", k)
+	}
+	return errors.Join(errs...)
+}
+
+// validate checks the keys of fs, leaving out no-op, lazy and precomputed fields.
+func validate(fs []fields.Field) error {
+	keys := make([]string, 0, len(fs))
+	for _, f := range fs {
+		switch f.Kind() {
+		case fields.FieldKindInvalid, fields.FieldKindLazyFields, fields.FieldKindLazyValue, fields.FieldKindPrecomputed:
+		default:
+			keys = append(keys, f.Key)
+		}
+	}
+	return logstox.ValidateKeys(keys)
+}
+
+// DEBUG (-1): for recording messages useful for debugging.
+func (lg logger) Debug(m string, f ...fields.Field) { lg.log(logstox.DebugLevel, m, f) }
+
+// INFO (0): for messages describing normal application operations.
+func (lg logger) Info(m string, f ...fields.Field) { lg.log(logstox.InfoLevel, m, f) }
+
+// Notice logs at logstox.NoticeLevel, see logstox.Noticer. apex has no notice level, so it's written at apex's info
+// level tagged with a true logstox.NoticeKey field, and Options.Writers filters see it as Info.
+func (lg logger) Notice(m string, f ...fields.Field) { lg.log(logstox.NoticeLevel, m, f) }
+
+// WARN (1): for recording messages indicating something unusual happened that may need attention before it escalates to a more severe issue.
+func (lg logger) Warn(m string, f ...fields.Field) { lg.log(logstox.WarnLevel, m, f) }
+
+// ERROR (2): for recording unexpected error conditions in the program.
+func (lg logger) Error(m string, f ...fields.Field) { lg.log(logstox.ErrorLevel, m, f) }
+
+// DPANIC (3): for recording severe error conditions in development. It behaves like PANIC in development and ERROR in production.
+// The entry carries a fields.PanicStack dict, and is written at apex's error level.
+func (lg logger) DPanic(m string, f ...fields.Field) {
+	lg.log(logstox.DPanicLevel, m, append(f[:len(f):len(f)], fields.PanicStack()))
+	if lg.development {
+		panic(m)
+	}
+}
+
+// PANIC (4): calls panic() after logging an error condition.
+// The entry carries a fields.PanicStack dict, and is written at apex's error level.
+func (lg logger) Panic(m string, f ...fields.Field) {
+	lg.log(logstox.PanicLevel, m, append(f[:len(f):len(f)], fields.PanicStack()))
+	panic(m)
+}
+
+// FATAL (5): calls os.Exit(1) after logging an error condition.
+func (lg logger) Fatal(m string, f ...fields.Field) {
+	lg.log(logstox.FatalLevel, m, f)
+	os.Exit(1) // apex exits itself, unless the entry was filtered
+}
+
+// With creates a child logger and adds structured context to it. Eager fields are converted once, lazy fields are
+// kept aside and evaluated per entry.
+func (lg logger) With(f ...fields.Field) logstox.Logger[fields.Field] {
+	return lg.with(f)
+}
+
+func (lg logger) with(fs []fields.Field) logger {
+	var eager []fields.Field
+	lazy := lg.lazy
+	for _, f := range fs {
+		switch f.Kind() {
+		case fields.FieldKindLazyFields, fields.FieldKindLazyValue:
+			lazy = append(lazy[:len(lazy):len(lazy)], f) // copy on append, the parent shares lg.lazy
+		default:
+			eager = append(eager, f)
+		}
+	}
+
+	child := lg
+	child.lazy = lazy
+	child.context = append(lg.context[:len(lg.context):len(lg.context)], fs...)
+	if len(eager) > 0 {
+		parent := lg.eagerFields()
+		fm := make(log.Fields, len(parent)+len(eager))
+		for k, v := range parent {
+			fm[k] = v
+		}
+		add(context.Background(), fm, eager, nil)
+		child.eager, child.pending = fm, nil
+	}
+	return child
+}
+
+// WithLazy is With, except converting the eager fields is deferred until the child, or one of its descendants,
+// first writes an entry.
+func (lg logger) WithLazy(f ...fields.Field) logstox.Logger[fields.Field] {
+	child := lg
+	child.pending = sync.OnceValue(func() log.Fields { return lg.with(f).eagerFields() })
+	child.context = append(lg.context[:len(lg.context):len(lg.context)], f...)
+	return child
+}
+
+// WithOptions applies the ErrorHandler and Hooks of opts; apex has no caller, so AddSource and CallerSkip don't
+// apply.
+func (lg logger) WithOptions(opts ...logstox.Option) logstox.Logger[fields.Field] {
+	a := logstox.Collect(opts...)
+
+	child := lg
+	if a.ErrorHandler != nil && lg.e.Logger != nil {
+		child.onError = a.ErrorHandler
+		e := *lg.e.WithFields(log.Fields{}) // keeps the fields of the entry passed to FromApex
+		e.Logger = &log.Logger{Handler: errorHandler{lg.e.Logger.Handler, a.ErrorHandler}, Level: lg.e.Logger.Level}
+		child.e = &e
+	}
+	if len(a.Hooks) > 0 {
+		child.hooks = append(lg.hooks[:len(lg.hooks):len(lg.hooks)], a.Hooks...)
+	}
+	return child
+}
+
+// errorHandler reports the failures of its handler to fn, instead of apex writing them to stderr.
+type errorHandler struct {
+	log.Handler
+	fn func(error)
+}
+
+func (h errorHandler) HandleLog(e *log.Entry) error {
+	if err := h.Handler.HandleLog(e); err != nil {
+		h.fn(err)
+	}
+	return nil
+}
+
+// WithContext returns a child bound to ctx: Options.ContextExtractors derive fields from it for every entry, and lazy
+// fields are handed it.
+func (lg logger) WithContext(ctx context.Context) logstox.Logger[fields.Field] {
+	child := lg
+	child.ctx = ctx
+	return child
+}
+
+// Named adds a new path segment to the logger's name. Segments are joined by
+// periods. By default, Loggers are unnamed.
+func (lg logger) Named(n string) logstox.Logger[fields.Field] {
+	child := lg
+	if lg.name == "" {
+		child.name = n
+	} else if n != "" {
+		child.name = lg.name + "." + n
+	}
+	return child
+}
+
+// Sync flushes the writers that support it (eg *os.File). apex itself doesn't buffer.
+func (lg logger) Sync() error {
+	for _, w := range lg.writers {
+		if s, ok := w.(interface{ Sync() error }); ok && w != os.Stdout && w != os.Stderr {
+			if err := s.Sync(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Close syncs the logger, then closes the Options writers that are io.Closers.
+func (lg logger) Close() error {
+	return errors.Join(lg.Sync(), logstox.CloseWriters(lg.writers...))
+}
+
+// Enabled reports whether lvl is at or above the logger's level and the apex logger's.
+func (lg logger) Enabled(lvl logstox.Level) bool {
+	if lvl.Compare(lg.min) < 0 || lg.levelVar != nil && lvl.Compare(lg.levelVar.Level()) < 0 {
+		return false
+	}
+	return lg.e.Logger == nil || lvl.Compare(FromApexLevel(lg.e.Logger.Level)) >= 0
+}
+
+// WithLevel returns a child writing entries at lvl and above, though never below Options.Level or LevelVar.
+func (lg logger) WithLevel(lvl logstox.Level) logstox.Logger[fields.Field] {
+	child := lg
+	child.min = max(lvl, lg.level)
+	return child
+}
+
+// Name returns the logger's name.
+func (lg logger) Name() string { return lg.name }
+
+// Fields returns the context fields added via With and Options.Fields. Fields of an entry passed to FromApex aren't
+// known.
+func (lg logger) Fields() []fields.Field { return lg.context }
+
+// LevelVar returns Options.LevelVar, nil if the logger wasn't built with one.
+func (lg logger) LevelVar() *logstox.LevelVar { return lg.levelVar }
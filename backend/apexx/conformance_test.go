@@ -0,0 +1,33 @@
+package apexx
+
+import (
+	"testing"
+
+	"github.com/khinshankhan/logstox"
+	"github.com/khinshankhan/logstox/backendtest"
+	"github.com/khinshankhan/logstox/fields"
+)
+
+func TestConformance(t *testing.T) {
+	backendtest.Run(t, backendtest.Subject[fields.Field]{
+		Backend:    Backend{},
+		Convert:    func(f fields.Field) fields.Field { return f },
+		MessageKey: "message",
+		LevelName: func(lvl logstox.Level) string {
+			l, _ := ToApexLevel(lvl)
+			return l.String()
+		},
+		// apex's JSON handler nests the fields, the logger name included, under "fields"
+		Decode: func(output []byte) ([]map[string]any, error) {
+			es, err := backendtest.NDJSON(output)
+			for _, e := range es {
+				fs, _ := e["fields"].(map[string]any)
+				delete(e, "fields")
+				for k, v := range fs {
+					e[k] = v
+				}
+			}
+			return es, err
+		},
+	})
+}
@@ -0,0 +1,15 @@
+module github.com/khinshankhan/logstox/backend/apexx
+
+go 1.24.2
+
+require (
+	github.com/apex/log v1.9.0
+	github.com/khinshankhan/logstox v0.0.0-20250914151607-81d0c77772ce
+)
+
+require (
+	github.com/pkg/errors v0.9.1 // indirect
+	github.com/stretchr/testify v1.8.4 // indirect
+)
+
+replace github.com/khinshankhan/logstox => ../..
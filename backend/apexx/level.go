@@ -0,0 +1,56 @@
+package apexx
+
+import (
+	"github.com/apex/log"
+
+	"github.com/khinshankhan/logstox"
+)
+
+// ToApexLevel maps l onto apex's levels, reporting false (and log.InfoLevel) for levels logstox doesn't define. apex
+// has no Notice, DPanic or Panic level, they're mapped to log.InfoLevel and log.ErrorLevel.
+func ToApexLevel(l logstox.Level) (log.Level, bool) {
+	switch l {
+	case logstox.DebugLevel:
+		return log.DebugLevel, true
+	case logstox.InfoLevel, logstox.NoticeLevel:
+		return log.InfoLevel, true
+	case logstox.WarnLevel:
+		return log.WarnLevel, true
+	case logstox.ErrorLevel, logstox.DPanicLevel, logstox.PanicLevel:
+		return log.ErrorLevel, true
+	case logstox.FatalLevel:
+		return log.FatalLevel, true
+	default:
+		return log.InfoLevel, false
+	}
+}
+
+// FromApexLevel maps l onto logstox's levels. log.InvalidLevel and unknown levels are DebugLevel, so nothing is
+// filtered by mistake.
+func FromApexLevel(l log.Level) logstox.Level {
+	switch l {
+	case log.InfoLevel:
+		return logstox.InfoLevel
+	case log.WarnLevel:
+		return logstox.WarnLevel
+	case log.ErrorLevel:
+		return logstox.ErrorLevel
+	case log.FatalLevel:
+		return logstox.FatalLevel
+	default:
+		return logstox.DebugLevel
+	}
+}
+
+// levelHandler passes entries to h only when enabled reports true for their level, see logstox.LevelWriter.
+type levelHandler struct {
+	h       log.Handler
+	enabled func(logstox.Level) bool
+}
+
+func (h levelHandler) HandleLog(e *log.Entry) error {
+	if h.enabled != nil && !h.enabled(FromApexLevel(e.Level)) {
+		return nil
+	}
+	return h.h.HandleLog(e)
+}
@@ -0,0 +1,18 @@
+package memx
+
+import (
+	"testing"
+
+	"github.com/khinshankhan/logstox"
+	"github.com/khinshankhan/logstox/backendtest"
+	"github.com/khinshankhan/logstox/fields"
+)
+
+func TestConformance(t *testing.T) {
+	backendtest.Run(t, backendtest.Subject[fields.Field]{
+		Backend:  Backend{},
+		Convert:  func(f fields.Field) fields.Field { return f },
+		NameKey:  "name",
+		Recorded: func(l logstox.Logger[fields.Field]) []logstox.Entry { return RecorderOf(l).Entries() },
+	})
+}
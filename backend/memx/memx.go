@@ -0,0 +1,313 @@
+package memx
+
+import (
+	"context"
+	"math"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/khinshankhan/logstox"
+	"github.com/khinshankhan/logstox/fields"
+)
+
+// DefaultCapacity is the number of entries a Recorder keeps when built with a capacity <= 0.
+const DefaultCapacity = 1000
+
+// Recorder is a thread-safe, fixed-capacity store of entries. Once full, recording an entry evicts the oldest one.
+type Recorder struct {
+	mu      sync.Mutex
+	buf     []logstox.Entry // ring buffer, the oldest entry at start
+	start   int
+	n       int
+	evicted uint64
+}
+
+// NewRecorder returns a Recorder keeping the last capacity entries (DefaultCapacity if capacity <= 0).
+func NewRecorder(capacity int) *Recorder {
+	if capacity <= 0 {
+		capacity = DefaultCapacity
+	}
+	return &Recorder{buf: make([]logstox.Entry, capacity)}
+}
+
+// Record appends e, evicting the oldest entry if the recorder is full.
+func (r *Recorder) Record(e logstox.Entry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.n < len(r.buf) {
+		r.buf[(r.start+r.n)%len(r.buf)] = e
+		r.n++
+		return
+	}
+	r.buf[r.start] = e
+	r.start = (r.start + 1) % len(r.buf)
+	r.evicted++
+}
+
+// Entries returns a copy of the retained entries, oldest first.
+func (r *Recorder) Entries() []logstox.Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]logstox.Entry, r.n)
+	for i := range out {
+		out[i] = r.buf[(r.start+i)%len(r.buf)]
+	}
+	return out
+}
+
+// Len returns the number of retained entries.
+func (r *Recorder) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.n
+}
+
+// Cap returns the maximum number of retained entries.
+func (r *Recorder) Cap() int {
+	return len(r.buf)
+}
+
+// Evicted returns the number of entries dropped to make room since the recorder was created.
+func (r *Recorder) Evicted() uint64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.evicted
+}
+
+// Reset forgets all retained entries.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	clear(r.buf)
+	r.start, r.n = 0, 0
+}
+
+// Backend builds loggers recording typed entries into a Recorder instead of writing them anywhere, for examples,
+// "recent logs" admin pages, and tests that don't want to parse output:
+//
+//	rec := memx.NewRecorder(500)
+//	log := memx.Backend{Recorder: rec}.New(logstox.Options[fields.Field]{Level: logstox.DebugLevel})
+//	...
+//	for _, e := range rec.Entries() { ... }
+//
+// Options.Level, LevelVar, Name, Fields, Development, Multiline's folding, Limits and ContextExtractors are honored;
+// the other options don't apply. Lazy fields are evaluated when an entry is recorded, so entries only hold plain
+// values.
+type Backend struct {
+	// Recorder receives the entries. If nil, New creates one with DefaultCapacity, reachable via RecorderOf.
+	Recorder *Recorder
+	// Development is the same as Options.Development: it makes DPanic panic after recording and validates field keys
+	// (see logstox.ValidateKeys).
+	Development bool
+}
+
+// Interface satisfaction (compile-time assertions).
+var _ logstox.Backend[fields.Field] = Backend{}
+
+// New constructs a Logger recording into b.Recorder.
+func (b Backend) New(o logstox.Options[fields.Field]) logstox.Logger[fields.Field] {
+	rec := b.Recorder
+	if rec == nil {
+		rec = NewRecorder(DefaultCapacity)
+	}
+	lg := logger{
+		rec:         rec,
+		level:       o.Level,
+		min:         o.Level,
+		levelVar:    o.LevelVar,
+		development: b.Development || o.Development,
+		name:        o.Name,
+		context:     o.Fields,
+		extractors:  o.ContextExtractors,
+		multiline:   o.Multiline,
+		limits:      o.Limits,
+	}
+	if o.LevelVar != nil {
+		// the LevelVar is the floor instead, WithLevel can only raise it
+		lg.level, lg.min = math.MinInt8, math.MinInt8
+	}
+	return lg
+}
+
+// RecorderOf returns the Recorder a memx logger records into, or nil if l isn't one.
+func RecorderOf(l logstox.Logger[fields.Field]) *Recorder {
+	if lg, ok := l.(logger); ok {
+		return lg.rec
+	}
+	return nil
+}
+
+// logger is the memx implementation of logstox.Logger[fields.Field].
+type logger struct {
+	rec         *Recorder
+	level       logstox.Level // Options.Level, the floor for WithLevel
+	min         logstox.Level // entries below are dropped
+	development bool
+	name        string
+	context     []fields.Field
+	levelVar    *logstox.LevelVar // checked on every entry if set
+	multiline   logstox.Multiline // Options.Multiline, only folding applies
+	limits      logstox.Limits    // Options.Limits
+	// ctx is the context bound by WithContext, handed to lazy fields; extractors (Options.ContextExtractors) derive
+	// fields from it per entry.
+	ctx        context.Context
+	extractors []func(context.Context) []fields.Field
+}
+
+// Interface satisfaction (compile-time assertions).
+var (
+	_ logstox.Logger[fields.Field]        = logger{}
+	_ logstox.Noticer[fields.Field]       = logger{}
+	_ logstox.LevelCheck                  = logger{}
+	_ logstox.LevelSetter[fields.Field]   = logger{}
+	_ logstox.Introspector[fields.Field]  = logger{}
+	_ logstox.LevelVarHolder              = logger{}
+	_ logstox.ContextBinder[fields.Field] = logger{}
+)
+
+// log records an entry if lvl is enabled.
+func (lg logger) log(lvl logstox.Level, msg string, fs []fields.Field) {
+	if !lg.Enabled(lvl) {
+		return
+	}
+	msg, detail := lg.multiline.Apply(msg, false)
+	if detail != "" {
+		fs = append(fs[:len(fs):len(fs)], fields.String(logstox.DetailKey, detail))
+	}
+	var invalid error
+	if lg.development && lvl.Compare(logstox.DPanicLevel) < 0 {
+		if invalid = validate(fs); invalid != nil {
+			lvl = logstox.DPanicLevel
+			fs = append(fs[:len(fs):len(fs)], fields.String(logstox.FieldErrorsKey, invalid.Error()), fields.PanicStack())
+		}
+	}
+	ctx := lg.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	all := make([]fields.Field, 0, len(lg.context)+len(fs))
+	all = fields.Resolve(ctx, all, lg.context)
+	n := len(all) // the context fields, which the limits don't bound
+	if lg.ctx != nil {
+		all = fields.Resolve(ctx, all, logstox.ExtractContext(ctx, lg.extractors))
+	}
+	all = fields.Resolve(ctx, all, fs)
+	if lg.limits.Enabled() {
+		var limited []fields.Field
+		msg, limited = lg.limits.Apply(msg, all[n:])
+		all = append(all[:n], limited...)
+	}
+	lg.rec.Record(logstox.Entry{
+		Time:    time.Now(),
+		Level:   lvl,
+		Name:    lg.name,
+		Message: msg,
+		Fields:  all,
+		Context: lg.ctx,
+	})
+	if invalid != nil {
+		panic(msg)
+	}
+}
+
+// validate checks the keys of fs, leaving out no-op, lazy and precomputed fields.
+func validate(fs []fields.Field) error {
+	keys := make([]string, 0, len(fs))
+	for _, f := range fs {
+		switch f.Kind() {
+		case fields.FieldKindInvalid, fields.FieldKindLazyFields, fields.FieldKindLazyValue, fields.FieldKindPrecomputed:
+		default:
+			keys = append(keys, f.Key)
+		}
+	}
+	return logstox.ValidateKeys(keys)
+}
+
+// DEBUG (-1): for recording messages useful for debugging.
+func (lg logger) Debug(m string, f ...fields.Field) { lg.log(logstox.DebugLevel, m, f) }
+
+// INFO (0): for messages describing normal application operations.
+func (lg logger) Info(m string, f ...fields.Field) { lg.log(logstox.InfoLevel, m, f) }
+
+// Notice logs at logstox.NoticeLevel, see logstox.Noticer.
+func (lg logger) Notice(m string, f ...fields.Field) { lg.log(logstox.NoticeLevel, m, f) }
+
+// WARN (1): for recording messages indicating something unusual happened that may need attention before it escalates to a more severe issue.
+func (lg logger) Warn(m string, f ...fields.Field) { lg.log(logstox.WarnLevel, m, f) }
+
+// ERROR (2): for recording unexpected error conditions in the program.
+func (lg logger) Error(m string, f ...fields.Field) { lg.log(logstox.ErrorLevel, m, f) }
+
+// DPANIC (3): for recording severe error conditions in development. It behaves like PANIC in development and ERROR in production.
+// The entry carries a fields.PanicStack dict.
+func (lg logger) DPanic(m string, f ...fields.Field) {
+	lg.log(logstox.DPanicLevel, m, append(f[:len(f):len(f)], fields.PanicStack()))
+	if lg.development {
+		panic(m)
+	}
+}
+
+// PANIC (4): calls panic() after logging an error condition.
+// The entry carries a fields.PanicStack dict.
+func (lg logger) Panic(m string, f ...fields.Field) {
+	lg.log(logstox.PanicLevel, m, append(f[:len(f):len(f)], fields.PanicStack()))
+	panic(m)
+}
+
+// FATAL (5): calls os.Exit(1) after logging an error condition.
+func (lg logger) Fatal(m string, f ...fields.Field) {
+	lg.log(logstox.FatalLevel, m, f)
+	os.Exit(1)
+}
+
+// With creates a child logger and adds structured context to it. Lazy fields are evaluated per recorded entry.
+func (lg logger) With(f ...fields.Field) logstox.Logger[fields.Field] {
+	child := lg
+	child.context = append(lg.context[:len(lg.context):len(lg.context)], f...)
+	return child
+}
+
+// WithContext returns a child bound to ctx: Options.ContextExtractors derive fields from it for every entry, and lazy
+// fields are handed it.
+func (lg logger) WithContext(ctx context.Context) logstox.Logger[fields.Field] {
+	child := lg
+	child.ctx = ctx
+	return child
+}
+
+// Named adds a new path segment to the logger's name. Segments are joined by
+// periods. By default, Loggers are unnamed.
+func (lg logger) Named(n string) logstox.Logger[fields.Field] {
+	child := lg
+	if lg.name == "" {
+		child.name = n
+	} else if n != "" {
+		child.name = lg.name + "." + n
+	}
+	return child
+}
+
+// Sync is a no-op, entries are recorded synchronously.
+func (lg logger) Sync() error { return nil }
+
+// Enabled reports whether lvl is at or above the logger's level.
+func (lg logger) Enabled(lvl logstox.Level) bool {
+	return lvl.Compare(lg.min) >= 0 && (lg.levelVar == nil || lvl.Compare(lg.levelVar.Level()) >= 0)
+}
+
+// WithLevel returns a child recording entries at lvl and above, though never below Options.Level or LevelVar.
+func (lg logger) WithLevel(lvl logstox.Level) logstox.Logger[fields.Field] {
+	child := lg
+	child.min = max(lvl, lg.level)
+	return child
+}
+
+// Name returns the logger's name.
+func (lg logger) Name() string { return lg.name }
+
+// Fields returns the context fields added via With and Options.Fields.
+func (lg logger) Fields() []fields.Field { return lg.context }
+
+// LevelVar returns Options.LevelVar, nil if the logger wasn't built with one.
+func (lg logger) LevelVar() *logstox.LevelVar { return lg.levelVar }
@@ -0,0 +1,17 @@
+package streamx
+
+import (
+	"testing"
+
+	"github.com/khinshankhan/logstox/backendtest"
+	"github.com/khinshankhan/logstox/fields"
+)
+
+func TestConformance(t *testing.T) {
+	backendtest.Run(t, backendtest.Subject[fields.Field]{
+		Backend: Backend{},
+		Convert: func(f fields.Field) fields.Field { return f },
+		NameKey: "name",
+		Decode:  backendtest.CanonicalJSON,
+	})
+}
@@ -0,0 +1,335 @@
+package streamx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/khinshankhan/logstox"
+	"github.com/khinshankhan/logstox/fields"
+)
+
+// Encoder encodes whole entries for a Backend.
+type Encoder interface {
+	// AppendEntry appends e to dst, including any framing needed to tell entries apart on a stream (eg a trailing
+	// newline or a length prefix).
+	AppendEntry(dst []byte, e logstox.Entry) ([]byte, error)
+}
+
+// JSON encodes entries as newline-delimited canonical JSON (see logstox.Entry.MarshalJSON), read back one line at a
+// time with json.Unmarshal into a logstox.Entry.
+type JSON struct{}
+
+// AppendEntry appends e as a line of canonical JSON.
+func (JSON) AppendEntry(dst []byte, e logstox.Entry) ([]byte, error) {
+	b, err := e.MarshalJSON()
+	if err != nil {
+		return dst, err
+	}
+	dst = append(dst, b...)
+	return append(dst, '\n'), nil
+}
+
+// Backend builds loggers writing whole entries with an Encoder, so other logstox-based tools can decode them back
+// into a logstox.Entry and route them further:
+//
+//	log := streamx.Backend{Encoder: protox.Encoder{}}.New(logstox.Options[fields.Field]{Writer: conn})
+//
+// Each entry is handed to each writer in a single Write, and writes are serialized per writer. Lazy fields are
+// evaluated when the entry is written.
+//
+// Options.Level, LevelVar, Name, Fields, Writer, Writers, SplitStdStreams, Development, Multiline's folding, Limits
+// and ContextExtractors are honored; encoders write the level as is and the time in full, and escape line breaks as
+// their format requires, and there's no caller or stacktrace, so the other options don't apply.
+type Backend struct {
+	// Encoder encodes the entries, JSON if nil.
+	Encoder Encoder
+	// Development is the same as Options.Development: it makes DPanic panic after logging and validates field keys
+	// (see logstox.ValidateKeys).
+	Development bool
+	// ErrorHandler is called with encoding and write errors, which are written to stderr if it's nil.
+	ErrorHandler func(error)
+}
+
+// Interface satisfaction (compile-time assertions).
+var (
+	_ logstox.Backend[fields.Field] = Backend{}
+	_ Encoder                       = JSON{}
+)
+
+// New constructs a Logger writing encoded entries to the Options writers, stderr if there are none.
+func (b Backend) New(o logstox.Options[fields.Field]) logstox.Logger[fields.Field] {
+	if o.SplitStdStreams {
+		o.Writers = append(o.Writers[:len(o.Writers):len(o.Writers)], logstox.StdStreams()...)
+	}
+	var sinks []*sink
+	if o.Writer != nil || len(o.Writers) == 0 {
+		w := o.Writer
+		if w == nil {
+			w = os.Stderr
+		}
+		sinks = append(sinks, &sink{w: w})
+	}
+	for _, w := range o.Writers {
+		sinks = append(sinks, &sink{w: w.Writer, enabled: w.Enabled})
+	}
+
+	enc := b.Encoder
+	if enc == nil {
+		enc = JSON{}
+	}
+	onError := b.ErrorHandler
+	if onError == nil {
+		onError = func(err error) { fmt.Fprintf(os.Stderr, "streamx: %v\n", err) }
+	}
+
+	lg := logger{
+		enc:         enc,
+		sinks:       sinks,
+		onError:     onError,
+		level:       o.Level,
+		min:         o.Level,
+		levelVar:    o.LevelVar,
+		development: b.Development || o.Development,
+		name:        o.Name,
+		context:     o.Fields,
+		extractors:  o.ContextExtractors,
+		multiline:   o.Multiline,
+		limits:      o.Limits,
+	}
+	if o.LevelVar != nil {
+		// the LevelVar is the floor instead, WithLevel can only raise it
+		lg.level, lg.min = math.MinInt8, math.MinInt8
+	}
+	return lg
+}
+
+// sink is a writer along with its level filter, serializing writes to it.
+type sink struct {
+	mu      sync.Mutex
+	w       io.Writer
+	enabled func(logstox.Level) bool // nil receives every level
+}
+
+// bufPool holds encoding buffers.
+var bufPool = sync.Pool{New: func() any { return new([]byte) }}
+
+// logger is the streamx implementation of logstox.Logger[fields.Field].
+type logger struct {
+	enc         Encoder
+	sinks       []*sink
+	onError     func(error)
+	level       logstox.Level // Options.Level, the floor for WithLevel
+	min         logstox.Level // entries below are dropped
+	development bool
+	name        string
+	context     []fields.Field
+	levelVar    *logstox.LevelVar // checked on every entry if set
+	multiline   logstox.Multiline // Options.Multiline, only folding applies
+	limits      logstox.Limits    // Options.Limits
+	// ctx is the context bound by WithContext, handed to lazy fields; extractors (Options.ContextExtractors) derive
+	// fields from it per entry.
+	ctx        context.Context
+	extractors []func(context.Context) []fields.Field
+}
+
+// Interface satisfaction (compile-time assertions).
+var (
+	_ logstox.Logger[fields.Field]        = logger{}
+	_ logstox.Noticer[fields.Field]       = logger{}
+	_ logstox.LevelCheck                  = logger{}
+	_ logstox.LevelSetter[fields.Field]   = logger{}
+	_ logstox.Introspector[fields.Field]  = logger{}
+	_ logstox.LevelVarHolder              = logger{}
+	_ logstox.Closer                      = logger{}
+	_ logstox.ContextBinder[fields.Field] = logger{}
+)
+
+// log encodes an entry and writes it to the sinks if lvl is enabled.
+func (lg logger) log(lvl logstox.Level, msg string, fs []fields.Field) {
+	if !lg.Enabled(lvl) {
+		return
+	}
+	msg, detail := lg.multiline.Apply(msg, false)
+	if detail != "" {
+		fs = append(fs[:len(fs):len(fs)], fields.String(logstox.DetailKey, detail))
+	}
+	var invalid error
+	if lg.development && lvl.Compare(logstox.DPanicLevel) < 0 {
+		if invalid = validate(fs); invalid != nil {
+			lvl = logstox.DPanicLevel
+			fs = append(fs[:len(fs):len(fs)], fields.String(logstox.FieldErrorsKey, invalid.Error()), fields.PanicStack())
+		}
+	}
+	ctx := lg.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	all := make([]fields.Field, 0, len(lg.context)+len(fs))
+	all = fields.Resolve(ctx, all, lg.context)
+	n := len(all) // the context fields, which the limits don't bound
+	if lg.ctx != nil {
+		all = fields.Resolve(ctx, all, logstox.ExtractContext(ctx, lg.extractors))
+	}
+	all = fields.Resolve(ctx, all, fs)
+	if lg.limits.Enabled() {
+		var limited []fields.Field
+		msg, limited = lg.limits.Apply(msg, all[n:])
+		all = append(all[:n], limited...)
+	}
+
+	buf := bufPool.Get().(*[]byte)
+	b, err := lg.enc.AppendEntry((*buf)[:0], logstox.Entry{
+		Time:    time.Now(),
+		Level:   lvl,
+		Name:    lg.name,
+		Message: msg,
+		Fields:  all,
+		Context: lg.ctx,
+	})
+	if err != nil {
+		lg.onError(err)
+	} else {
+		for _, s := range lg.sinks {
+			if s.enabled == nil || s.enabled(lvl) {
+				s.mu.Lock()
+				_, err := s.w.Write(b)
+				s.mu.Unlock()
+				if err != nil {
+					lg.onError(err)
+				}
+			}
+		}
+	}
+	*buf = b
+	bufPool.Put(buf)
+
+	if invalid != nil {
+		panic(msg)
+	}
+}
+
+// validate checks the keys of fs, leaving out no-op, lazy and precomputed fields.
+func validate(fs []fields.Field) error {
+	keys := make([]string, 0, len(fs))
+	for _, f := range fs {
+		switch f.Kind() {
+		case fields.FieldKindInvalid, fields.FieldKindLazyFields, fields.FieldKindLazyValue, fields.FieldKindPrecomputed:
+		default:
+			keys = append(keys, f.Key)
+		}
+	}
+	return logstox.ValidateKeys(keys)
+}
+
+// DEBUG (-1): for recording messages useful for debugging.
+func (lg logger) Debug(m string, f ...fields.Field) { lg.log(logstox.DebugLevel, m, f) }
+
+// INFO (0): for messages describing normal application operations.
+func (lg logger) Info(m string, f ...fields.Field) { lg.log(logstox.InfoLevel, m, f) }
+
+// Notice logs at logstox.NoticeLevel, see logstox.Noticer.
+func (lg logger) Notice(m string, f ...fields.Field) { lg.log(logstox.NoticeLevel, m, f) }
+
+// WARN (1): for recording messages indicating something unusual happened that may need attention before it escalates to a more severe issue.
+func (lg logger) Warn(m string, f ...fields.Field) { lg.log(logstox.WarnLevel, m, f) }
+
+// ERROR (2): for recording unexpected error conditions in the program.
+func (lg logger) Error(m string, f ...fields.Field) { lg.log(logstox.ErrorLevel, m, f) }
+
+// DPANIC (3): for recording severe error conditions in development. It behaves like PANIC in development and ERROR in production.
+// The entry carries a fields.PanicStack dict.
+func (lg logger) DPanic(m string, f ...fields.Field) {
+	lg.log(logstox.DPanicLevel, m, append(f[:len(f):len(f)], fields.PanicStack()))
+	if lg.development {
+		panic(m)
+	}
+}
+
+// PANIC (4): calls panic() after logging an error condition.
+// The entry carries a fields.PanicStack dict.
+func (lg logger) Panic(m string, f ...fields.Field) {
+	lg.log(logstox.PanicLevel, m, append(f[:len(f):len(f)], fields.PanicStack()))
+	panic(m)
+}
+
+// FATAL (5): calls os.Exit(1) after logging an error condition. The writers are synced first.
+func (lg logger) Fatal(m string, f ...fields.Field) {
+	lg.log(logstox.FatalLevel, m, f)
+	_ = lg.Sync()
+	os.Exit(1)
+}
+
+// With creates a child logger and adds structured context to it. Lazy fields are evaluated per entry.
+func (lg logger) With(f ...fields.Field) logstox.Logger[fields.Field] {
+	child := lg
+	child.context = append(lg.context[:len(lg.context):len(lg.context)], f...)
+	return child
+}
+
+// WithContext returns a child bound to ctx: Options.ContextExtractors derive fields from it for every entry, lazy
+// fields are handed it, and encoders see it as Entry.Context.
+func (lg logger) WithContext(ctx context.Context) logstox.Logger[fields.Field] {
+	child := lg
+	child.ctx = ctx
+	return child
+}
+
+// Named adds a new path segment to the logger's name. Segments are joined by
+// periods. By default, Loggers are unnamed.
+func (lg logger) Named(n string) logstox.Logger[fields.Field] {
+	child := lg
+	if lg.name == "" {
+		child.name = n
+	} else if n != "" {
+		child.name = lg.name + "." + n
+	}
+	return child
+}
+
+// Sync syncs the writers that support it (eg *os.File). Entries aren't buffered.
+func (lg logger) Sync() error {
+	for _, s := range lg.sinks {
+		if sy, ok := s.w.(interface{ Sync() error }); ok && s.w != os.Stdout && s.w != os.Stderr {
+			if err := sy.Sync(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Close syncs the logger, then closes the Options writers that are io.Closers.
+func (lg logger) Close() error {
+	writers := make([]io.Writer, len(lg.sinks))
+	for i, s := range lg.sinks {
+		writers[i] = s.w
+	}
+	return errors.Join(lg.Sync(), logstox.CloseWriters(writers...))
+}
+
+// Enabled reports whether lvl is at or above the logger's level.
+func (lg logger) Enabled(lvl logstox.Level) bool {
+	return lvl.Compare(lg.min) >= 0 && (lg.levelVar == nil || lvl.Compare(lg.levelVar.Level()) >= 0)
+}
+
+// WithLevel returns a child writing entries at lvl and above, though never below Options.Level or LevelVar.
+func (lg logger) WithLevel(lvl logstox.Level) logstox.Logger[fields.Field] {
+	child := lg
+	child.min = max(lvl, lg.level)
+	return child
+}
+
+// Name returns the logger's name.
+func (lg logger) Name() string { return lg.name }
+
+// Fields returns the context fields added via With and Options.Fields.
+func (lg logger) Fields() []fields.Field { return lg.context }
+
+// LevelVar returns Options.LevelVar, nil if the logger wasn't built with one.
+func (lg logger) LevelVar() *logstox.LevelVar { return lg.levelVar }
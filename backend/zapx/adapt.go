@@ -0,0 +1,213 @@
+package zapx
+
+import (
+	"context"
+	"sync"
+
+	"github.com/khinshankhan/logstox"
+	"github.com/khinshankhan/logstox/fields"
+)
+
+// maxPooledFields caps the capacity of buffers returned to the pool, so one huge entry doesn't pin a huge buffer.
+const maxPooledFields = 64
+
+// fieldPool holds []ZapField buffers reused across log calls.
+var fieldPool = sync.Pool{
+	New: func() any {
+		buf := make([]ZapField, 0, 16)
+		return &buf
+	},
+}
+
+// toZapFields converts fs into a pooled buffer, which must be handed back via releaseZapFields. LazyFields functions
+// get ctx, or context.Background() if it's nil.
+func toZapFields(ctx context.Context, fs []fields.Field) *[]ZapField {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	buf := fieldPool.Get().(*[]ZapField)
+	for _, f := range fs {
+		*buf = append(*buf, ToZapContext(ctx, f))
+	}
+	return buf
+}
+
+// releaseZapFields returns buf to the pool.
+func releaseZapFields(buf *[]ZapField) {
+	if cap(*buf) > maxPooledFields {
+		return
+	}
+	clear(*buf) // drop references so pooled buffers don't keep values alive
+	*buf = (*buf)[:0]
+	fieldPool.Put(buf)
+}
+
+// Adapt bridges a zap-backed logger so callers can log with logstox fields. It's adapter.Adapter specialized for
+// zap: level methods convert fields into pooled buffers instead of allocating a fresh slice per call.
+//
+// This is safe because zap's cores encode fields during Write and don't keep them. If you inject a core that
+// retains fields past Write (eg zaptest/observer), use adapter.Adapter with ToZap instead.
+func Adapt(l logstox.Logger[ZapField]) logstox.Logger[fields.Field] {
+	// skip adapted's own level method so file:line points at the caller
+	return adapted{l: logstox.WithOptions(l, logstox.AddCallerSkip(1))}
+}
+
+// adapted is the Logger returned by Adapt.
+type adapted struct {
+	l       logstox.Logger[ZapField]
+	context []fields.Field  // kept for Fields, the underlying logger only has the converted ones
+	ctx     context.Context // bound by WithContext, handed to LazyFields functions
+}
+
+// Interface satisfaction (compile-time assertions).
+var (
+	_ logstox.Logger[fields.Field]         = adapted{}
+	_ logstox.LevelCheck                   = adapted{}
+	_ logstox.OptionsApplier[fields.Field] = adapted{}
+	_ logstox.LevelSetter[fields.Field]    = adapted{}
+	_ logstox.Introspector[fields.Field]   = adapted{}
+	_ logstox.LazyWither[fields.Field]     = adapted{}
+	_ logstox.Noticer[fields.Field]        = adapted{}
+	_ logstox.LevelVarHolder               = adapted{}
+	_ logstox.Closer                       = adapted{}
+	_ logstox.ContextBinder[fields.Field]  = adapted{}
+)
+
+// DEBUG (-1): for recording messages useful for debugging.
+func (a adapted) Debug(m string, f ...fields.Field) {
+	buf := toZapFields(a.ctx, f)
+	a.l.Debug(m, *buf...)
+	releaseZapFields(buf)
+}
+
+// INFO (0): for messages describing normal application operations.
+func (a adapted) Info(m string, f ...fields.Field) {
+	buf := toZapFields(a.ctx, f)
+	a.l.Info(m, *buf...)
+	releaseZapFields(buf)
+}
+
+// Notice logs a notice entry, or an Info one if the underlying logger isn't a Noticer.
+func (a adapted) Notice(m string, f ...fields.Field) {
+	buf := toZapFields(a.ctx, f)
+	if n, ok := a.l.(logstox.Noticer[ZapField]); ok {
+		n.Notice(m, *buf...)
+	} else {
+		a.l.Info(m, *buf...)
+	}
+	releaseZapFields(buf)
+}
+
+// WARN (1): for recording messages indicating something unusual happened that may need attention before it escalates to a more severe issue.
+func (a adapted) Warn(m string, f ...fields.Field) {
+	buf := toZapFields(a.ctx, f)
+	a.l.Warn(m, *buf...)
+	releaseZapFields(buf)
+}
+
+// ERROR (2): for recording unexpected error conditions in the program.
+func (a adapted) Error(m string, f ...fields.Field) {
+	buf := toZapFields(a.ctx, f)
+	a.l.Error(m, *buf...)
+	releaseZapFields(buf)
+}
+
+// DPANIC (3): for recording severe error conditions in development. It behaves like PANIC in development and ERROR in production.
+func (a adapted) DPanic(m string, f ...fields.Field) {
+	buf := toZapFields(a.ctx, f)
+	a.l.DPanic(m, *buf...)
+	releaseZapFields(buf)
+}
+
+// PANIC (4): calls panic() after logging an error condition.
+// The buffer isn't returned to the pool since the call doesn't return.
+func (a adapted) Panic(m string, f ...fields.Field) {
+	buf := toZapFields(a.ctx, f)
+	a.l.Panic(m, *buf...)
+}
+
+// FATAL (5): calls os.Exit(1) after logging an error condition.
+func (a adapted) Fatal(m string, f ...fields.Field) {
+	buf := toZapFields(a.ctx, f)
+	a.l.Fatal(m, *buf...)
+}
+
+// With returns a child with f converted and added as context.
+// With fields aren't pooled: zap holds onto them (WithLazy keeps them until first use).
+func (a adapted) With(f ...fields.Field) logstox.Logger[fields.Field] {
+	child := a
+	child.l = a.l.With(a.convert(f)...)
+	child.context = append(a.context[:len(a.context):len(a.context)], f...)
+	return child
+}
+
+// WithLazy returns a child with f converted and deferred on the underlying logger.
+func (a adapted) WithLazy(f ...fields.Field) logstox.Logger[fields.Field] {
+	child := a
+	child.l = logstox.WithLazy(a.l, a.convert(f)...)
+	child.context = append(a.context[:len(a.context):len(a.context)], f...)
+	return child
+}
+
+// convert converts f into a fresh slice, handing the bound context to LazyFields functions.
+func (a adapted) convert(f []fields.Field) []ZapField {
+	ctx := a.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	zf := make([]ZapField, len(f))
+	for i, v := range f {
+		zf[i] = ToZapContext(ctx, v)
+	}
+	return zf
+}
+
+// WithContext binds ctx to the underlying logger, and hands it to LazyFields functions.
+func (a adapted) WithContext(ctx context.Context) logstox.Logger[fields.Field] {
+	return adapted{l: logstox.WithContext(a.l, ctx), context: a.context, ctx: ctx}
+}
+
+// Named adds a new path segment to the logger's name.
+func (a adapted) Named(n string) logstox.Logger[fields.Field] {
+	return adapted{l: a.l.Named(n), context: a.context, ctx: a.ctx}
+}
+
+// Sync delegates to the underlying logger's Sync.
+func (a adapted) Sync() error {
+	return a.l.Sync()
+}
+
+// Enabled reports whether the underlying logger records lvl.
+func (a adapted) Enabled(lvl logstox.Level) bool {
+	return logstox.Enabled(a.l, lvl)
+}
+
+// WithOptions applies opts to the underlying logger.
+func (a adapted) WithOptions(opts ...logstox.Option) logstox.Logger[fields.Field] {
+	return adapted{l: logstox.WithOptions(a.l, opts...), context: a.context, ctx: a.ctx}
+}
+
+// WithLevel sets the underlying logger's minimum level.
+func (a adapted) WithLevel(lvl logstox.Level) logstox.Logger[fields.Field] {
+	return adapted{l: logstox.WithLevel(a.l, lvl), context: a.context, ctx: a.ctx}
+}
+
+// Name returns the underlying logger's name.
+func (a adapted) Name() string {
+	return logstox.NameOf(a.l)
+}
+
+// LevelVar returns the underlying logger's LevelVar.
+func (a adapted) LevelVar() *logstox.LevelVar {
+	return logstox.LevelVarOf(a.l)
+}
+
+// Close closes the underlying logger.
+func (a adapted) Close() error {
+	return logstox.Close(a.l)
+}
+
+// Fields returns the logstox fields added via With.
+func (a adapted) Fields() []fields.Field {
+	return a.context
+}
@@ -0,0 +1,54 @@
+package zapx
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/khinshankhan/logstox"
+	"github.com/khinshankhan/logstox/adapter"
+	"github.com/khinshankhan/logstox/fields"
+)
+
+// BenchmarkAdapt logs five fields per op through Adapt, converting them into pooled buffers, and through
+// adapter.Adapter, converting them into a fresh slice per call.
+func BenchmarkAdapt(b *testing.B) {
+	base := Backend{}.New(logstox.Options[ZapField]{Writer: io.Discard})
+	for _, bb := range []struct {
+		name string
+		l    logstox.Logger[fields.Field]
+	}{
+		{"pooled", Adapt(base)},
+		{"fresh", adapter.Adapter[ZapField, fields.Field]{Base: base, ToBase: ToZap}},
+	} {
+		b.Run(bb.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; b.Loop(); i++ {
+				bb.l.Info("request",
+					fields.String("method", "GET"),
+					fields.Int64("status", 200),
+					fields.Duration("elapsed", time.Duration(i)),
+					fields.Bool("cached", i%2 == 0),
+					fields.Float64("ratio", 0.5),
+				)
+			}
+		})
+	}
+}
+
+func TestAdaptReleasesBuffers(t *testing.T) {
+	buf := toZapFields(nil, []fields.Field{fields.String("k", "v")})
+	if len(*buf) != 1 {
+		t.Fatalf("converted %d fields, want 1", len(*buf))
+	}
+	releaseZapFields(buf)
+	if len(*buf) != 0 || (*buf)[:1][0].Key != "" {
+		t.Errorf("released buffer %v still holds its fields", (*buf)[:1])
+	}
+
+	big := toZapFields(nil, make([]fields.Field, maxPooledFields+1))
+	releaseZapFields(big)
+	if len(*big) != maxPooledFields+1 {
+		t.Errorf("a buffer of %d fields was pooled, want it left to the GC", maxPooledFields+1)
+	}
+}
@@ -1,6 +1,7 @@
 package zapx
 
 import (
+	"context"
 	"encoding/hex"
 	"encoding/json"
 	"time"
@@ -11,111 +12,254 @@ import (
 	"go.uber.org/zap/zapcore"
 )
 
+// ToZap converts a logstox field into a zap field.
+// LazyFields functions are handed context.Background(); use ToZapContext to supply a request context.
 func ToZap(f fields.Field) ZapField {
+	return ToZapContext(context.Background(), f)
+}
+
+// Mapper returns a converter (eg for adapter.Adapter.ToBase) handing ctx to LazyFields functions.
+func Mapper(ctx context.Context) func(fields.Field) ZapField {
+	return func(f fields.Field) ZapField {
+		return ToZapContext(ctx, f)
+	}
+}
+
+// ToZapContext converts a logstox field into a zap field, handing ctx to LazyFields functions (including those
+// nested in Dicts). Lazy fields are only evaluated once zap encodes the entry.
+func ToZapContext(ctx context.Context, f fields.Field) ZapField {
 	switch f.Kind() {
 	case fields.FieldKindString:
-		return zap.String(f.Key, f.Value.(string))
+		return zap.String(f.Key, f.Str())
 	case fields.FieldKindBool:
-		return zap.Bool(f.Key, f.Value.(bool))
+		return zap.Bool(f.Key, f.Bool())
 	case fields.FieldKindInt64:
-		return zap.Int64(f.Key, f.Value.(int64))
+		return zap.Int64(f.Key, f.Int64())
 	case fields.FieldKindUint64:
-		return zap.Uint64(f.Key, f.Value.(uint64))
+		return zap.Uint64(f.Key, f.Uint64())
 	case fields.FieldKindFloat64:
-		return zap.Float64(f.Key, f.Value.(float64))
+		return zap.Float64(f.Key, f.Float64())
 	case fields.FieldKindDuration:
-		return zap.Duration(f.Key, f.Value.(time.Duration))
+		return zap.Duration(f.Key, f.Duration())
 	case fields.FieldKindTime:
-		return zap.Time(f.Key, f.Value.(time.Time))
+		return zap.Time(f.Key, f.Time())
 	case fields.FieldKindError:
 		if f.Key == "" || f.Key == fields.ErrorKey {
-			return zap.Error(f.Value.(error))
+			return zap.Error(f.Err())
 		}
-		return zap.NamedError(f.Key, f.Value.(error))
+		return zap.NamedError(f.Key, f.Err())
 	case fields.FieldKindStrings:
-		return zap.Strings(f.Key, f.Value.([]string))
+		return zap.Strings(f.Key, f.Interface().([]string))
 	case fields.FieldKindBools:
-		return zap.Bools(f.Key, f.Value.([]bool))
+		return zap.Bools(f.Key, f.Interface().([]bool))
 	case fields.FieldKindInt64s:
-		return zap.Int64s(f.Key, f.Value.([]int64))
+		return zap.Int64s(f.Key, f.Interface().([]int64))
 	case fields.FieldKindUint64s:
-		return zap.Uint64s(f.Key, f.Value.([]uint64))
+		return zap.Uint64s(f.Key, f.Interface().([]uint64))
 	case fields.FieldKindFloat64s:
-		return zap.Float64s(f.Key, f.Value.([]float64))
+		return zap.Float64s(f.Key, f.Interface().([]float64))
 	case fields.FieldKindErrors:
-		return zap.Errors(f.Key, f.Value.([]error))
+		return zap.Errors(f.Key, f.Interface().([]error))
 	case fields.FieldKindRawJSON:
-		return zap.Any(f.Key, json.RawMessage(f.Value.([]byte)))
+		return zap.Reflect(f.Key, json.RawMessage(f.Bytes())) // zap.Any would write it through fmt.Stringer, as a string
 	case fields.FieldKindHexBytes:
-		return zap.String(f.Key, hex.EncodeToString(f.Value.([]byte)))
+		return zap.String(f.Key, hex.EncodeToString(f.Bytes()))
 	case fields.FieldKindDict:
-		return zap.Object(f.Key, dict{f.Value.([]fields.Field)})
+		return zap.Object(f.Key, dict{ctx, f.Fields()})
 	case fields.FieldKindTimestamp:
-		t := f.Value.(time.Time)
+		t := f.Time()
 		if t.IsZero() {
 			t = time.Now()
 		}
 		return zap.Time(f.Key, t)
 	case fields.FieldKindAny:
-		return zap.Any(f.Key, f.Value)
+		switch v := f.Interface().(type) {
+		case native:
+			return v.f
+		case zapcore.ObjectMarshaler:
+			return zap.Object(f.Key, v)
+		case zapcore.ArrayMarshaler:
+			return zap.Array(f.Key, v)
+		}
+		return zap.Any(f.Key, f.Interface())
+	case fields.FieldKindLazyValue:
+		return zap.Inline(lazyValue{ctx, f.Interface().(func() []fields.Field)})
+	case fields.FieldKindLazyFields:
+		return zap.Inline(lazyFields{ctx, f.Interface().(func(context.Context) []fields.Field)})
+	case fields.FieldKindPrecomputed:
+		return zap.Inline(precomputed(ctx, f.Precomputed()))
 	default:
 		// TODO: look into exhaustive checks
 		return zap.Skip()
 	}
 }
 
-type dict struct{ fs []fields.Field }
+// Native is an escape hatch carrying a zap field through the logstox field layer untouched, for zap features the
+// facade doesn't model (eg zap.Stack, zap.Inline). It's emitted as is at the top level and encoded with zap's own
+// encoding inside Dicts. Other backends will see a fields.Any holding an opaque value.
+func Native(f ZapField) fields.Field {
+	return fields.Any(f.Key, native{f})
+}
+
+// native marks a zap field wrapped by Native.
+type native struct{ f ZapField }
+
+type dict struct {
+	ctx context.Context
+	fs  []fields.Field
+}
 
 func (d dict) MarshalLogObject(enc zapcore.ObjectEncoder) error {
 	for _, f := range d.fs {
 		switch f.Kind() {
 		case fields.FieldKindString:
-			enc.AddString(f.Key, f.Value.(string))
+			enc.AddString(f.Key, f.Str())
 		case fields.FieldKindBool:
-			enc.AddBool(f.Key, f.Value.(bool))
+			enc.AddBool(f.Key, f.Bool())
 		case fields.FieldKindInt64:
-			enc.AddInt64(f.Key, f.Value.(int64))
+			enc.AddInt64(f.Key, f.Int64())
 		case fields.FieldKindUint64:
-			enc.AddUint64(f.Key, f.Value.(uint64))
+			enc.AddUint64(f.Key, f.Uint64())
 		case fields.FieldKindFloat64:
-			enc.AddFloat64(f.Key, f.Value.(float64))
+			enc.AddFloat64(f.Key, f.Float64())
 		case fields.FieldKindDuration:
-			enc.AddDuration(f.Key, f.Value.(time.Duration))
+			enc.AddDuration(f.Key, f.Duration())
 		case fields.FieldKindTime:
-			enc.AddTime(f.Key, f.Value.(time.Time))
+			enc.AddTime(f.Key, f.Time())
 		case fields.FieldKindError:
-			enc.AddString(f.Key, f.Value.(error).Error())
+			enc.AddString(f.Key, f.Err().Error())
 		case fields.FieldKindStrings:
-			enc.AddArray(f.Key, stringArray(f.Value.([]string)))
+			enc.AddArray(f.Key, stringArray(f.Interface().([]string)))
 		case fields.FieldKindBools:
-			enc.AddArray(f.Key, boolArray(f.Value.([]bool)))
+			enc.AddArray(f.Key, boolArray(f.Interface().([]bool)))
 		case fields.FieldKindInt64s:
-			enc.AddArray(f.Key, int64Array(f.Value.([]int64)))
+			enc.AddArray(f.Key, int64Array(f.Interface().([]int64)))
 		case fields.FieldKindUint64s:
-			enc.AddArray(f.Key, uint64Array(f.Value.([]uint64)))
+			enc.AddArray(f.Key, uint64Array(f.Interface().([]uint64)))
 		case fields.FieldKindFloat64s:
-			enc.AddArray(f.Key, float64Array(f.Value.([]float64)))
+			enc.AddArray(f.Key, float64Array(f.Interface().([]float64)))
 		case fields.FieldKindErrors:
-			enc.AddArray(f.Key, errorArray(f.Value.([]error)))
+			enc.AddArray(f.Key, errorArray(f.Interface().([]error)))
 		case fields.FieldKindDict:
-			enc.AddObject(f.Key, dict{f.Value.([]fields.Field)})
+			enc.AddObject(f.Key, dict{d.ctx, f.Fields()})
 		case fields.FieldKindRawJSON:
-			enc.AddReflected(f.Key, json.RawMessage(f.Value.([]byte)))
+			enc.AddReflected(f.Key, json.RawMessage(f.Bytes()))
 		case fields.FieldKindHexBytes:
-			enc.AddString(f.Key, hex.EncodeToString(f.Value.([]byte)))
+			enc.AddString(f.Key, hex.EncodeToString(f.Bytes()))
 		case fields.FieldKindTimestamp:
-			t := f.Value.(time.Time)
+			t := f.Time()
 			if t.IsZero() {
 				t = time.Now()
 			}
 			enc.AddTime(f.Key, t)
 		case fields.FieldKindAny:
-			enc.AddReflected(f.Key, f.Value)
+			// prefer zap-native encoders over reflection
+			switch v := f.Interface().(type) {
+			case native:
+				v.f.AddTo(enc)
+			case zapcore.ObjectMarshaler:
+				if err := enc.AddObject(f.Key, v); err != nil {
+					return err
+				}
+			case zapcore.ArrayMarshaler:
+				if err := enc.AddArray(f.Key, v); err != nil {
+					return err
+				}
+			default:
+				enc.AddReflected(f.Key, f.Interface())
+			}
+		case fields.FieldKindLazyValue:
+			if err := (lazyValue{d.ctx, f.Interface().(func() []fields.Field)}).MarshalLogObject(enc); err != nil {
+				return err
+			}
+		case fields.FieldKindLazyFields:
+			if err := (lazyFields{d.ctx, f.Interface().(func(context.Context) []fields.Field)}).MarshalLogObject(enc); err != nil {
+				return err
+			}
+		case fields.FieldKindPrecomputed:
+			if err := precomputed(d.ctx, f.Precomputed()).MarshalLogObject(enc); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// bundle is a precomputed field's conversion, inlined into the enclosing object. The conversion is shared by every
+// entry, so the lazy fields and Dicts among it are handed the entry's context as they're encoded.
+type bundle struct {
+	ctx context.Context
+	fs  []ZapField
+}
+
+func (b bundle) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for _, f := range b.fs {
+		switch v := f.Interface.(type) {
+		case lazyValue:
+			v.ctx = b.ctx
+			f.Interface = v
+		case lazyFields:
+			v.ctx = b.ctx
+			f.Interface = v
+		case dict:
+			v.ctx = b.ctx
+			f.Interface = v
 		}
+		f.AddTo(enc)
 	}
 	return nil
 }
 
+// bundleKey keys zap's conversion in fields.Precomputed.Native.
+type bundleKey struct{}
+
+// precomputed returns p's fields, converted once for zap, bound to ctx.
+func precomputed(ctx context.Context, p *fields.Precomputed) bundle {
+	fs := p.Native(bundleKey{}, func(fs []fields.Field) any {
+		b := make([]ZapField, len(fs))
+		for i, f := range fs {
+			b[i] = ToZap(f)
+		}
+		return b
+	}).([]ZapField)
+	return bundle{ctx, fs}
+}
+
+// lazyValue defers building fields until zap encodes them, which only happens once an entry's level is known to be
+// enabled. The resulting fields are inlined into the enclosing object.
+type lazyValue struct {
+	ctx context.Context // for any LazyFields the function returns
+	fn  func() []fields.Field
+}
+
+func (l lazyValue) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	return dict{l.ctx, l.fn()}.MarshalLogObject(enc)
+}
+
+// lazyFields is lazyValue for context-aware functions.
+type lazyFields struct {
+	ctx context.Context
+	fn  func(context.Context) []fields.Field
+}
+
+func (l lazyFields) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	return dict{l.ctx, l.fn(l.ctx)}.MarshalLogObject(enc)
+}
+
+// hasLazy reports whether any of fs was produced from a lazy field by ToZap.
+func hasLazy(fs []ZapField) bool {
+	for _, f := range fs {
+		if f.Type != zapcore.InlineMarshalerType {
+			continue
+		}
+		switch f.Interface.(type) {
+		case lazyValue, lazyFields:
+			return true
+		}
+	}
+	return false
+}
+
 type (
 	stringArray  []string
 	boolArray    []bool
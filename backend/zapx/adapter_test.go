@@ -0,0 +1,26 @@
+package zapx
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+
+	"github.com/khinshankhan/logstox/fields"
+)
+
+type ctxKey struct{}
+
+func TestPrecomputedLazyFieldsGetTheEntryContext(t *testing.T) {
+	meta := fields.Precompute(fields.String("service", "api"), fields.LazyFields(func(ctx context.Context) []fields.Field {
+		id, _ := ctx.Value(ctxKey{}).(string)
+		return []fields.Field{fields.String("request_id", id)}
+	}))
+	for _, id := range []string{"r1", "r2"} { // the second entry reuses the cached conversion
+		enc := zapcore.NewMapObjectEncoder()
+		ToZapContext(context.WithValue(context.Background(), ctxKey{}, id), meta).AddTo(enc)
+		if enc.Fields["service"] != "api" || enc.Fields["request_id"] != id {
+			t.Errorf("got %v, want service api and request_id %s", enc.Fields, id)
+		}
+	}
+}
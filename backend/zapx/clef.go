@@ -0,0 +1,63 @@
+package zapx
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/khinshankhan/logstox"
+	"github.com/khinshankhan/logstox/fields"
+)
+
+func init() {
+	// lets cfg.Build (used without Options writers) find the encoder by name; it can't fail, the name is ours
+	_ = zap.RegisterEncoder(EncodingCLEF, func(cfg zapcore.EncoderConfig) (zapcore.Encoder, error) {
+		return noticeEncoder{newCLEFEncoder(cfg)}, nil
+	})
+}
+
+// clefEncoderConfig returns enc with CLEF's keys and level names, keys explicitly set on b left alone.
+func (b Backend) clefEncoderConfig(enc zapcore.EncoderConfig) zapcore.EncoderConfig {
+	enc.TimeKey = encoderKey(b.TimeKey, logstox.CLEFTimeKey)
+	enc.MessageKey = encoderKey(b.MessageKey, logstox.CLEFTemplateKey)
+	enc.LevelKey = encoderKey(b.LevelKey, logstox.CLEFLevelKey)
+	enc.NameKey = encoderKey(b.NameKey, logstox.CLEFNameKey)
+	enc.EncodeLevel = func(l zapcore.Level, pae zapcore.PrimitiveArrayEncoder) {
+		pae.AppendString(logstox.CLEFLevel(FromZapLevel(l)))
+	}
+	return enc
+}
+
+// clefEncoder is a JSON encoder writing CLEF: messages are escaped as templates and errors logged under
+// fields.ErrorKey go to the exception key.
+type clefEncoder struct {
+	zapcore.Encoder
+}
+
+func newCLEFEncoder(cfg zapcore.EncoderConfig) zapcore.Encoder {
+	return clefEncoder{zapcore.NewJSONEncoder(cfg)}
+}
+
+func (e clefEncoder) Clone() zapcore.Encoder {
+	return clefEncoder{e.Encoder.Clone()}
+}
+
+// AddString renames errors added as context (zap encodes them as strings through the encoder).
+func (e clefEncoder) AddString(k, v string) {
+	if k == fields.ErrorKey {
+		k = logstox.CLEFExceptionKey
+	}
+	e.Encoder.AddString(k, v)
+}
+
+func (e clefEncoder) EncodeEntry(ent zapcore.Entry, fs []zapcore.Field) (*buffer.Buffer, error) {
+	ent.Message = logstox.CLEFTemplate(ent.Message)
+	for i, f := range fs {
+		if f.Type == zapcore.ErrorType && f.Key == fields.ErrorKey {
+			fs = append([]zapcore.Field(nil), fs...) // fs belongs to the caller
+			fs[i].Key = logstox.CLEFExceptionKey
+			break
+		}
+	}
+	return e.Encoder.EncodeEntry(ent, fs)
+}
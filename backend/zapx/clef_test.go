@@ -0,0 +1,43 @@
+package zapx
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/khinshankhan/logstox"
+	"github.com/khinshankhan/logstox/fields"
+)
+
+func TestCLEF(t *testing.T) {
+	var buf bytes.Buffer
+	log := Backend{Encoding: EncodingCLEF}.New(logstox.Options[ZapField]{Writer: &buf, Name: "api"})
+	log.Warn("user {id} failed",
+		ToZap(fields.Error(errors.New("boom"))),
+		ToZap(fields.NamedError("cause", errors.New("io"))),
+	)
+
+	var e map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &e); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{
+		logstox.CLEFTemplateKey:  "user {{id}} failed",
+		logstox.CLEFLevelKey:     "Warning",
+		logstox.CLEFNameKey:      "api",
+		logstox.CLEFExceptionKey: "boom",
+		"cause":                  "io",
+	}
+	for k, v := range want {
+		if e[k] != v {
+			t.Errorf("%s = %v, want %v", k, e[k], v)
+		}
+	}
+	if _, ok := e[fields.ErrorKey]; ok {
+		t.Errorf("%s written as is, want it renamed to %s", fields.ErrorKey, logstox.CLEFExceptionKey)
+	}
+	if _, ok := e[logstox.CLEFTimeKey]; !ok {
+		t.Errorf("%s missing", logstox.CLEFTimeKey)
+	}
+}
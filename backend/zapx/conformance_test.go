@@ -0,0 +1,11 @@
+package zapx
+
+import (
+	"testing"
+
+	"github.com/khinshankhan/logstox/backendtest"
+)
+
+func TestConformance(t *testing.T) {
+	backendtest.Run(t, backendtest.Subject[ZapField]{Backend: Backend{}, Convert: ToZap})
+}
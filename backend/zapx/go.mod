@@ -3,7 +3,10 @@ module github.com/khinshankhan/logstox/backend/zapx
 go 1.24.2
 
 require (
-	github.com/khinshankhan/logstox v0.0.0-20250914151607-81d0c77772ce // indirect
-	go.uber.org/multierr v1.11.0 // indirect
-	go.uber.org/zap v1.27.0 // indirect
+	github.com/khinshankhan/logstox v0.0.0-20250914151607-81d0c77772ce
+	go.uber.org/zap v1.27.0
 )
+
+require go.uber.org/multierr v1.11.0 // indirect
+
+replace github.com/khinshankhan/logstox => ../..
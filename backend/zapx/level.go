@@ -1,17 +1,89 @@
 package zapx
 
 import (
+	"errors"
+	"strings"
+
 	"github.com/khinshankhan/logstox"
 
+	"go.uber.org/zap"
+	"go.uber.org/zap/buffer"
 	"go.uber.org/zap/zapcore"
 )
 
-func toZapLevel(l logstox.Level) (zapcore.Level, bool) {
+// noticeField tags notices. zap has no room for a level between Info and Warn, so notices are written at InfoLevel
+// with a true logstox.NoticeKey field: cores, level enablers, hooks and the sampler treat them as Info entries, and
+// the encoders Backend builds drop the tag and name their level "notice" instead. Other encoders (Backend.Core,
+// FromZap) write the tag as is. As zap only checks the level of entries, not their fields, a minimum of
+// logstox.NoticeLevel is InfoLevel to zap.
+var noticeField = zapcore.Field{Key: logstox.NoticeKey, Type: zapcore.BoolType, Integer: 1, Interface: noticeTag{}}
+
+// noticeTag is the Interface of noticeField, so encoders can tell it from fields of the same key.
+type noticeTag struct{}
+
+// noticeLevel is the level noticeEncoder hands levelEncoder for notices. It's outside zap's range, and never reaches
+// cores.
+const noticeLevel zapcore.Level = zapcore.DebugLevel - 1
+
+// noticeEncoder writes entries tagged with noticeField at noticeLevel, without the tag.
+type noticeEncoder struct {
+	zapcore.Encoder
+}
+
+func (e noticeEncoder) Clone() zapcore.Encoder {
+	return noticeEncoder{e.Encoder.Clone()}
+}
+
+func (e noticeEncoder) EncodeEntry(ent zapcore.Entry, fs []zapcore.Field) (*buffer.Buffer, error) {
+	if n := len(fs); n > 0 && fs[n-1].Interface == (noticeTag{}) {
+		ent.Level = noticeLevel
+		fs = fs[:n-1]
+	}
+	return e.Encoder.EncodeEntry(ent, fs)
+}
+
+// noticeEncodings maps the encodings zap's default sinks know to their counterpart wrapped in a noticeEncoder.
+var noticeEncodings = map[string]string{EncodingJSON: "zapx-json", EncodingConsole: "zapx-console"}
+
+func init() {
+	// lets cfg.Build (used without Options writers) find the wrapped encoders by name; it can't fail, the names are ours
+	_ = zap.RegisterEncoder(noticeEncodings[EncodingJSON], func(cfg zapcore.EncoderConfig) (zapcore.Encoder, error) {
+		return noticeEncoder{zapcore.NewJSONEncoder(cfg)}, nil
+	})
+	_ = zap.RegisterEncoder(noticeEncodings[EncodingConsole], func(cfg zapcore.EncoderConfig) (zapcore.Encoder, error) {
+		return noticeEncoder{zapcore.NewConsoleEncoder(cfg)}, nil
+	})
+}
+
+// levelEncoder wraps enc so notices are named, upper-cased like zap's capital encoders if upper is set, and levels
+// are written as numbers if f is a numeric format.
+func levelEncoder(enc zapcore.LevelEncoder, upper bool, f logstox.LevelFormat) zapcore.LevelEncoder {
+	name := logstox.NoticeName
+	if upper {
+		name = strings.ToUpper(name)
+	}
+	return func(l zapcore.Level, pae zapcore.PrimitiveArrayEncoder) {
+		if n, ok := f.Number(FromZapLevel(l)); ok {
+			pae.AppendInt(n)
+			return
+		}
+		if l == noticeLevel {
+			pae.AppendString(name)
+			return
+		}
+		enc(l, pae)
+	}
+}
+
+// ToZapLevel maps l onto zap's levels, reporting false (and zapcore.InfoLevel) for levels logstox doesn't define.
+func ToZapLevel(l logstox.Level) (zapcore.Level, bool) {
 	switch l {
 	case logstox.DebugLevel:
 		return zapcore.DebugLevel, true
 	case logstox.InfoLevel:
 		return zapcore.InfoLevel, true
+	case logstox.NoticeLevel:
+		return zapcore.InfoLevel, true // tagged, see noticeField
 	case logstox.WarnLevel:
 		return zapcore.WarnLevel, true
 	case logstox.ErrorLevel:
@@ -26,3 +98,166 @@ func toZapLevel(l logstox.Level) (zapcore.Level, bool) {
 		return zapcore.InfoLevel, false
 	}
 }
+
+// FromZapLevel maps l onto logstox's levels. Levels zap doesn't define keep their value.
+func FromZapLevel(l zapcore.Level) logstox.Level {
+	switch l {
+	case zapcore.DebugLevel:
+		return logstox.DebugLevel
+	case zapcore.InfoLevel:
+		return logstox.InfoLevel
+	case zapcore.WarnLevel:
+		return logstox.WarnLevel
+	case zapcore.ErrorLevel:
+		return logstox.ErrorLevel
+	case zapcore.DPanicLevel:
+		return logstox.DPanicLevel
+	case zapcore.PanicLevel:
+		return logstox.PanicLevel
+	case zapcore.FatalLevel:
+		return logstox.FatalLevel
+	case noticeLevel:
+		return logstox.NoticeLevel
+	default:
+		return logstox.Level(l)
+	}
+}
+
+// zapLevel is the zap level a core's minimum of l is reported as by zapcore.LevelOf.
+func zapLevel(l logstox.Level) zapcore.Level {
+	if zl, ok := ToZapLevel(l); ok {
+		return zl
+	}
+	return zapcore.Level(l) // out of range levels share zap's numbering
+}
+
+// varLevelCore drops entries below a logstox.LevelVar's current level before they reach the wrapped core.
+type varLevelCore struct {
+	zapcore.Core
+	v *logstox.LevelVar
+}
+
+func (c varLevelCore) Enabled(l zapcore.Level) bool {
+	return atLeast(l, c.v.Level()) && c.Core.Enabled(l)
+}
+
+// Level implements zapcore.LevelOf's fast path.
+func (c varLevelCore) Level() zapcore.Level {
+	return max(zapLevel(c.v.Level()), zapcore.LevelOf(c.Core))
+}
+
+func (c varLevelCore) unwrap() zapcore.Core                { return c.Core }
+func (c varLevelCore) rewrap(in zapcore.Core) zapcore.Core { return varLevelCore{Core: in, v: c.v} }
+
+func (c varLevelCore) With(fs []zapcore.Field) zapcore.Core {
+	return varLevelCore{Core: c.Core.With(fs), v: c.v}
+}
+
+func (c varLevelCore) Check(e zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !atLeast(e.Level, c.v.Level()) {
+		return ce
+	}
+	return c.Core.Check(e, ce)
+}
+
+// minLevelCore drops entries below min before they reach the wrapped core. It backs WithLevel; unlike
+// zap.IncreaseLevel it may also lower a level set by an earlier WithLevel.
+type minLevelCore struct {
+	zapcore.Core
+	min logstox.Level
+}
+
+// withMinLevel wraps c so it drops entries below min, replacing an earlier minimum if c already has one, even under
+// the cores zapx wrapped it in since, eg for WithOptions' hooks.
+func withMinLevel(c zapcore.Core, min logstox.Level) zapcore.Core {
+	if c, ok := replaceMinLevel(c, min); ok {
+		return c
+	}
+	return minLevelCore{Core: c, min: min}
+}
+
+// replaceMinLevel returns c with the minimum of its minLevelCore replaced by min, looking through the cores zapx wraps
+// others with. It reports false if c has no minLevelCore, or only under a core zapx doesn't know.
+func replaceMinLevel(c zapcore.Core, min logstox.Level) (zapcore.Core, bool) {
+	switch c := c.(type) {
+	case minLevelCore:
+		return minLevelCore{Core: c.Core, min: min}, true
+	case coreWrapper:
+		if inner, ok := replaceMinLevel(c.unwrap(), min); ok {
+			return c.rewrap(inner), true
+		}
+	}
+	return c, false
+}
+
+// coreWrapper is implemented by the cores zapx wraps others with, other than minLevelCore, so replaceMinLevel can
+// look through them.
+type coreWrapper interface {
+	// unwrap returns the wrapped core.
+	unwrap() zapcore.Core
+	// rewrap returns a copy of the wrapper wrapping c instead.
+	rewrap(c zapcore.Core) zapcore.Core
+}
+
+func (c minLevelCore) Enabled(l zapcore.Level) bool {
+	return atLeast(l, c.min) && c.Core.Enabled(l)
+}
+
+// Level implements zapcore.LevelOf's fast path.
+func (c minLevelCore) Level() zapcore.Level {
+	return max(zapLevel(c.min), zapcore.LevelOf(c.Core))
+}
+
+func (c minLevelCore) With(fs []zapcore.Field) zapcore.Core {
+	return minLevelCore{Core: c.Core.With(fs), min: c.min}
+}
+
+func (c minLevelCore) Check(e zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !atLeast(e.Level, c.min) {
+		return ce
+	}
+	return c.Core.Check(e, ce)
+}
+
+// atLeast reports whether a minimum of min enables the zap level l, a minimum of logstox.NoticeLevel being InfoLevel.
+func atLeast(l zapcore.Level, min logstox.Level) bool {
+	if min == logstox.NoticeLevel {
+		min = logstox.InfoLevel
+	}
+	return FromZapLevel(l).Compare(min) >= 0
+}
+
+// hooksCore runs hooks with every entry the wrapped core records, like zapcore.RegisterHooks, but withMinLevel can
+// look through it. It backs WithOptions' hooks.
+type hooksCore struct {
+	zapcore.Core
+	hooks []func(zapcore.Entry) error
+}
+
+func (c hooksCore) unwrap() zapcore.Core                { return c.Core }
+func (c hooksCore) rewrap(in zapcore.Core) zapcore.Core { return hooksCore{Core: in, hooks: c.hooks} }
+
+// Level implements zapcore.LevelOf's fast path.
+func (c hooksCore) Level() zapcore.Level {
+	return zapcore.LevelOf(c.Core)
+}
+
+func (c hooksCore) With(fs []zapcore.Field) zapcore.Core {
+	return hooksCore{Core: c.Core.With(fs), hooks: c.hooks}
+}
+
+func (c hooksCore) Check(e zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if next := c.Core.Check(e, ce); next != nil {
+		return next.AddCore(e, c)
+	}
+	return ce
+}
+
+// Write runs the hooks, the wrapped core having been added to the CheckedEntry on its own by Check.
+func (c hooksCore) Write(e zapcore.Entry, _ []zapcore.Field) error {
+	errs := make([]error, 0, len(c.hooks))
+	for _, hook := range c.hooks {
+		errs = append(errs, hook(e))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,48 @@
+package zapx
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/khinshankhan/logstox"
+)
+
+func TestWithLevelReplacesMinimumUnderHooks(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	var hooked int
+	log := FromZap(zap.New(core), logstox.Options[ZapField]{})
+	log = logstox.WithLevel(log, logstox.WarnLevel)
+	log = logstox.WithOptions(log, logstox.Hooks(func(logstox.Entry) error { hooked++; return nil }))
+	log = logstox.WithLevel(log, logstox.DebugLevel)
+
+	if !logstox.Enabled(log, logstox.DebugLevel) {
+		t.Fatal("debug is disabled, want the later WithLevel to lower the minimum")
+	}
+	log.Debug("d")
+	logstox.NoticerOf(log).Notice("n")
+	if logs.Len() != 2 || hooked != 2 {
+		t.Errorf("wrote %d entries and ran the hook %d times, want 2 and 2", logs.Len(), hooked)
+	}
+	if e := logs.All()[1]; e.Level != zapcore.InfoLevel || e.ContextMap()[logstox.NoticeKey] != true {
+		t.Errorf("notice written at %v with %v, want info tagged with %s", e.Level, e.ContextMap(), logstox.NoticeKey)
+	}
+}
+
+func TestNoticesAreNamedByBackendEncoders(t *testing.T) {
+	var buf bytes.Buffer
+	log := Backend{}.New(logstox.Options[ZapField]{Writer: &buf})
+	logstox.NoticerOf(log).Notice("n")
+
+	var e map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &e); err != nil {
+		t.Fatal(err)
+	}
+	if e["level"] != logstox.NoticeName || e[logstox.NoticeKey] != nil {
+		t.Errorf("notice written as %v, want level %s without the tag", e, logstox.NoticeName)
+	}
+}
@@ -0,0 +1,100 @@
+package zapx
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/khinshankhan/logstox"
+	"github.com/khinshankhan/logstox/fields"
+)
+
+// limit applies Options.Limits to the message m and the fields f of a call if lvl is enabled, see logstox.Limits.
+// Context fields added via With aren't bounded or counted, and lazy fields count for one field, their values bounded
+// when they're evaluated.
+func (lg logger) limit(lvl zapcore.Level, m string, f []ZapField) (string, []ZapField) {
+	if !lg.limits.Enabled() || !lg.l.Core().Enabled(lvl) {
+		return m, f
+	}
+	m, truncated := lg.limits.Message(m)
+	f, cut, overflow := limitFields(lg.limits, f)
+	if (truncated || cut) && !hasKey(f, logstox.TruncatedKey) {
+		f = append(f[:len(f):len(f)], zap.Bool(logstox.TruncatedKey, true))
+	}
+	if overflow > 0 && !hasKey(f, logstox.OverflowCountKey) {
+		f = append(f[:len(f):len(f)], zap.Int(logstox.OverflowCountKey, overflow))
+	}
+	return m, f
+}
+
+// limitFields is logstox.Limits.Fields for zap fields.
+func limitFields(l logstox.Limits, fs []ZapField) (out []ZapField, truncated bool, overflow int) {
+	if l.MaxFields > 0 && len(fs) > l.MaxFields {
+		overflow = len(fs) - l.MaxFields
+		fs = fs[:l.MaxFields:l.MaxFields]
+	}
+	for i, f := range fs {
+		g, changed, cut, dropped := limitField(l, f)
+		if !changed {
+			continue
+		}
+		if out == nil {
+			out = slices.Clone(fs)
+		}
+		out[i] = g
+		truncated = truncated || cut
+		overflow += dropped
+	}
+	if out == nil {
+		return fs, false, overflow
+	}
+	return out, truncated, overflow
+}
+
+// limitField returns f cut down to l and whether it changed, reporting whether its value was truncated and how many
+// fields were dropped from it. Lazy fields are wrapped to be cut down when they're evaluated.
+func limitField(l logstox.Limits, f ZapField) (ZapField, bool, bool, int) {
+	limit := l.MaxFieldBytes
+	switch f.Type {
+	case zapcore.StringType:
+		if limit > 0 && len(f.String) > limit {
+			f.String = logstox.Truncate(f.String, limit)
+			return f, true, true, 0
+		}
+	case zapcore.ByteStringType, zapcore.BinaryType:
+		if b, _ := f.Interface.([]byte); limit > 0 && len(b) > limit {
+			f.Interface = b[:limit:limit]
+			return f, true, true, 0
+		}
+	case zapcore.ReflectType:
+		if b, ok := f.Interface.(json.RawMessage); ok && limit > 0 && len(b) > limit {
+			return zap.String(f.Key, logstox.Truncate(string(b), limit)), true, true, 0
+		}
+	case zapcore.ObjectMarshalerType, zapcore.InlineMarshalerType:
+		switch v := f.Interface.(type) {
+		case dict:
+			if sub, truncated, overflow := l.Fields(v.fs); truncated || overflow > 0 {
+				f.Interface = dict{v.ctx, sub}
+				return f, true, truncated, overflow
+			}
+		case lazyValue:
+			f.Interface = lazyValue{v.ctx, func() []fields.Field { fs, _, _ := l.Fields(v.fn()); return fs }}
+			return f, true, false, 0
+		case lazyFields:
+			f.Interface = lazyFields{v.ctx, func(ctx context.Context) []fields.Field {
+				fs, _, _ := l.Fields(v.fn(ctx))
+				return fs
+			}}
+			return f, true, false, 0
+		}
+	}
+	return f, false, false, 0
+}
+
+// hasKey reports whether a field of fs has the key k.
+func hasKey(fs []ZapField, k string) bool {
+	return slices.ContainsFunc(fs, func(f ZapField) bool { return f.Key == k })
+}
@@ -1,9 +1,15 @@
 package zapx
 
 import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/khinshankhan/logstox"
+	"github.com/khinshankhan/logstox/fields"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -13,6 +19,8 @@ type ZapField = zap.Field
 
 // Backend builds a Logger[ZapField] from logstox.Options[ZapField].
 type Backend struct {
+	// Development is the same as Options.Development: it starts from zap's development config, defaults Encoding to
+	// console for every sink, makes DPanic panic and validates field keys (see logstox.ValidateKeys).
 	Development bool
 	// If non-empty, sets the timestamp layout (eg, time.RFC3339Nano).
 	// Options.TimeLayout takes precedence over this.
@@ -20,8 +28,40 @@ type Backend struct {
 	AddSource  bool
 	// CallerSkip controls the number of stack frames to skip when reporting the caller.
 	// If zero, defaults to 1, which typically points to the caller of the logger method.
-	// Increase this value if wrapping the logger in additional abstraction layers.
+	// Options.CallerSkip is added on top, prefer it when wrapping the logger in additional abstraction layers.
 	CallerSkip int
+	// SamplingInitial and SamplingThereafter configure zap's sampler: each second, the first SamplingInitial entries
+	// with the same level and message are logged, then every SamplingThereafter-th one after that.
+	// If both are zero, the dev/prod default applies (production samples 100/100, development doesn't sample).
+	SamplingInitial    int
+	SamplingThereafter int
+	// DisableSampling turns sampling off entirely, taking precedence over the fields above.
+	DisableSampling bool
+	// OnSampled, if set, is called with the entries the sampler drops, without their fields, eg
+	// (*middleware.Summaries).Record to log how many were. It runs on the logging goroutine, so keep it cheap.
+	OnSampled func(logstox.Entry)
+	// Encoder key names, so output can match downstream schema expectations (eg "message" instead of "msg").
+	// Empty keeps zap's default for the dev/prod config; OmitKey drops the key from output.
+	MessageKey string
+	LevelKey   string
+	TimeKey    string
+	NameKey    string
+	CallerKey  string
+	// Encoding selects the encoder, either EncodingJSON, EncodingConsole or EncodingCLEF.
+	// If empty, defaults to console in development and JSON otherwise.
+	Encoding string
+	// Core, if set, is used as is instead of building one, so existing cores (sentry, tee, ...) can be kept.
+	// Level, Writer, encoding, sampling and time layout settings are then the core's business and are ignored.
+	Core zapcore.Core
+	// ZapOptions are appended to the options used to build the logger (eg zap.Hooks, zap.WrapCore).
+	ZapOptions []zap.Option
+	// Hooks are called with every entry that's written, eg to count entries per level or alert on errors.
+	// They run synchronously on the logging goroutine, so keep them cheap.
+	Hooks []func(zapcore.Entry) error
+	// PanicHook and FatalHook, if set, run instead of zap's default panic/ os.Exit(1) after a Panic/ Fatal entry
+	// is written (see zapcore.CheckWriteHook).
+	PanicHook zapcore.CheckWriteHook
+	FatalHook zapcore.CheckWriteHook
 }
 
 // Interface satisfaction (compile-time assertions).
@@ -37,11 +77,42 @@ func firstNonEmpty(ss ...string) string {
 	return ""
 }
 
+// Encodings supported by Backend.Encoding.
+const (
+	EncodingJSON    = "json"
+	EncodingConsole = "console"
+	// EncodingCLEF writes the Compact Log Event Format Seq and Serilog tooling ingest: JSON with the time, message
+	// template, level and logger name under the logstox.CLEF* keys, levels by their Serilog names. Errors logged
+	// under fields.ErrorKey ("error"), eg by fields.Error, are renamed to logstox.CLEFExceptionKey ("@x"), so there's
+	// no "error" key in the output; other error fields keep theirs. Backend encoder key names still take precedence.
+	EncodingCLEF = "clef"
+)
+
+// OmitKey can be set as any of the Backend encoder key names to drop that key from output.
+// zap's own zapcore.OmitKey is the empty string, which Backend already uses for "keep the default".
+const OmitKey = "-"
+
+// encoderKey resolves a Backend encoder key name against zap's default.
+func encoderKey(key, def string) string {
+	switch key {
+	case "":
+		return def
+	case OmitKey:
+		return zapcore.OmitKey
+	default:
+		return key
+	}
+}
+
 // New constructs a zap-backed Logger[ZapField].
 func (b Backend) New(o logstox.Options[ZapField]) logstox.Logger[ZapField] {
 	// Base config: dev/prod
+	o.Development = b.Development || o.Development
+	if o.SplitStdStreams {
+		o.Writers = append(o.Writers[:len(o.Writers):len(o.Writers)], logstox.StdStreams()...)
+	}
 	var cfg zap.Config
-	if b.Development {
+	if o.Development {
 		cfg = zap.NewDevelopmentConfig()
 	} else {
 		cfg = zap.NewProductionConfig()
@@ -51,85 +122,397 @@ func (b Backend) New(o logstox.Options[ZapField]) logstox.Logger[ZapField] {
 	enc := cfg.EncoderConfig
 	layout := firstNonEmpty(o.TimeLayout, b.TimeLayout, time.RFC3339Nano)
 	enc.EncodeTime = zapcore.TimeEncoderOfLayout(layout)
-	enc.StacktraceKey = ""
+	if !o.AddStacktrace {
+		enc.StacktraceKey = ""
+	}
+	// we add our own stacktrace option below rather than zap's dev/prod default
+	cfg.DisableStacktrace = true
+	enc.MessageKey = encoderKey(b.MessageKey, enc.MessageKey)
+	enc.LevelKey = encoderKey(b.LevelKey, enc.LevelKey)
+	enc.TimeKey = encoderKey(b.TimeKey, enc.TimeKey)
+	enc.NameKey = encoderKey(b.NameKey, enc.NameKey)
+	enc.CallerKey = encoderKey(b.CallerKey, enc.CallerKey)
+	enc.EncodeLevel = levelEncoder(enc.EncodeLevel, o.Development, o.LevelFormat)
 	cfg.EncoderConfig = enc
 
-	// Level override from Options if provided/ mapped.
-	if zl, ok := toZapLevel(o.Level); ok {
-		cfg.Level = zap.NewAtomicLevelAt(zl)
+	if b.Encoding != "" {
+		cfg.Encoding = b.Encoding
+	}
+	encoder := zapcore.NewJSONEncoder
+	switch cfg.Encoding {
+	case EncodingConsole:
+		encoder = zapcore.NewConsoleEncoder
+	case EncodingCLEF:
+		encoder = newCLEFEncoder
+		enc = b.clefEncoderConfig(enc)
+		cfg.EncoderConfig = enc
+	}
+	newEncoder := func(cfg zapcore.EncoderConfig) zapcore.Encoder { return noticeEncoder{encoder(cfg)} }
+
+	// Sampling, explicit knobs override the dev/prod default.
+	switch {
+	case b.DisableSampling:
+		cfg.Sampling = nil
+	case b.SamplingInitial != 0 || b.SamplingThereafter != 0:
+		cfg.Sampling = &zap.SamplingConfig{
+			Initial:    b.SamplingInitial,
+			Thereafter: b.SamplingThereafter,
+		}
+	}
+	if cfg.Sampling != nil && b.OnSampled != nil {
+		cfg.Sampling.Hook = b.samplingHook
+	}
+
+	// Level override from Options if provided/ mapped. A LevelVar is applied by FromZap, the cores then let everything
+	// through so it can be lowered later.
+	minLevel := o.Level
+	if o.LevelVar != nil {
+		minLevel = logstox.DebugLevel
+	}
+	if _, ok := ToZapLevel(minLevel); ok {
+		cfg.Level = zap.NewAtomicLevelAt(zapLevel(minLevel))
+	} else {
+		minLevel = FromZapLevel(cfg.Level.Level())
 	}
 
 	// Build logger, either via provided Writer or default sinks.
 	var base *zap.Logger
 	var opts []zap.Option
+	var writers []io.Writer // closed by Close
+	// AddCallerSkip to point at the user's callsite (skipping wrapper methods), set even without AddSource so that
+	// turning it on later via WithOptions points at the right frame.
+	skip := b.CallerSkip
+	if skip == 0 {
+		skip = 1
+	}
+	skip += o.CallerSkip
+	opts = append(opts, zap.AddCallerSkip(skip))
 	if b.AddSource || o.AddSource {
-		skip := b.CallerSkip
-		if skip == 0 {
-			skip = 1
-		}
-		// AddCallerSkip to point at the user's callsite (skipping wrapper methods).
-		opts = append(opts, zap.AddCaller(), zap.AddCallerSkip(skip))
+		opts = append(opts, zap.AddCaller())
+	}
+	var stacktrace zapcore.LevelEnabler
+	if o.AddStacktrace && o.StacktraceLevel.Valid() {
+		stacktrace = levelFilter(o.StacktraceLevel, nil)
+		opts = append(opts, zap.AddStacktrace(stacktrace))
+	}
+	if len(b.Hooks) > 0 {
+		opts = append(opts, zap.Hooks(b.Hooks...))
 	}
+	if b.PanicHook != nil {
+		opts = append(opts, zap.WithPanicHook(b.PanicHook))
+	}
+	if b.FatalHook != nil {
+		opts = append(opts, zap.WithFatalHook(b.FatalHook))
+	}
+	// cfg.Build only applies cfg.Development itself, set it for every sink
+	if o.Development {
+		opts = append(opts, zap.Development())
+	}
+	opts = append(opts, b.ZapOptions...)
 
-	if o.Writer != nil {
-		core := zapcore.NewCore(
-			zapcore.NewJSONEncoder(enc),
-			zapcore.AddSync(o.Writer),
-			cfg.Level,
-		)
+	switch {
+	case b.Core != nil:
+		base = zap.New(b.Core, opts...)
+	case o.Writer != nil || len(o.Writers) > 0:
+		var cores []zapcore.Core
+		if o.Writer != nil {
+			cores = append(cores, zapcore.NewCore(newEncoder(enc), writeSyncer(o.Writer), levelFilter(minLevel, nil)))
+			writers = append(writers, o.Writer)
+		}
+		for _, w := range o.Writers {
+			cores = append(cores, zapcore.NewCore(newEncoder(enc), writeSyncer(w.Writer), levelFilter(minLevel, w.Enabled)))
+			writers = append(writers, w.Writer)
+		}
+		core := zapcore.NewTee(cores...)
+		// cfg.Build applies sampling itself, mirror it here so behavior doesn't depend on the sink.
+		if cfg.Sampling != nil {
+			var sopts []zapcore.SamplerOption
+			if cfg.Sampling.Hook != nil {
+				sopts = append(sopts, zapcore.SamplerHook(cfg.Sampling.Hook))
+			}
+			core = zapcore.NewSamplerWithOptions(core, time.Second, cfg.Sampling.Initial, cfg.Sampling.Thereafter, sopts...)
+		}
 		base = zap.New(core, opts...)
-	} else {
+	default:
+		if name, ok := noticeEncodings[cfg.Encoding]; ok {
+			cfg.Encoding = name
+		}
 		base = zap.Must(cfg.Build(opts...))
 	}
 
+	lg := fromZap(base, o)
+	lg.writers = writers
+	lg.stacktrace = stacktrace
+	lg.console = b.Core == nil && cfg.Encoding == EncodingConsole
+	return lg
+}
+
+// samplingHook hands the entries the sampler drops to OnSampled.
+func (b Backend) samplingHook(e zapcore.Entry, dec zapcore.SamplingDecision) {
+	if dec&zapcore.LogDropped != 0 {
+		b.OnSampled(logstox.Entry{Time: e.Time, Level: FromZapLevel(e.Level), Name: e.LoggerName, Message: e.Message})
+	}
+}
+
+// writeSyncer adapts w for a core. Standard streams don't get synced, which fails when they're a terminal or pipe.
+func writeSyncer(w io.Writer) zapcore.WriteSyncer {
+	if w == os.Stdout || w == os.Stderr {
+		return zapcore.AddSync(struct{ io.Writer }{w})
+	}
+	return zapcore.AddSync(w)
+}
+
+// levelFilter enables min and above, narrowed by a LevelWriter's filter if enabled is set.
+func levelFilter(min logstox.Level, enabled func(logstox.Level) bool) zapcore.LevelEnabler {
+	return zap.LevelEnablerFunc(func(l zapcore.Level) bool {
+		return atLeast(l, min) && (enabled == nil || enabled(FromZapLevel(l)))
+	})
+}
+
+// NewFromCore constructs a Logger[ZapField] from a user-constructed core and zap options.
+// Options are applied as by FromZap; everything else is up to core and opts.
+func NewFromCore(core zapcore.Core, o logstox.Options[ZapField], opts ...zap.Option) logstox.Logger[ZapField] {
+	return FromZap(zap.New(core, opts...), o)
+}
+
+// FromZap wraps an existing *zap.Logger, applying Options.Name, Options.Fields, Options.LevelVar (on top of base's
+// own level), Options.Development's key validation (base's own development mode is up to base), Options.Limits (to
+// the message and fields of each call) and MultilineFold (base's encoding isn't known, so line breaks are otherwise
+// left to it).
+func FromZap(base *zap.Logger, o logstox.Options[ZapField]) logstox.Logger[ZapField] {
+	return fromZap(base, o)
+}
+
+func fromZap(base *zap.Logger, o logstox.Options[ZapField]) logger {
+	if o.LevelVar != nil {
+		base = base.WithOptions(zap.WrapCore(func(c zapcore.Core) zapcore.Core {
+			return varLevelCore{Core: c, v: o.LevelVar}
+		}))
+	}
 	if o.Name != "" {
 		base = base.Named(o.Name)
 	}
 	if len(o.Fields) > 0 {
-		base = base.With(o.Fields...)
+		base = with(base, o.Fields)
 	}
 
-	return logger{l: base}
+	return logger{
+		l:           base,
+		development: o.Development,
+		multiline:   o.Multiline,
+		limits:      o.Limits,
+		levelVar:    o.LevelVar,
+		context:     o.Fields,
+		extractors:  o.ContextExtractors,
+	}
 }
 
 // logger is a thin zap-backed implementation of logstox.Logger[ZapField].
-type logger struct{ l *zap.Logger }
+type logger struct {
+	l *zap.Logger
+	// context keeps the fields added via With (and Options.Fields) for Fields, zap doesn't expose them.
+	// Fields baked into a *zap.Logger passed to FromZap aren't known.
+	context []ZapField
+	// development validates field keys, see logstox.ValidateKeys.
+	development bool
+	// multiline is Options.Multiline, console whether the encoding is known to be zap's console one.
+	multiline logstox.Multiline
+	console   bool
+	levelVar  *logstox.LevelVar
+	writers   []io.Writer    // Options writers, closed by Close
+	limits    logstox.Limits // Options.Limits
+	// stacktrace is the levels zap adds a stacktrace to (Options.AddStacktrace), nil if unknown.
+	stacktrace zapcore.LevelEnabler
+	// ctx is the context bound by WithContext, extractors (Options.ContextExtractors) derive fields from it per entry.
+	ctx        context.Context
+	extractors []func(context.Context) []ZapField
+}
 
 // Interface satisfaction (compile-time assertions).
-var _ logstox.Logger[ZapField] = logger{}
+var (
+	_ logstox.Logger[ZapField]         = logger{}
+	_ logstox.LevelCheck               = logger{}
+	_ logstox.OptionsApplier[ZapField] = logger{}
+	_ logstox.LevelSetter[ZapField]    = logger{}
+	_ logstox.Introspector[ZapField]   = logger{}
+	_ logstox.LazyWither[ZapField]     = logger{}
+	_ logstox.Noticer[ZapField]        = logger{}
+	_ logstox.LevelVarHolder           = logger{}
+	_ logstox.Closer                   = logger{}
+	_ logstox.ContextBinder[ZapField]  = logger{}
+)
 
 // DEBUG (-1): for recording messages useful for debugging.
-func (lg logger) Debug(m string, f ...ZapField) { lg.l.Debug(m, f...) }
+func (lg logger) Debug(m string, f ...ZapField) {
+	f = lg.bound(zapcore.DebugLevel, f)
+	m, f = lg.lines(zapcore.DebugLevel, m, f)
+	m, f = lg.limit(zapcore.DebugLevel, m, f)
+	if lg.development && lg.invalid(zapcore.DebugLevel, m, f) {
+		return
+	}
+	lg.l.Debug(m, f...)
+}
 
 // INFO (0): for messages describing normal application operations.
-func (lg logger) Info(m string, f ...ZapField) { lg.l.Info(m, f...) }
+func (lg logger) Info(m string, f ...ZapField) {
+	f = lg.bound(zapcore.InfoLevel, f)
+	m, f = lg.lines(zapcore.InfoLevel, m, f)
+	m, f = lg.limit(zapcore.InfoLevel, m, f)
+	if lg.development && lg.invalid(zapcore.InfoLevel, m, f) {
+		return
+	}
+	lg.l.Info(m, f...)
+}
+
+// Notice logs at logstox.NoticeLevel, see logstox.Noticer. zap has no notice level, so it's written at Info, tagged
+// with a true logstox.NoticeKey field that Backend's encoders write as the level instead.
+func (lg logger) Notice(m string, f ...ZapField) {
+	f = lg.bound(zapcore.InfoLevel, f)
+	m, f = lg.lines(zapcore.InfoLevel, m, f)
+	m, f = lg.limit(zapcore.InfoLevel, m, f)
+	if lg.development && lg.invalid(zapcore.InfoLevel, m, f) {
+		return
+	}
+	lg.l.Info(m, append(f[:len(f):len(f)], noticeField)...)
+}
 
 // WARN (1): for recording messages indicating something unusual happened that may need attention before it escalates to a more severe issue.
-func (lg logger) Warn(m string, f ...ZapField) { lg.l.Warn(m, f...) }
+func (lg logger) Warn(m string, f ...ZapField) {
+	f = lg.bound(zapcore.WarnLevel, f)
+	m, f = lg.lines(zapcore.WarnLevel, m, f)
+	m, f = lg.limit(zapcore.WarnLevel, m, f)
+	if lg.development && lg.invalid(zapcore.WarnLevel, m, f) {
+		return
+	}
+	lg.l.Warn(m, f...)
+}
 
 // ERROR (2): for recording unexpected error conditions in the program.
-func (lg logger) Error(m string, f ...ZapField) { lg.l.Error(m, f...) }
+func (lg logger) Error(m string, f ...ZapField) {
+	f = lg.bound(zapcore.ErrorLevel, f)
+	m, f = lg.lines(zapcore.ErrorLevel, m, f)
+	m, f = lg.limit(zapcore.ErrorLevel, m, f)
+	if lg.development && lg.invalid(zapcore.ErrorLevel, m, f) {
+		return
+	}
+	lg.l.Error(m, f...)
+}
 
 // DPANIC (3): for recording severe error conditions in development. It behaves like PANIC in development and ERROR in production.
-func (lg logger) DPanic(m string, f ...ZapField) { lg.l.DPanic(m, f...) }
+// The entry carries a fields.PanicStack dict, unless Options.AddStacktrace already covers the level.
+func (lg logger) DPanic(m string, f ...ZapField) {
+	f = lg.bound(zapcore.DPanicLevel, f)
+	m, f = lg.lines(zapcore.DPanicLevel, m, f)
+	m, f = lg.limit(zapcore.DPanicLevel, m, f)
+	lg.l.DPanic(m, append(f[:len(f):len(f)], lg.panicStack(zapcore.DPanicLevel))...)
+}
 
 // PANIC (4): calls panic() after logging an error condition.
-func (lg logger) Panic(m string, f ...ZapField) { lg.l.Panic(m, f...) }
+// The entry carries a fields.PanicStack dict, unless Options.AddStacktrace already covers the level.
+func (lg logger) Panic(m string, f ...ZapField) {
+	f = lg.bound(zapcore.PanicLevel, f)
+	m, f = lg.lines(zapcore.PanicLevel, m, f)
+	m, f = lg.limit(zapcore.PanicLevel, m, f)
+	lg.l.Panic(m, append(f[:len(f):len(f)], lg.panicStack(zapcore.PanicLevel))...)
+}
+
+// panicStack returns the fields.PanicStack attached to lvl's entries, skipped if zap adds its own stacktrace to them.
+func (lg logger) panicStack(lvl zapcore.Level) ZapField {
+	if lg.stacktrace != nil && lg.stacktrace.Enabled(lvl) {
+		return zap.Skip()
+	}
+	return ToZap(fields.PanicStack())
+}
 
 // FATAL (5): calls os.Exit(1) after logging an error condition.
-func (lg logger) Fatal(m string, f ...ZapField) { lg.l.Fatal(m, f...) }
+func (lg logger) Fatal(m string, f ...ZapField) {
+	f = lg.bound(zapcore.FatalLevel, f)
+	m, f = lg.lines(zapcore.FatalLevel, m, f)
+	m, f = lg.limit(zapcore.FatalLevel, m, f)
+	lg.l.Fatal(m, f...)
+}
+
+// bound prepends the fields the extractors derive from the bound context to f, if lvl is enabled.
+func (lg logger) bound(lvl zapcore.Level, f []ZapField) []ZapField {
+	if lg.ctx == nil || len(lg.extractors) == 0 || !lg.l.Core().Enabled(lvl) {
+		return f
+	}
+	return append(logstox.ExtractContext(lg.ctx, lg.extractors), f...)
+}
+
+// lines applies Options.Multiline to the message m if lvl is enabled, adding the detail to f when it's folded.
+func (lg logger) lines(lvl zapcore.Level, m string, f []ZapField) (string, []ZapField) {
+	if lg.multiline == logstox.MultilineKeep || !lg.l.Core().Enabled(lvl) {
+		return m, f
+	}
+	m, detail := lg.multiline.Apply(m, lg.console)
+	if detail != "" {
+		f = append(f[:len(f):len(f)], zap.String(logstox.DetailKey, detail))
+	}
+	return m, f
+}
+
+// invalid validates the keys of f if lvl is enabled, escalating the entry to DPanic if they fail. It must be called
+// directly by the level methods, its caller skip accounts for one extra frame.
+func (lg logger) invalid(lvl zapcore.Level, m string, f []ZapField) bool {
+	if !lg.l.Core().Enabled(lvl) {
+		return false
+	}
+	keys := make([]string, 0, len(f))
+	for _, field := range f {
+		if field.Type != zapcore.SkipType && field.Type != zapcore.InlineMarshalerType {
+			keys = append(keys, field.Key)
+		}
+	}
+	err := logstox.ValidateKeys(keys)
+	if err == nil {
+		return false
+	}
+	fs := append(f[:len(f):len(f)], zap.String(logstox.FieldErrorsKey, err.Error()), lg.panicStack(zapcore.DPanicLevel))
+	lg.l.WithOptions(zap.AddCallerSkip(1)).DPanic(m, fs...)
+	return true
+}
 
 // With creates a child logger and adds structured context to it. Fields added
 // to the child don't affect the parent, and vice versa. Any fields that
 // require evaluation (such as Objects) are evaluated upon invocation of With.
 func (lg logger) With(f ...ZapField) logstox.Logger[ZapField] {
-	return logger{l: lg.l.With(f...)}
+	child := lg
+	child.l = with(lg.l, f)
+	child.context = append(lg.context[:len(lg.context):len(lg.context)], f...)
+	return child
+}
+
+// WithLazy creates a child logger via zap's WithLazy: the fields are only encoded once the child is first used.
+func (lg logger) WithLazy(f ...ZapField) logstox.Logger[ZapField] {
+	child := lg
+	child.l = lg.l.WithLazy(f...)
+	child.context = append(lg.context[:len(lg.context):len(lg.context)], f...)
+	return child
+}
+
+// with adds context fields to l. zap encodes With fields immediately, so lazy fields are attached via WithLazy
+// instead: they're then only evaluated once an entry is actually emitted at an enabled level.
+func with(l *zap.Logger, f []ZapField) *zap.Logger {
+	if hasLazy(f) {
+		return l.WithLazy(f...)
+	}
+	return l.With(f...)
+}
+
+// WithContext returns a child bound to ctx: Options.ContextExtractors derive fields from it for every entry.
+func (lg logger) WithContext(ctx context.Context) logstox.Logger[ZapField] {
+	child := lg
+	child.ctx = ctx
+	return child
 }
 
 // Named adds a new path segment to the logger's name. Segments are joined by
 // periods. By default, Loggers are unnamed.
 func (lg logger) Named(n string) logstox.Logger[ZapField] {
-	return logger{l: lg.l.Named(n)}
+	child := lg
+	child.l = lg.l.Named(n)
+	return child
 }
 
 // Sync calls the underlying Core's Sync method, flushing any buffered log
@@ -137,3 +520,82 @@ func (lg logger) Named(n string) logstox.Logger[ZapField] {
 func (lg logger) Sync() error {
 	return lg.l.Sync()
 }
+
+// Close syncs the logger, then closes the Options writers that are io.Closers. Sinks opened by zap itself (the
+// default stderr) and Backend.Core are left alone.
+func (lg logger) Close() error {
+	return errors.Join(lg.Sync(), logstox.CloseWriters(lg.writers...))
+}
+
+// Enabled reports whether the underlying core records lvl.
+func (lg logger) Enabled(lvl logstox.Level) bool {
+	zl, ok := ToZapLevel(lvl)
+	return ok && lg.l.Core().Enabled(zl)
+}
+
+// WithOptions returns a child logger with opts translated into zap options.
+func (lg logger) WithOptions(opts ...logstox.Option) logstox.Logger[ZapField] {
+	a := logstox.Collect(opts...)
+
+	var zopts []zap.Option
+	if a.AddSource != nil {
+		zopts = append(zopts, zap.WithCaller(*a.AddSource))
+	}
+	if a.CallerSkip != 0 {
+		zopts = append(zopts, zap.AddCallerSkip(a.CallerSkip))
+	}
+	if a.ErrorHandler != nil {
+		zopts = append(zopts, zap.ErrorOutput(zapcore.AddSync(errorWriter(a.ErrorHandler))))
+	}
+	if len(a.Hooks) > 0 {
+		hooks := make([]func(zapcore.Entry) error, len(a.Hooks))
+		for i, hook := range a.Hooks {
+			hooks[i] = func(e zapcore.Entry) error {
+				return hook(logstox.Entry{
+					Time:    e.Time,
+					Level:   FromZapLevel(e.Level),
+					Name:    e.LoggerName,
+					Message: e.Message,
+				})
+			}
+		}
+		zopts = append(zopts, zap.WrapCore(func(c zapcore.Core) zapcore.Core {
+			return hooksCore{Core: c, hooks: hooks}
+		}))
+	}
+	child := lg
+	child.l = lg.l.WithOptions(zopts...)
+	return child
+}
+
+// WithLevel returns a child logger whose core drops entries below lvl.
+func (lg logger) WithLevel(lvl logstox.Level) logstox.Logger[ZapField] {
+	child := lg
+	child.l = lg.l.WithOptions(zap.WrapCore(func(c zapcore.Core) zapcore.Core {
+		return withMinLevel(c, lvl)
+	}))
+	return child
+}
+
+// Name returns the zap logger's name.
+func (lg logger) Name() string {
+	return lg.l.Name()
+}
+
+// Fields returns the context fields added via With.
+func (lg logger) Fields() []ZapField {
+	return lg.context
+}
+
+// LevelVar returns Options.LevelVar, nil if the logger wasn't built with one.
+func (lg logger) LevelVar() *logstox.LevelVar {
+	return lg.levelVar
+}
+
+// errorWriter adapts an error handler to the io.Writer zap reports internal errors to.
+type errorWriter func(error)
+
+func (w errorWriter) Write(p []byte) (int, error) {
+	w(errors.New(strings.TrimSpace(string(p))))
+	return len(p), nil
+}
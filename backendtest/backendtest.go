@@ -0,0 +1,527 @@
+package backendtest
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/khinshankhan/logstox"
+	"github.com/khinshankhan/logstox/fields"
+)
+
+// Subject describes the backend under test. By default its output must be NDJSON, one object per entry, with context
+// and call site fields as top-level keys; Decode and Recorded cover other layouts.
+type Subject[FT any] struct {
+	Backend logstox.Backend[FT]
+	// Convert turns logstox fields into the backend's field type (eg zapx.ToZap).
+	Convert func(fields.Field) FT
+
+	// Keys the backend writes the message, level and logger name under. Empty uses "msg", "level" and "logger".
+	MessageKey string
+	LevelKey   string
+	NameKey    string
+	// LevelName returns the name the backend writes lvl with, lvl.String() if nil, for backends with levels of their
+	// own, eg apex writing DPanic and Panic as "error".
+	LevelName func(lvl logstox.Level) string
+
+	// Decode, if set, decodes the backend's output into one flat object per entry instead of NDJSON, eg CanonicalJSON
+	// for streamx.
+	Decode func(output []byte) ([]map[string]any, error)
+	// Recorded, if set, returns the entries l recorded, for backends that don't write to Options.Writer, eg memx's
+	// RecorderOf(l).Entries(). They're checked as their canonical JSON (see CanonicalJSON), Decode being ignored.
+	Recorded func(l logstox.Logger[FT]) []logstox.Entry
+}
+
+// Run checks that s behaves as logstox expects of a Backend, as subtests of t:
+//
+//	func TestConformance(t *testing.T) {
+//		backendtest.Run(t, backendtest.Subject[mylog.Field]{Backend: mylog.Backend{}, Convert: mylog.FromLogstox})
+//	}
+//
+// It covers honoring Options (Writer, Level, Name, Fields), level names (notices included) and filtering, Enabled
+// agreeing with what's written, encoding every FieldKind, With/ Named semantics, gating Lazy fields on the level,
+// what Limits.MaxFields counts, and Sync. Fatal isn't exercised since it exits.
+func Run[FT any](t *testing.T, s Subject[FT]) {
+	t.Helper()
+	if s.Backend == nil || s.Convert == nil {
+		t.Fatal("backendtest: Subject needs a Backend and a Convert func")
+	}
+	s.MessageKey = cmp.Or(s.MessageKey, "msg")
+	s.LevelKey = cmp.Or(s.LevelKey, "level")
+	s.NameKey = cmp.Or(s.NameKey, "logger")
+	if s.LevelName == nil {
+		s.LevelName = logstox.Level.String
+	}
+
+	t.Run("Writer", s.testWriter)
+	t.Run("Levels", s.testLevels)
+	t.Run("LevelFiltering", s.testLevelFiltering)
+	t.Run("LevelCheck", s.testLevelCheck)
+	t.Run("FieldKinds", s.testFieldKinds)
+	t.Run("With", s.testWith)
+	t.Run("Named", s.testNamed)
+	t.Run("OptionsFields", s.testOptionsFields)
+	t.Run("LazyGating", s.testLazyGating)
+	t.Run("MaxFields", s.testMaxFields)
+	t.Run("Sync", s.testSync)
+}
+
+// newLogger builds a logger writing to a fresh buffer, and the func decoding what it wrote so far.
+func (s Subject[FT]) newLogger(t *testing.T, o logstox.Options[FT]) (logstox.Logger[FT], func() []map[string]any) {
+	var buf bytes.Buffer
+	o.Writer = &buf
+	l := s.Backend.New(o)
+	return l, func() []map[string]any {
+		t.Helper()
+		output, decode := buf.Bytes(), s.Decode
+		if s.Recorded != nil {
+			output, decode = canonical(t, s.Recorded(l)), CanonicalJSON
+		}
+		if decode == nil {
+			decode = NDJSON
+		}
+		es, err := decode(output)
+		if err != nil {
+			t.Fatalf("decoding output: %v\n%s", err, output)
+		}
+		return es
+	}
+}
+
+// canonical returns the canonical JSON of es, one entry per line.
+func canonical(t *testing.T, es []logstox.Entry) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	for _, e := range es {
+		b, err := e.MarshalJSON()
+		if err != nil {
+			t.Fatalf("encoding recorded entry %q: %v", e.Message, err)
+		}
+		buf.Write(b)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes()
+}
+
+// conv converts fs with s.Convert.
+func (s Subject[FT]) conv(fs ...fields.Field) []FT {
+	out := make([]FT, len(fs))
+	for i, f := range fs {
+		out[i] = s.Convert(f)
+	}
+	return out
+}
+
+func (s Subject[FT]) testWriter(t *testing.T) {
+	l, output := s.newLogger(t, logstox.Options[FT]{})
+	l.Info("hello")
+	_ = l.Sync()
+
+	es := output()
+	if len(es) != 1 {
+		t.Fatalf("got %d entries written to Options.Writer, want 1", len(es))
+	}
+	if got := es[0][s.MessageKey]; got != "hello" {
+		t.Errorf("%s = %v, want %q", s.MessageKey, got, "hello")
+	}
+}
+
+func (s Subject[FT]) testLevels(t *testing.T) {
+	l, output := s.newLogger(t, logstox.Options[FT]{Level: logstox.DebugLevel})
+	l.Debug("m")
+	l.Info("m")
+	logstox.NoticerOf(l).Notice("m")
+	l.Warn("m")
+	l.Error("m")
+	l.DPanic("m") // Options carry no development flag, so it must not panic
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("Panic didn't panic")
+			}
+		}()
+		l.Panic("m")
+	}()
+
+	want := []logstox.Level{
+		logstox.DebugLevel, logstox.InfoLevel, logstox.NoticeLevel, logstox.WarnLevel, logstox.ErrorLevel,
+		logstox.DPanicLevel, logstox.PanicLevel,
+	}
+	es := output()
+	if len(es) != len(want) {
+		t.Fatalf("got %d entries, want %d", len(es), len(want))
+	}
+	for i, lvl := range want {
+		if got, want := es[i][s.LevelKey], s.LevelName(lvl); got != want {
+			t.Errorf("entry %d: %s = %v, want %q", i, s.LevelKey, got, want)
+		}
+	}
+	// backends without a notice level of their own must tag notices so they can be told from Info entries
+	tagged := s.LevelName(logstox.NoticeLevel) == s.LevelName(logstox.InfoLevel)
+	if notice := es[2]; tagged && notice[logstox.NoticeKey] != true {
+		t.Errorf("notice written as %v, want it tagged with %s", notice, logstox.NoticeKey)
+	}
+}
+
+func (s Subject[FT]) testLevelFiltering(t *testing.T) {
+	l, output := s.newLogger(t, logstox.Options[FT]{Level: logstox.WarnLevel})
+	l.Debug("debug")
+	l.Info("info")
+	l.Warn("warn")
+	l.Error("error")
+
+	var got []any
+	for _, e := range output() {
+		got = append(got, e[s.MessageKey])
+	}
+	if want := []any{"warn", "error"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got messages %v at Level warn, want %v", got, want)
+	}
+
+	if !logstox.Enabled(l, logstox.WarnLevel) {
+		t.Error("Enabled(warn) = false at Level warn")
+	}
+	if _, ok := l.(logstox.LevelCheck); ok && logstox.Enabled(l, logstox.InfoLevel) {
+		t.Error("Enabled(info) = true at Level warn")
+	}
+}
+
+// testLevelCheck checks that Enabled agrees with what's written at every minimum level, for loggers implementing
+// logstox.LevelCheck.
+func (s Subject[FT]) testLevelCheck(t *testing.T) {
+	levels := []logstox.Level{
+		logstox.DebugLevel, logstox.InfoLevel, logstox.NoticeLevel, logstox.WarnLevel, logstox.ErrorLevel,
+		logstox.DPanicLevel, logstox.PanicLevel,
+	}
+	for _, min := range levels {
+		l, output := s.newLogger(t, logstox.Options[FT]{Level: min})
+		if _, ok := l.(logstox.LevelCheck); !ok {
+			t.Skip("the logger doesn't implement logstox.LevelCheck")
+		}
+		var enabled []any
+		for _, lvl := range levels {
+			if logstox.Enabled(l, lvl) {
+				enabled = append(enabled, lvl.String())
+			}
+			logAt(l, lvl, lvl.String())
+		}
+		var written []any
+		for _, e := range output() {
+			written = append(written, e[s.MessageKey])
+		}
+		if !reflect.DeepEqual(written, enabled) {
+			t.Errorf("at Level %v, wrote %v but Enabled reports %v", min, written, enabled)
+		}
+	}
+}
+
+// logAt logs msg at lvl, recovering from Panic.
+func logAt[FT any](l logstox.Logger[FT], lvl logstox.Level, msg string) {
+	defer func() { _ = recover() }()
+	logstox.LogAt(l, lvl, msg)
+}
+
+// ctxKey is the context key testFieldKinds binds a value under for LazyFields.
+type ctxKey struct{}
+
+// check validates a decoded value.
+type check func(v any) bool
+
+func equals(want any) check {
+	return func(v any) bool { return reflect.DeepEqual(v, want) }
+}
+
+func present(any) bool { return true }
+
+func arrayOf(n int) check {
+	return func(v any) bool {
+		a, ok := v.([]any)
+		return ok && len(a) == n
+	}
+}
+
+func object(want map[string]any) check {
+	return func(v any) bool {
+		m, ok := v.(map[string]any)
+		if !ok {
+			return false
+		}
+		for k, wv := range want {
+			if !reflect.DeepEqual(m[k], wv) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+func (s Subject[FT]) testFieldKinds(t *testing.T) {
+	l, output := s.newLogger(t, logstox.Options[FT]{})
+	// LazyFields get the context the logger is bound to, when the logger is the one converting them
+	l = logstox.WithContext(l, context.WithValue(context.Background(), ctxKey{}, "bound"))
+	bound := present
+	if _, ok := any(l).(logstox.Logger[fields.Field]); ok {
+		bound = equals("bound")
+	}
+
+	cases := []struct {
+		field fields.Field
+		key   string
+		check check
+	}{
+		{fields.String("string", "v"), "string", equals("v")},
+		{fields.Bool("bool", true), "bool", equals(true)},
+		{fields.Int64("int64", -3), "int64", equals(json.Number("-3"))},
+		{fields.Uint64("uint64", 3), "uint64", equals(json.Number("3"))},
+		{fields.Float64("float64", 1.5), "float64", equals(json.Number("1.5"))},
+		{fields.TimeField("time_field", time.Unix(0, 0)), "time_field", present},
+		{fields.Duration("duration", time.Second), "duration", present},
+		{fields.NamedError("error", errors.New("boom")), "error", equals("boom")},
+		{fields.Strings("strings", []string{"a", "b"}), "strings", equals([]any{"a", "b"})},
+		{fields.Bools("bools", []bool{true, false}), "bools", equals([]any{true, false})},
+		{fields.Int64s("int64s", []int64{1, -1}), "int64s", arrayOf(2)},
+		{fields.Uint64s("uint64s", []uint64{1, 2}), "uint64s", arrayOf(2)},
+		{fields.Float64s("float64s", []float64{1, 2.5}), "float64s", arrayOf(2)},
+		{fields.Errors("errors", []error{errors.New("a"), errors.New("b")}), "errors", arrayOf(2)},
+		{fields.Dict("dict", fields.String("k", "v")), "dict", object(map[string]any{"k": "v"})},
+		{fields.RawJSON("raw", []byte(`{"a":1}`)), "raw", object(map[string]any{"a": json.Number("1")})},
+		{fields.Hex("hex", []byte{0x0a, 0xff}), "hex", equals("0aff")},
+		{fields.Any("any", map[string]int{"n": 1}), "any", object(map[string]any{"n": json.Number("1")})},
+		{fields.Lazy(func() []fields.Field { return []fields.Field{fields.String("lazy", "v")} }), "lazy", equals("v")},
+		{fields.LazyDict("lazy_dict", func() []fields.Field { return []fields.Field{fields.String("k", "v")} }),
+			"lazy_dict", object(map[string]any{"k": "v"})},
+		{fields.LazyFields(func(ctx context.Context) []fields.Field {
+			v, _ := ctx.Value(ctxKey{}).(string)
+			return []fields.Field{fields.String("lazy_fields", v)}
+		}), "lazy_fields", bound},
+		{fields.Timestamp(time.Time{}), fields.TimestampKey, present},
+		{fields.TimestampAt("timestamp_at", time.Unix(0, 0)), "timestamp_at", present},
+		{fields.Precompute(fields.String("precomputed", "v")), "precomputed", equals("v")},
+	}
+
+	fs := make([]fields.Field, 0, len(cases)+1)
+	for _, c := range cases {
+		fs = append(fs, c.field)
+	}
+	fs = append(fs, fields.Nop())
+	l.Info("kinds", s.conv(fs...)...)
+
+	es := output()
+	if len(es) != 1 {
+		t.Fatalf("got %d entries, want 1", len(es))
+	}
+	e := es[0]
+	for _, c := range cases {
+		v, ok := e[c.key]
+		switch {
+		case !ok:
+			t.Errorf("%s: key %q missing", c.field.Kind(), c.key)
+		case !c.check(v):
+			t.Errorf("%s: %q = %#v", c.field.Kind(), c.key, v)
+		}
+	}
+	if _, ok := e[""]; ok {
+		t.Error("Nop field was written")
+	}
+}
+
+func (s Subject[FT]) testWith(t *testing.T) {
+	l, output := s.newLogger(t, logstox.Options[FT]{})
+	parent := l.With(s.conv(fields.String("parent", "p"))...)
+	a := parent.With(s.conv(fields.String("a", "a"))...)
+	b := parent.With(s.conv(fields.String("b", "b"))...)
+	parent.Info("parent")
+	a.Info("a")
+	b.Info("b")
+
+	es := output()
+	if len(es) != 3 {
+		t.Fatalf("got %d entries, want 3", len(es))
+	}
+	want := []struct{ has, hasNot []string }{
+		{[]string{"parent"}, []string{"a", "b"}},
+		{[]string{"parent", "a"}, []string{"b"}},
+		{[]string{"parent", "b"}, []string{"a"}},
+	}
+	for i, w := range want {
+		for _, k := range w.has {
+			if _, ok := es[i][k]; !ok {
+				t.Errorf("%v: context key %q missing", es[i][s.MessageKey], k)
+			}
+		}
+		for _, k := range w.hasNot {
+			if _, ok := es[i][k]; ok {
+				t.Errorf("%v: has sibling/child context key %q", es[i][s.MessageKey], k)
+			}
+		}
+	}
+}
+
+func (s Subject[FT]) testNamed(t *testing.T) {
+	l, output := s.newLogger(t, logstox.Options[FT]{Name: "root"})
+	l.Info("root")
+	l.Named("a").Named("b").Info("child")
+
+	es := output()
+	if len(es) != 2 {
+		t.Fatalf("got %d entries, want 2", len(es))
+	}
+	if got := es[0][s.NameKey]; got != "root" {
+		t.Errorf("Options.Name: %s = %v, want %q", s.NameKey, got, "root")
+	}
+	if got := es[1][s.NameKey]; got != "root.a.b" {
+		t.Errorf("Named: %s = %v, want %q", s.NameKey, got, "root.a.b")
+	}
+}
+
+func (s Subject[FT]) testOptionsFields(t *testing.T) {
+	l, output := s.newLogger(t, logstox.Options[FT]{Fields: s.conv(fields.String("service", "svc"))})
+	l.Info("m")
+
+	es := output()
+	if len(es) != 1 {
+		t.Fatalf("got %d entries, want 1", len(es))
+	}
+	if got := es[0]["service"]; got != "svc" {
+		t.Errorf("service = %v, want %q", got, "svc")
+	}
+}
+
+func (s Subject[FT]) testLazyGating(t *testing.T) {
+	var calls int
+	lazy := fields.Lazy(func() []fields.Field {
+		calls++
+		return []fields.Field{fields.Int("calls", calls)}
+	})
+
+	l, output := s.newLogger(t, logstox.Options[FT]{Level: logstox.InfoLevel})
+	l.Debug("disabled", s.conv(lazy)...)
+	child := l.With(s.conv(lazy)...)
+	child.Debug("disabled")
+	if calls != 0 {
+		t.Fatalf("Lazy evaluated %d times for disabled entries, want 0", calls)
+	}
+
+	l.Info("enabled", s.conv(lazy)...)
+	if calls != 1 {
+		t.Fatalf("Lazy evaluated %d times for an enabled entry, want 1", calls)
+	}
+	// whether lazy context is evaluated per entry or once (as zap's WithLazy does) is up to the backend
+	child.Info("enabled")
+	child.Info("enabled")
+	if calls == 1 {
+		t.Error("Lazy context wasn't evaluated for enabled entries")
+	}
+	if es := output(); len(es) != 3 {
+		t.Errorf("got %d entries, want 3", len(es))
+	}
+}
+
+// testMaxFields checks that Limits.MaxFields counts the call-site and extracted fields of an entry, but not its
+// context fields, for backends honoring it.
+func (s Subject[FT]) testMaxFields(t *testing.T) {
+	l, output := s.newLogger(t, logstox.Options[FT]{
+		Fields: s.conv(fields.String("options", "o")),
+		Limits: logstox.Limits{MaxFields: 2},
+		ContextExtractors: []func(context.Context) []FT{
+			func(context.Context) []FT { return s.conv(fields.String("extracted", "e")) },
+		},
+	})
+	l = logstox.WithContext(l.With(s.conv(fields.String("with", "w"))...), context.Background())
+	l.Info("m", s.conv(fields.String("a", "a"), fields.String("b", "b"))...)
+
+	es := output()
+	if len(es) != 1 {
+		t.Fatalf("got %d entries, want 1", len(es))
+	}
+	e := es[0]
+	if _, ok := e[logstox.OverflowCountKey]; !ok && e["b"] != nil {
+		t.Skip("the backend doesn't honor Options.Limits")
+	}
+	for _, k := range []string{"options", "with", "extracted", "a"} {
+		if _, ok := e[k]; !ok {
+			t.Errorf("%q was dropped, want context fields not counted and the first fields kept", k)
+		}
+	}
+	if _, ok := e["b"]; ok {
+		t.Error(`"b" was kept past MaxFields`)
+	}
+	if got := e[logstox.OverflowCountKey]; got != json.Number("1") {
+		t.Errorf("%s = %v, want 1", logstox.OverflowCountKey, got)
+	}
+}
+
+func (s Subject[FT]) testSync(t *testing.T) {
+	l, output := s.newLogger(t, logstox.Options[FT]{})
+	l.Info("m")
+	if err := l.Sync(); err != nil {
+		t.Errorf("Sync() = %v", err)
+	}
+	if len(output()) == 0 {
+		t.Error("nothing written by Sync")
+	}
+}
+
+// NDJSON decodes output as one JSON object per line, keeping numbers as json.Number. It's the default Decode.
+func NDJSON(output []byte) ([]map[string]any, error) {
+	var out []map[string]any
+	for _, line := range bytes.Split(output, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var e map[string]any
+		dec := json.NewDecoder(bytes.NewReader(line))
+		dec.UseNumber()
+		if err := dec.Decode(&e); err != nil {
+			return nil, fmt.Errorf("output isn't NDJSON: %w", err)
+		}
+		out = append(out, e)
+	}
+	return out, nil
+}
+
+// CanonicalJSON decodes output written in the canonical JSON of logstox.Entry.MarshalJSON, one entry per line, eg by
+// streamx, flattening every entry: "msg", "level" and "name" stay as they are, and fields become top-level keys,
+// Dicts nested objects, with their values as written. Use NameKey "name" with it.
+func CanonicalJSON(output []byte) ([]map[string]any, error) {
+	es, err := NDJSON(output)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range es {
+		fs, _ := e["fields"].([]any)
+		delete(e, "fields")
+		if err := flatten(e, fs); err != nil {
+			return nil, err
+		}
+	}
+	return es, nil
+}
+
+// flatten adds the canonical JSON fields fs to dst, keyed by their keys.
+func flatten(dst map[string]any, fs []any) error {
+	for _, f := range fs {
+		wf, ok := f.(map[string]any)
+		if !ok {
+			return fmt.Errorf("field %v isn't an object", f)
+		}
+		k, _ := wf["k"].(string)
+		v := wf["v"]
+		if wf["t"] == fields.FieldKindDict.String() {
+			sub, _ := v.([]any)
+			m := make(map[string]any, len(sub))
+			if err := flatten(m, sub); err != nil {
+				return err
+			}
+			v = m
+		}
+		dst[k] = v
+	}
+	return nil
+}
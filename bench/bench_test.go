@@ -0,0 +1,144 @@
+// Package bench runs standardized logging workloads against every first-party backend, so performance regressions
+// across the field mapping layers are caught:
+//
+//	go test -bench . -benchmem ./bench
+package bench
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/khinshankhan/logstox"
+	"github.com/khinshankhan/logstox/backend/memx"
+	"github.com/khinshankhan/logstox/backend/streamx"
+	"github.com/khinshankhan/logstox/backend/zapx"
+	"github.com/khinshankhan/logstox/fields"
+)
+
+// backend is a logger factory under benchmark. New must build a logger writing to w at minimum level lvl.
+type backend struct {
+	name string
+	new  func(w io.Writer, lvl logstox.Level) logstox.Logger[fields.Field]
+}
+
+// backends are the backends every scenario runs against.
+var backends = []backend{
+	{
+		name: "stream",
+		new: func(w io.Writer, lvl logstox.Level) logstox.Logger[fields.Field] {
+			return streamx.Backend{}.New(logstox.Options[fields.Field]{Writer: w, Level: lvl})
+		},
+	},
+	{
+		name: "zap",
+		new: func(w io.Writer, lvl logstox.Level) logstox.Logger[fields.Field] {
+			return zapx.Adapt(zapx.Backend{}.New(logstox.Options[zapx.ZapField]{Writer: w, Level: lvl}))
+		},
+	},
+	{
+		name: "mem",
+		new: func(_ io.Writer, lvl logstox.Level) logstox.Logger[fields.Field] {
+			return memx.Backend{Recorder: memx.NewRecorder(64)}.New(logstox.Options[fields.Field]{Level: lvl})
+		},
+	},
+}
+
+// scenario is a standardized logging workload. run logs once per call.
+type scenario struct {
+	name string
+	// level is the minimum level the logger is built with.
+	level logstox.Level
+	// setup, if set, derives the logger used by run (eg adding context).
+	setup func(logstox.Logger[fields.Field]) logstox.Logger[fields.Field]
+	run   func(logstox.Logger[fields.Field])
+}
+
+var errBench = errors.New("fail")
+
+// tenFields are ten scalar fields of mixed kinds.
+func tenFields() []fields.Field {
+	return []fields.Field{
+		fields.String("string", "value"),
+		fields.Int("int", 42),
+		fields.Int64("int64", 1<<40),
+		fields.Uint64("uint64", 7),
+		fields.Float64("float64", 3.14),
+		fields.Bool("bool", true),
+		fields.Duration("duration", time.Second),
+		fields.TimeField("time", time.Unix(1700000000, 0)),
+		fields.Error(errBench),
+		fields.String("another", "value"),
+	}
+}
+
+// scenarios are the standard workloads.
+var scenarios = []scenario{
+	{
+		name:  "no fields",
+		level: logstox.InfoLevel,
+		run:   func(l logstox.Logger[fields.Field]) { l.Info("message") },
+	},
+	{
+		name:  "10 scalar fields",
+		level: logstox.InfoLevel,
+		run:   func(l logstox.Logger[fields.Field]) { l.Info("message", tenFields()...) },
+	},
+	{
+		name:  "10 context fields",
+		level: logstox.InfoLevel,
+		setup: func(l logstox.Logger[fields.Field]) logstox.Logger[fields.Field] { return l.With(tenFields()...) },
+		run:   func(l logstox.Logger[fields.Field]) { l.Info("message") },
+	},
+	{
+		name:  "nested dicts",
+		level: logstox.InfoLevel,
+		run: func(l logstox.Logger[fields.Field]) {
+			l.Info("message", fields.Dict("outer",
+				fields.String("a", "b"),
+				fields.Dict("inner", fields.Int("x", 1), fields.Strings("tags", []string{"a", "b"})),
+			))
+		},
+	},
+	{
+		name:  "lazy fields",
+		level: logstox.InfoLevel,
+		run: func(l logstox.Logger[fields.Field]) {
+			l.Info("message", fields.LazyFields(func(context.Context) []fields.Field { return tenFields() }))
+		},
+	},
+	{
+		name:  "disabled level",
+		level: logstox.InfoLevel,
+		run:   func(l logstox.Logger[fields.Field]) { l.Debug("message", tenFields()...) },
+	},
+	{
+		name:  "disabled lazy fields",
+		level: logstox.InfoLevel,
+		run: func(l logstox.Logger[fields.Field]) {
+			l.Debug("message", fields.LazyFields(func(context.Context) []fields.Field { return tenFields() }))
+		},
+	},
+}
+
+// BenchmarkBackends runs every scenario against every backend, as backend/scenario, writing output to io.Discard.
+func BenchmarkBackends(b *testing.B) {
+	for _, bk := range backends {
+		b.Run(bk.name, func(b *testing.B) {
+			for _, s := range scenarios {
+				b.Run(s.name, func(b *testing.B) {
+					l := bk.new(io.Discard, s.level)
+					if s.setup != nil {
+						l = s.setup(l)
+					}
+					b.ReportAllocs()
+					for b.Loop() {
+						s.run(l)
+					}
+				})
+			}
+		})
+	}
+}
@@ -0,0 +1,18 @@
+module github.com/khinshankhan/logstox/bench
+
+go 1.24.2
+
+require (
+	github.com/khinshankhan/logstox v0.0.0-20250914151607-81d0c77772ce
+	github.com/khinshankhan/logstox/backend/zapx v0.0.0-20250914151607-81d0c77772ce
+)
+
+require (
+	go.uber.org/multierr v1.11.0 // indirect
+	go.uber.org/zap v1.27.0 // indirect
+)
+
+replace (
+	github.com/khinshankhan/logstox => ..
+	github.com/khinshankhan/logstox/backend/zapx => ../backend/zapx
+)
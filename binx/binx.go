@@ -0,0 +1,523 @@
+package binx
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	"github.com/khinshankhan/logstox"
+	"github.com/khinshankhan/logstox/backend/streamx"
+	"github.com/khinshankhan/logstox/fields"
+)
+
+// Limits on what a decoder accepts, so corrupt input can't make it allocate unbounded memory or recurse without end.
+const (
+	MaxItemSize = 16 << 20 // bytes of a string or binary item, or items of an array or map
+	MaxDepth    = 64       // nesting of arrays and maps
+)
+
+// Format is a binary encoding of entries, for bandwidth-sensitive deployments that re-expand entries to JSON
+// server-side. Formats are encoders for streamx.Backend:
+//
+//	log := streamx.Backend{Encoder: binx.MsgPack}.New(logstox.Options[fields.Field]{Writer: conn})
+//
+// and entries are read back with a Reader, then eg re-encoded with json.Marshal (see logstox.Entry.MarshalJSON).
+//
+// Entries follow the canonical JSON schema (same keys, same version, see logstox.EntrySchemaVersion) with values in
+// the format's own types: levels and durations are integers, times are native timestamps, hex bytes are binary, each
+// field is a [key, kind, value] array (value left out for a nil error or a "now" timestamp), and the values of
+// fields.Any fields are their encoding/json encoding. Entries are self-delimiting, so streams need no framing.
+type Format struct {
+	name string
+	c    codec
+}
+
+// Formats.
+var (
+	// MsgPack encodes entries as MessagePack, times using the timestamp extension type.
+	MsgPack = Format{name: "msgpack", c: msgpack{}}
+	// CBOR encodes entries as CBOR (RFC 8949), times as RFC 3339 strings tagged as such.
+	CBOR = Format{name: "cbor", c: cbor{}}
+)
+
+// Interface satisfaction (compile-time assertions).
+var _ streamx.Encoder = Format{}
+
+// codec writes and reads the items of a format. Decoded items are nil, bool, int64 (negative integers), uint64,
+// float64, string, []byte, time.Time, []any and map[string]any.
+type codec interface {
+	appendArray(dst []byte, n int) []byte
+	appendMap(dst []byte, n int) []byte
+	appendString(dst []byte, s string) []byte
+	appendBinary(dst []byte, b []byte) []byte
+	appendInt(dst []byte, v int64) []byte
+	appendUint(dst []byte, v uint64) []byte
+	appendFloat(dst []byte, v float64) []byte
+	appendBool(dst []byte, v bool) []byte
+	appendNil(dst []byte) []byte
+	appendTime(dst []byte, t time.Time) []byte
+
+	// mapLen reads the header of a map, returning its number of pairs.
+	mapLen(r byteReader) (int, error)
+	// decode reads an item, depth being the nesting it's found at.
+	decode(r byteReader, depth int) (any, error)
+}
+
+// byteReader is what codecs decode from.
+type byteReader interface {
+	io.Reader
+	io.ByteReader
+}
+
+// String returns the format's name.
+func (f Format) String() string { return f.name }
+
+// AppendEntry appends e to dst. Lazy fields are evaluated (handed e.Context, or context.Background if it's nil) and
+// precomputed bundles expanded; the Context itself isn't written.
+func (f Format) AppendEntry(dst []byte, e logstox.Entry) ([]byte, error) {
+	ctx := e.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	fs := fields.Resolve(ctx, nil, e.Fields)
+
+	n := 3 // level, v and msg
+	if !e.Time.IsZero() {
+		n++
+	}
+	if e.Name != "" {
+		n++
+	}
+	if len(fs) > 0 {
+		n++
+	}
+	// level first, so Level finds it right away
+	dst = f.c.appendMap(dst, n)
+	dst = f.c.appendInt(f.c.appendString(dst, "level"), int64(e.Level))
+	dst = f.c.appendInt(f.c.appendString(dst, "v"), logstox.EntrySchemaVersion)
+	if !e.Time.IsZero() {
+		dst = f.c.appendTime(f.c.appendString(dst, "time"), e.Time)
+	}
+	if e.Name != "" {
+		dst = f.c.appendString(f.c.appendString(dst, "name"), e.Name)
+	}
+	dst = f.c.appendString(f.c.appendString(dst, "msg"), e.Message)
+	if len(fs) > 0 {
+		return f.appendFields(ctx, f.c.appendString(dst, "fields"), fs)
+	}
+	return dst, nil
+}
+
+// Marshal returns e encoded as by AppendEntry.
+func (f Format) Marshal(e logstox.Entry) ([]byte, error) {
+	return f.AppendEntry(nil, e)
+}
+
+// Unmarshal decodes an entry written by AppendEntry.
+func (f Format) Unmarshal(b []byte) (logstox.Entry, error) {
+	v, err := f.c.decode(bytes.NewReader(b), 0)
+	if err != nil {
+		return logstox.Entry{}, err
+	}
+	return entryOf(v)
+}
+
+// Level returns the level of an encoded entry, InfoLevel if it can't be read. It's meant for asyncx.Options.Level, so
+// asyncx.DropDebugFirst works with binary entries.
+func (f Format) Level(entry []byte) logstox.Level {
+	r := bytes.NewReader(entry)
+	n, err := f.c.mapLen(r)
+	if err != nil || n == 0 {
+		return logstox.InfoLevel
+	}
+	if k, err := f.c.decode(r, 1); err != nil || k != "level" {
+		return logstox.InfoLevel
+	}
+	v, err := f.c.decode(r, 1)
+	if err != nil {
+		return logstox.InfoLevel
+	}
+	lvl, ok := asInt(v)
+	if !ok || lvl < math.MinInt8 || lvl > math.MaxInt8 {
+		return logstox.InfoLevel
+	}
+	return logstox.Level(lvl)
+}
+
+// Reader reads entries from a stream written by a Format.
+type Reader struct {
+	r *bufio.Reader
+	f Format
+}
+
+// NewReader returns a Reader reading entries encoded with f from r.
+func NewReader(r io.Reader, f Format) *Reader {
+	return &Reader{r: bufio.NewReader(r), f: f}
+}
+
+// Read returns the next entry. It returns io.EOF at the end of the stream, and io.ErrUnexpectedEOF if the stream ends
+// in the middle of an entry.
+func (r *Reader) Read() (logstox.Entry, error) {
+	if _, err := r.r.Peek(1); err != nil {
+		return logstox.Entry{}, err
+	}
+	v, err := r.f.c.decode(r.r, 0)
+	if err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return logstox.Entry{}, err
+	}
+	return entryOf(v)
+}
+
+// appendFields appends fs as an array of fields, evaluating lazy fields with ctx and expanding bundles.
+func (f Format) appendFields(ctx context.Context, dst []byte, fs []fields.Field) ([]byte, error) {
+	fs = fields.Resolve(ctx, nil, fs)
+	dst = f.c.appendArray(dst, len(fs))
+	for _, fl := range fs {
+		var err error
+		if dst, err = f.appendField(ctx, dst, fl); err != nil {
+			return dst, fmt.Errorf("%s: marshaling field %q: %w", f.name, fl.Key, err)
+		}
+	}
+	return dst, nil
+}
+
+// appendField appends fl as a [key, kind, value] array, fl holding a plain value.
+func (f Format) appendField(ctx context.Context, dst []byte, fl fields.Field) ([]byte, error) {
+	c := f.c
+	n := 3
+	if fl.Kind() == fields.FieldKindError && fl.Err() == nil || fl.Kind() == fields.FieldKindTimestamp && fl.Time().IsZero() {
+		n = 2
+	}
+	dst = c.appendArray(dst, n)
+	dst = c.appendString(dst, fl.Key)
+	dst = c.appendString(dst, fl.Kind().String())
+	if n == 2 {
+		return dst, nil
+	}
+
+	switch fl.Kind() {
+	case fields.FieldKindString:
+		dst = c.appendString(dst, fl.Str())
+	case fields.FieldKindBool:
+		dst = c.appendBool(dst, fl.Bool())
+	case fields.FieldKindInt64:
+		dst = c.appendInt(dst, fl.Int64())
+	case fields.FieldKindUint64:
+		dst = c.appendUint(dst, fl.Uint64())
+	case fields.FieldKindFloat64:
+		dst = c.appendFloat(dst, fl.Float64())
+	case fields.FieldKindTime, fields.FieldKindTimestamp:
+		dst = c.appendTime(dst, fl.Time())
+	case fields.FieldKindDuration:
+		dst = c.appendInt(dst, int64(fl.Duration()))
+	case fields.FieldKindError:
+		dst = c.appendString(dst, fl.Err().Error())
+	case fields.FieldKindStrings:
+		vs := fl.Interface().([]string)
+		dst = c.appendArray(dst, len(vs))
+		for _, v := range vs {
+			dst = c.appendString(dst, v)
+		}
+	case fields.FieldKindBools:
+		vs := fl.Interface().([]bool)
+		dst = c.appendArray(dst, len(vs))
+		for _, v := range vs {
+			dst = c.appendBool(dst, v)
+		}
+	case fields.FieldKindInt64s:
+		vs := fl.Interface().([]int64)
+		dst = c.appendArray(dst, len(vs))
+		for _, v := range vs {
+			dst = c.appendInt(dst, v)
+		}
+	case fields.FieldKindUint64s:
+		vs := fl.Interface().([]uint64)
+		dst = c.appendArray(dst, len(vs))
+		for _, v := range vs {
+			dst = c.appendUint(dst, v)
+		}
+	case fields.FieldKindFloat64s:
+		vs := fl.Interface().([]float64)
+		dst = c.appendArray(dst, len(vs))
+		for _, v := range vs {
+			dst = c.appendFloat(dst, v)
+		}
+	case fields.FieldKindErrors:
+		errs := fl.Interface().([]error)
+		dst = c.appendArray(dst, len(errs))
+		for _, err := range errs {
+			if err == nil {
+				dst = c.appendNil(dst)
+			} else {
+				dst = c.appendString(dst, err.Error())
+			}
+		}
+	case fields.FieldKindDict:
+		return f.appendFields(ctx, dst, fl.Fields())
+	case fields.FieldKindRawJSON:
+		dst = c.appendString(dst, string(fl.Bytes()))
+	case fields.FieldKindHexBytes:
+		dst = c.appendBinary(dst, fl.Bytes())
+	default:
+		b, err := json.Marshal(fl.Boxed())
+		if err != nil {
+			return dst, err
+		}
+		dst = c.appendString(dst, string(b))
+	}
+	return dst, nil
+}
+
+// entryOf converts a decoded entry.
+func entryOf(v any) (logstox.Entry, error) {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return logstox.Entry{}, fmt.Errorf("binx: entry is a %T, not a map", v)
+	}
+	if version, _ := asInt(m["v"]); version < 1 || version > logstox.EntrySchemaVersion {
+		return logstox.Entry{}, fmt.Errorf("binx: unsupported entry schema version %v", m["v"])
+	}
+
+	var e logstox.Entry
+	if lvl, ok := asInt(m["level"]); ok && lvl >= math.MinInt8 && lvl <= math.MaxInt8 {
+		e.Level = logstox.Level(lvl)
+	} else if m["level"] != nil {
+		return logstox.Entry{}, fmt.Errorf("binx: invalid level %v", m["level"])
+	}
+	e.Time, _ = m["time"].(time.Time)
+	e.Name, _ = m["name"].(string)
+	e.Message, _ = m["msg"].(string)
+	if m["fields"] != nil {
+		var err error
+		if e.Fields, err = fieldsOf(m["fields"]); err != nil {
+			return logstox.Entry{}, err
+		}
+	}
+	return e, nil
+}
+
+// fieldsOf converts a decoded array of fields.
+func fieldsOf(v any) ([]fields.Field, error) {
+	items, ok := v.([]any)
+	if !ok {
+		return nil, fmt.Errorf("binx: fields are a %T, not an array", v)
+	}
+	fs := make([]fields.Field, 0, len(items))
+	for _, item := range items {
+		f, err := fieldOf(item)
+		if err != nil {
+			return nil, err
+		}
+		fs = append(fs, f)
+	}
+	return fs, nil
+}
+
+// fieldOf converts a decoded [key, kind, value] array.
+func fieldOf(item any) (fields.Field, error) {
+	a, ok := item.([]any)
+	if !ok || len(a) < 2 || len(a) > 3 {
+		return fields.Field{}, fmt.Errorf("binx: field is a %T, not a [key, kind, value] array", item)
+	}
+	k, _ := a[0].(string)
+	kind, _ := a[1].(string)
+	var v any
+	if len(a) == 3 {
+		v = a[2]
+	}
+
+	f, ok := field(k, kind, v)
+	if !ok {
+		return fields.Field{}, fmt.Errorf("binx: invalid value for field %q of kind %q", k, kind)
+	}
+	return f, nil
+}
+
+// field builds the field k of the given kind from its decoded value, reporting whether v suits the kind.
+func field(k, kind string, v any) (fields.Field, bool) {
+	switch kind {
+	case "string":
+		s, ok := v.(string)
+		return fields.String(k, s), ok
+	case "bool":
+		b, ok := v.(bool)
+		return fields.Bool(k, b), ok
+	case "int64":
+		n, ok := asInt(v)
+		return fields.Int64(k, n), ok
+	case "uint64":
+		n, ok := v.(uint64)
+		return fields.Uint64(k, n), ok
+	case "float64":
+		x, ok := asFloat(v)
+		return fields.Float64(k, x), ok
+	case "time":
+		t, ok := v.(time.Time)
+		return fields.TimeField(k, t), ok
+	case "timestamp":
+		t, ok := v.(time.Time)
+		return fields.TimestampAt(k, t), ok || v == nil
+	case "duration":
+		n, ok := asInt(v)
+		return fields.Duration(k, time.Duration(n)), ok
+	case "error":
+		if v == nil {
+			return fields.Nop(), true
+		}
+		s, ok := v.(string)
+		return fields.NamedError(k, errors.New(s)), ok
+	case "strings":
+		vs, ok := each(v, func(v any) (string, bool) { s, ok := v.(string); return s, ok })
+		return fields.Strings(k, vs), ok
+	case "bools":
+		vs, ok := each(v, func(v any) (bool, bool) { b, ok := v.(bool); return b, ok })
+		return fields.Bools(k, vs), ok
+	case "int64s":
+		vs, ok := each(v, asInt)
+		return fields.Int64s(k, vs), ok
+	case "uint64s":
+		vs, ok := each(v, func(v any) (uint64, bool) { n, ok := v.(uint64); return n, ok })
+		return fields.Uint64s(k, vs), ok
+	case "float64s":
+		vs, ok := each(v, asFloat)
+		return fields.Float64s(k, vs), ok
+	case "errors":
+		vs, ok := each(v, func(v any) (error, bool) {
+			if v == nil {
+				return nil, true
+			}
+			s, ok := v.(string)
+			return errors.New(s), ok
+		})
+		return fields.Errors(k, vs), ok
+	case "dict":
+		fs, err := fieldsOf(v)
+		return fields.Dict(k, fs...), err == nil
+	case "rawjson":
+		s, ok := v.(string)
+		return fields.RawJSON(k, []byte(s)), ok
+	case "hexbytes":
+		b, ok := v.([]byte)
+		return fields.Hex(k, b), ok
+	case "any":
+		s, ok := v.(string)
+		var value any
+		if ok && json.Unmarshal([]byte(s), &value) != nil {
+			ok = false
+		}
+		return fields.Any(k, value), ok
+	default:
+		return fields.Field{}, false
+	}
+}
+
+// each converts every item of the decoded array v with conv, reporting whether v is an array and every item
+// converted.
+func each[T any](v any, conv func(any) (T, bool)) ([]T, bool) {
+	items, ok := v.([]any)
+	if !ok {
+		return nil, v == nil
+	}
+	out := make([]T, len(items))
+	for i, item := range items {
+		if out[i], ok = conv(item); !ok {
+			return nil, false
+		}
+	}
+	return out, true
+}
+
+// asInt returns a decoded integer as an int64, reporting whether v is one that fits.
+func asInt(v any) (int64, bool) {
+	switch n := v.(type) {
+	case int64:
+		return n, true
+	case uint64:
+		return int64(n), n <= math.MaxInt64
+	default:
+		return 0, false
+	}
+}
+
+// asFloat returns a decoded number as a float64, accepting integers as other encoders may write integral floats as
+// such.
+func asFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int64:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	default:
+		return 0, false
+	}
+}
+
+// readN reads an n-byte item, checking n against MaxItemSize.
+func readN(r byteReader, n uint64) ([]byte, error) {
+	if n > MaxItemSize {
+		return nil, fmt.Errorf("binx: item of %d bytes exceeds MaxItemSize", n)
+	}
+	b := make([]byte, n)
+	if _, err := io.ReadFull(r, b); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return b, nil
+}
+
+// decodeArray reads n items at depth.
+func decodeArray(c codec, r byteReader, n uint64, depth int) ([]any, error) {
+	if n > MaxItemSize {
+		return nil, fmt.Errorf("binx: array of %d items exceeds MaxItemSize", n)
+	}
+	if depth >= MaxDepth {
+		return nil, errors.New("binx: nesting exceeds MaxDepth")
+	}
+	a := make([]any, 0, min(n, 1024))
+	for range n {
+		v, err := c.decode(r, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		a = append(a, v)
+	}
+	return a, nil
+}
+
+// decodeMap reads n pairs at depth, keys being strings.
+func decodeMap(c codec, r byteReader, n uint64, depth int) (map[string]any, error) {
+	if n > MaxItemSize {
+		return nil, fmt.Errorf("binx: map of %d pairs exceeds MaxItemSize", n)
+	}
+	if depth >= MaxDepth {
+		return nil, errors.New("binx: nesting exceeds MaxDepth")
+	}
+	m := make(map[string]any, min(n, 1024))
+	for range n {
+		k, err := c.decode(r, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		key, ok := k.(string)
+		if !ok {
+			return nil, fmt.Errorf("binx: map key is a %T, not a string", k)
+		}
+		if m[key], err = c.decode(r, depth+1); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
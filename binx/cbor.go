@@ -0,0 +1,232 @@
+package binx
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// CBOR major types.
+const (
+	cborUint   = 0 << 5
+	cborNegInt = 1 << 5
+	cborBytes  = 2 << 5
+	cborText   = 3 << 5
+	cborArray  = 4 << 5
+	cborMap    = 5 << 5
+	cborTag    = 6 << 5
+	cborSimple = 7 << 5
+)
+
+// CBOR tags of times: RFC 3339 strings, and seconds since the epoch.
+const (
+	cborTagTime  = 0
+	cborTagEpoch = 1
+)
+
+// cbor is the CBOR codec. It writes definite lengths only, and reads items using them.
+type cbor struct{}
+
+// appendHead writes the head of an item of the major type with argument n, in its shortest form.
+func appendHead(dst []byte, major byte, n uint64) []byte {
+	switch {
+	case n < 24:
+		return append(dst, major|byte(n))
+	case n <= math.MaxUint8:
+		return append(dst, major|24, byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(dst, major|25), uint16(n))
+	case n <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(dst, major|26), uint32(n))
+	default:
+		return binary.BigEndian.AppendUint64(append(dst, major|27), n)
+	}
+}
+
+func (cbor) appendArray(dst []byte, n int) []byte {
+	return appendHead(dst, cborArray, uint64(n))
+}
+
+func (cbor) appendMap(dst []byte, n int) []byte {
+	return appendHead(dst, cborMap, uint64(n))
+}
+
+func (cbor) appendString(dst []byte, s string) []byte {
+	return append(appendHead(dst, cborText, uint64(len(s))), s...)
+}
+
+func (cbor) appendBinary(dst []byte, b []byte) []byte {
+	return append(appendHead(dst, cborBytes, uint64(len(b))), b...)
+}
+
+func (cbor) appendInt(dst []byte, v int64) []byte {
+	if v < 0 {
+		return appendHead(dst, cborNegInt, uint64(-1-v))
+	}
+	return appendHead(dst, cborUint, uint64(v))
+}
+
+func (cbor) appendUint(dst []byte, v uint64) []byte {
+	return appendHead(dst, cborUint, v)
+}
+
+// appendFloat writes v as a float32 if that's exact.
+func (cbor) appendFloat(dst []byte, v float64) []byte {
+	if f := float32(v); float64(f) == v {
+		return binary.BigEndian.AppendUint32(append(dst, cborSimple|26), math.Float32bits(f))
+	}
+	return binary.BigEndian.AppendUint64(append(dst, cborSimple|27), math.Float64bits(v))
+}
+
+func (cbor) appendBool(dst []byte, v bool) []byte {
+	if v {
+		return append(dst, cborSimple|21)
+	}
+	return append(dst, cborSimple|20)
+}
+
+func (cbor) appendNil(dst []byte) []byte {
+	return append(dst, cborSimple|22)
+}
+
+// appendTime writes t as an RFC 3339 string, keeping its nanoseconds and offset.
+func (c cbor) appendTime(dst []byte, t time.Time) []byte {
+	return c.appendString(appendHead(dst, cborTag, cborTagTime), t.Format(time.RFC3339Nano))
+}
+
+// head reads the head of an item, returning its major type, additional information and argument.
+func (cbor) head(r byteReader) (major, info byte, n uint64, err error) {
+	b, err := r.ReadByte()
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	major, info = b&0xe0, b&0x1f
+	switch {
+	case info < 24:
+		return major, info, uint64(info), nil
+	case info <= 27:
+		p, err := readN(r, 1<<(info-24))
+		if err != nil {
+			return 0, 0, 0, err
+		}
+		for _, b := range p {
+			n = n<<8 | uint64(b)
+		}
+		return major, info, n, nil
+	case info == 31:
+		return 0, 0, 0, errors.New("cbor: indefinite-length items aren't supported")
+	default:
+		return 0, 0, 0, fmt.Errorf("cbor: invalid additional information %d", info)
+	}
+}
+
+func (c cbor) mapLen(r byteReader) (int, error) {
+	major, _, n, err := c.head(r)
+	if err != nil {
+		return 0, err
+	}
+	if major != cborMap || n > MaxItemSize {
+		return 0, errors.New("cbor: not a map")
+	}
+	return int(n), nil
+}
+
+func (c cbor) decode(r byteReader, depth int) (any, error) {
+	major, info, n, err := c.head(r)
+	if err != nil {
+		return nil, err
+	}
+	switch major {
+	case cborUint:
+		return n, nil
+	case cborNegInt:
+		if n > math.MaxInt64 {
+			return nil, errors.New("cbor: negative integer overflows int64")
+		}
+		return -1 - int64(n), nil
+	case cborBytes:
+		return readN(r, n)
+	case cborText:
+		p, err := readN(r, n)
+		return string(p), err
+	case cborArray:
+		return decodeArray(c, r, n, depth)
+	case cborMap:
+		return decodeMap(c, r, n, depth)
+	case cborTag:
+		if depth >= MaxDepth {
+			return nil, errors.New("binx: nesting exceeds MaxDepth")
+		}
+		v, err := c.decode(r, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		return cborTagged(n, v)
+	}
+
+	// major type 7: simple values and floats
+	switch info {
+	case 20:
+		return false, nil
+	case 21:
+		return true, nil
+	case 22, 23: // null, undefined
+		return nil, nil
+	case 25:
+		return halfFloat(uint16(n)), nil
+	case 26:
+		return float64(math.Float32frombits(uint32(n))), nil
+	case 27:
+		return math.Float64frombits(n), nil
+	default:
+		return nil, fmt.Errorf("cbor: unsupported simple value %d", info)
+	}
+}
+
+// cborTagged returns the value of the item v tagged n: times for the time tags, v itself for other tags.
+func cborTagged(n uint64, v any) (any, error) {
+	switch n {
+	case cborTagTime:
+		s, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("cbor: time is a %T, not a string", v)
+		}
+		return time.Parse(time.RFC3339Nano, s)
+	case cborTagEpoch:
+		switch sec := v.(type) {
+		case uint64:
+			return time.Unix(int64(sec), 0).UTC(), nil
+		case int64:
+			return time.Unix(sec, 0).UTC(), nil
+		case float64:
+			whole, frac := math.Modf(sec)
+			return time.Unix(int64(whole), int64(frac*1e9)).UTC(), nil
+		default:
+			return nil, fmt.Errorf("cbor: epoch time is a %T, not a number", v)
+		}
+	default:
+		return v, nil
+	}
+}
+
+// halfFloat converts an IEEE 754 half-precision float.
+func halfFloat(h uint16) float64 {
+	sign := 1.0
+	if h&0x8000 != 0 {
+		sign = -1
+	}
+	exp, frac := int(h>>10&0x1f), float64(h&0x3ff)
+	switch exp {
+	case 0:
+		return sign * math.Ldexp(frac, -24)
+	case 0x1f:
+		if frac != 0 {
+			return math.NaN()
+		}
+		return math.Inf(int(sign))
+	default:
+		return sign * math.Ldexp(frac+1024, exp-25)
+	}
+}
@@ -0,0 +1,278 @@
+package binx
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"time"
+)
+
+// msgpackTimestamp is the MessagePack extension type of timestamps.
+const msgpackTimestamp = -1
+
+// msgpack is the MessagePack codec.
+type msgpack struct{}
+
+func (msgpack) appendArray(dst []byte, n int) []byte {
+	switch {
+	case n < 16:
+		return append(dst, 0x90|byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(dst, 0xdc), uint16(n))
+	default:
+		return binary.BigEndian.AppendUint32(append(dst, 0xdd), uint32(n))
+	}
+}
+
+func (msgpack) appendMap(dst []byte, n int) []byte {
+	switch {
+	case n < 16:
+		return append(dst, 0x80|byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(dst, 0xde), uint16(n))
+	default:
+		return binary.BigEndian.AppendUint32(append(dst, 0xdf), uint32(n))
+	}
+}
+
+func (msgpack) appendString(dst []byte, s string) []byte {
+	switch n := len(s); {
+	case n < 32:
+		dst = append(dst, 0xa0|byte(n))
+	case n <= math.MaxUint8:
+		dst = append(dst, 0xd9, byte(n))
+	case n <= math.MaxUint16:
+		dst = binary.BigEndian.AppendUint16(append(dst, 0xda), uint16(n))
+	default:
+		dst = binary.BigEndian.AppendUint32(append(dst, 0xdb), uint32(n))
+	}
+	return append(dst, s...)
+}
+
+func (msgpack) appendBinary(dst []byte, b []byte) []byte {
+	switch n := len(b); {
+	case n <= math.MaxUint8:
+		dst = append(dst, 0xc4, byte(n))
+	case n <= math.MaxUint16:
+		dst = binary.BigEndian.AppendUint16(append(dst, 0xc5), uint16(n))
+	default:
+		dst = binary.BigEndian.AppendUint32(append(dst, 0xc6), uint32(n))
+	}
+	return append(dst, b...)
+}
+
+func (c msgpack) appendInt(dst []byte, v int64) []byte {
+	switch {
+	case v >= 0:
+		return c.appendUint(dst, uint64(v))
+	case v >= -32:
+		return append(dst, byte(v)) // negative fixint
+	case v >= math.MinInt8:
+		return append(dst, 0xd0, byte(v))
+	case v >= math.MinInt16:
+		return binary.BigEndian.AppendUint16(append(dst, 0xd1), uint16(v))
+	case v >= math.MinInt32:
+		return binary.BigEndian.AppendUint32(append(dst, 0xd2), uint32(v))
+	default:
+		return binary.BigEndian.AppendUint64(append(dst, 0xd3), uint64(v))
+	}
+}
+
+func (msgpack) appendUint(dst []byte, v uint64) []byte {
+	switch {
+	case v < 128:
+		return append(dst, byte(v)) // positive fixint
+	case v <= math.MaxUint8:
+		return append(dst, 0xcc, byte(v))
+	case v <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(dst, 0xcd), uint16(v))
+	case v <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(dst, 0xce), uint32(v))
+	default:
+		return binary.BigEndian.AppendUint64(append(dst, 0xcf), v)
+	}
+}
+
+// appendFloat writes v as a float32 if that's exact.
+func (msgpack) appendFloat(dst []byte, v float64) []byte {
+	if f := float32(v); float64(f) == v {
+		return binary.BigEndian.AppendUint32(append(dst, 0xca), math.Float32bits(f))
+	}
+	return binary.BigEndian.AppendUint64(append(dst, 0xcb), math.Float64bits(v))
+}
+
+func (msgpack) appendBool(dst []byte, v bool) []byte {
+	if v {
+		return append(dst, 0xc3)
+	}
+	return append(dst, 0xc2)
+}
+
+func (msgpack) appendNil(dst []byte) []byte {
+	return append(dst, 0xc0)
+}
+
+// appendTime writes t as a timestamp extension, in its 64-bit form when it fits and its 96-bit form otherwise.
+func (msgpack) appendTime(dst []byte, t time.Time) []byte {
+	sec, nsec := t.Unix(), uint64(t.Nanosecond())
+	if sec>>34 == 0 {
+		return binary.BigEndian.AppendUint64(append(dst, 0xd7, 0xff), nsec<<34|uint64(sec))
+	}
+	dst = binary.BigEndian.AppendUint32(append(dst, 0xc7, 12, 0xff), uint32(nsec))
+	return binary.BigEndian.AppendUint64(dst, uint64(sec))
+}
+
+func (c msgpack) mapLen(r byteReader) (int, error) {
+	b, err := r.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	n, err := c.length(r, b, 0x80, 0xde)
+	return int(n), err
+}
+
+// length reads the length of a fix/16/32 array, map or string item starting with b, fix items having the type bits
+// fix and the 16-bit form being the byte at16 (the 32-bit one following it).
+func (msgpack) length(r byteReader, b, fix, at16 byte) (uint64, error) {
+	switch {
+	case fix == 0xa0 && b&0xe0 == fix:
+		return uint64(b & 0x1f), nil
+	case fix != 0xa0 && b&0xf0 == fix:
+		return uint64(b & 0x0f), nil
+	case b == at16:
+		p, err := readN(r, 2)
+		if err != nil {
+			return 0, err
+		}
+		return uint64(binary.BigEndian.Uint16(p)), nil
+	case b == at16+1:
+		p, err := readN(r, 4)
+		if err != nil {
+			return 0, err
+		}
+		return uint64(binary.BigEndian.Uint32(p)), nil
+	default:
+		return 0, fmt.Errorf("msgpack: unexpected type byte %#x", b)
+	}
+}
+
+func (c msgpack) decode(r byteReader, depth int) (any, error) {
+	b, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case b < 0x80:
+		return uint64(b), nil
+	case b >= 0xe0:
+		return int64(int8(b)), nil
+	case b&0xf0 == 0x80, b == 0xde, b == 0xdf:
+		n, err := c.length(r, b, 0x80, 0xde)
+		if err != nil {
+			return nil, err
+		}
+		return decodeMap(c, r, n, depth)
+	case b&0xf0 == 0x90, b == 0xdc, b == 0xdd:
+		n, err := c.length(r, b, 0x90, 0xdc)
+		if err != nil {
+			return nil, err
+		}
+		return decodeArray(c, r, n, depth)
+	case b&0xe0 == 0xa0, b == 0xda, b == 0xdb:
+		n, err := c.length(r, b, 0xa0, 0xda)
+		if err != nil {
+			return nil, err
+		}
+		p, err := readN(r, n)
+		return string(p), err
+	}
+
+	switch b {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xd9: // str 8
+		n, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		p, err := readN(r, uint64(n))
+		return string(p), err
+	case 0xc4, 0xc5, 0xc6: // bin 8/16/32
+		n, err := c.uint(r, 1<<(b-0xc4))
+		if err != nil {
+			return nil, err
+		}
+		return readN(r, n)
+	case 0xcc, 0xcd, 0xce, 0xcf: // uint 8/16/32/64
+		return c.uint(r, 1<<(b-0xcc))
+	case 0xd0, 0xd1, 0xd2, 0xd3: // int 8/16/32/64
+		size := 1 << (b - 0xd0)
+		v, err := c.uint(r, size)
+		if err != nil {
+			return nil, err
+		}
+		shift := 64 - 8*size
+		return int64(v<<shift) >> shift, nil // sign-extend
+	case 0xca:
+		v, err := c.uint(r, 4)
+		return float64(math.Float32frombits(uint32(v))), err
+	case 0xcb:
+		v, err := c.uint(r, 8)
+		return math.Float64frombits(v), err
+	case 0xd6, 0xd7, 0xd8: // fixext 4/8/16
+		return c.ext(r, uint64(4<<(b-0xd6)))
+	case 0xd4, 0xd5: // fixext 1/2
+		return c.ext(r, uint64(1<<(b-0xd4)))
+	case 0xc7, 0xc8, 0xc9: // ext 8/16/32
+		n, err := c.uint(r, 1<<(b-0xc7))
+		if err != nil {
+			return nil, err
+		}
+		return c.ext(r, n)
+	default:
+		return nil, fmt.Errorf("msgpack: unexpected type byte %#x", b)
+	}
+}
+
+// uint reads a big-endian unsigned integer of size bytes.
+func (msgpack) uint(r byteReader, size int) (uint64, error) {
+	p, err := readN(r, uint64(size))
+	if err != nil {
+		return 0, err
+	}
+	var v uint64
+	for _, b := range p {
+		v = v<<8 | uint64(b)
+	}
+	return v, nil
+}
+
+// ext reads an extension of n bytes, only timestamps being supported.
+func (msgpack) ext(r byteReader, n uint64) (any, error) {
+	typ, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	p, err := readN(r, n)
+	if err != nil {
+		return nil, err
+	}
+	if int8(typ) != msgpackTimestamp {
+		return nil, fmt.Errorf("msgpack: unsupported extension type %d", int8(typ))
+	}
+	switch n {
+	case 4:
+		return time.Unix(int64(binary.BigEndian.Uint32(p)), 0).UTC(), nil
+	case 8:
+		v := binary.BigEndian.Uint64(p)
+		return time.Unix(int64(v&(1<<34-1)), int64(v>>34)).UTC(), nil
+	case 12:
+		return time.Unix(int64(binary.BigEndian.Uint64(p[4:])), int64(binary.BigEndian.Uint32(p))).UTC(), nil
+	default:
+		return nil, fmt.Errorf("msgpack: invalid timestamp of %d bytes", n)
+	}
+}
@@ -0,0 +1,98 @@
+package logstox
+
+// CheckedEntry is an entry that passed the level check, waiting for its fields. See Check.
+type CheckedEntry[FT any] struct {
+	Level   Level
+	Message string
+
+	l Logger[FT] // skips Write's frame
+}
+
+// Check returns a CheckedEntry if l records lvl, and nil otherwise, so expensive fields are only built for entries
+// that get written:
+//
+//	if ce := logstox.Check(log, logstox.DebugLevel, "state"); ce != nil {
+//		ce.Write(fields.Any("dump", expensiveDump()))
+//	}
+//
+// Panic and Fatal entries are always returned, so they keep their semantics. Loggers that don't implement LevelCheck
+// are assumed to record everything.
+//
+// Each returned entry derives a child of l to fix up the caller; on hot paths, use a Checker, which derives it once.
+func Check[FT any](l Logger[FT], lvl Level, msg string) *CheckedEntry[FT] {
+	// skip Write's own frame so file:line points at the caller
+	return check(l, lvl, msg, 1)
+}
+
+// check implements Check, skipping skip frames for the entries it returns.
+func check[FT any](l Logger[FT], lvl Level, msg string, skip int) *CheckedEntry[FT] {
+	if lvl.Compare(PanicLevel) < 0 && !Enabled(l, lvl) {
+		return nil
+	}
+	// skip LogAt's frame too, Write logs through it
+	return &CheckedEntry[FT]{Level: lvl, Message: msg, l: WithOptions(l, AddCallerSkip(skip+1))}
+}
+
+// Checker is Check with the caller fix-up done once, for loggers checked on every call:
+//
+//	c := logstox.NewChecker(log)
+//	...
+//	if ce := c.Check(logstox.DebugLevel, "state"); ce != nil {
+//		ce.Write(fields.Any("dump", expensiveDump()))
+//	}
+type Checker[FT any] struct {
+	l Logger[FT] // skips Write's frame
+}
+
+// NewChecker returns a Checker for l.
+func NewChecker[FT any](l Logger[FT]) Checker[FT] {
+	// skip Write and LogAt so file:line points at the caller
+	return Checker[FT]{l: WithOptions(l, AddCallerSkip(2))}
+}
+
+// Check is the package-level Check without deriving a logger per entry.
+func (c Checker[FT]) Check(lvl Level, msg string) *CheckedEntry[FT] {
+	if lvl.Compare(PanicLevel) < 0 && !Enabled(c.l, lvl) {
+		return nil
+	}
+	return &CheckedEntry[FT]{Level: lvl, Message: msg, l: c.l}
+}
+
+// Write logs the entry with fs through the level method matching ce.Level, so Panic and Fatal entries keep their
+// semantics. It's a no-op on a nil CheckedEntry.
+func (ce *CheckedEntry[FT]) Write(fs ...FT) {
+	if ce == nil {
+		return
+	}
+	LogAt(ce.l, ce.Level, ce.Message, fs...)
+}
+
+// LogAt logs msg with fs to l through the level method matching lvl, notices through NoticerOf, so Panic and Fatal
+// keep their semantics. Levels below Debug log at Debug, and levels above Panic at Fatal.
+//
+// LogAt is a frame of its own: wrappers calling it should skip one more frame (see AddCallerSkip) so file:line
+// points at their caller.
+func LogAt[FT any](l Logger[FT], lvl Level, msg string, fs ...FT) {
+	switch lvl {
+	case DebugLevel:
+		l.Debug(msg, fs...)
+	case InfoLevel:
+		l.Info(msg, fs...)
+	case NoticeLevel:
+		NoticerOf(l).Notice(msg, fs...)
+	case WarnLevel:
+		l.Warn(msg, fs...)
+	case ErrorLevel:
+		l.Error(msg, fs...)
+	case DPanicLevel:
+		l.DPanic(msg, fs...)
+	case PanicLevel:
+		l.Panic(msg, fs...)
+	default:
+		if lvl.Compare(DebugLevel) < 0 {
+			l.Debug(msg, fs...)
+		} else {
+			l.Fatal(msg, fs...)
+		}
+	}
+}
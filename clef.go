@@ -0,0 +1,45 @@
+package logstox
+
+import "strings"
+
+// Keys of the Compact Log Event Format (CLEF), the NDJSON format Seq and other Serilog tooling ingest. The zapx
+// backend writes it with its EncodingCLEF; no other bundled backend does.
+const (
+	CLEFTimeKey      = "@t"  // ISO 8601 timestamp
+	CLEFTemplateKey  = "@mt" // message template, see CLEFTemplate
+	CLEFLevelKey     = "@l"  // Serilog level name, see CLEFLevel
+	CLEFExceptionKey = "@x"  // exception text, what's logged under fields.ErrorKey ("error") is written under it
+	// CLEFNameKey is the property Serilog keeps the logger name in.
+	CLEFNameKey = "SourceContext"
+)
+
+// CLEFLevel returns the Serilog level name l is written as in CLEF: Debug, Information, Warning, Error or Fatal.
+// DPanic and Panic are Error, and levels below Debug are Verbose.
+func CLEFLevel(l Level) string {
+	switch {
+	case l.Compare(DebugLevel) < 0:
+		return "Verbose"
+	case l == DebugLevel:
+		return "Debug"
+	case l == InfoLevel, l == NoticeLevel:
+		return "Information"
+	case l == WarnLevel:
+		return "Warning"
+	case l.Compare(FatalLevel) < 0:
+		return "Error"
+	default:
+		return "Fatal"
+	}
+}
+
+// clefEscaper doubles braces, see CLEFTemplate.
+var clefEscaper = strings.NewReplacer("{", "{{", "}", "}}")
+
+// CLEFTemplate returns msg as a CLEF message template, its braces doubled so Serilog tooling doesn't mistake them for
+// property holes.
+func CLEFTemplate(msg string) string {
+	if !strings.ContainsAny(msg, "{}") {
+		return msg
+	}
+	return clefEscaper.Replace(msg)
+}
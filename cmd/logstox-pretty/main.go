@@ -0,0 +1,138 @@
+// Command logstox-pretty renders NDJSON logs for humans:
+//
+//	kubectl logs deploy/api | logstox-pretty -level warn -name http -match status=500
+//	logstox-pretty app.log rotated.log
+//
+// It reads the files given, or stdin, and writes one colorized line per entry. Lines that aren't JSON objects are
+// passed through unchanged.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/khinshankhan/logstox"
+	"github.com/khinshankhan/logstox/pretty"
+)
+
+// matchers collects repeated -match key=value flags.
+type matchers map[string]string
+
+func (m matchers) String() string { return fmt.Sprint(map[string]string(m)) }
+
+func (m matchers) Set(s string) error {
+	k, v, ok := strings.Cut(s, "=")
+	if !ok || k == "" {
+		return fmt.Errorf("want key=value, got %q", s)
+	}
+	m[k] = v
+	return nil
+}
+
+// filter selects the entries to render.
+type filter struct {
+	level   logstox.Level
+	name    string
+	matches matchers
+}
+
+func (f filter) keep(e pretty.Entry) bool {
+	if lvl, err := logstox.ParseLevel(e.Level); err == nil && lvl.Compare(f.level) < 0 {
+		return false
+	}
+	if f.name != "" && e.Name != f.name && !strings.HasPrefix(e.Name, f.name+".") {
+		return false
+	}
+	for k, want := range f.matches {
+		if got, ok := e.Lookup(k); !ok || got != want {
+			return false
+		}
+	}
+	return true
+}
+
+func main() {
+	var (
+		level   = logstox.LevelFlag("level", logstox.DebugLevel, "only show entries at or above this level")
+		name    = flag.String("name", "", "only show entries from this logger or its children")
+		color   = flag.String("color", "auto", "colorize output: auto, always or never")
+		layout  = flag.String("time", "15:04:05.000", "time layout for timestamps, empty keeps them as written")
+		matches = matchers{}
+	)
+	flag.Var(matches, "match", "only show entries with a top-level field key=value (repeatable)")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: logstox-pretty [flags] [file ...]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	f := filter{level: *level, name: *name, matches: matches}
+	r := pretty.Renderer{Color: useColor(*color), TimeLayout: *layout}
+
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+
+	if flag.NArg() == 0 {
+		if err := render(out, os.Stdin, r, f); err != nil {
+			fatal(err)
+		}
+		return
+	}
+	for _, path := range flag.Args() {
+		file, err := os.Open(path)
+		if err != nil {
+			fatal(err)
+		}
+		err = render(out, file, r, f)
+		file.Close()
+		if err != nil {
+			fatal(err)
+		}
+	}
+}
+
+// render copies in to out, rendering the entries kept by f.
+func render(out *bufio.Writer, in io.Reader, r pretty.Renderer, f filter) error {
+	sc := bufio.NewScanner(in)
+	sc.Buffer(make([]byte, 64*1024), 16*1024*1024) // entries with stack traces get long
+	for sc.Scan() {
+		line := sc.Bytes()
+		e, err := pretty.Parse(line)
+		if err != nil {
+			out.Write(line)
+			out.WriteByte('\n')
+			continue
+		}
+		if !f.keep(e) {
+			continue
+		}
+		if err := r.Render(out, e); err != nil {
+			return err
+		}
+	}
+	return sc.Err()
+}
+
+// useColor resolves the -color flag, coloring in auto mode only when stdout is a terminal.
+func useColor(mode string) bool {
+	switch mode {
+	case "always":
+		return true
+	case "never":
+		return false
+	}
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	fi, err := os.Stdout.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, "logstox-pretty:", err)
+	os.Exit(1)
+}
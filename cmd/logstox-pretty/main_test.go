@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	"github.com/khinshankhan/logstox"
+	"github.com/khinshankhan/logstox/pretty"
+)
+
+const input = `{"level":"debug","logger":"http","msg":"a"}
+starting up
+{"level":"info","logger":"http.client","msg":"b","status":500}
+{"level":"warn","logger":"httpx","msg":"c","status":"500"}
+{"level":"error","logger":"db","msg":"d","status":200}
+{"level":"loud","msg":"e"}
+`
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name string
+		f    filter
+		want string
+	}{
+		{"everything", filter{level: logstox.DebugLevel}, "DEBUG http a\nstarting up\nINFO  http.client b  status=500\n" +
+			"WARN  httpx c  status=500\nERROR db d  status=200\nLOUD  e\n"},
+		{"level", filter{level: logstox.WarnLevel}, "starting up\nWARN  httpx c  status=500\nERROR db d  status=200\n" +
+			"LOUD  e\n"},
+		{"name and children", filter{level: logstox.DebugLevel, name: "http"},
+			"DEBUG http a\nstarting up\nINFO  http.client b  status=500\n"},
+		{"match", filter{level: logstox.DebugLevel, matches: matchers{"status": "500"}},
+			"starting up\nINFO  http.client b  status=500\nWARN  httpx c  status=500\n"},
+		{"everything at once", filter{level: logstox.InfoLevel, name: "http", matches: matchers{"status": "500"}},
+			"starting up\nINFO  http.client b  status=500\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b strings.Builder
+			out := bufio.NewWriter(&b)
+			if err := render(out, strings.NewReader(input), pretty.Renderer{}, tt.f); err != nil {
+				t.Fatal(err)
+			}
+			out.Flush()
+			if b.String() != tt.want {
+				t.Errorf("rendered\n%s\nwant\n%s", b.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchers(t *testing.T) {
+	m := matchers{}
+	for _, s := range []string{"status=500", "path=/a=b", "empty="} {
+		if err := m.Set(s); err != nil {
+			t.Errorf("Set(%q) = %v", s, err)
+		}
+	}
+	if m["status"] != "500" || m["path"] != "/a=b" || m["empty"] != "" || len(m) != 3 {
+		t.Errorf("matchers = %v", m)
+	}
+	for _, s := range []string{"status", "=500"} {
+		if err := m.Set(s); err == nil {
+			t.Errorf("Set(%q) succeeded", s)
+		}
+	}
+}
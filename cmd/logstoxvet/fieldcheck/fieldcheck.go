@@ -0,0 +1,163 @@
+package fieldcheck
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// FieldsPath is the import path of the logstox fields package.
+const FieldsPath = "github.com/khinshankhan/logstox/fields"
+
+// Analyzer reports misuse of logstox field constructors:
+//
+//   - fields.Any with a value that has a typed constructor (fields.Any("n", 1) should be fields.Int("n", 1)),
+//     which boxes the value and loses its kind;
+//   - the same constant key passed twice to a single call, which backends write twice or overwrite;
+//   - fields.Error(nil) and fields.NamedError(k, nil), which always produce a no-op.
+var Analyzer = &analysis.Analyzer{
+	Name:     "logstoxfields",
+	Doc:      "report misuse of logstox field constructors",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+// typed maps the types fields.Any can be swapped for a typed constructor on.
+var typed = map[string]string{
+	"string":                   "String",
+	"bool":                     "Bool",
+	"int":                      "Int",
+	"int64":                    "Int64",
+	"uint":                     "Uint",
+	"uint64":                   "Uint64",
+	"float64":                  "Float64",
+	"time.Time":                "TimeField",
+	"time.Duration":            "Duration",
+	"error":                    "NamedError",
+	"[]string":                 "Strings",
+	"[]bool":                   "Bools",
+	"[]int64":                  "Int64s",
+	"[]uint64":                 "Uint64s",
+	"[]float64":                "Float64s",
+	"[]error":                  "Errors",
+	"encoding/json.RawMessage": "RawJSON",
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	ins := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	ins.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node) {
+		call := n.(*ast.CallExpr)
+		if name := fieldsFunc(pass, call); name != "" {
+			checkConstructor(pass, call, name)
+		}
+		checkDuplicateKeys(pass, call)
+	})
+	return nil, nil
+}
+
+// fieldsFunc returns the name of the fields package function call invokes, or "".
+func fieldsFunc(pass *analysis.Pass, call *ast.CallExpr) string {
+	fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != FieldsPath {
+		return ""
+	}
+	if sig, ok := fn.Type().(*types.Signature); !ok || sig.Recv() != nil {
+		return ""
+	}
+	return fn.Name()
+}
+
+// checkConstructor reports Any calls with typed values and Error calls with a nil error.
+func checkConstructor(pass *analysis.Pass, call *ast.CallExpr, name string) {
+	switch name {
+	case "Any":
+		if len(call.Args) != 2 {
+			return
+		}
+		t := pass.TypesInfo.TypeOf(call.Args[1])
+		if t == nil {
+			return
+		}
+		if alt, ok := typed[types.TypeString(t, nil)]; ok {
+			pass.Report(analysis.Diagnostic{
+				Pos:     call.Pos(),
+				End:     call.End(),
+				Message: "fields.Any boxes a " + types.TypeString(t, nil) + ": use fields." + alt,
+			})
+		}
+	case "Error", "NamedError":
+		if len(call.Args) == 0 || !isNil(pass, call.Args[len(call.Args)-1]) {
+			return
+		}
+		pass.Report(analysis.Diagnostic{
+			Pos:     call.Pos(),
+			End:     call.End(),
+			Message: "fields." + name + " with a nil error is always a no-op",
+		})
+	}
+}
+
+// checkDuplicateKeys reports constant keys used by more than one field constructor among call's arguments.
+func checkDuplicateKeys(pass *analysis.Pass, call *ast.CallExpr) {
+	if call.Ellipsis.IsValid() {
+		return
+	}
+	seen := make(map[string]bool)
+	for _, arg := range call.Args {
+		inner, ok := ast.Unparen(arg).(*ast.CallExpr)
+		if !ok {
+			continue
+		}
+		key, ok := fieldKey(pass, inner)
+		if !ok {
+			continue
+		}
+		if seen[key] {
+			pass.Reportf(inner.Pos(), "duplicate field key %q in call", key)
+		}
+		seen[key] = true
+	}
+}
+
+// fieldKey returns the constant key of a fields constructor call.
+func fieldKey(pass *analysis.Pass, call *ast.CallExpr) (string, bool) {
+	name := fieldsFunc(pass, call)
+	switch name {
+	case "":
+		return "", false
+	case "Nop", "Lazy", "LazyFields", "Timestamp":
+		return "", false
+	case "Error", "NamedError":
+		if isNil(pass, call.Args[len(call.Args)-1]) {
+			return "", false // a no-op, reported on its own
+		}
+		if name == "Error" {
+			return "error", true
+		}
+	}
+
+	fn := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+	params := fn.Type().(*types.Signature).Params()
+	if params.Len() == 0 || len(call.Args) == 0 || !isString(params.At(0).Type()) || params.At(0).Name() != "k" {
+		return "", false
+	}
+	tv, ok := pass.TypesInfo.Types[call.Args[0]]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return "", false
+	}
+	return constant.StringVal(tv.Value), true
+}
+
+func isNil(pass *analysis.Pass, e ast.Expr) bool {
+	return pass.TypesInfo.Types[e].IsNil()
+}
+
+func isString(t types.Type) bool {
+	b, ok := t.Underlying().(*types.Basic)
+	return ok && b.Kind() == types.String
+}
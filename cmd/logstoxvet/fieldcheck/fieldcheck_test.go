@@ -0,0 +1,13 @@
+package fieldcheck_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/khinshankhan/logstox/cmd/logstoxvet/fieldcheck"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), fieldcheck.Analyzer, "a")
+}
@@ -0,0 +1,35 @@
+package a
+
+import (
+	"errors"
+	"time"
+
+	"github.com/khinshankhan/logstox/fields"
+)
+
+type user struct{ Name string }
+
+func constructors(name string, err error) {
+	_ = fields.Any("user", user{Name: name})
+	_ = fields.Any("name", name)           // want `fields.Any boxes a string: use fields.String`
+	_ = fields.Any("n", 1)                 // want `fields.Any boxes a int: use fields.Int`
+	_ = fields.Any("d", time.Second)       // want `fields.Any boxes a time.Duration: use fields.Duration`
+	_ = fields.Any("err", errors.New("x")) // want `fields.Any boxes a error: use fields.NamedError`
+
+	_ = fields.Error(err)
+	_ = fields.Error(nil)           // want `fields.Error with a nil error is always a no-op`
+	_ = fields.NamedError("e", nil) // want `fields.NamedError with a nil error is always a no-op`
+}
+
+func keys(name string) {
+	_ = fields.Dict("req", fields.String("name", name), fields.Int("n", 1))
+	_ = fields.Dict("req",
+		fields.String("name", name),
+		fields.String("name", name), // want `duplicate field key "name" in call`
+	)
+	_ = fields.Dict("req",
+		fields.Error(errors.New("x")),
+		fields.NamedError("error", errors.New("y")), // want `duplicate field key "error" in call`
+	)
+	_ = fields.Dict("req", fields.Nop(), fields.Nop(), fields.Error(nil), fields.Error(nil)) // want `fields.Error with a nil error` `fields.Error with a nil error`
+}
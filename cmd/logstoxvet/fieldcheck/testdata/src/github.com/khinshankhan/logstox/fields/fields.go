@@ -0,0 +1,16 @@
+// Package fields is a stub of the constructors fieldcheck looks for.
+package fields
+
+import "time"
+
+type Field struct{}
+
+func Any(k string, v any) Field                { return Field{} }
+func String(k, v string) Field                 { return Field{} }
+func Int(k string, v int) Field                { return Field{} }
+func Duration(k string, v time.Duration) Field { return Field{} }
+func Error(err error) Field                    { return Field{} }
+func NamedError(k string, err error) Field     { return Field{} }
+func Dict(k string, fs ...Field) Field         { return Field{} }
+func Lazy(k string, fn func() any) Field       { return Field{} }
+func Nop() Field                               { return Field{} }
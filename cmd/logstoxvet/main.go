@@ -0,0 +1,16 @@
+// Command logstoxvet runs the logstox analyzers, as a go vet tool:
+//
+//	go install github.com/khinshankhan/logstox/cmd/logstoxvet@latest
+//	go vet -vettool=$(which logstoxvet) ./...
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/unitchecker"
+
+	"github.com/khinshankhan/logstox/cmd/logstoxvet/fieldcheck"
+	"github.com/khinshankhan/logstox/cmd/logstoxvet/msgcheck"
+)
+
+func main() {
+	unitchecker.Main(fieldcheck.Analyzer, msgcheck.Analyzer)
+}
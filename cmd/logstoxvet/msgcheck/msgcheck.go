@@ -0,0 +1,135 @@
+package msgcheck
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// LogstoxPath is the import path of the logstox package declaring Logger.
+const LogstoxPath = "github.com/khinshankhan/logstox"
+
+// Analyzer reports log messages that don't follow a consistent style, for calls to the level methods of
+// logstox.Logger:
+//
+//   - messages built with fmt.Sprintf and friends, or by string concatenation, whose values belong in fields and
+//     make entries hard to search and group;
+//   - constant messages not starting in the configured case, or ending in punctuation.
+//
+// Other non-constant messages, eg a parameter passed straight through by a helper, aren't reported.
+//
+// Each check is configurable through the analyzer's flags, eg go vet -vettool=$(which logstoxvet) -logstoxmsg.case=upper.
+var Analyzer = &analysis.Analyzer{
+	Name:     "logstoxmsg",
+	Doc:      "report log messages that don't follow the project's style",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+// Flag values, shared by every pass.
+var (
+	checkSprintf = true
+	checkConcat  = true
+	messageCase  = "lower"
+	checkPunct   = true
+)
+
+func init() {
+	Analyzer.Flags.BoolVar(&checkSprintf, "sprintf", checkSprintf, "report messages built with fmt.Sprint* (suggest fields)")
+	Analyzer.Flags.BoolVar(&checkConcat, "concat", checkConcat, "report messages built by string concatenation (suggest fields)")
+	Analyzer.Flags.StringVar(&messageCase, "case", messageCase, "required case of a message's first letter: lower, upper or any")
+	Analyzer.Flags.BoolVar(&checkPunct, "punct", checkPunct, "report messages ending in punctuation")
+}
+
+// levelMethods are the Logger methods taking a message.
+var levelMethods = map[string]bool{
+	"Debug": true, "Info": true, "Notice": true, "Warn": true, "Error": true, "DPanic": true, "Panic": true, "Fatal": true,
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	ins := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	ins.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node) {
+		call := n.(*ast.CallExpr)
+		if len(call.Args) == 0 || !isLevelMethod(pass, call) {
+			return
+		}
+		checkMessage(pass, call.Args[0])
+	})
+	return nil, nil
+}
+
+// isLevelMethod reports whether call invokes a level method of logstox.Logger.
+func isLevelMethod(pass *analysis.Pass, call *ast.CallExpr) bool {
+	fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != LogstoxPath || !levelMethods[fn.Name()] {
+		return false
+	}
+	sig, ok := fn.Type().(*types.Signature)
+	return ok && sig.Recv() != nil
+}
+
+// checkMessage reports style issues with msg.
+func checkMessage(pass *analysis.Pass, msg ast.Expr) {
+	tv := pass.TypesInfo.Types[msg]
+	if tv.Value == nil || tv.Value.Kind() != constant.String {
+		if checkSprintf {
+			if name := sprintf(pass, msg); name != "" {
+				pass.Reportf(msg.Pos(), "log message built with fmt.%s: log a constant message and pass the values as fields", name)
+				return
+			}
+		}
+		if checkConcat {
+			if bin, ok := ast.Unparen(msg).(*ast.BinaryExpr); ok && bin.Op == token.ADD {
+				pass.Reportf(msg.Pos(), "log message built by concatenation: log a constant message and pass the values as fields")
+			}
+		}
+		return
+	}
+
+	s := constant.StringVal(tv.Value)
+	if s == "" {
+		return
+	}
+	first, _ := utf8.DecodeRuneInString(s)
+	second, _ := utf8.DecodeRuneInString(s[utf8.RuneLen(first):])
+	acronym := unicode.IsUpper(first) && unicode.IsUpper(second) // eg "HTTP server started"
+	switch {
+	case messageCase == "lower" && unicode.IsUpper(first) && !acronym:
+		pass.Reportf(msg.Pos(), "log message should start in lower case")
+	case messageCase == "upper" && unicode.IsLower(first):
+		pass.Reportf(msg.Pos(), "log message should start in upper case")
+	}
+
+	if checkPunct {
+		last, _ := utf8.DecodeLastRuneInString(s)
+		if strings.ContainsRune(".!?:;,", last) && !strings.HasSuffix(s, "...") {
+			pass.Reportf(msg.Pos(), "log message should not end in punctuation")
+		}
+	}
+}
+
+// sprintf returns the name of the fmt formatting function msg is a call to, or "".
+func sprintf(pass *analysis.Pass, msg ast.Expr) string {
+	call, ok := ast.Unparen(msg).(*ast.CallExpr)
+	if !ok {
+		return ""
+	}
+	fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != "fmt" {
+		return ""
+	}
+	switch fn.Name() {
+	case "Sprintf", "Sprint", "Sprintln":
+		return fn.Name()
+	}
+	return ""
+}
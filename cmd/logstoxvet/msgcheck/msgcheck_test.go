@@ -0,0 +1,13 @@
+package msgcheck_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/khinshankhan/logstox/cmd/logstoxvet/msgcheck"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), msgcheck.Analyzer, "a")
+}
@@ -0,0 +1,26 @@
+package a
+
+import (
+	"fmt"
+
+	"github.com/khinshankhan/logstox"
+)
+
+const started = "server started"
+
+func messages(l logstox.Logger, user, msg string) {
+	l.Info("user logged in")
+	l.Info(started)
+	l.Info("HTTP server started")
+	l.Info("loading...")
+
+	l.Info(fmt.Sprintf("user %s logged in", user)) // want `log message built with fmt.Sprintf`
+	l.Warn(fmt.Sprint("user ", user))              // want `log message built with fmt.Sprint`
+	l.Error("user " + user + " logged in")         // want `log message built by concatenation`
+	l.Notice(("user " + user))                     // want `log message built by concatenation`
+	l.Debug("server " + "started")
+
+	l.Info(msg)               // passed straight through
+	l.Info("User logged in")  // want `log message should start in lower case`
+	l.Info("user logged in.") // want `log message should not end in punctuation`
+}
@@ -0,0 +1,12 @@
+// Package logstox is a stub of the level methods msgcheck looks for.
+package logstox
+
+type Field struct{}
+
+type Logger interface {
+	Debug(msg string, fs ...Field)
+	Info(msg string, fs ...Field)
+	Notice(msg string, fs ...Field)
+	Warn(msg string, fs ...Field)
+	Error(msg string, fs ...Field)
+}
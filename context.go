@@ -0,0 +1,57 @@
+package logstox
+
+import "context"
+
+// ContextBinder is an optional extension for loggers that can be bound to a context.Context, so their entries carry
+// what it holds: the fields Options.ContextExtractors derive from it, and whatever lazy fields read from it.
+type ContextBinder[FT any] interface {
+	WithContext(context.Context) Logger[FT]
+}
+
+// WithContext returns a child of l bound to ctx, for request-scoped logging without per-call boilerplate:
+//
+//	log := logstox.WithContext(base, r.Context())
+//	log.Info("handled") // carries the trace ID and user the extractors found in the request context
+//
+// Binding the child again replaces the context, rather than adding its fields on top. If ctx carries a minimum level
+// (see ContextWithMinLevel), the child gets it like with WithLevel. Loggers that don't implement ContextBinder aren't
+// bound, only leveled.
+func WithContext[FT any](l Logger[FT], ctx context.Context) Logger[FT] {
+	if cb, ok := l.(ContextBinder[FT]); ok {
+		l = cb.WithContext(ctx)
+	}
+	if lvl, ok := ContextMinLevel(ctx); ok {
+		l = WithLevel(l, lvl)
+	}
+	return l
+}
+
+// minLevelKey is the context key of ContextWithMinLevel.
+type minLevelKey struct{}
+
+// ContextWithMinLevel returns a copy of ctx carrying lvl as the minimum level of the loggers bound to it, by
+// WithContext or contextx.Logger, eg to log a single misbehaving request at DebugLevel in production. It's
+// contextx.WithMinLevel.
+func ContextWithMinLevel(ctx context.Context, lvl Level) context.Context {
+	return context.WithValue(ctx, minLevelKey{}, lvl)
+}
+
+// ContextMinLevel returns the minimum level stored in ctx by ContextWithMinLevel. A nil ctx has none.
+func ContextMinLevel(ctx context.Context) (Level, bool) {
+	if ctx == nil {
+		return 0, false
+	}
+	lvl, ok := ctx.Value(minLevelKey{}).(Level)
+	return lvl, ok
+}
+
+// ExtractContext returns the fields extractors derive from ctx, in order, for backends implementing ContextBinder.
+func ExtractContext[FT any](ctx context.Context, extractors []func(context.Context) []FT) []FT {
+	var fs []FT
+	for _, extract := range extractors {
+		if extract != nil {
+			fs = append(fs, extract(ctx)...)
+		}
+	}
+	return fs
+}
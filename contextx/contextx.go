@@ -0,0 +1,115 @@
+package contextx
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/khinshankhan/logstox"
+	"github.com/khinshankhan/logstox/fields"
+)
+
+// unexported key types so no other package can collide with ours.
+type (
+	loggerKey        struct{}
+	correlationIDKey struct{}
+	spanKey          struct{}
+	forceDebugKey    struct{}
+)
+
+// stored is a logger stored in a context, caching its leveled child.
+type stored struct {
+	l       logstox.Logger[fields.Field]
+	leveled atomic.Pointer[leveledLogger]
+}
+
+// leveledLogger is a stored logger at a context's minimum level.
+type leveledLogger struct {
+	min logstox.Level
+	l   logstox.Logger[fields.Field]
+}
+
+// at returns the stored logger with the minimum level stored in ctx, leveling it once per level rather than on every
+// call.
+func (s *stored) at(ctx context.Context) logstox.Logger[fields.Field] {
+	lvl, ok := MinLevel(ctx)
+	if !ok || s.l == nil {
+		return s.l
+	}
+	if c := s.leveled.Load(); c != nil && c.min == lvl {
+		return c.l
+	}
+	c := &leveledLogger{min: lvl, l: logstox.WithLevel(s.l, lvl)}
+	s.leveled.Store(c)
+	return c.l
+}
+
+// WithLogger returns a copy of ctx carrying l.
+func WithLogger(ctx context.Context, l logstox.Logger[fields.Field]) context.Context {
+	return context.WithValue(ctx, loggerKey{}, &stored{l: l})
+}
+
+// Logger returns the logger stored in ctx by WithLogger or StartSpan, or fallback if there is none, with the minimum
+// level set by WithMinLevel if any. The stored logger is leveled once and cached, so calling Logger on every use
+// doesn't allocate; fallback is leveled on every call.
+func Logger(ctx context.Context, fallback logstox.Logger[fields.Field]) logstox.Logger[fields.Field] {
+	switch v := ctx.Value(loggerKey{}).(type) {
+	case *stored:
+		return v.at(ctx)
+	case *Span:
+		return v.l.at(ctx)
+	default:
+		return withMinLevel(ctx, fallback)
+	}
+}
+
+// WithMinLevel returns a copy of ctx carrying lvl as the minimum level of the loggers Logger and StartSpan return
+// for it, eg to log a single misbehaving request at DebugLevel in production (see httpx.Debug). It replaces their
+// level like logstox.WithLevel, so it can't record entries the backend itself drops: build the backend at DebugLevel
+// and set the everyday level with logstox.WithLevel for requests to be able to go below it. Loggers bound to ctx with
+// logstox.WithContext get the level too; it's logstox.ContextWithMinLevel.
+func WithMinLevel(ctx context.Context, lvl logstox.Level) context.Context {
+	return logstox.ContextWithMinLevel(ctx, lvl)
+}
+
+// MinLevel returns the minimum level stored in ctx by WithMinLevel.
+func MinLevel(ctx context.Context) (logstox.Level, bool) {
+	return logstox.ContextMinLevel(ctx)
+}
+
+// withMinLevel returns l with the minimum level stored in ctx, or l itself if there is none.
+func withMinLevel(ctx context.Context, l logstox.Logger[fields.Field]) logstox.Logger[fields.Field] {
+	if lvl, ok := MinLevel(ctx); ok && l != nil {
+		return logstox.WithLevel(l, lvl)
+	}
+	return l
+}
+
+// WithCorrelationID returns a copy of ctx carrying the correlation ID id.
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationIDKey{}, id)
+}
+
+// CorrelationID returns the correlation ID stored in ctx by WithCorrelationID.
+func CorrelationID(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(correlationIDKey{}).(string)
+	return id, ok && id != ""
+}
+
+// WithForceDebug returns a copy of ctx flagged for debug logging, so middleware.SampledDebug keeps the Debug entries
+// of loggers bound to it even when its trace isn't sampled. WithMinLevel at DebugLevel or below flags it too.
+func WithForceDebug(ctx context.Context) context.Context {
+	return context.WithValue(ctx, forceDebugKey{}, true)
+}
+
+// ForceDebug reports whether ctx was flagged by WithForceDebug, or carries a minimum level at DebugLevel or below. A
+// nil ctx isn't.
+func ForceDebug(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	if forced, _ := ctx.Value(forceDebugKey{}).(bool); forced {
+		return true
+	}
+	lvl, ok := MinLevel(ctx)
+	return ok && lvl <= logstox.DebugLevel
+}
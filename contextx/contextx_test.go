@@ -0,0 +1,45 @@
+package contextx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/khinshankhan/logstox"
+	"github.com/khinshankhan/logstox/backend/memx"
+	"github.com/khinshankhan/logstox/fields"
+)
+
+func TestMinLevel(t *testing.T) {
+	// built at Debug so the context can go below the everyday Info
+	base := memx.Backend{}.New(logstox.Options[fields.Field]{Level: logstox.DebugLevel})
+	log := logstox.WithLevel(base, logstox.InfoLevel)
+	ctx := WithMinLevel(WithLogger(context.Background(), log), logstox.DebugLevel)
+
+	tests := []struct {
+		name string
+		l    logstox.Logger[fields.Field]
+	}{
+		{"Logger", Logger(ctx, nil)},
+		{"Logger fallback", Logger(WithMinLevel(context.Background(), logstox.DebugLevel), log)},
+		{"logstox.WithContext", logstox.WithContext(log, ctx)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !logstox.Enabled(tt.l, logstox.DebugLevel) {
+				t.Error("debug entries are dropped, want the context's minimum level")
+			}
+		})
+	}
+	if logstox.Enabled(logstox.WithContext(log, context.Background()), logstox.DebugLevel) {
+		t.Error("debug entries are recorded without a minimum level in the context")
+	}
+}
+
+func TestLoggerCachesTheLeveledLogger(t *testing.T) {
+	log := memx.Backend{}.New(logstox.Options[fields.Field]{Level: logstox.DebugLevel})
+	ctx := WithMinLevel(WithLogger(context.Background(), log), logstox.WarnLevel)
+	Logger(ctx, nil)
+	if n := testing.AllocsPerRun(100, func() { Logger(ctx, nil) }); n != 0 {
+		t.Errorf("Logger allocates %v times per call, want 0", n)
+	}
+}
@@ -0,0 +1,116 @@
+package contextx
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync/atomic"
+	"time"
+
+	"github.com/khinshankhan/logstox"
+	"github.com/khinshankhan/logstox/fields"
+)
+
+// Keys used by spans. They're namespaced so they don't collide with the trace_id/span_id of a real tracer (see
+// httpx.TraceFields).
+const (
+	SpanNameKey     = "span.name"
+	SpanIDKey       = "span.id"
+	SpanParentIDKey = "span.parent_id"
+	SpanDurationKey = "span.duration"
+	SpanOutcomeKey  = "span.outcome"
+)
+
+// Span outcomes, logged under SpanOutcomeKey.
+const (
+	SpanSuccess = "success"
+	SpanFailure = "failure"
+)
+
+// Span is a scoped operation, a poor man's tracing for code without a tracer. See StartSpan.
+type Span struct {
+	id    string
+	base  logstox.Logger[fields.Field] // the logger the span fields were added to, inherited by nested spans
+	l     *stored                      // base with the span fields
+	self  logstox.Logger[fields.Field] // l, skipping the Span's own frames
+	start time.Time
+	ended atomic.Bool
+}
+
+// StartSpan starts the span name, logging a "span started" entry at DebugLevel. It returns a context carrying the
+// span's logger (the one in ctx, or fallback, with fs, the span name, a random ID and the enclosing span's ID),
+// so everything logged through Logger within the operation is tied to it, nested spans included:
+//
+//	ctx, span := contextx.StartSpan(ctx, log, "charge card")
+//	defer func() { span.End(err) }()
+//
+// Run the logger at InfoLevel or above to only get End's entry. The span's logger honors the context's WithMinLevel.
+// If ctx carries no logger and fallback is nil, StartSpan returns ctx as is and a nil Span, whose methods are no-ops.
+//
+// It's a function taking the logger rather than a log.Span(ctx, name) method, as a method would have to be added to
+// logstox.Logger, breaking every implementation of it outside this module.
+func StartSpan(ctx context.Context, fallback logstox.Logger[fields.Field], name string, fs ...fields.Field) (
+	context.Context, *Span,
+) {
+	base := Logger(ctx, fallback)
+	if base == nil {
+		return ctx, nil
+	}
+	if parent, ok := ctx.Value(loggerKey{}).(*Span); ok {
+		// the parent's logger is still the current one: start from its base so its span fields aren't repeated
+		base = withMinLevel(ctx, parent.base)
+	}
+	if len(fs) > 0 {
+		base = base.With(fs...)
+	}
+
+	id := newSpanID()
+	span := []fields.Field{fields.String(SpanNameKey, name), fields.String(SpanIDKey, id)}
+	if parent, ok := ctx.Value(spanKey{}).(*Span); ok {
+		span = append(span, fields.String(SpanParentIDKey, parent.id))
+	}
+
+	l := base.With(span...)
+	s := &Span{
+		id:   id,
+		base: base,
+		l:    &stored{l: l},
+		// skip StartSpan or End so file:line points at the caller
+		self:  logstox.WithOptions(l, logstox.AddCallerSkip(1)),
+		start: time.Now(),
+	}
+	s.self.Debug("span started")
+
+	// the span doubles as the context's logger, see Logger
+	ctx = context.WithValue(ctx, spanKey{}, s)
+	return context.WithValue(ctx, loggerKey{}, s), s
+}
+
+// Logger returns the span's logger, the one StartSpan stored in the context, or nil for a nil Span.
+func (s *Span) Logger() logstox.Logger[fields.Field] {
+	if s == nil {
+		return nil
+	}
+	return s.l.l
+}
+
+// End logs a "span ended" entry with the span's duration and outcome: at InfoLevel with SpanSuccess if err is nil,
+// at ErrorLevel with SpanFailure and err otherwise. Only the first call logs, and it's a no-op on a nil Span.
+func (s *Span) End(err error) {
+	if s == nil || !s.ended.CompareAndSwap(false, true) {
+		return
+	}
+	d := fields.Duration(SpanDurationKey, time.Since(s.start))
+	if err != nil {
+		s.self.Error("span ended", d, fields.String(SpanOutcomeKey, SpanFailure), fields.Error(err))
+		return
+	}
+	s.self.Info("span ended", d, fields.String(SpanOutcomeKey, SpanSuccess))
+}
+
+// newSpanID returns 8 random bytes, hex encoded, like a W3C span ID.
+func newSpanID() string {
+	var b [8]byte
+	_, _ = rand.Read(b[:]) // never returns an error
+	return hex.EncodeToString(b[:])
+}
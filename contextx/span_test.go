@@ -0,0 +1,76 @@
+package contextx
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/khinshankhan/logstox"
+	"github.com/khinshankhan/logstox/backend/memx"
+	"github.com/khinshankhan/logstox/fields"
+)
+
+func TestStartSpanWithoutALogger(t *testing.T) {
+	ctx := context.Background()
+	got, span := StartSpan(ctx, nil, "op")
+	if got != ctx || span != nil {
+		t.Fatalf("StartSpan = %v, %v, want ctx as is and a nil Span", got, span)
+	}
+	span.End(errors.New("boom")) // no-op
+	if l := span.Logger(); l != nil {
+		t.Errorf("Logger() = %v, want nil", l)
+	}
+}
+
+func TestSpan(t *testing.T) {
+	rec := memx.NewRecorder(8)
+	log := memx.Backend{Recorder: rec}.New(logstox.Options[fields.Field]{Level: logstox.DebugLevel})
+	ctx, parent := StartSpan(context.Background(), log, "request", fields.String("user", "ada"))
+	ctx, child := StartSpan(ctx, nil, "query")
+	Logger(ctx, nil).Info("inside")
+	child.End(errors.New("timeout"))
+	child.End(nil) // only the first call logs
+	parent.End(nil)
+
+	es := rec.Entries()
+	want := []struct {
+		msg, name, outcome string
+		level              logstox.Level
+	}{
+		{"span started", "request", "", logstox.DebugLevel},
+		{"span started", "query", "", logstox.DebugLevel},
+		{"inside", "query", "", logstox.InfoLevel},
+		{"span ended", "query", SpanFailure, logstox.ErrorLevel},
+		{"span ended", "request", SpanSuccess, logstox.InfoLevel},
+	}
+	if len(es) != len(want) {
+		t.Fatalf("logged %d entries, want %d", len(es), len(want))
+	}
+	for i, w := range want {
+		fs := fields.Fields(es[i].Fields)
+		name, _ := fs.Get(SpanNameKey)
+		outcome, _ := fs.Get(SpanOutcomeKey)
+		if es[i].Message != w.msg || name.Str() != w.name || outcome.Str() != w.outcome || es[i].Level != w.level {
+			t.Errorf("entry %d: %s of %q, %q at %v, want %s of %q, %q at %v", i, es[i].Message, name.Str(),
+				outcome.Str(), es[i].Level, w.msg, w.name, w.outcome, w.level)
+		}
+		if user, _ := fs.Get("user"); user.Str() != "ada" {
+			t.Errorf("entry %d: user = %v, want the fields inherited from the enclosing span", i, user)
+		}
+	}
+
+	inside := fields.Fields(es[2].Fields)
+	parentID, _ := inside.Get(SpanParentIDKey)
+	if id, _ := fields.Fields(es[0].Fields).Get(SpanIDKey); parentID.Str() != id.Str() {
+		t.Errorf("%s = %q, want the enclosing span's ID %q", SpanParentIDKey, parentID.Str(), id.Str())
+	}
+	var names int
+	for _, f := range inside {
+		if f.Key == SpanNameKey {
+			names++
+		}
+	}
+	if names != 1 {
+		t.Errorf("nested span entry has %d %s fields, want 1", names, SpanNameKey)
+	}
+}
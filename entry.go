@@ -0,0 +1,22 @@
+package logstox
+
+import (
+	"context"
+	"time"
+
+	"github.com/khinshankhan/logstox/fields"
+)
+
+// Entry is a single log event in a backend-agnostic shape.
+// It's what middleware and sinks see before (or instead of) a backend encoding it.
+type Entry struct {
+	Time    time.Time      // when the entry was created
+	Level   Level          // severity, middleware may rewrite it
+	Name    string         // logger name, segments joined by periods
+	Message string         // the log message
+	Fields  []fields.Field // accumulated context fields followed by call-site fields
+
+	// Context is the context the logger was bound to with WithContext, or nil if it wasn't. Middleware can read
+	// request-scoped state from it, eg whether the active trace is sampled.
+	Context context.Context
+}
@@ -0,0 +1,325 @@
+package logstox
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/khinshankhan/logstox/fields"
+)
+
+// EntrySchemaVersion is the version of the wire schema Entry's MarshalJSON writes. UnmarshalJSON reads it and every
+// earlier version, and rejects later ones.
+const EntrySchemaVersion = 1
+
+// wireEntry is the wire schema of an Entry:
+//
+//	{"v":1,"time":"2025-01-02T15:04:05.123Z","level":"info","name":"db","msg":"query done",
+//	 "fields":[{"k":"rows","t":"int64","v":12},{"k":"elapsed","t":"duration","v":1500000}]}
+//
+// Fields are tagged with their kind (fields.FieldKind.String) so they're read back as they were built.
+type wireEntry struct {
+	Version int             `json:"v"`
+	Time    time.Time       `json:"time,omitzero"`
+	Level   json.RawMessage `json:"level"`
+	Name    string          `json:"name,omitempty"`
+	Message string          `json:"msg"`
+	Fields  []wireField     `json:"fields,omitempty"`
+}
+
+// wireField is the wire schema of a field. Values are encoded by kind:
+//
+//   - strings, bools and numbers as themselves, float64s that JSON can't represent as "NaN", "+Inf" or "-Inf"
+//   - durations as nanoseconds, times as RFC 3339 with nanoseconds, errors as their message (null for nil)
+//   - slices as arrays of the above, dicts as arrays of fields
+//   - raw JSON inline, hex bytes as hex, timestamps as their time (omitted for "now")
+//   - anything else (fields.Any) as encoding/json encodes it
+type wireField struct {
+	Key   string          `json:"k"`
+	Kind  string          `json:"t"`
+	Value json.RawMessage `json:"v,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler, writing e in the versioned wire schema (see EntrySchemaVersion), so tools
+// can read entries back losslessly with UnmarshalJSON and route them further. Lazy fields are evaluated (handed
+// e.Context, or context.Background if it's nil) and precomputed bundles expanded; the Context itself isn't written.
+// Values of fields.Any fields are encoded by encoding/json and read back as generic JSON values.
+func (e Entry) MarshalJSON() ([]byte, error) {
+	ctx := e.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	fs, err := marshalFields(ctx, nil, e.Fields)
+	if err != nil {
+		return nil, err
+	}
+	var lvl []byte
+	if e.Level.Valid() {
+		lvl = strconv.AppendQuote(nil, e.Level.String())
+	} else {
+		lvl = strconv.AppendInt(nil, int64(e.Level), 10)
+	}
+	return json.Marshal(wireEntry{
+		Version: EntrySchemaVersion,
+		Time:    e.Time,
+		Level:   lvl,
+		Name:    e.Name,
+		Message: e.Message,
+		Fields:  fs,
+	})
+}
+
+// UnmarshalJSON implements json.Unmarshaler, reading an entry written by MarshalJSON.
+func (e *Entry) UnmarshalJSON(data []byte) error {
+	var w wireEntry
+	if err := json.Unmarshal(data, &w); err != nil {
+		return err
+	}
+	if w.Version < 1 || w.Version > EntrySchemaVersion {
+		return fmt.Errorf("unsupported entry schema version %d", w.Version)
+	}
+	lvl, err := unmarshalWireLevel(w.Level)
+	if err != nil {
+		return err
+	}
+	fs, err := unmarshalFields(w.Fields)
+	if err != nil {
+		return err
+	}
+	*e = Entry{Time: w.Time, Level: lvl, Name: w.Name, Message: w.Message, Fields: fs}
+	return nil
+}
+
+// unmarshalWireLevel reads a level written by name, or as a number for levels that aren't Valid.
+func unmarshalWireLevel(raw json.RawMessage) (Level, error) {
+	if len(raw) > 0 && raw[0] == '"' {
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return 0, err
+		}
+		return ParseLevel(s)
+	}
+	n, err := strconv.ParseInt(string(raw), 10, 8)
+	if err != nil {
+		return 0, fmt.Errorf("cannot unmarshal level %s", raw)
+	}
+	return Level(n), nil
+}
+
+// marshalFields appends fs to dst in the wire schema, evaluating lazy fields with ctx and expanding bundles.
+func marshalFields(ctx context.Context, dst []wireField, fs []fields.Field) ([]wireField, error) {
+	for _, f := range fields.Resolve(ctx, nil, fs) {
+		var v any
+		switch f.Kind() {
+		case fields.FieldKindFloat64:
+			v = wireFloat(f.Float64())
+		case fields.FieldKindFloat64s:
+			vs := f.Interface().([]float64)
+			out := make([]any, len(vs))
+			for i, x := range vs {
+				out[i] = wireFloat(x)
+			}
+			v = out
+		case fields.FieldKindDuration:
+			v = int64(f.Duration())
+		case fields.FieldKindTime:
+			v = f.Time()
+		case fields.FieldKindTimestamp:
+			if t := f.Time(); !t.IsZero() {
+				v = t
+			}
+		case fields.FieldKindError:
+			v = errorMessage(f.Err())
+		case fields.FieldKindErrors:
+			errs := f.Interface().([]error)
+			out := make([]any, len(errs))
+			for i, err := range errs {
+				out[i] = errorMessage(err)
+			}
+			v = out
+		case fields.FieldKindDict:
+			sub, err := marshalFields(ctx, []wireField{}, f.Fields())
+			if err != nil {
+				return nil, err
+			}
+			v = sub
+		case fields.FieldKindRawJSON:
+			v = json.RawMessage(f.Bytes())
+		case fields.FieldKindHexBytes:
+			v = hex.EncodeToString(f.Bytes())
+		default:
+			v = f.Boxed()
+		}
+
+		w := wireField{Key: f.Key, Kind: f.Kind().String()}
+		if v != nil {
+			raw, err := json.Marshal(v)
+			if err != nil {
+				return nil, fmt.Errorf("marshaling field %q: %w", f.Key, err)
+			}
+			w.Value = raw
+		}
+		dst = append(dst, w)
+	}
+	return dst, nil
+}
+
+// wireFloat returns f, or its name if JSON can't represent it.
+func wireFloat(f float64) any {
+	switch {
+	case math.IsNaN(f):
+		return "NaN"
+	case math.IsInf(f, 1):
+		return "+Inf"
+	case math.IsInf(f, -1):
+		return "-Inf"
+	default:
+		return f
+	}
+}
+
+// errorMessage returns err's message, or nil for a nil error.
+func errorMessage(err error) any {
+	if err == nil {
+		return nil
+	}
+	return err.Error()
+}
+
+// unmarshalFields reads fields written by marshalFields.
+func unmarshalFields(ws []wireField) ([]fields.Field, error) {
+	fs := make([]fields.Field, 0, len(ws))
+	for _, w := range ws {
+		f, err := unmarshalField(w)
+		if err != nil {
+			return nil, fmt.Errorf("unmarshaling field %q: %w", w.Key, err)
+		}
+		fs = append(fs, f)
+	}
+	return fs, nil
+}
+
+// unmarshalField reads a field written by marshalFields.
+func unmarshalField(w wireField) (fields.Field, error) {
+	k := w.Key
+	switch w.Kind {
+	case "string":
+		v, err := decode[string](w.Value)
+		return fields.String(k, v), err
+	case "bool":
+		v, err := decode[bool](w.Value)
+		return fields.Bool(k, v), err
+	case "int64":
+		v, err := decode[int64](w.Value)
+		return fields.Int64(k, v), err
+	case "uint64":
+		v, err := decode[uint64](w.Value)
+		return fields.Uint64(k, v), err
+	case "float64":
+		v, err := decodeFloat(w.Value)
+		return fields.Float64(k, v), err
+	case "time":
+		v, err := decode[time.Time](w.Value)
+		return fields.TimeField(k, v), err
+	case "duration":
+		v, err := decode[int64](w.Value)
+		return fields.Duration(k, time.Duration(v)), err
+	case "error":
+		v, err := decode[*string](w.Value)
+		if err != nil || v == nil {
+			return fields.Nop(), err
+		}
+		return fields.NamedError(k, errors.New(*v)), nil
+	case "strings":
+		v, err := decode[[]string](w.Value)
+		return fields.Strings(k, v), err
+	case "bools":
+		v, err := decode[[]bool](w.Value)
+		return fields.Bools(k, v), err
+	case "int64s":
+		v, err := decode[[]int64](w.Value)
+		return fields.Int64s(k, v), err
+	case "uint64s":
+		v, err := decode[[]uint64](w.Value)
+		return fields.Uint64s(k, v), err
+	case "float64s":
+		raws, err := decode[[]json.RawMessage](w.Value)
+		if err != nil {
+			return fields.Field{}, err
+		}
+		v := make([]float64, len(raws))
+		for i, raw := range raws {
+			if v[i], err = decodeFloat(raw); err != nil {
+				return fields.Field{}, err
+			}
+		}
+		return fields.Float64s(k, v), nil
+	case "errors":
+		msgs, err := decode[[]*string](w.Value)
+		if err != nil {
+			return fields.Field{}, err
+		}
+		v := make([]error, len(msgs))
+		for i, msg := range msgs {
+			if msg != nil {
+				v[i] = errors.New(*msg)
+			}
+		}
+		return fields.Errors(k, v), nil
+	case "dict":
+		ws, err := decode[[]wireField](w.Value)
+		if err != nil {
+			return fields.Field{}, err
+		}
+		sub, err := unmarshalFields(ws)
+		return fields.Dict(k, sub...), err
+	case "rawjson":
+		return fields.RawJSON(k, bytes.Clone(w.Value)), nil
+	case "hexbytes":
+		s, err := decode[string](w.Value)
+		if err != nil {
+			return fields.Field{}, err
+		}
+		v, err := hex.DecodeString(s)
+		return fields.Hex(k, v), err
+	case "timestamp":
+		if len(w.Value) == 0 {
+			return fields.TimestampAt(k, time.Time{}), nil
+		}
+		v, err := decode[time.Time](w.Value)
+		return fields.TimestampAt(k, v), err
+	case "any":
+		v, err := decode[any](w.Value)
+		return fields.Any(k, v), err
+	default:
+		return fields.Field{}, fmt.Errorf("unknown kind %q", w.Kind)
+	}
+}
+
+// decode unmarshals raw into a T, raw being absent for nil values.
+func decode[T any](raw json.RawMessage) (T, error) {
+	var v T
+	if len(raw) == 0 {
+		return v, nil
+	}
+	err := json.Unmarshal(raw, &v)
+	return v, err
+}
+
+// decodeFloat reads a float written by wireFloat.
+func decodeFloat(raw json.RawMessage) (float64, error) {
+	if len(raw) > 0 && raw[0] == '"' {
+		s, err := decode[string](raw)
+		if err != nil {
+			return 0, err
+		}
+		return strconv.ParseFloat(s, 64) // accepts NaN and ±Inf
+	}
+	return decode[float64](raw)
+}
@@ -0,0 +1,118 @@
+package events
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/khinshankhan/logstox"
+	"github.com/khinshankhan/logstox/fields"
+)
+
+// Keys the catalog middleware appends, following ECS where it has them.
+const (
+	DescriptionKey = "event.description"
+	ReferenceKey   = "event.reference" // documentation URL
+)
+
+// Event describes an event code.
+type Event struct {
+	Code        string
+	Description string // human-readable description, omitted from entries if empty
+	URL         string // documentation or runbook URL, omitted from entries if empty
+}
+
+// Catalog maps event codes (see fields.EventCode) to their descriptions. It's safe for concurrent use.
+type Catalog struct {
+	// OnUnknown, if set, is called with codes logged without being registered, eg to fail tests.
+	OnUnknown func(code string)
+
+	mu     sync.RWMutex
+	events map[string]Event
+}
+
+// NewCatalog returns a Catalog with events registered, panicking on duplicate or empty codes like a bad
+// regexp.MustCompile, since catalogs are usually static:
+//
+//	var catalog = events.NewCatalog(
+//		events.Event{Code: "PAY-001", Description: "payment declined", URL: "https://runbooks.example.com/pay-001"},
+//	)
+func NewCatalog(events ...Event) *Catalog {
+	c := &Catalog{}
+	for _, e := range events {
+		if err := c.Register(e); err != nil {
+			panic(err)
+		}
+	}
+	return c
+}
+
+// Register adds e to the catalog. It fails if e's code is empty or already registered.
+func (c *Catalog) Register(e Event) error {
+	if e.Code == "" {
+		return fmt.Errorf("events: empty event code")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.events[e.Code]; ok {
+		return fmt.Errorf("events: event code %q already registered", e.Code)
+	}
+	if c.events == nil {
+		c.events = make(map[string]Event)
+	}
+	c.events[e.Code] = e
+	return nil
+}
+
+// Lookup returns the event registered for code.
+func (c *Catalog) Lookup(code string) (Event, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	e, ok := c.events[code]
+	return e, ok
+}
+
+// Events returns the registered events, in no particular order, eg to render documentation.
+func (c *Catalog) Events() []Event {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	out := make([]Event, 0, len(c.events))
+	for _, e := range c.events {
+		out = append(out, e)
+	}
+	return out
+}
+
+// Middleware returns a middleware appending the description and URL of the entry's event code (the first top-level
+// fields.EventCodeKey field) under DescriptionKey and ReferenceKey. Entries without a code pass through unchanged.
+func (c *Catalog) Middleware() logstox.Middleware {
+	return func(e logstox.Entry) (logstox.Entry, bool) {
+		code, ok := eventCode(e.Fields)
+		if !ok {
+			return e, true
+		}
+		ev, ok := c.Lookup(code)
+		if !ok {
+			if c.OnUnknown != nil {
+				c.OnUnknown(code)
+			}
+			return e, true
+		}
+		if ev.Description != "" {
+			e.Fields = append(e.Fields, fields.String(DescriptionKey, ev.Description))
+		}
+		if ev.URL != "" {
+			e.Fields = append(e.Fields, fields.String(ReferenceKey, ev.URL))
+		}
+		return e, true
+	}
+}
+
+// eventCode returns the value of the first event code field in fs.
+func eventCode(fs []fields.Field) (string, bool) {
+	for _, f := range fs {
+		if f.Key == fields.EventCodeKey && f.Kind() == fields.FieldKindString {
+			return f.Str(), true
+		}
+	}
+	return "", false
+}
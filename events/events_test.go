@@ -0,0 +1,110 @@
+package events
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/khinshankhan/logstox"
+	"github.com/khinshankhan/logstox/backend/memx"
+	"github.com/khinshankhan/logstox/fields"
+)
+
+// payments returns a catalog of payment events.
+func payments() *Catalog {
+	return NewCatalog(
+		Event{Code: "PAY-001", Description: "payment declined", URL: "https://runbooks.example.com/pay-001"},
+		Event{Code: "PAY-002", Description: "refund issued"},
+		Event{Code: "PAY-003"},
+	)
+}
+
+// describe formats fs as "key=value" pairs.
+func describe(fs []fields.Field) string {
+	out := make([]string, len(fs))
+	for i, f := range fs {
+		out[i] = f.Key + "=" + f.Str()
+	}
+	return strings.Join(out, " ")
+}
+
+func TestMiddleware(t *testing.T) {
+	tests := []struct {
+		name    string
+		fields  []fields.Field
+		want    string
+		unknown []string
+	}{
+		{"description and URL", []fields.Field{fields.EventCode("PAY-001")},
+			"event.code=PAY-001 event.description=payment declined event.reference=https://runbooks.example.com/pay-001",
+			nil},
+		{"description only", []fields.Field{fields.EventCode("PAY-002"), fields.String("k", "v")},
+			"event.code=PAY-002 k=v event.description=refund issued", nil},
+		{"code only", []fields.Field{fields.EventCode("PAY-003")}, "event.code=PAY-003", nil},
+		{"first code", []fields.Field{fields.EventCode("PAY-002"), fields.EventCode("PAY-001")},
+			"event.code=PAY-002 event.code=PAY-001 event.description=refund issued", nil},
+		{"unknown", []fields.Field{fields.EventCode("PAY-404")}, "event.code=PAY-404", []string{"PAY-404"}},
+		{"no code", []fields.Field{fields.String("k", "v")}, "k=v", nil},
+		{"not a string", []fields.Field{fields.Int(fields.EventCodeKey, 1)}, "event.code=", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var unknown []string
+			c := payments()
+			c.OnUnknown = func(code string) { unknown = append(unknown, code) }
+
+			e, ok := c.Middleware()(logstox.Entry{Fields: tt.fields})
+			if !ok {
+				t.Fatal("entry dropped")
+			}
+			if got := describe(e.Fields); got != tt.want {
+				t.Errorf("fields %s, want %s", got, tt.want)
+			}
+			if !slices.Equal(unknown, tt.unknown) {
+				t.Errorf("reported %v unknown, want %v", unknown, tt.unknown)
+			}
+		})
+	}
+}
+
+func TestMiddlewareLogged(t *testing.T) {
+	rec := memx.NewRecorder(1)
+	base := memx.Backend{Recorder: rec}.New(logstox.Options[fields.Field]{})
+	log := logstox.WithMiddleware(base, payments().Middleware())
+	log.Error("charge failed", fields.EventCode("PAY-001"))
+
+	if d, _ := fields.Fields(rec.Entries()[0].Fields).Get(DescriptionKey); d.Str() != "payment declined" {
+		t.Errorf("%s = %q, want payment declined", DescriptionKey, d.Str())
+	}
+}
+
+func TestRegister(t *testing.T) {
+	c := &Catalog{}
+	if err := c.Register(Event{Code: "A", Description: "a"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Register(Event{Code: "A"}); err == nil {
+		t.Error("registered a duplicate code")
+	}
+	if err := c.Register(Event{}); err == nil {
+		t.Error("registered an empty code")
+	}
+	if e, ok := c.Lookup("A"); !ok || e.Description != "a" {
+		t.Errorf("Lookup(A) = %v, %v, want the first registration", e, ok)
+	}
+	if _, ok := c.Lookup("B"); ok {
+		t.Error("Lookup(B) found an unregistered code")
+	}
+	if es := c.Events(); len(es) != 1 || es[0].Code != "A" {
+		t.Errorf("Events = %v, want A", es)
+	}
+}
+
+func TestNewCatalogPanicsOnDuplicates(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("NewCatalog didn't panic")
+		}
+	}()
+	NewCatalog(Event{Code: "A"}, Event{Code: "A"})
+}
@@ -0,0 +1,32 @@
+package fields
+
+import (
+	"runtime/debug"
+)
+
+// BuildInfoKey is the key used by BuildInfo.
+const BuildInfoKey = "build"
+
+// BuildInfo groups what the running binary was built from under BuildInfoKey ("build"): the main module path and
+// version plus the vcs.revision, vcs.time and vcs.modified (dirty) settings stamped by the Go toolchain.
+// Settings the toolchain didn't record are omitted. If build info is unavailable, returns a no-op.
+func BuildInfo() Field {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return Nop()
+	}
+
+	fs := []Field{
+		String("module.path", info.Main.Path),
+		String("module.version", info.Main.Version),
+	}
+	for _, s := range info.Settings {
+		switch s.Key {
+		case "vcs.revision", "vcs.time":
+			fs = append(fs, String(s.Key, s.Value))
+		case "vcs.modified":
+			fs = append(fs, Bool("vcs.dirty", s.Value == "true"))
+		}
+	}
+	return Dict(BuildInfoKey, fs...)
+}
@@ -0,0 +1,75 @@
+package fields
+
+import (
+	"cmp"
+	"slices"
+)
+
+// Fields is a collection of fields with the operations middleware and application code commonly need. It converts
+// to and from []Field freely:
+//
+//	fs := fields.Fields(e.Fields)
+//	if f, ok := fs.Get("user"); ok { ... }
+//	e.Fields = fs.Delete("password")
+//
+// Methods that return Fields may reuse fs's backing array, like append does; Clone first to keep fs intact.
+type Fields []Field
+
+// Append returns fs with more appended.
+func (fs Fields) Append(more ...Field) Fields {
+	return append(fs, more...)
+}
+
+// Merge returns fs with the fields of other added. The fields whose key fs already has replace the first such field
+// of fs if overwrite is set and are left out otherwise; the others are appended in order.
+func (fs Fields) Merge(other []Field, overwrite bool) Fields {
+	for _, f := range other {
+		i := fs.index(f.Key)
+		switch {
+		case i < 0:
+			fs = append(fs, f)
+		case overwrite:
+			fs[i] = f
+		}
+	}
+	return fs
+}
+
+// Get returns the first field with key k, no-op fields aside.
+func (fs Fields) Get(k string) (Field, bool) {
+	if i := fs.index(k); i >= 0 {
+		return fs[i], true
+	}
+	return Field{}, false
+}
+
+// index returns the index of the first field with key k, no-op fields aside, or -1.
+func (fs Fields) index(k string) int {
+	return slices.IndexFunc(fs, func(f Field) bool { return f.Key == k && !f.IsZero() })
+}
+
+// Delete returns fs without the fields with key k, removing them in place.
+func (fs Fields) Delete(k string) Fields {
+	return slices.DeleteFunc(fs, func(f Field) bool { return f.Key == k })
+}
+
+// Clone returns a copy of fs, recursively copying Dicts so the copy can be modified in place.
+func (fs Fields) Clone() Fields {
+	if fs == nil {
+		return nil
+	}
+	out := slices.Clone(fs)
+	for i, f := range out {
+		if f.kind == FieldKindDict {
+			out[i].iface = []Field(Fields(f.Fields()).Clone())
+		}
+	}
+	return out
+}
+
+// Sort sorts fs by key in place and returns it. Fields with the same key keep their order; Dicts aren't sorted, see
+// middleware.SortKeys for that.
+func (fs Fields) Sort() Fields {
+	slices.SortStableFunc(fs, func(a, b Field) int { return cmp.Compare(a.Key, b.Key) })
+	return fs
+}
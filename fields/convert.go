@@ -0,0 +1,184 @@
+package fields
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+)
+
+// maxConvertDepth bounds how deep FromMap and FromStruct turn nested maps and structs into Dicts, so cyclic values
+// end. Deeper values are logged with Any.
+const maxConvertDepth = 16
+
+// Redacted is the value FromHeader writes for the headers in SensitiveHeaders.
+const Redacted = "[REDACTED]"
+
+// SensitiveHeaders are the headers whose values FromHeader replaces by Redacted, as they carry credentials. They're
+// matched case-insensitively.
+var SensitiveHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// FromMap returns a field per entry of m, sorted by key, typed like From. Nested maps with string keys become
+// Dicts, and slices of strings, bools, int64s, uint64s and float64s the matching slice fields.
+func FromMap(m map[string]any) Fields {
+	return mapFields(reflect.ValueOf(m), 0)
+}
+
+// FromStruct returns a field per exported field of the struct v (or the struct v points to), in declaration order,
+// nil if v isn't one. Fields are keyed by their `log` tag, or else their `json` tag, or else their name; "-" skips
+// them, and embedded structs without a tag are inlined:
+//
+//	type Order struct {
+//		ID     string  `log:"order_id"`
+//		Total  float64 `json:"total"`
+//		Secret string  `log:"-"`
+//	}
+//	log.Info("order placed", fields.FromStruct(order)...)
+//
+// Values are typed like FromMap's, nested structs and maps becoming Dicts.
+func FromStruct(v any) Fields {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil
+	}
+	return structFields(rv, 0)
+}
+
+// FromURLValues returns a field per key of v, sorted by key: a String if it has one value, Strings otherwise. It
+// takes url.Values as is.
+func FromURLValues(v map[string][]string) Fields {
+	return multiFields(v, nil)
+}
+
+// FromHeader returns a field per header of h, sorted by key: a String if it has one value, Strings otherwise, with
+// SensitiveHeaders redacted. It takes http.Header as is, or any map of header names.
+func FromHeader(h map[string][]string) Fields {
+	return multiFields(h, SensitiveHeaders)
+}
+
+// multiFields returns the fields of the multi-valued map m, redacting the values of the keys in redact, whatever
+// their case.
+func multiFields(m map[string][]string, redact []string) Fields {
+	fs := make(Fields, 0, len(m))
+	for k, vs := range m {
+		switch {
+		case slices.ContainsFunc(redact, func(r string) bool { return strings.EqualFold(r, k) }):
+			fs = append(fs, String(k, Redacted))
+		case len(vs) == 1:
+			fs = append(fs, String(k, vs[0]))
+		default:
+			fs = append(fs, Strings(k, vs))
+		}
+	}
+	return fs.Sort()
+}
+
+// mapFields returns the fields of the map m, sorted by key, if its keys are strings.
+func mapFields(m reflect.Value, depth int) Fields {
+	if m.Kind() != reflect.Map || m.Type().Key().Kind() != reflect.String {
+		return nil
+	}
+	fs := make(Fields, 0, m.Len())
+	iter := m.MapRange()
+	for iter.Next() {
+		fs = append(fs, convert(iter.Key().String(), iter.Value(), depth))
+	}
+	return fs.Sort()
+}
+
+// structFields returns the fields of the struct s, see FromStruct.
+func structFields(s reflect.Value, depth int) Fields {
+	t := s.Type()
+	fs := make(Fields, 0, t.NumField())
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		k, tagged := fieldKey(sf)
+		if k == "-" {
+			continue
+		}
+		v := s.Field(i)
+		if sf.Anonymous && !tagged {
+			for v.Kind() == reflect.Pointer && !v.IsNil() {
+				v = v.Elem()
+			}
+			// inlining counts as a level too, so a struct embedding a pointer to itself ends
+			if v.Kind() == reflect.Struct && v.Type() != timeType && depth < maxConvertDepth {
+				fs = append(fs, structFields(v, depth+1)...)
+				continue
+			}
+		}
+		fs = append(fs, convert(k, v, depth))
+	}
+	return fs
+}
+
+// fieldKey returns the key of the struct field sf, reporting whether it comes from a tag.
+func fieldKey(sf reflect.StructField) (string, bool) {
+	for _, tag := range []string{"log", "json"} {
+		if v, ok := sf.Tag.Lookup(tag); ok {
+			if name, _, _ := strings.Cut(v, ","); name != "" {
+				return name, true
+			}
+		}
+	}
+	return sf.Name, false
+}
+
+// timeType is the type of time.Time, a struct logged as a time.
+var timeType = reflect.TypeFor[time.Time]()
+
+// convert returns v as a field keyed k, nested maps and structs up to maxConvertDepth becoming Dicts.
+func convert(k string, v reflect.Value, depth int) Field {
+	if !v.IsValid() {
+		return Any(k, nil)
+	}
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+		if v.IsNil() {
+			return Any(k, nil)
+		}
+	}
+	switch x := v.Interface().(type) {
+	case time.Time, time.Duration, error:
+		return From(k, x)
+	case []string:
+		return Strings(k, x)
+	case []bool:
+		return Bools(k, x)
+	case []int64:
+		return Int64s(k, x)
+	case []uint64:
+		return Uint64s(k, x)
+	case []float64:
+		return Float64s(k, x)
+	}
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		return convert(k, v.Elem(), depth)
+	case reflect.String:
+		return String(k, v.String())
+	case reflect.Bool:
+		return Bool(k, v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return Int64(k, v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Uint64(k, v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return Float64(k, v.Float())
+	case reflect.Struct:
+		if depth < maxConvertDepth {
+			return Dict(k, structFields(v, depth+1)...)
+		}
+	case reflect.Map:
+		if depth < maxConvertDepth && v.Type().Key().Kind() == reflect.String {
+			return Dict(k, mapFields(v, depth+1)...)
+		}
+	}
+	return Any(k, v.Interface())
+}
@@ -0,0 +1,92 @@
+package fields
+
+import (
+	"slices"
+	"testing"
+)
+
+type Node struct {
+	*Node
+	Name string
+}
+
+func TestFromStructSelfEmbedding(t *testing.T) {
+	n := &Node{Name: "root"}
+	n.Node = n
+
+	fs := FromStruct(n) // mustn't recurse forever
+	if len(fs) == 0 {
+		t.Fatal("FromStruct returned no fields")
+	}
+	if last := fs[len(fs)-1]; last.Key != "Name" || last.Str() != "root" {
+		t.Errorf("last field = %s %v, want Name root", last.Key, last.Boxed())
+	}
+}
+
+func TestFromStruct(t *testing.T) {
+	type Inner struct {
+		A int `log:"a"`
+	}
+	type outer struct {
+		Inner
+		Skip  string `log:"-"`
+		Named string `json:"named,omitempty"`
+		In    Inner
+	}
+	fs := FromStruct(outer{Inner: Inner{A: 1}, Skip: "x", Named: "n", In: Inner{A: 2}})
+
+	var keys []string
+	for _, f := range fs {
+		keys = append(keys, f.Key)
+	}
+	if want := []string{"a", "named", "In"}; !slices.Equal(keys, want) {
+		t.Fatalf("keys = %v, want %v", keys, want)
+	}
+	if got := fs[2].Fields(); len(got) != 1 || got[0].Int64() != 2 {
+		t.Errorf("In = %v, want a Dict holding a=2", fs[2].Boxed())
+	}
+}
+
+func TestFromHeader(t *testing.T) {
+	tests := []struct {
+		name   string
+		header map[string][]string
+		want   map[string]any
+	}{
+		{
+			name:   "canonical",
+			header: map[string][]string{"Authorization": {"Bearer x"}, "Accept": {"a", "b"}},
+			want:   map[string]any{"Authorization": Redacted, "Accept": []string{"a", "b"}},
+		},
+		{
+			name:   "lower case",
+			header: map[string][]string{"authorization": {"Bearer x"}, "cookie": {"c"}, "x-id": {"1"}},
+			want:   map[string]any{"authorization": Redacted, "cookie": Redacted, "x-id": "1"},
+		},
+		{
+			name:   "upper case",
+			header: map[string][]string{"SET-COOKIE": {"s"}},
+			want:   map[string]any{"SET-COOKIE": Redacted},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := FromHeader(tt.header)
+			if len(fs) != len(tt.want) {
+				t.Fatalf("got %d fields, want %d", len(fs), len(tt.want))
+			}
+			for _, f := range fs {
+				switch want := tt.want[f.Key].(type) {
+				case string:
+					if f.Str() != want {
+						t.Errorf("%s = %q, want %q", f.Key, f.Str(), want)
+					}
+				case []string:
+					if got, _ := f.Boxed().([]string); !slices.Equal(got, want) {
+						t.Errorf("%s = %v, want %v", f.Key, got, want)
+					}
+				}
+			}
+		})
+	}
+}
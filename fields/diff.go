@@ -0,0 +1,99 @@
+package fields
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Diff compares actual against expected, returning a readable report of the missing, extra and mismatched fields,
+// one per line, or "" if they match:
+//
+//	if d := fields.Diff(want, entry.Fields); d != "" {
+//		t.Errorf("fields mismatch:\n%s", d)
+//	}
+//
+// Fields are paired by key, in order when a key repeats, and Dicts are compared recursively, their fields reported
+// by dotted path. Lazy fields are evaluated and precomputed bundles expanded, and no-op fields are ignored. Values
+// are compared by kind and value: errors by message, times by instant.
+func Diff(expected, actual []Field) string {
+	var b strings.Builder
+	diff(&b, "", expected, actual)
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// diff writes the differences between expected and actual to b, their keys prefixed by prefix.
+func diff(b *strings.Builder, prefix string, expected, actual []Field) {
+	ctx := context.Background()
+	expected = Resolve(ctx, nil, expected)
+	actual = Resolve(ctx, nil, actual)
+
+	matched := make([]bool, len(actual))
+	for _, want := range expected {
+		i := -1
+		for j, got := range actual {
+			if !matched[j] && got.Key == want.Key {
+				i = j
+				break
+			}
+		}
+		if i < 0 {
+			fmt.Fprintf(b, "missing %s%s: %s\n", prefix, want.Key, describe(want))
+			continue
+		}
+		matched[i] = true
+		got := actual[i]
+		switch {
+		case want.kind == FieldKindDict && got.kind == FieldKindDict:
+			diff(b, prefix+want.Key+".", want.Fields(), got.Fields())
+		case !equal(want, got):
+			fmt.Fprintf(b, "mismatched %s%s: want %s, got %s\n", prefix, want.Key, describe(want), describe(got))
+		}
+	}
+	for j, got := range actual {
+		if !matched[j] {
+			fmt.Fprintf(b, "extra %s%s: %s\n", prefix, got.Key, describe(got))
+		}
+	}
+}
+
+// equal reports whether a and b have the same kind and value.
+func equal(a, b Field) bool {
+	if a.kind != b.kind {
+		return false
+	}
+	switch a.kind {
+	case FieldKindError:
+		return errorText(a.Err()) == errorText(b.Err())
+	case FieldKindTime:
+		return a.Time().Equal(b.Time())
+	case FieldKindFloat64:
+		x, y := a.Float64(), b.Float64()
+		return x == y || x != x && y != y // NaNs are alike
+	default:
+		return reflect.DeepEqual(a.Boxed(), b.Boxed())
+	}
+}
+
+// describe formats the value and kind of f for Diff.
+func describe(f Field) string {
+	switch f.kind {
+	case FieldKindString:
+		return fmt.Sprintf("%q (%s)", f.Str(), f.kind)
+	case FieldKindError:
+		return fmt.Sprintf("%q (%s)", errorText(f.Err()), f.kind)
+	case FieldKindRawJSON:
+		return fmt.Sprintf("%s (%s)", f.Bytes(), f.kind)
+	default:
+		return fmt.Sprintf("%v (%s)", f.Boxed(), f.kind)
+	}
+}
+
+// errorText returns the message of err, "<nil>" if it's nil.
+func errorText(err error) string {
+	if err == nil {
+		return "<nil>"
+	}
+	return err.Error()
+}
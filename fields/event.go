@@ -0,0 +1,13 @@
+package fields
+
+// EventCodeKey is the key used by EventCode, following ECS.
+const EventCodeKey = "event.code"
+
+// EventCode tags an entry with a stable event code, so alerts and runbooks can key on it rather than on the message
+// wording, which may change. See package events for a catalog describing codes at emission. An empty code is a no-op.
+func EventCode(code string) Field {
+	if code == "" {
+		return Nop()
+	}
+	return String(EventCodeKey, code)
+}
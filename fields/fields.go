@@ -2,6 +2,8 @@ package fields
 
 import (
 	"context"
+	"fmt"
+	"math"
 	"time"
 )
 
@@ -31,14 +33,71 @@ const (
 	FieldKindErrors
 
 	// Special
-	FieldKindDict       // sub-fields (Value is []Field)
+	FieldKindDict       // sub-fields (Dict returns []Field)
 	FieldKindRawJSON    // []byte that is already JSON
 	FieldKindHexBytes   // []byte to render as hex string
 	FieldKindLazyFields // lazy: func(context.Context) []Field
 	FieldKindLazyValue  // lazy: func() []Field
-	FieldKindTimestamp  // backend inserts current timestamp (or uses Value as time.Time if provided)
+	FieldKindTimestamp  // backend inserts current timestamp (or uses Time if non-zero)
+
+	// Bundles
+	FieldKindPrecomputed // fields inlined by backends, see Precompute
 )
 
+// String implements fmt.Stringer, returning the lower-case name of the kind.
+func (k FieldKind) String() string {
+	switch k {
+	case FieldKindInvalid:
+		return "invalid"
+	case FieldKindAny:
+		return "any"
+	case FieldKindString:
+		return "string"
+	case FieldKindBool:
+		return "bool"
+	case FieldKindInt64:
+		return "int64"
+	case FieldKindUint64:
+		return "uint64"
+	case FieldKindFloat64:
+		return "float64"
+	case FieldKindTime:
+		return "time"
+	case FieldKindDuration:
+		return "duration"
+	case FieldKindError:
+		return "error"
+	case FieldKindStrings:
+		return "strings"
+	case FieldKindBools:
+		return "bools"
+	case FieldKindInt64s:
+		return "int64s"
+	case FieldKindUint64s:
+		return "uint64s"
+	case FieldKindFloat64s:
+		return "float64s"
+	case FieldKindErrors:
+		return "errors"
+	case FieldKindDict:
+		return "dict"
+	case FieldKindRawJSON:
+		return "rawjson"
+	case FieldKindHexBytes:
+		return "hexbytes"
+	case FieldKindLazyFields:
+		return "lazyfields"
+	case FieldKindLazyValue:
+		return "lazyvalue"
+	case FieldKindTimestamp:
+		return "timestamp"
+	case FieldKindPrecomputed:
+		return "precomputed"
+	default:
+		return fmt.Sprintf("FieldKind(%d)", uint8(k))
+	}
+}
+
 // Conventional keys used by helpers.
 const (
 	ErrorKey     = "error"
@@ -47,10 +106,17 @@ const (
 
 // Field is a portable structured field: a key plus a typed value.
 // The unexported 'kind' enforces invariants via the constructors below.
+//
+// Scalar values (strings, bools, numbers, durations, times) live in unexported slots, so scalar fields are built
+// without boxing into an interface and allocating; the other kinds are boxed. Read the value back with the accessor
+// matching the kind, or Boxed for a boxed copy of any kind.
 type Field struct {
-	Key   string
-	kind  FieldKind
-	Value any
+	Key  string
+	kind FieldKind
+	num  uint64 // Bool, Int64, Uint64, Float64 (bits), Duration, Time (unix nanos)
+	str  string // String
+	// iface is the value of the kinds Interface returns; for scalar kinds it's nil, or the location of a Time.
+	iface any
 }
 
 // Kind returns the field's discriminant for backend mapping.
@@ -60,9 +126,8 @@ func (f Field) Kind() FieldKind {
 
 func Nop() Field {
 	return Field{
-		Key:   "",
-		kind:  FieldKindInvalid,
-		Value: struct{}{},
+		Key:  "",
+		kind: FieldKindInvalid,
 	}
 }
 
@@ -79,34 +144,34 @@ func (f Field) IsSkip() bool {
 // Scalars
 
 func Any(k string, v any) Field {
-	return Field{Key: k, kind: FieldKindAny, Value: v}
+	return Field{Key: k, kind: FieldKindAny, iface: v}
 }
 func String(k, v string) Field {
-	return Field{Key: k, kind: FieldKindString, Value: v}
+	return Field{Key: k, kind: FieldKindString, str: v}
 }
 func Bool(k string, v bool) Field {
-	return Field{Key: k, kind: FieldKindBool, Value: v}
+	return Field{Key: k, kind: FieldKindBool, num: boolBits(v)}
 }
 func Int(k string, v int) Field {
-	return Field{Key: k, kind: FieldKindInt64, Value: int64(v)}
+	return Field{Key: k, kind: FieldKindInt64, num: uint64(v)}
 }
 func Int64(k string, v int64) Field {
-	return Field{Key: k, kind: FieldKindInt64, Value: v}
+	return Field{Key: k, kind: FieldKindInt64, num: uint64(v)}
 }
 func Uint(k string, v uint) Field {
-	return Field{Key: k, kind: FieldKindUint64, Value: uint64(v)}
+	return Field{Key: k, kind: FieldKindUint64, num: uint64(v)}
 }
 func Uint64(k string, v uint64) Field {
-	return Field{Key: k, kind: FieldKindUint64, Value: v}
+	return Field{Key: k, kind: FieldKindUint64, num: v}
 }
 func Float64(k string, v float64) Field {
-	return Field{Key: k, kind: FieldKindFloat64, Value: v}
+	return Field{Key: k, kind: FieldKindFloat64, num: math.Float64bits(v)}
 }
 func TimeField(k string, v time.Time) Field {
-	return Field{Key: k, kind: FieldKindTime, Value: v}
+	return timeField(k, FieldKindTime, v)
 }
 func Duration(k string, v time.Duration) Field {
-	return Field{Key: k, kind: FieldKindDuration, Value: v}
+	return Field{Key: k, kind: FieldKindDuration, num: uint64(v)}
 }
 
 // Errors
@@ -117,7 +182,7 @@ func Error(err error) Field {
 	if err == nil {
 		return Nop()
 	}
-	return Field{Key: ErrorKey, kind: FieldKindError, Value: err}
+	return Field{Key: ErrorKey, kind: FieldKindError, iface: err}
 }
 
 // NamedError adds a non-nil error with a custom key.
@@ -126,28 +191,28 @@ func NamedError(k string, err error) Field {
 	if err == nil {
 		return Nop()
 	}
-	return Field{Key: k, kind: FieldKindError, Value: err}
+	return Field{Key: k, kind: FieldKindError, iface: err}
 }
 
 // Slices (not copied; pass a copy if you may mutate later)
 
 func Strings(k string, v []string) Field {
-	return Field{Key: k, kind: FieldKindStrings, Value: v}
+	return Field{Key: k, kind: FieldKindStrings, iface: v}
 }
 func Bools(k string, v []bool) Field {
-	return Field{Key: k, kind: FieldKindBools, Value: v}
+	return Field{Key: k, kind: FieldKindBools, iface: v}
 }
 func Int64s(k string, v []int64) Field {
-	return Field{Key: k, kind: FieldKindInt64s, Value: v}
+	return Field{Key: k, kind: FieldKindInt64s, iface: v}
 }
 func Uint64s(k string, v []uint64) Field {
-	return Field{Key: k, kind: FieldKindUint64s, Value: v}
+	return Field{Key: k, kind: FieldKindUint64s, iface: v}
 }
 func Float64s(k string, v []float64) Field {
-	return Field{Key: k, kind: FieldKindFloat64s, Value: v}
+	return Field{Key: k, kind: FieldKindFloat64s, iface: v}
 }
 func Errors(k string, v []error) Field {
-	return Field{Key: k, kind: FieldKindErrors, Value: v}
+	return Field{Key: k, kind: FieldKindErrors, iface: v}
 }
 
 // Special
@@ -155,19 +220,19 @@ func Errors(k string, v []error) Field {
 // Dict groups sub-fields under a single key (zap: Object, slog: Group).
 // NOTE: this does not copy the slice; pass a copy if you will mutate it.
 func Dict(k string, fields ...Field) Field {
-	return Field{Key: k, kind: FieldKindDict, Value: fields}
+	return Field{Key: k, kind: FieldKindDict, iface: fields}
 }
 
 // RawJSON inserts pre-encoded JSON bytes under key.
 // NOTE: Backends that don’t support raw JSON may encode it as a string or bytes (eg zap supports; slog may treat as
 // []byte/string).
 func RawJSON(k string, json []byte) Field {
-	return Field{Key: k, kind: FieldKindRawJSON, Value: json}
+	return Field{Key: k, kind: FieldKindRawJSON, iface: json}
 }
 
 // Hex encodes []byte as a lowercase hexadecimal string at the backend.
 func Hex(k string, b []byte) Field {
-	return Field{Key: k, kind: FieldKindHexBytes, Value: b}
+	return Field{Key: k, kind: FieldKindHexBytes, iface: b}
 }
 
 // LazyFields runs lazily at log time (only if enabled) and returns extra fields to append.
@@ -176,7 +241,7 @@ func LazyFields(fn func(context.Context) []Field) Field {
 	if fn == nil {
 		return Field{}
 	}
-	return Field{kind: FieldKindLazyFields, Value: fn}
+	return Field{kind: FieldKindLazyFields, iface: fn}
 }
 
 // Convenience for no-ctx callers.
@@ -185,19 +250,29 @@ func Lazy(fn func() []Field) Field {
 	if fn == nil {
 		return Field{}
 	}
-	return LazyFields(func(context.Context) []Field { return fn() })
+	return Field{kind: FieldKindLazyValue, iface: fn}
+}
+
+// LazyDict is Dict with the sub-fields built lazily at log time (only if enabled), unlike Dict which needs them built
+// upfront. Backends see it as a lazy field expanding to the Dict.
+// NOTE: The function should be fast and side-effect free.
+func LazyDict(k string, fn func() []Field) Field {
+	if fn == nil {
+		return Field{}
+	}
+	return Lazy(func() []Field { return []Field{Dict(k, fn()...)} })
 }
 
 // Timestamp asks the backend to attach a timestamp field.
 // If t is zero, backends should use time.Now(); otherwise use t.
 // The key defaults to TimestampKey ("ts"); backends may honor Options.TimeLayout.
 func Timestamp(t time.Time) Field {
-	return Field{Key: TimestampKey, kind: FieldKindTimestamp, Value: t}
+	return timeField(TimestampKey, FieldKindTimestamp, t)
 }
 
 // TimestampAt is the same as Timestamp but with a custom key.
 func TimestampAt(k string, t time.Time) Field {
-	return Field{Key: k, kind: FieldKindTimestamp, Value: t}
+	return timeField(k, FieldKindTimestamp, t)
 }
 
 // From chooses a FieldKind for common types; otherwise returns Any.
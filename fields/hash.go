@@ -0,0 +1,32 @@
+package fields
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// Hasher turns a sensitive value into a stable token that can be correlated across entries without exposing the
+// value itself.
+type Hasher func(v string) string
+
+// HMACSHA256 returns a Hasher computing the hex encoded HMAC-SHA256 of values keyed by salt.
+// Keep salt secret and stable: rotating it breaks correlation with earlier entries.
+func HMACSHA256(salt []byte) Hasher {
+	return func(v string) string {
+		mac := hmac.New(sha256.New, salt)
+		mac.Write([]byte(v))
+		return hex.EncodeToString(mac.Sum(nil))
+	}
+}
+
+// Hashed adds v under k as its HMAC-SHA256 keyed by salt, rather than the raw value.
+// The hash is computed immediately, so the raw value never reaches the backend.
+func Hashed(k, v string, salt []byte) Field {
+	return HashedWith(k, v, HMACSHA256(salt))
+}
+
+// HashedWith is the same as Hashed but with a custom Hasher.
+func HashedWith(k, v string, h Hasher) Field {
+	return String(k, h(v))
+}
@@ -0,0 +1,59 @@
+package fields
+
+import (
+	"sync"
+)
+
+// maxPooledList caps the capacity of lists returned to the pool, so one huge entry doesn't pin a huge buffer.
+const maxPooledList = 256
+
+var listPool = sync.Pool{
+	New: func() any {
+		return &List{fs: make([]Field, 0, 32)}
+	},
+}
+
+// List is a pooled field buffer for call sites building many fields per entry:
+//
+//	l := fields.NewList()
+//	defer l.Release()
+//	l.Append(fields.String("method", r.Method), fields.String("path", r.URL.Path))
+//	...
+//	log.Info("request", l.Fields()...)
+//
+// A List must not be used, nor its Fields retained, after Release. Loggers don't keep call-site fields past the call,
+// but don't pass l.Fields() to With.
+type List struct {
+	fs []Field
+}
+
+// NewList returns an empty List from the pool.
+func NewList() *List {
+	return listPool.Get().(*List)
+}
+
+// Append adds fs to the list, returning it for chaining.
+func (l *List) Append(fs ...Field) *List {
+	l.fs = append(l.fs, fs...)
+	return l
+}
+
+// Fields returns the accumulated fields. The slice is owned by the list.
+func (l *List) Fields() []Field {
+	return l.fs
+}
+
+// Len returns the number of accumulated fields.
+func (l *List) Len() int {
+	return len(l.fs)
+}
+
+// Release resets the list and returns it to the pool.
+func (l *List) Release() {
+	if cap(l.fs) > maxPooledList {
+		return
+	}
+	clear(l.fs) // drop references so pooled lists don't keep values alive
+	l.fs = l.fs[:0]
+	listPool.Put(l)
+}
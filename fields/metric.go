@@ -0,0 +1,40 @@
+package fields
+
+import "math"
+
+// MetricKind is the kind of metric update a Counter or Gauge field carries.
+type MetricKind uint8
+
+const (
+	// NoMetric is the MetricKind of fields that aren't metric updates.
+	NoMetric MetricKind = iota
+	// CounterMetric adds the field's value to a counter.
+	CounterMetric
+	// GaugeMetric sets a gauge to the field's value.
+	GaugeMetric
+)
+
+// Counter returns a field adding delta to the counter name, for log-based metrics: a metrics middleware (see package
+// metrics) turns it into a counter update, while backends render it as a plain name=delta float field.
+func Counter(name string, delta float64) Field {
+	return metric(name, delta, CounterMetric)
+}
+
+// Gauge returns a field setting the gauge name to value. Like Counter, backends render it as a plain float field.
+func Gauge(name string, value float64) Field {
+	return metric(name, value, GaugeMetric)
+}
+
+// metric returns a FieldKindFloat64 field tagged with kind.
+func metric(name string, v float64, kind MetricKind) Field {
+	return Field{Key: name, kind: FieldKindFloat64, num: math.Float64bits(v), iface: kind}
+}
+
+// Metric returns the kind of metric update the field carries, NoMetric if it isn't a Counter or Gauge.
+func (f Field) Metric() MetricKind {
+	if f.kind != FieldKindFloat64 {
+		return NoMetric
+	}
+	kind, _ := f.iface.(MetricKind)
+	return kind
+}
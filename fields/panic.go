@@ -0,0 +1,62 @@
+package fields
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// PanicKey is the key used by Panic and PanicStack.
+const PanicKey = "panic"
+
+// Panic groups a panic value under PanicKey ("panic"): the value itself, its Go type, and the stack of the calling
+// goroutine. Use it to log a recovered value:
+//
+//	defer func() {
+//		if v := recover(); v != nil {
+//			log.Error("recovered", fields.Panic(v))
+//		}
+//	}()
+//
+// It's lazy, so the stack is only captured if the entry is written, when the backend resolves the field. That's the
+// stack of the goroutine logging, which only shows where the panic happened while its deferred calls run; use
+// PanicWithStack to log the stack captured in the recover.
+func Panic(v any) Field {
+	return LazyDict(PanicKey, func() []Field { return panicFields(v, stack()) })
+}
+
+// PanicWithStack is Panic with the given stack rather than a lazily captured one, eg debug.Stack() called in the
+// deferred function that recovered v, so the stack shows where the panic happened wherever the entry is written:
+//
+//	defer func() {
+//		if v := recover(); v != nil {
+//			stack := debug.Stack()
+//			...
+//			log.Error("recovered", fields.PanicWithStack(v, stack))
+//		}
+//	}()
+func PanicWithStack(v any, stack []byte) Field {
+	return Dict(PanicKey, panicFields(v, String("stack", string(stack)))...)
+}
+
+// panicFields returns the fields Panic groups for v and its stack.
+func panicFields(v any, stack Field) []Field {
+	value := Any("value", v)
+	switch v := v.(type) {
+	case string:
+		value = String("value", v)
+	case error:
+		value = NamedError("value", v)
+	}
+	return []Field{value, String("type", fmt.Sprintf("%T", v)), stack}
+}
+
+// PanicStack groups only the stack of the calling goroutine under PanicKey, captured lazily like Panic's. Backends
+// attach it to every Panic and DPanic entry, whose message already says what happened, unless they add a stacktrace
+// of their own.
+func PanicStack() Field {
+	return LazyDict(PanicKey, func() []Field { return []Field{stack()} })
+}
+
+func stack() Field {
+	return String("stack", string(debug.Stack()))
+}
@@ -0,0 +1,63 @@
+package fields
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestPanic(t *testing.T) {
+	tests := []struct {
+		name     string
+		f        Field
+		wantKeys []string
+		wantType string
+	}{
+		{"string", Panic("boom"), []string{"value", "type", "stack"}, "string"},
+		{"error", Panic(errors.New("boom")), []string{"value", "type", "stack"}, "*errors.errorString"},
+		{"any", Panic(42), []string{"value", "type", "stack"}, "int"},
+		{"stack only", PanicStack(), []string{"stack"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.f.Kind() != FieldKindLazyValue {
+				t.Fatalf("kind = %v, want a lazy field", tt.f.Kind())
+			}
+			fs := Resolve(context.Background(), nil, []Field{tt.f})
+			if len(fs) != 1 || fs[0].Key != PanicKey {
+				t.Fatalf("resolved to %v, want a single %s Dict", fs, PanicKey)
+			}
+			sub := fs[0].Fields()
+			if len(sub) != len(tt.wantKeys) {
+				t.Fatalf("got %d fields, want %v", len(sub), tt.wantKeys)
+			}
+			for i, f := range sub {
+				if f.Key != tt.wantKeys[i] {
+					t.Errorf("field %d = %s, want %s", i, f.Key, tt.wantKeys[i])
+				}
+				switch f.Key {
+				case "type":
+					if f.Str() != tt.wantType {
+						t.Errorf("type = %s, want %s", f.Str(), tt.wantType)
+					}
+				case "stack":
+					if !strings.Contains(f.Str(), "TestPanic") {
+						t.Errorf("stack doesn't hold the caller:\n%s", f.Str())
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestPanicWithStack(t *testing.T) {
+	f := PanicWithStack("boom", []byte("goroutine 1 [running]:\nmain.crash()"))
+	if f.Kind() != FieldKindDict || f.Key != PanicKey {
+		t.Fatalf("got a %v %s, want a %s Dict", f.Kind(), f.Key, PanicKey)
+	}
+	sub := f.Fields()
+	if len(sub) != 3 || sub[0].Str() != "boom" || sub[1].Str() != "string" || !strings.HasSuffix(sub[2].Str(), "crash()") {
+		t.Errorf("got %v, want the value, its type and the given stack", sub)
+	}
+}
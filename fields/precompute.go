@@ -0,0 +1,49 @@
+package fields
+
+import "sync"
+
+// Precomputed is a fixed bundle of fields whose conversion into a backend's native representation is computed once
+// and cached, see Precompute.
+type Precomputed struct {
+	fields []Field
+	native sync.Map // backend key -> converted fields
+}
+
+// Precompute bundles fs into a single field, for static metadata (service name, version, host, ...) added to many
+// loggers or entries:
+//
+//	var meta = fields.Precompute(fields.String("service", "api"), fields.String("version", version))
+//	...
+//	log := log.With(meta)
+//
+// Backends inline the bundled fields where the bundle appears, converting them into their native fields once rather
+// than on every With or entry; lazy fields among them are still evaluated per entry, with its context. Middleware see
+// a single FieldKindPrecomputed field, whose bundled fields they reach through Precomputed().Fields() (fields.Resolve
+// expands it too); fs is used as is and must not be modified afterwards.
+func Precompute(fs ...Field) Field {
+	if len(fs) == 0 {
+		return Field{}
+	}
+	return Field{kind: FieldKindPrecomputed, iface: &Precomputed{fields: fs}}
+}
+
+// Precomputed returns the bundle of a FieldKindPrecomputed field.
+func (f Field) Precomputed() *Precomputed {
+	p, _ := f.iface.(*Precomputed)
+	return p
+}
+
+// Fields returns the bundled fields.
+func (p *Precomputed) Fields() []Field {
+	return p.fields
+}
+
+// Native returns the bundled fields converted by convert, calling it only the first time it's asked for key. Backends
+// pass a private key and their own conversion, eg a []zap.Field builder.
+func (p *Precomputed) Native(key any, convert func([]Field) any) any {
+	if v, ok := p.native.Load(key); ok {
+		return v
+	}
+	v, _ := p.native.LoadOrStore(key, convert(p.fields))
+	return v
+}
@@ -0,0 +1,140 @@
+package fields
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// Time range representable as unix nanoseconds; times outside it are boxed instead.
+var (
+	minUnixNano = time.Unix(0, math.MinInt64)
+	maxUnixNano = time.Unix(0, math.MaxInt64)
+)
+
+func boolBits(v bool) uint64 {
+	if v {
+		return 1
+	}
+	return 0
+}
+
+// timeField stores t as unix nanos plus its location, falling back to boxing t when it's out of range.
+// The zero time is stored as nothing at all, so Timestamp(time.Time{}) stays cheap.
+func timeField(k string, kind FieldKind, t time.Time) Field {
+	switch {
+	case t.IsZero():
+		return Field{Key: k, kind: kind}
+	case t.Before(minUnixNano) || t.After(maxUnixNano):
+		return Field{Key: k, kind: kind, iface: t}
+	default:
+		return Field{Key: k, kind: kind, num: uint64(t.UnixNano()), iface: t.Location()}
+	}
+}
+
+// Boxed returns the field's value boxed into an interface, in the type documented by its kind (eg int64 for
+// FieldKindInt64, []Field for FieldKindDict). It allocates for scalars; backends should prefer the typed accessors.
+// Returns nil for no-op fields.
+func (f Field) Boxed() any {
+	switch f.kind {
+	case FieldKindInvalid:
+		return nil
+	case FieldKindString:
+		return f.str
+	case FieldKindBool:
+		return f.Bool()
+	case FieldKindInt64:
+		return f.Int64()
+	case FieldKindUint64:
+		return f.Uint64()
+	case FieldKindFloat64:
+		return f.Float64()
+	case FieldKindDuration:
+		return f.Duration()
+	case FieldKindTime, FieldKindTimestamp:
+		return f.Time()
+	default:
+		return f.iface
+	}
+}
+
+// Str returns the value of a FieldKindString field.
+func (f Field) Str() string { return f.str }
+
+// Bool returns the value of a FieldKindBool field.
+func (f Field) Bool() bool { return f.num == 1 }
+
+// Int64 returns the value of a FieldKindInt64 field.
+func (f Field) Int64() int64 { return int64(f.num) }
+
+// Uint64 returns the value of a FieldKindUint64 field.
+func (f Field) Uint64() uint64 { return f.num }
+
+// Float64 returns the value of a FieldKindFloat64 field.
+func (f Field) Float64() float64 { return math.Float64frombits(f.num) }
+
+// Duration returns the value of a FieldKindDuration field.
+func (f Field) Duration() time.Duration { return time.Duration(f.num) }
+
+// Time returns the value of a FieldKindTime or FieldKindTimestamp field (zero for a Timestamp meaning "now").
+func (f Field) Time() time.Time {
+	switch v := f.iface.(type) {
+	case *time.Location:
+		return time.Unix(0, int64(f.num)).In(v)
+	case time.Time:
+		return v
+	default:
+		return time.Time{}
+	}
+}
+
+// Err returns the value of a FieldKindError field.
+func (f Field) Err() error {
+	err, _ := f.iface.(error)
+	return err
+}
+
+// Fields returns the sub-fields of a FieldKindDict field.
+func (f Field) Fields() []Field {
+	fs, _ := f.iface.([]Field)
+	return fs
+}
+
+// Bytes returns the value of a FieldKindRawJSON or FieldKindHexBytes field.
+func (f Field) Bytes() []byte {
+	b, _ := f.iface.([]byte)
+	return b
+}
+
+// LazyFunc returns the function of a FieldKindLazyFields or FieldKindLazyValue field, adapted to take a context.
+func (f Field) LazyFunc() func(context.Context) []Field {
+	switch fn := f.iface.(type) {
+	case func(context.Context) []Field:
+		return fn
+	case func() []Field:
+		return func(context.Context) []Field { return fn() }
+	default:
+		return nil
+	}
+}
+
+// Interface returns the value of fields stored boxed: Any, Error, the slice kinds, Dict, RawJSON, HexBytes and the
+// lazy kinds. It's nil for scalars.
+func (f Field) Interface() any { return f.iface }
+
+// Resolve appends fs to dst with lazy fields evaluated (handed ctx) and precomputed bundles expanded, recursively,
+// and no-op fields dropped, so the result only holds plain values. It's for sinks that keep or encode whole entries.
+func Resolve(ctx context.Context, dst, fs []Field) []Field {
+	for _, f := range fs {
+		switch f.kind {
+		case FieldKindInvalid:
+		case FieldKindLazyFields, FieldKindLazyValue:
+			dst = Resolve(ctx, dst, f.LazyFunc()(ctx))
+		case FieldKindPrecomputed:
+			dst = Resolve(ctx, dst, f.Precomputed().Fields())
+		default:
+			dst = append(dst, f)
+		}
+	}
+	return dst
+}
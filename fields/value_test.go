@@ -0,0 +1,113 @@
+package fields
+
+import (
+	"testing"
+	"time"
+	"unsafe"
+)
+
+// boxedField is how Field stored every value before scalars got their own slots, kept to benchmark against.
+type boxedField struct {
+	Key   string
+	kind  FieldKind
+	Value any
+}
+
+var (
+	sinkFields [5]Field
+	sinkBoxed  [5]boxedField
+)
+
+// BenchmarkScalarFields builds five scalar fields per op, unboxed as the constructors do and boxed as they used to.
+// Field is bigger (B/field) but building it doesn't allocate.
+func BenchmarkScalarFields(b *testing.B) {
+	now := time.Now()
+	s := now.String()
+
+	b.Run("unboxed", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; b.Loop(); i++ {
+			sinkFields = [...]Field{
+				String("string", s),
+				Int64("int64", int64(i)+1000),
+				Float64("float64", float64(i)+0.5),
+				Duration("duration", time.Duration(i)+time.Hour),
+				TimeField("time", now),
+			}
+		}
+		b.ReportMetric(float64(unsafe.Sizeof(Field{})), "B/field")
+	})
+	b.Run("boxed", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; b.Loop(); i++ {
+			sinkBoxed = [...]boxedField{
+				{Key: "string", kind: FieldKindString, Value: s},
+				{Key: "int64", kind: FieldKindInt64, Value: int64(i) + 1000},
+				{Key: "float64", kind: FieldKindFloat64, Value: float64(i) + 0.5},
+				{Key: "duration", kind: FieldKindDuration, Value: time.Duration(i) + time.Hour},
+				{Key: "time", kind: FieldKindTime, Value: now},
+			}
+		}
+		b.ReportMetric(float64(unsafe.Sizeof(boxedField{})), "B/field")
+	})
+}
+
+func TestScalarAccessors(t *testing.T) {
+	now := time.Unix(1700000000, 123).In(time.FixedZone("x", 3600))
+	tests := []struct {
+		name string
+		f    Field
+		want any
+	}{
+		{"string", String("k", "v"), "v"},
+		{"bool", Bool("k", true), true},
+		{"int64", Int64("k", -42), int64(-42)},
+		{"uint64", Uint64("k", 1<<63), uint64(1 << 63)},
+		{"float64", Float64("k", 3.25), 3.25},
+		{"duration", Duration("k", time.Minute), time.Minute},
+		{"time", TimeField("k", now), now},
+		{"far time", TimeField("k", time.Date(3000, 1, 1, 0, 0, 0, 0, time.UTC)), time.Date(3000, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"nop", Nop(), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.f.Boxed()
+			if tm, ok := tt.want.(time.Time); ok {
+				if gt, _ := got.(time.Time); !gt.Equal(tm) || gt.Location().String() != tm.Location().String() {
+					t.Errorf("Boxed() = %v, want %v", got, tt.want)
+				}
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Boxed() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScalarFieldsDontAllocate(t *testing.T) {
+	now := time.Now()
+	s := now.String()
+	var f Field
+	tests := []struct {
+		name string
+		fn   func()
+	}{
+		{"String", func() { f = String("k", s); _ = f.Str() }},
+		{"Bool", func() { f = Bool("k", true); _ = f.Bool() }},
+		{"Int", func() { f = Int("k", 42); _ = f.Int64() }},
+		{"Int64", func() { f = Int64("k", -42); _ = f.Int64() }},
+		{"Uint", func() { f = Uint("k", 42); _ = f.Uint64() }},
+		{"Uint64", func() { f = Uint64("k", 1<<63); _ = f.Uint64() }},
+		{"Float64", func() { f = Float64("k", 3.25); _ = f.Float64() }},
+		{"Duration", func() { f = Duration("k", time.Minute); _ = f.Duration() }},
+		{"TimeField", func() { f = TimeField("k", now); _ = f.Time() }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if n := testing.AllocsPerRun(100, tt.fn); n != 0 {
+				t.Errorf("building and reading a %s field allocates %v times, want 0", tt.name, n)
+			}
+		})
+	}
+}
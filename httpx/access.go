@@ -0,0 +1,139 @@
+package httpx
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"github.com/khinshankhan/logstox"
+	"github.com/khinshankhan/logstox/contextx"
+	"github.com/khinshankhan/logstox/fields"
+)
+
+// DefaultAccessLogMessage is the message AccessLog logs requests with when its Message is empty.
+const DefaultAccessLogMessage = "http request"
+
+// AccessLog logs every request once it's served, with RequestFields and ResponseFields: at ErrorLevel for 5xx
+// responses, InfoLevel otherwise. A request whose handler panics is still logged, see LogPanic, before the panic
+// carries on to net/http. Requests are logged to the logger in their context (see contextx.Logger), eg the one
+// Correlation stores, falling back to Logger:
+//
+//	h = httpx.Correlation{Logger: log}.Handler(httpx.AccessLog{Logger: log}.Handler(h))
+type AccessLog struct {
+	// Logger logs requests whose context carries no logger. If nil, those aren't logged.
+	Logger logstox.Logger[fields.Field]
+	// Message is the message requests are logged with. If empty, defaults to DefaultAccessLogMessage.
+	Message string
+}
+
+// Handler wraps next with access logging.
+func (a AccessLog) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rw := &responseWriter{ResponseWriter: w}
+		defer func() {
+			if v := recover(); v != nil {
+				a.LogPanic(r, rw.status, rw.size, time.Since(start), v)
+				panic(v)
+			}
+			a.Log(r, rw.status, rw.size, time.Since(start))
+		}()
+		next.ServeHTTP(rw, r)
+	})
+}
+
+// Log logs the served request r like Handler does, with extra appended, for middleware of routers and frameworks
+// that track the response themselves. A status of 0 is logged as 200.
+func (a AccessLog) Log(r *http.Request, status int, size int64, dur time.Duration, extra ...fields.Field) {
+	log := contextx.Logger(r.Context(), a.Logger)
+	if log == nil {
+		return
+	}
+	msg := a.Message
+	if msg == "" {
+		msg = DefaultAccessLogMessage
+	}
+	if status == 0 {
+		status = http.StatusOK
+	}
+	fs := append(RequestFields(r), ResponseFields(status, size, dur)...)
+	fs = append(fs, extra...)
+	if status >= http.StatusInternalServerError {
+		log.Error(msg, fs...)
+		return
+	}
+	log.Info(msg, fs...)
+}
+
+// LogPanic logs the request r whose handler panicked with v like Handler does, for middleware of routers and
+// frameworks recovering panics themselves: as a 500 unless a status was written, with v and the stack under
+// fields.PanicKey, or without them for http.ErrAbortHandler, the sentinel net/http aborts a response with quietly.
+// Call it from the deferred func that recovered v, so the stack shows where the handler panicked, and panic(v) again
+// after so net/http still sees it.
+func (a AccessLog) LogPanic(r *http.Request, status int, size int64, dur time.Duration, v any, extra ...fields.Field) {
+	if status == 0 {
+		status = http.StatusInternalServerError
+	}
+	if v != http.ErrAbortHandler {
+		extra = append(extra[:len(extra):len(extra)], fields.PanicWithStack(v, debug.Stack()))
+	}
+	a.Log(r, status, size, dur, extra...)
+}
+
+// responseWriter records the status code and body size of a response.
+type responseWriter struct {
+	http.ResponseWriter
+	status int
+	size   int64
+}
+
+// Interface satisfaction (compile-time assertions).
+var (
+	_ http.Flusher  = (*responseWriter)(nil)
+	_ http.Hijacker = (*responseWriter)(nil)
+)
+
+func (w *responseWriter) WriteHeader(code int) {
+	if w.status == 0 && code >= 200 {
+		w.status = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.size += int64(n)
+	return n, err
+}
+
+// Flush flushes the underlying writer if it supports it.
+func (w *responseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack hijacks the underlying connection if it supports it, eg for websockets. A hijacked response with no status
+// written is logged as a 101.
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("httpx: %T doesn't support hijacking", w.ResponseWriter)
+	}
+	conn, rw, err := h.Hijack()
+	if err == nil && w.status == 0 {
+		w.status = http.StatusSwitchingProtocols
+	}
+	return conn, rw, err
+}
+
+// Unwrap returns the underlying writer, for http.ResponseController.
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
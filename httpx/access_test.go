@@ -0,0 +1,51 @@
+package httpx
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/khinshankhan/logstox"
+	"github.com/khinshankhan/logstox/backend/memx"
+	"github.com/khinshankhan/logstox/fields"
+)
+
+func TestAccessLogLogsPanickingRequests(t *testing.T) {
+	rec := memx.NewRecorder(4)
+	h := AccessLog{Logger: memx.Backend{Recorder: rec}.New(logstox.Options[fields.Field]{})}.Handler(
+		http.HandlerFunc(func(http.ResponseWriter, *http.Request) { panic("boom") }),
+	)
+
+	func() {
+		defer func() {
+			if v := recover(); v != "boom" {
+				t.Errorf("recovered %v, want the handler's panic to carry on", v)
+			}
+		}()
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	}()
+
+	es := rec.Entries()
+	if len(es) != 1 || es[0].Level != logstox.ErrorLevel {
+		t.Fatalf("logged %v, want one ErrorLevel entry", es)
+	}
+	var panicked bool
+	for _, f := range es[0].Fields {
+		panicked = panicked || f.Key == fields.PanicKey
+	}
+	if !panicked {
+		t.Errorf("fields %v have no %s", es[0].Fields, fields.PanicKey)
+	}
+}
+
+func TestAccessLogHijack(t *testing.T) {
+	h := AccessLog{}.Handler(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if _, ok := w.(http.Hijacker); !ok {
+			t.Error("the wrapped writer isn't an http.Hijacker")
+		}
+		if _, _, err := http.NewResponseController(w).Hijack(); err == nil {
+			t.Error("hijacking a recorder succeeded, want an error")
+		}
+	}))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+}
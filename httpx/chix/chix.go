@@ -0,0 +1,42 @@
+package chix
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/khinshankhan/logstox/fields"
+	"github.com/khinshankhan/logstox/httpx"
+)
+
+// Middleware returns chi middleware logging every request like a's Handler does, with the route pattern it matched
+// under httpx.RouteKey, so requests to /users/1 and /users/2 share one low-cardinality route:
+//
+//	r := chi.NewRouter()
+//	r.Use(chix.Middleware(httpx.AccessLog{Logger: log}))
+//
+// Requests no route matched are logged without a route, and requests whose handler panics like a's Handler logs
+// them, see httpx.AccessLog.LogPanic.
+func Middleware(a httpx.AccessLog) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			defer func() {
+				var extra []fields.Field
+				if rctx := chi.RouteContext(r.Context()); rctx != nil {
+					if route := rctx.RoutePattern(); route != "" {
+						extra = append(extra, fields.String(httpx.RouteKey, route))
+					}
+				}
+				if v := recover(); v != nil {
+					a.LogPanic(r, ww.Status(), int64(ww.BytesWritten()), time.Since(start), v, extra...)
+					panic(v)
+				}
+				a.Log(r, ww.Status(), int64(ww.BytesWritten()), time.Since(start), extra...)
+			}()
+			next.ServeHTTP(ww, r)
+		})
+	}
+}
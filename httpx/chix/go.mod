@@ -0,0 +1,10 @@
+module github.com/khinshankhan/logstox/httpx/chix
+
+go 1.24.2
+
+require (
+	github.com/go-chi/chi/v5 v5.2.5
+	github.com/khinshankhan/logstox v0.0.0-20250914151607-81d0c77772ce
+)
+
+replace github.com/khinshankhan/logstox => ../..
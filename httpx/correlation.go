@@ -0,0 +1,88 @@
+package httpx
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+
+	"github.com/khinshankhan/logstox"
+	"github.com/khinshankhan/logstox/contextx"
+	"github.com/khinshankhan/logstox/fields"
+)
+
+const (
+	// CorrelationIDHeader is the default header a correlation ID is read from and echoed on.
+	CorrelationIDHeader = "X-Correlation-ID"
+	// CorrelationIDKey is the field key the correlation ID is logged under.
+	CorrelationIDKey = "correlation_id"
+)
+
+// maxCorrelationIDLen bounds incoming IDs so clients can't stuff arbitrary payloads into every log line.
+const maxCorrelationIDLen = 128
+
+// Correlation ensures every request carries a correlation ID: taken from the incoming header when present and sane,
+// generated otherwise. The ID is stored via contextx.WithCorrelationID and echoed on the response header.
+type Correlation struct {
+	// Header to read and echo the ID on. If empty, defaults to CorrelationIDHeader.
+	Header string
+	// Generate creates IDs for requests without one. If nil, defaults to 16 random bytes hex encoded.
+	Generate func() string
+	// Logger, if set, is stored in the request context (see contextx.Logger) with the correlation ID attached, so
+	// every entry logged through it within the request carries the ID.
+	Logger logstox.Logger[fields.Field]
+}
+
+// Handler wraps next with correlation ID handling.
+func (c Correlation) Handler(next http.Handler) http.Handler {
+	header := c.Header
+	if header == "" {
+		header = CorrelationIDHeader
+	}
+	generate := c.Generate
+	if generate == nil {
+		generate = newID
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(header)
+		if !validCorrelationID(id) {
+			id = generate()
+		}
+
+		ctx := contextx.WithCorrelationID(r.Context(), id)
+		if c.Logger != nil {
+			ctx = contextx.WithLogger(ctx, c.Logger.With(fields.String(CorrelationIDKey, id)))
+		}
+		w.Header().Set(header, id)
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// CorrelationField returns the correlation ID stored in r's context as a field, or a no-op if there is none.
+func CorrelationField(r *http.Request) fields.Field {
+	if id, ok := contextx.CorrelationID(r.Context()); ok {
+		return fields.String(CorrelationIDKey, id)
+	}
+	return fields.Nop()
+}
+
+// newID returns 16 random bytes, hex encoded.
+func newID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:]) // never returns an error
+	return hex.EncodeToString(b[:])
+}
+
+// validCorrelationID reports whether id is non-empty, bounded, and printable ASCII.
+func validCorrelationID(id string) bool {
+	if id == "" || len(id) > maxCorrelationIDLen {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,107 @@
+package httpx
+
+import (
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/khinshankhan/logstox"
+	"github.com/khinshankhan/logstox/backend/memx"
+	"github.com/khinshankhan/logstox/contextx"
+	"github.com/khinshankhan/logstox/fields"
+)
+
+func TestCorrelation(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string // Correlation.Header
+		in     map[string]string
+		want   string // "" for a generated ID
+	}{
+		{"incoming", "", map[string]string{CorrelationIDHeader: "req-42"}, "req-42"},
+		{"missing", "", nil, ""},
+		{"too long", "", map[string]string{CorrelationIDHeader: strings.Repeat("a", maxCorrelationIDLen+1)}, ""},
+		{"longest", "", map[string]string{CorrelationIDHeader: strings.Repeat("a", maxCorrelationIDLen)},
+			strings.Repeat("a", maxCorrelationIDLen)},
+		{"space", "", map[string]string{CorrelationIDHeader: "req 42"}, ""},
+		{"control character", "", map[string]string{CorrelationIDHeader: "req\x0142"}, ""},
+		{"custom header", "X-Request-ID", map[string]string{"X-Request-ID": "req-42", CorrelationIDHeader: "other"},
+			"req-42"},
+		{"custom header missing", "X-Request-ID", map[string]string{CorrelationIDHeader: "other"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var seen string
+			h := Correlation{Header: tt.header, Generate: func() string { return "generated" }}.Handler(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					seen, _ = contextx.CorrelationID(r.Context())
+				}))
+			r := httptest.NewRequest("GET", "/", nil)
+			for k, v := range tt.in {
+				r.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, r)
+
+			want := tt.want
+			if want == "" {
+				want = "generated"
+			}
+			header := tt.header
+			if header == "" {
+				header = CorrelationIDHeader
+			}
+			if seen != want {
+				t.Errorf("context carries %q, want %q", seen, want)
+			}
+			if got := rec.Header().Get(header); got != want {
+				t.Errorf("response %s = %q, want %q", header, got, want)
+			}
+		})
+	}
+}
+
+func TestCorrelationDefaultGenerator(t *testing.T) {
+	ids := map[string]bool{}
+	h := Correlation{}.Handler(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	for range 3 {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+		id := rec.Header().Get(CorrelationIDHeader)
+		if b, err := hex.DecodeString(id); err != nil || len(b) != 16 {
+			t.Fatalf("generated %q, want 16 hex encoded bytes", id)
+		}
+		ids[id] = true
+	}
+	if len(ids) != 3 {
+		t.Errorf("generated %d distinct IDs for 3 requests", len(ids))
+	}
+}
+
+func TestCorrelationLogger(t *testing.T) {
+	rec := memx.NewRecorder(4)
+	log := memx.Backend{Recorder: rec}.New(logstox.Options[fields.Field]{})
+	var field fields.Field
+	h := Correlation{Logger: log}.Handler(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		contextx.Logger(r.Context(), nil).Info("handled")
+		field = CorrelationField(r)
+	}))
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set(CorrelationIDHeader, "req-42")
+	h.ServeHTTP(httptest.NewRecorder(), r)
+
+	if rec.Len() != 1 {
+		t.Fatalf("logged %d entries, want 1", rec.Len())
+	}
+	if id, _ := fields.Fields(rec.Entries()[0].Fields).Get(CorrelationIDKey); id.Str() != "req-42" {
+		t.Errorf("%s = %q, want req-42", CorrelationIDKey, id.Str())
+	}
+	if field.Key != CorrelationIDKey || field.Str() != "req-42" {
+		t.Errorf("CorrelationField = %v, want req-42", field)
+	}
+	if f := CorrelationField(httptest.NewRequest("GET", "/", nil)); !f.IsZero() {
+		t.Errorf("CorrelationField without an ID = %v, want a no-op", f)
+	}
+}
@@ -0,0 +1,94 @@
+package httpx
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/khinshankhan/logstox"
+	"github.com/khinshankhan/logstox/contextx"
+)
+
+// DebugHeader is the default header a signed debug level is read from.
+const DebugHeader = "X-Debug-Level"
+
+// Debug lowers the log level of single requests carrying a signed debug header, storing it via
+// contextx.WithMinLevel, so a misbehaving request can be logged verbosely in production without turning debug
+// logging on for everyone. Header values have the form "level.expiry.signature", eg "debug.1767225600.3f9a...":
+// the level name, the Unix time the value expires at and the hex HMAC-SHA256 of "level.expiry" under Key, as made
+// by Sign. Missing, malformed, expired or badly signed values are ignored.
+type Debug struct {
+	// Header to read the value from. If empty, defaults to DebugHeader.
+	Header string
+	// Key signs header values. If empty, the header is never honored.
+	Key []byte
+	// Now returns the current time, to check expiries against. If nil, defaults to time.Now.
+	Now func() time.Time
+}
+
+// Handler wraps next with debug header handling.
+func (d Debug) Handler(next http.Handler) http.Handler {
+	header := d.Header
+	if header == "" {
+		header = DebugHeader
+	}
+	now := d.Now
+	if now == nil {
+		now = time.Now
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if lvl, ok := d.verify(r.Header.Get(header), now()); ok {
+			r = r.WithContext(contextx.WithMinLevel(r.Context(), lvl))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Sign returns a header value activating lvl until expires, eg for an operator tool:
+//
+//	req.Header.Set(httpx.DebugHeader, d.Sign(logstox.DebugLevel, time.Now().Add(15*time.Minute)))
+func (d Debug) Sign(lvl logstox.Level, expires time.Time) string {
+	payload := lvl.String() + "." + strconv.FormatInt(expires.Unix(), 10)
+	return payload + "." + hex.EncodeToString(d.mac(payload))
+}
+
+// verify returns the level of the header value v if it's well formed, unexpired at now and signed with d.Key.
+func (d Debug) verify(v string, now time.Time) (logstox.Level, bool) {
+	if len(d.Key) == 0 || v == "" {
+		return 0, false
+	}
+	i := strings.LastIndexByte(v, '.')
+	if i < 0 {
+		return 0, false
+	}
+	payload, sig := v[:i], v[i+1:]
+	got, err := hex.DecodeString(sig)
+	if err != nil || !hmac.Equal(got, d.mac(payload)) {
+		return 0, false
+	}
+	name, expiry, ok := strings.Cut(payload, ".")
+	if !ok {
+		return 0, false
+	}
+	exp, err := strconv.ParseInt(expiry, 10, 64)
+	if err != nil || now.Unix() > exp {
+		return 0, false
+	}
+	lvl, err := logstox.ParseLevel(name)
+	if err != nil {
+		return 0, false
+	}
+	return lvl, true
+}
+
+// mac returns the HMAC-SHA256 of payload under d.Key.
+func (d Debug) mac(payload string) []byte {
+	m := hmac.New(sha256.New, d.Key)
+	m.Write([]byte(payload))
+	return m.Sum(nil)
+}
@@ -0,0 +1,51 @@
+package echox
+
+import (
+	"time"
+
+	"github.com/khinshankhan/logstox/fields"
+	"github.com/khinshankhan/logstox/httpx"
+	"github.com/labstack/echo/v4"
+)
+
+// Middleware returns echo middleware logging every request like a's Handler does, with the route it matched under
+// httpx.RouteKey and the error the handler returned, if any:
+//
+//	e := echo.New()
+//	e.Use(echox.Middleware(httpx.AccessLog{Logger: log}))
+//
+// A returned error is handled here, by passing it to the echo error handler so the request is logged with the status
+// it picks, and isn't returned: middleware registered before this one don't see it, and the error handler runs once.
+// Requests whose handler panics are logged like a's Handler logs them (see httpx.AccessLog.LogPanic) before the
+// panic carries on, eg to echo's Recover middleware registered before this one.
+func Middleware(a httpx.AccessLog) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+			var err error
+			defer func() {
+				var extra []fields.Field
+				if route := c.Path(); route != "" {
+					extra = append(extra, fields.String(httpx.RouteKey, route))
+				}
+				res := c.Response()
+				if v := recover(); v != nil {
+					status := 0 // echo reports 200 until a status is written
+					if res.Committed {
+						status = res.Status
+					}
+					a.LogPanic(c.Request(), status, res.Size, time.Since(start), v, extra...)
+					panic(v)
+				}
+				if err != nil {
+					extra = append(extra, fields.Error(err))
+				}
+				a.Log(c.Request(), res.Status, res.Size, time.Since(start), extra...)
+			}()
+			if err = next(c); err != nil {
+				c.Error(err)
+			}
+			return nil
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package echox
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/khinshankhan/logstox"
+	"github.com/khinshankhan/logstox/backend/memx"
+	"github.com/khinshankhan/logstox/fields"
+	"github.com/khinshankhan/logstox/httpx"
+)
+
+func TestMiddleware(t *testing.T) {
+	tests := []struct {
+		name       string
+		path       string
+		wantRoute  string
+		wantStatus int64
+		wantPanic  bool
+	}{
+		{"route pattern", "/users/1", "/users/:id", http.StatusOK, false},
+		{"unmatched route", "/nope", "", http.StatusNotFound, false},
+		{"panicking handler", "/panic", "/panic", http.StatusInternalServerError, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := memx.NewRecorder(1)
+			e := echo.New()
+			e.Use(Middleware(httpx.AccessLog{Logger: memx.Backend{Recorder: rec}.New(logstox.Options[fields.Field]{})}))
+			e.GET("/users/:id", func(c echo.Context) error { return c.String(http.StatusOK, "ok") })
+			e.GET("/panic", func(echo.Context) error { panic("boom") })
+
+			func() {
+				defer func() {
+					if v := recover(); (v != nil) != tt.wantPanic {
+						t.Errorf("recovered %v, want the handler's panic to carry on: %v", v, tt.wantPanic)
+					}
+				}()
+				e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, tt.path, nil))
+			}()
+
+			es := rec.Entries()
+			if len(es) != 1 {
+				t.Fatalf("logged %d entries, want 1", len(es))
+			}
+			fs := fields.Fields(es[0].Fields)
+			route, _ := fs.Get(httpx.RouteKey)
+			status, _ := fs.Get(httpx.StatusCodeKey)
+			_, panicked := fs.Get(fields.PanicKey)
+			if route.Str() != tt.wantRoute || status.Int64() != tt.wantStatus || panicked != tt.wantPanic {
+				t.Errorf("logged route %q, status %d and panic %v, want %q, %d and %v", route.Str(), status.Int64(),
+					panicked, tt.wantRoute, tt.wantStatus, tt.wantPanic)
+			}
+		})
+	}
+}
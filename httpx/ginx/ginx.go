@@ -0,0 +1,57 @@
+package ginx
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/khinshankhan/logstox/fields"
+	"github.com/khinshankhan/logstox/httpx"
+)
+
+// AbortedKey is the key of the field marking requests whose handler chain was aborted.
+const AbortedKey = "http.aborted"
+
+// Middleware returns gin middleware logging every request like a's Handler does, with the route it matched under
+// httpx.RouteKey, the errors attached with c.Error and, under AbortedKey, whether a handler aborted the chain:
+//
+//	r := gin.New()
+//	r.Use(ginx.Middleware(httpx.AccessLog{Logger: log}))
+//
+// Register it first, so requests aborted by later middleware, eg authentication, are logged with the status they
+// were aborted with. Requests are logged to the logger in the context of c.Request once the chain returns, so
+// handlers replacing c.Request with a derived context are taken into account. Requests whose handler panics are
+// logged like a's Handler logs them (see httpx.AccessLog.LogPanic) before the panic carries on, eg to gin.Recovery
+// registered before this middleware.
+func Middleware(a httpx.AccessLog) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		defer func() {
+			var extra []fields.Field
+			if route := c.FullPath(); route != "" {
+				extra = append(extra, fields.String(httpx.RouteKey, route))
+			}
+			if c.IsAborted() {
+				extra = append(extra, fields.Bool(AbortedKey, true))
+			}
+			if len(c.Errors) > 0 {
+				errs := make([]error, len(c.Errors))
+				for i, err := range c.Errors {
+					errs[i] = err
+				}
+				extra = append(extra, fields.Error(errors.Join(errs...)))
+			}
+			size := max(c.Writer.Size(), 0) // -1 until the body is written
+			if v := recover(); v != nil {
+				status := 0 // gin reports 200 until a status is written
+				if c.Writer.Written() {
+					status = c.Writer.Status()
+				}
+				a.LogPanic(c.Request, status, int64(size), time.Since(start), v, extra...)
+				panic(v)
+			}
+			a.Log(c.Request, c.Writer.Status(), int64(size), time.Since(start), extra...)
+		}()
+		c.Next()
+	}
+}
@@ -0,0 +1,71 @@
+package httpx
+
+import (
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/khinshankhan/logstox/fields"
+)
+
+// Keys used by RequestFields and ResponseFields, following the OpenTelemetry semantic conventions for HTTP, which
+// ECS shares for most of them.
+const (
+	MethodKey        = "http.request.method"
+	SchemeKey        = "url.scheme"
+	PathKey          = "url.path"
+	RouteKey         = "http.route" // the matched route template, set by router adapters
+	ServerAddressKey = "server.address"
+	ProtocolKey      = "network.protocol.version"
+	ClientAddressKey = "client.address"
+	UserAgentKey     = "user_agent.original"
+	RequestSizeKey   = "http.request.body.size"
+	StatusCodeKey    = "http.response.status_code"
+	ResponseSizeKey  = "http.response.body.size"
+	DurationKey      = "event.duration" // ECS, OpenTelemetry has no log attribute for it
+)
+
+// RequestFields returns the method, scheme, path, host, protocol version, client address, user agent and, if it's
+// known, body size of r as fields, for access logs or entries about a request:
+//
+//	log.Info("upload rejected", httpx.RequestFields(r)...)
+//
+// The client address is the host of r.RemoteAddr; headers like X-Forwarded-For can be spoofed by clients, so
+// they're left to the caller's proxy setup. The query isn't included, as it may carry secrets.
+func RequestFields(r *http.Request) []fields.Field {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	fs := []fields.Field{
+		fields.String(MethodKey, r.Method),
+		fields.String(SchemeKey, scheme),
+		fields.String(PathKey, r.URL.Path),
+		fields.String(ServerAddressKey, r.Host),
+		fields.String(ProtocolKey, strings.TrimPrefix(r.Proto, "HTTP/")),
+	}
+	if r.RemoteAddr != "" {
+		host, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			host = r.RemoteAddr
+		}
+		fs = append(fs, fields.String(ClientAddressKey, host))
+	}
+	if ua := r.UserAgent(); ua != "" {
+		fs = append(fs, fields.String(UserAgentKey, ua))
+	}
+	if r.ContentLength > 0 {
+		fs = append(fs, fields.Int64(RequestSizeKey, r.ContentLength))
+	}
+	return fs
+}
+
+// ResponseFields returns the status code, body size and duration of a response as fields.
+func ResponseFields(status int, size int64, dur time.Duration) []fields.Field {
+	return []fields.Field{
+		fields.Int(StatusCodeKey, status),
+		fields.Int64(ResponseSizeKey, size),
+		fields.Duration(DurationKey, dur),
+	}
+}
@@ -0,0 +1,126 @@
+package httpx
+
+import (
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/khinshankhan/logstox/fields"
+)
+
+// Keys used by TraceFields, matching the OpenTelemetry log data model.
+const (
+	TraceIDKey = "trace_id"
+	SpanIDKey  = "span_id"
+	SampledKey = "trace_sampled"
+)
+
+// Trace propagation headers understood by TraceFields.
+const (
+	TraceparentHeader = "traceparent"
+	B3Header          = "b3"
+	B3TraceIDHeader   = "X-B3-TraceId"
+	B3SpanIDHeader    = "X-B3-SpanId"
+	B3SampledHeader   = "X-B3-Sampled"
+)
+
+// TraceFields extracts trace and span IDs from r's propagation headers, for services that aren't on the
+// OpenTelemetry SDK but still want logs correlated with traces.
+//
+// W3C traceparent takes precedence, then single-header B3, then multi-header B3. Malformed or all-zero IDs are
+// ignored. Returns nil when no usable trace context is present.
+func TraceFields(r *http.Request) []fields.Field {
+	var traceID, spanID, sampled string
+	var ok bool
+	if v := r.Header.Get(TraceparentHeader); v != "" {
+		traceID, spanID, sampled, ok = parseTraceparent(v)
+	}
+	if !ok {
+		if v := r.Header.Get(B3Header); v != "" {
+			traceID, spanID, sampled, ok = parseB3(v)
+		}
+	}
+	if !ok {
+		traceID, spanID, sampled, ok = parseB3Multi(r.Header)
+	}
+	if !ok {
+		return nil
+	}
+
+	fs := []fields.Field{
+		fields.String(TraceIDKey, traceID),
+		fields.String(SpanIDKey, spanID),
+	}
+	if sampled != "" {
+		fs = append(fs, fields.Bool(SampledKey, sampled == "1" || sampled == "true" || sampled == "d"))
+	}
+	return fs
+}
+
+// parseTraceparent parses "version-traceid-spanid-flags" per W3C Trace Context.
+func parseTraceparent(v string) (traceID, spanID, sampled string, ok bool) {
+	parts := strings.Split(strings.TrimSpace(v), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" || len(parts[3]) != 2 {
+		return "", "", "", false
+	}
+	if !validID(parts[1], 32) || !validID(parts[2], 16) {
+		return "", "", "", false
+	}
+	flags, err := hex.DecodeString(parts[3])
+	if err != nil {
+		return "", "", "", false
+	}
+	sampled = "0"
+	if flags[0]&0x01 == 1 {
+		sampled = "1"
+	}
+	return parts[1], parts[2], sampled, true
+}
+
+// parseB3 parses the single "b3" header: "traceid-spanid[-sampled[-parentspanid]]".
+// A bare sampling decision ("0", "1", "d") carries no IDs and is rejected.
+func parseB3(v string) (traceID, spanID, sampled string, ok bool) {
+	parts := strings.Split(strings.TrimSpace(v), "-")
+	if len(parts) < 2 {
+		return "", "", "", false
+	}
+	traceID, spanID = normalizeB3TraceID(parts[0]), parts[1]
+	if !validID(traceID, 32) || !validID(spanID, 16) {
+		return "", "", "", false
+	}
+	if len(parts) > 2 {
+		sampled = parts[2]
+	}
+	return traceID, spanID, sampled, true
+}
+
+// parseB3Multi parses the X-B3-* headers.
+func parseB3Multi(h http.Header) (traceID, spanID, sampled string, ok bool) {
+	traceID = normalizeB3TraceID(strings.TrimSpace(h.Get(B3TraceIDHeader)))
+	spanID = strings.TrimSpace(h.Get(B3SpanIDHeader))
+	if !validID(traceID, 32) || !validID(spanID, 16) {
+		return "", "", "", false
+	}
+	return traceID, spanID, strings.TrimSpace(h.Get(B3SampledHeader)), true
+}
+
+// normalizeB3TraceID left-pads 64-bit B3 trace IDs to the 128-bit form used by W3C.
+func normalizeB3TraceID(id string) string {
+	if len(id) == 16 {
+		return strings.Repeat("0", 16) + id
+	}
+	return id
+}
+
+// validID reports whether id is n lowercase hex characters and not all zeros.
+func validID(id string, n int) bool {
+	if len(id) != n || strings.Trim(id, "0") == "" {
+		return false
+	}
+	for _, c := range id {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,88 @@
+package httpx
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/khinshankhan/logstox/fields"
+)
+
+// describe formats the fields of TraceFields as "key=value" pairs.
+func describe(fs []fields.Field) string {
+	out := make([]string, len(fs))
+	for i, f := range fs {
+		if f.Kind() == fields.FieldKindBool {
+			out[i] = fmt.Sprintf("%s=%t", f.Key, f.Bool())
+			continue
+		}
+		out[i] = f.Key + "=" + f.Str()
+	}
+	return strings.Join(out, " ")
+}
+
+func TestTraceFields(t *testing.T) {
+	const (
+		trace  = "4bf92f3577b34da6a3ce929d0e0e4736"
+		span   = "00f067aa0ba902b7"
+		trace8 = "a3ce929d0e0e4736"
+		padded = "0000000000000000" + trace8
+	)
+	w3c := "trace_id=" + trace + " span_id=" + span
+	b3 := "trace_id=" + padded + " span_id=" + span
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    string
+	}{
+		{"none", nil, ""},
+		{"traceparent sampled", map[string]string{TraceparentHeader: "00-" + trace + "-" + span + "-01"},
+			w3c + " trace_sampled=true"},
+		{"traceparent not sampled", map[string]string{TraceparentHeader: "00-" + trace + "-" + span + "-00"},
+			w3c + " trace_sampled=false"},
+		{"traceparent future version", map[string]string{TraceparentHeader: "cc-" + trace + "-" + span + "-03-extra"},
+			w3c + " trace_sampled=true"},
+		{"traceparent version ff", map[string]string{TraceparentHeader: "ff-" + trace + "-" + span + "-01"}, ""},
+		{"traceparent upper case", map[string]string{
+			TraceparentHeader: "00-" + strings.ToUpper(trace) + "-" + span + "-01"}, ""},
+		{"traceparent zero trace", map[string]string{
+			TraceparentHeader: "00-" + strings.Repeat("0", 32) + "-" + span + "-01"}, ""},
+		{"traceparent bad flags", map[string]string{TraceparentHeader: "00-" + trace + "-" + span + "-zz"}, ""},
+		{"traceparent short", map[string]string{TraceparentHeader: "00-" + trace + "-" + span}, ""},
+		{"b3", map[string]string{B3Header: trace + "-" + span + "-1-" + span}, w3c + " trace_sampled=true"},
+		{"b3 debug", map[string]string{B3Header: trace + "-" + span + "-d"}, w3c + " trace_sampled=true"},
+		{"b3 64-bit", map[string]string{B3Header: trace8 + "-" + span}, b3},
+		{"b3 sampling only", map[string]string{B3Header: "1"}, ""},
+		{"b3 multi", map[string]string{B3TraceIDHeader: trace8, B3SpanIDHeader: span, B3SampledHeader: "0"},
+			b3 + " trace_sampled=false"},
+		{"b3 multi without span", map[string]string{B3TraceIDHeader: trace}, ""},
+		{"traceparent over b3", map[string]string{
+			TraceparentHeader: "00-" + trace + "-" + span + "-01",
+			B3Header:          trace8 + "-" + span + "-0",
+		}, w3c + " trace_sampled=true"},
+		{"b3 over b3 multi", map[string]string{
+			B3Header:        trace + "-" + span,
+			B3TraceIDHeader: trace8, B3SpanIDHeader: span,
+		}, w3c},
+		{"malformed traceparent falls back", map[string]string{
+			TraceparentHeader: "garbage",
+			B3TraceIDHeader:   trace8, B3SpanIDHeader: span,
+		}, b3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/", nil)
+			for k, v := range tt.headers {
+				r.Header.Set(k, v)
+			}
+			fs := TraceFields(r)
+			if got := describe(fs); got != tt.want {
+				t.Errorf("TraceFields = %s, want %s", got, tt.want)
+			}
+			if tt.want == "" && fs != nil {
+				t.Errorf("TraceFields = %v, want nil", fs)
+			}
+		})
+	}
+}
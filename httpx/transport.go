@@ -0,0 +1,139 @@
+package httpx
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/khinshankhan/logstox"
+	"github.com/khinshankhan/logstox/contextx"
+	"github.com/khinshankhan/logstox/fields"
+)
+
+// Keys used by Transport on top of the RequestFields and ResponseFields ones.
+const (
+	QueryKey       = "url.query"                 // the query, see TransportOptions.Query
+	ResendCountKey = "http.request.resend_count" // how many times the request was sent before, see WithAttempt
+)
+
+// DefaultRedactParams are the query parameters Transport redacts when TransportOptions.RedactParams is nil.
+var DefaultRedactParams = []string{
+	"access_token", "api_key", "apikey", "code", "key", "password", "secret", "sig", "signature", "token",
+}
+
+// TransportOptions configure Transport.
+type TransportOptions struct {
+	// Base sends the requests. If nil, defaults to http.DefaultTransport.
+	Base http.RoundTripper
+	// Message is the message calls are logged with. If empty, defaults to "http call".
+	Message string
+	// Query logs the URL query under QueryKey, with the values of RedactParams replaced by fields.Redacted.
+	Query bool
+	// RedactParams are the query parameters whose values are redacted, matched case-insensitively. If nil, defaults
+	// to DefaultRedactParams.
+	RedactParams []string
+}
+
+// attemptKey is the context key of the attempt set by WithAttempt.
+type attemptKey struct{}
+
+// WithAttempt returns a copy of ctx carrying the 1-based attempt at sending a request, for retry loops, so Transport
+// logs it under ResendCountKey:
+//
+//	for attempt := 1; attempt <= 3; attempt++ {
+//		req = req.WithContext(httpx.WithAttempt(ctx, attempt))
+//		if resp, err = client.Do(req); err == nil {
+//			break
+//		}
+//	}
+func WithAttempt(ctx context.Context, attempt int) context.Context {
+	return context.WithValue(ctx, attemptKey{}, attempt)
+}
+
+// Transport returns a RoundTripper logging every outbound call with its method, scheme, host, path, status, body
+// size (-1 if unknown) and duration, to the logger in the request's context (see contextx.Logger) or else l:
+//
+//	client := &http.Client{Transport: httpx.Transport(log, httpx.TransportOptions{Query: true})}
+//
+// Calls are logged at ErrorLevel if they fail, with the error, at WarnLevel for 5xx responses and at InfoLevel
+// otherwise, once the response headers are in. The RoundTripper forwards CloseIdleConnections to Base, so
+// http.Client.CloseIdleConnections still reaches it.
+func Transport(l logstox.Logger[fields.Field], o TransportOptions) http.RoundTripper {
+	if o.Base == nil {
+		o.Base = http.DefaultTransport
+	}
+	if o.Message == "" {
+		o.Message = "http call"
+	}
+	if o.RedactParams == nil {
+		o.RedactParams = DefaultRedactParams
+	}
+	return transport{l: l, o: o}
+}
+
+// transport is the RoundTripper returned by Transport.
+type transport struct {
+	l logstox.Logger[fields.Field]
+	o TransportOptions
+}
+
+// Interface satisfaction (compile-time assertions).
+var _ interface{ CloseIdleConnections() } = transport{}
+
+// CloseIdleConnections closes the idle connections of Base if it keeps any.
+func (t transport) CloseIdleConnections() {
+	if c, ok := t.o.Base.(interface{ CloseIdleConnections() }); ok {
+		c.CloseIdleConnections()
+	}
+}
+
+func (t transport) RoundTrip(r *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.o.Base.RoundTrip(r)
+	dur := time.Since(start)
+
+	log := contextx.Logger(r.Context(), t.l)
+	if log == nil {
+		return resp, err
+	}
+	fs := []fields.Field{
+		fields.String(MethodKey, r.Method),
+		fields.String(SchemeKey, r.URL.Scheme),
+		fields.String(ServerAddressKey, r.URL.Host),
+		fields.String(PathKey, r.URL.Path),
+	}
+	if t.o.Query && r.URL.RawQuery != "" {
+		fs = append(fs, fields.String(QueryKey, t.redact(r.URL.RawQuery)))
+	}
+	if attempt, ok := r.Context().Value(attemptKey{}).(int); ok && attempt > 1 {
+		fs = append(fs, fields.Int(ResendCountKey, attempt-1))
+	}
+	switch {
+	case err != nil:
+		log.Error(t.o.Message, append(fs, fields.Duration(DurationKey, dur), fields.Error(err))...)
+	case resp.StatusCode >= http.StatusInternalServerError:
+		log.Warn(t.o.Message, append(fs, ResponseFields(resp.StatusCode, resp.ContentLength, dur)...)...)
+	default:
+		log.Info(t.o.Message, append(fs, ResponseFields(resp.StatusCode, resp.ContentLength, dur)...)...)
+	}
+	return resp, err
+}
+
+// redact returns the query q with the values of the RedactParams replaced, keeping its order.
+func (t transport) redact(q string) string {
+	pairs := strings.Split(q, "&")
+	for i, pair := range pairs {
+		k, _, ok := strings.Cut(pair, "=")
+		key, err := url.QueryUnescape(k)
+		if err != nil {
+			key = k
+		}
+		if ok && slices.ContainsFunc(t.o.RedactParams, func(p string) bool { return strings.EqualFold(p, key) }) {
+			pairs[i] = k + "=" + fields.Redacted
+		}
+	}
+	return strings.Join(pairs, "&")
+}
@@ -0,0 +1,23 @@
+package httpx
+
+import (
+	"net/http"
+	"testing"
+)
+
+// idleCloser is a RoundTripper recording CloseIdleConnections calls.
+type idleCloser struct {
+	http.RoundTripper
+	closed int
+}
+
+func (c *idleCloser) CloseIdleConnections() { c.closed++ }
+
+func TestTransportClosesIdleConnections(t *testing.T) {
+	base := &idleCloser{RoundTripper: http.DefaultTransport}
+	client := &http.Client{Transport: Transport(nil, TransportOptions{Base: base})}
+	client.CloseIdleConnections()
+	if base.closed != 1 {
+		t.Errorf("Base closed idle connections %d times, want 1", base.closed)
+	}
+}
@@ -0,0 +1,31 @@
+package logstox
+
+import (
+	"slices"
+)
+
+// Introspector is an optional extension for loggers that can report the context they carry, for middleware, routing
+// and tests.
+type Introspector[FT any] interface {
+	// Name returns the logger's name as built by Named, empty if unnamed.
+	Name() string
+	// Fields returns the context fields added via With, oldest first. The slice must not be modified.
+	Fields() []FT
+}
+
+// NameOf returns l's name, or "" if l doesn't implement Introspector.
+func NameOf[FT any](l Logger[FT]) string {
+	if in, ok := l.(Introspector[FT]); ok {
+		return in.Name()
+	}
+	return ""
+}
+
+// FieldsOf returns the context fields l carries, or nil if l doesn't implement Introspector.
+// The result is safe to append to.
+func FieldsOf[FT any](l Logger[FT]) []FT {
+	if in, ok := l.(Introspector[FT]); ok {
+		return slices.Clip(in.Fields())
+	}
+	return nil
+}
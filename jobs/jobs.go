@@ -0,0 +1,142 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sync/atomic"
+	"time"
+
+	"github.com/khinshankhan/logstox"
+	"github.com/khinshankhan/logstox/contextx"
+	"github.com/khinshankhan/logstox/fields"
+)
+
+// Keys of the fields runs are logged with.
+const (
+	JobKey      = "job"            // the job's name, on every entry of a run
+	RunKey      = "job.run"        // the run's number over the Job's lifetime, counting from 1
+	AttemptKey  = "job.attempt"    // the attempt's number within its run, counting from 1, see Job.Retries
+	DurationKey = "event.duration" // how long the attempt took
+	NextRunKey  = "job.next_run"   // when the job runs next, if it's known
+)
+
+// Job runs a function on a schedule with logging: every attempt of a run is logged when it starts and when it ends,
+// "job done" with its duration, "job failed" with its error, or "job panicked" with the recovered value and the stack
+// of the panic (see fields.PanicWithStack). Its Run method makes it a robfig/cron Job:
+//
+//	sched, _ := cron.ParseStandard("*/5 * * * *")
+//	job := &jobs.Job{Name: "sync-users", Func: syncUsers, Logger: log, Next: sched.Next}
+//	c.Schedule(sched, job)
+//
+// and Every runs it on a ticker. Runs are logged to the logger in their context (see contextx.Logger), falling back
+// to Logger, with JobKey, RunKey and AttemptKey; Func's context carries that logger, so its entries have them too. A
+// Job is safe for concurrent use, and mustn't be copied after its first run.
+type Job struct {
+	// Name names the job under JobKey.
+	Name string
+	// Func is the work of a run. Panics are recovered, so a scheduler keeps running, and returned as errors.
+	Func func(ctx context.Context) error
+	// Retries is how many more attempts a run makes when Func fails or panics, RetryDelay apart. The run returns the
+	// error of its last attempt.
+	Retries    int
+	RetryDelay time.Duration
+	// Logger logs the runs whose context carries no logger. If nil, those aren't logged.
+	Logger logstox.Logger[fields.Field]
+	// Next, if set, returns when the job runs next after the given time, logged under NextRunKey when a run ends, eg
+	// a robfig/cron Schedule's Next method.
+	Next func(time.Time) time.Time
+
+	runs atomic.Int64
+}
+
+// Run runs the job once with a background context, for schedulers calling a func(), eg robfig/cron.
+func (j *Job) Run() {
+	_ = j.RunContext(context.Background())
+}
+
+// RunContext runs the job once with ctx, returning the error of Func, or the panic it recovered from.
+func (j *Job) RunContext(ctx context.Context) error {
+	var next time.Time
+	if j.Next != nil {
+		next = j.Next(time.Now())
+	}
+	return j.run(ctx, next)
+}
+
+// Every runs the job every d until ctx is done, the next run being logged under NextRunKey. Runs don't overlap: a
+// tick missed while a run is in progress is dropped, like time.Ticker does. If now is set, the job runs once right
+// away.
+func (j *Job) Every(ctx context.Context, d time.Duration, now bool) {
+	t := time.NewTicker(d)
+	defer t.Stop()
+	if now {
+		_ = j.run(ctx, time.Now().Add(d))
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case tick := <-t.C:
+			_ = j.run(ctx, tick.Add(d))
+		}
+	}
+}
+
+// run runs the job once, next being when it runs next, or the zero time if that isn't known.
+func (j *Job) run(ctx context.Context, next time.Time) error {
+	n := j.runs.Add(1)
+	var err error
+	for attempt := int64(1); ; attempt++ {
+		if err = j.attempt(ctx, n, attempt, next); err == nil || attempt > int64(j.Retries) {
+			return err
+		}
+		t := time.NewTimer(j.RetryDelay)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return err
+		case <-t.C:
+		}
+	}
+}
+
+// attempt runs attempt of the job's run n.
+func (j *Job) attempt(ctx context.Context, n, attempt int64, next time.Time) (err error) {
+	log := contextx.Logger(ctx, j.Logger)
+	if log != nil {
+		log = log.With(fields.String(JobKey, j.Name), fields.Int64(RunKey, n), fields.Int64(AttemptKey, attempt))
+		ctx = contextx.WithLogger(ctx, log)
+		log.Info("job started")
+	}
+
+	start := time.Now()
+	defer func() {
+		v := recover()
+		var stack []byte
+		if v != nil {
+			stack = debug.Stack() // here, while it still shows where Func panicked
+			if e, ok := v.(error); ok {
+				err = fmt.Errorf("jobs: %s panicked: %w", j.Name, e)
+			} else {
+				err = fmt.Errorf("jobs: %s panicked: %v", j.Name, v)
+			}
+		}
+		if log == nil {
+			return
+		}
+		fs := []fields.Field{fields.Duration(DurationKey, time.Since(start))}
+		if !next.IsZero() {
+			fs = append(fs, fields.TimeField(NextRunKey, next))
+		}
+		switch {
+		case v != nil:
+			log.Error("job panicked", append(fs, fields.PanicWithStack(v, stack))...)
+		case err != nil:
+			log.Error("job failed", append(fs, fields.Error(err))...)
+		default:
+			log.Info("job done", fs...)
+		}
+	}()
+	return j.Func(ctx)
+}
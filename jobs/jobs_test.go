@@ -0,0 +1,90 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/khinshankhan/logstox"
+	"github.com/khinshankhan/logstox/backend/memx"
+	"github.com/khinshankhan/logstox/fields"
+)
+
+func crash(context.Context) error { panic("boom") }
+
+func TestJobPanicLogsThePanicStack(t *testing.T) {
+	rec := memx.NewRecorder(8)
+	j := &Job{Name: "j", Func: crash, Logger: memx.Backend{Recorder: rec}.New(logstox.Options[fields.Field]{})}
+	for range 2 {
+		if err := j.RunContext(context.Background()); err == nil || !strings.Contains(err.Error(), "boom") {
+			t.Fatalf("RunContext = %v, want the panic", err)
+		}
+	}
+
+	var runs []int64
+	for _, e := range rec.Entries() {
+		for _, f := range e.Fields {
+			if f.Key == RunKey {
+				runs = append(runs, f.Int64())
+			}
+			if e.Message == "job panicked" && f.Key == fields.PanicKey {
+				if stack := f.Fields()[2].Str(); !strings.Contains(stack, "jobs.crash") {
+					t.Errorf("stack doesn't show where the job panicked:\n%s", stack)
+				}
+			}
+		}
+	}
+	if want := []int64{1, 1, 2, 2}; !slices.Equal(runs, want) {
+		t.Errorf("%s = %v, want %v", RunKey, runs, want)
+	}
+}
+
+func TestJobRetries(t *testing.T) {
+	errFlaky := errors.New("flaky")
+	var calls int
+	rec := memx.NewRecorder(16)
+	j := &Job{
+		Name: "j",
+		Func: func(context.Context) error {
+			calls++
+			if calls%3 != 0 {
+				return errFlaky
+			}
+			return nil
+		},
+		Logger:  memx.Backend{Recorder: rec}.New(logstox.Options[fields.Field]{}),
+		Retries: 2,
+	}
+	if err := j.RunContext(context.Background()); err != nil {
+		t.Fatalf("RunContext = %v, want the third attempt's success", err)
+	}
+	j.Retries = 0
+	if err := j.RunContext(context.Background()); !errors.Is(err, errFlaky) {
+		t.Fatalf("RunContext = %v, want %v without retries", err, errFlaky)
+	}
+
+	var got []string
+	for _, e := range rec.Entries() {
+		if e.Message == "job started" {
+			continue
+		}
+		run, _ := fields.Fields(e.Fields).Get(RunKey)
+		attempt, _ := fields.Fields(e.Fields).Get(AttemptKey)
+		got = append(got, fmt.Sprintf("%s %d.%d", e.Message, run.Int64(), attempt.Int64()))
+	}
+	want := []string{"job failed 1.1", "job failed 1.2", "job done 1.3", "job failed 2.1"}
+	if !slices.Equal(got, want) {
+		t.Errorf("logged %v, want %v", got, want)
+	}
+}
+
+func TestJobPanicWrapsErrors(t *testing.T) {
+	errBoom := errors.New("boom")
+	j := &Job{Name: "j", Func: func(context.Context) error { panic(errBoom) }}
+	if err := j.RunContext(context.Background()); !errors.Is(err, errBoom) {
+		t.Errorf("RunContext = %v, want it wrapping %v", err, errBoom)
+	}
+}
@@ -0,0 +1,182 @@
+package logstox
+
+import (
+	"context"
+	"sync"
+)
+
+// LazyWither is an optional extension for loggers that can defer adding context fields.
+type LazyWither[FT any] interface {
+	WithLazy(...FT) Logger[FT]
+}
+
+// WithLazy returns a child of l with fs added as context, like With, except the work With does (eg encoding the
+// fields) is deferred until the child, or one of its descendants, first emits an entry at an enabled level. It's for
+// contexts that are built often and rarely logged, such as per-request loggers:
+//
+//	log := logstox.WithLazy(base, fields.String("request_id", id), fields.Any("user", u))
+//
+// The fields are added once; values referenced by them must not change in between. Loggers that don't implement
+// LazyWither are wrapped so that With is called on first use.
+func WithLazy[FT any](l Logger[FT], fs ...FT) Logger[FT] {
+	if len(fs) == 0 {
+		return l
+	}
+	if lw, ok := l.(LazyWither[FT]); ok {
+		return lw.WithLazy(fs...)
+	}
+
+	// skip deferred's own level method so file:line points at the caller
+	base := WithOptions(l, AddCallerSkip(1))
+	return deferred[FT]{
+		get:     sync.OnceValue(func() Logger[FT] { return base.With(fs...) }),
+		base:    base,
+		context: fs,
+	}
+}
+
+// deferred is the fallback Logger returned by WithLazy.
+type deferred[FT any] struct {
+	get func() Logger[FT] // the logger with the context applied, built on first call
+	// base mirrors get's logger minus the deferred context, so Enabled and Name don't force it.
+	base    Logger[FT]
+	context []FT // fields deferred so far, for Fields
+}
+
+// Interface satisfaction (compile-time assertions).
+var (
+	_ Logger[any]         = deferred[any]{}
+	_ LevelCheck          = deferred[any]{}
+	_ OptionsApplier[any] = deferred[any]{}
+	_ LevelSetter[any]    = deferred[any]{}
+	_ Introspector[any]   = deferred[any]{}
+	_ LazyWither[any]     = deferred[any]{}
+	_ Noticer[any]        = deferred[any]{}
+	_ LevelVarHolder      = deferred[any]{}
+	_ Closer              = deferred[any]{}
+	_ ContextBinder[any]  = deferred[any]{}
+)
+
+// DEBUG (-1): for recording messages useful for debugging.
+func (d deferred[FT]) Debug(m string, f ...FT) {
+	if Enabled(d.base, DebugLevel) {
+		d.get().Debug(m, f...)
+	}
+}
+
+// INFO (0): for messages describing normal application operations.
+func (d deferred[FT]) Info(m string, f ...FT) {
+	if Enabled(d.base, InfoLevel) {
+		d.get().Info(m, f...)
+	}
+}
+
+// Notice logs at NoticeLevel. It logs at Info if the wrapped logger isn't a Noticer.
+func (d deferred[FT]) Notice(m string, f ...FT) {
+	if !Enabled(d.base, NoticeLevel) {
+		return
+	}
+	if n, ok := d.get().(Noticer[FT]); ok {
+		n.Notice(m, f...)
+	} else {
+		d.get().Info(m, f...)
+	}
+}
+
+// WARN (1): for recording messages indicating something unusual happened that may need attention before it escalates to a more severe issue.
+func (d deferred[FT]) Warn(m string, f ...FT) {
+	if Enabled(d.base, WarnLevel) {
+		d.get().Warn(m, f...)
+	}
+}
+
+// ERROR (2): for recording unexpected error conditions in the program.
+func (d deferred[FT]) Error(m string, f ...FT) {
+	if Enabled(d.base, ErrorLevel) {
+		d.get().Error(m, f...)
+	}
+}
+
+// DPANIC (3): for recording severe error conditions in development. It behaves like PANIC in development and ERROR in production.
+// It's always passed on, since the logger decides whether it panics.
+func (d deferred[FT]) DPanic(m string, f ...FT) { d.get().DPanic(m, f...) }
+
+// PANIC (4): calls panic() after logging an error condition.
+func (d deferred[FT]) Panic(m string, f ...FT) { d.get().Panic(m, f...) }
+
+// FATAL (5): calls os.Exit(1) after logging an error condition.
+func (d deferred[FT]) Fatal(m string, f ...FT) { d.get().Fatal(m, f...) }
+
+// With returns a child with f added after the deferred context. Adding f is deferred as well.
+func (d deferred[FT]) With(f ...FT) Logger[FT] {
+	return d.derive(d.base, func(l Logger[FT]) Logger[FT] { return l.With(f...) }, f)
+}
+
+// WithLazy is With: the child is deferred either way.
+func (d deferred[FT]) WithLazy(f ...FT) Logger[FT] {
+	return d.With(f...)
+}
+
+// Named adds a new path segment to the logger's name.
+func (d deferred[FT]) Named(n string) Logger[FT] {
+	return d.derive(d.base.Named(n), func(l Logger[FT]) Logger[FT] { return l.Named(n) }, nil)
+}
+
+// WithOptions applies opts to the underlying logger.
+func (d deferred[FT]) WithOptions(opts ...Option) Logger[FT] {
+	return d.derive(WithOptions(d.base, opts...), func(l Logger[FT]) Logger[FT] { return WithOptions(l, opts...) }, nil)
+}
+
+// WithLevel sets the underlying logger's minimum level.
+func (d deferred[FT]) WithLevel(lvl Level) Logger[FT] {
+	return d.derive(WithLevel(d.base, lvl), func(l Logger[FT]) Logger[FT] { return WithLevel(l, lvl) }, nil)
+}
+
+// WithContext binds the underlying logger to ctx.
+func (d deferred[FT]) WithContext(ctx context.Context) Logger[FT] {
+	return d.derive(WithContext(d.base, ctx), func(l Logger[FT]) Logger[FT] { return WithContext(l, ctx) }, nil)
+}
+
+// derive returns a deferred child whose logger is apply(parent's logger), built on first use.
+func (d deferred[FT]) derive(base Logger[FT], apply func(Logger[FT]) Logger[FT], fs []FT) deferred[FT] {
+	get := d.get
+	context := d.context
+	if len(fs) > 0 {
+		context = append(context[:len(context):len(context)], fs...)
+	}
+	return deferred[FT]{
+		get:     sync.OnceValue(func() Logger[FT] { return apply(get()) }),
+		base:    base,
+		context: context,
+	}
+}
+
+// Sync delegates to the underlying logger's Sync. It doesn't force the deferred context.
+func (d deferred[FT]) Sync() error {
+	return d.base.Sync()
+}
+
+// Enabled reports whether the underlying logger records lvl. It doesn't force the deferred context.
+func (d deferred[FT]) Enabled(lvl Level) bool {
+	return Enabled(d.base, lvl)
+}
+
+// Name returns the underlying logger's name.
+func (d deferred[FT]) Name() string {
+	return NameOf(d.base)
+}
+
+// LevelVar returns the wrapped logger's LevelVar without forcing the deferred context.
+func (d deferred[FT]) LevelVar() *LevelVar {
+	return LevelVarOf(d.base)
+}
+
+// Close closes the wrapped logger without forcing the deferred context.
+func (d deferred[FT]) Close() error {
+	return Close(d.base)
+}
+
+// Fields returns the underlying logger's context fields followed by the deferred ones.
+func (d deferred[FT]) Fields() []FT {
+	return append(FieldsOf(d.base), d.context...)
+}
@@ -1,7 +1,9 @@
 package logstox
 
 import (
+	"cmp"
 	"encoding"
+	"flag"
 	"fmt"
 	"strings"
 )
@@ -9,7 +11,7 @@ import (
 // TODO: migrate to using a tool like stringer to generate interface implementations.
 
 // Level is an implementation-agnostic log severity.
-// Higher numbers are more severe. The zero value is InfoLevel.
+// Higher numbers are more severe, except for NoticeLevel (see Compare). The zero value is InfoLevel.
 type Level int8
 
 const (
@@ -29,8 +31,28 @@ const (
 	PanicLevel Level = 4
 	// FatalLevel is intended for errors after which a logger will exit.
 	FatalLevel Level = 5
+	// NoticeLevel describes normal but significant conditions, for syslog and GELF consumers that require NOTICE.
+	// Loggers write it through Noticer.
+	//
+	// It was added after the other levels, which keep their values: it's numbered after FatalLevel but ranks between
+	// InfoLevel and WarnLevel, so compare levels with Compare rather than < and >.
+	NoticeLevel Level = 6
 )
 
+// Compare returns -1, 0 or +1 as l is less severe than, as severe as or more severe than o. NoticeLevel ranks between
+// InfoLevel and WarnLevel, other levels by their value.
+func (l Level) Compare(o Level) int {
+	return cmp.Compare(l.rank(), o.rank())
+}
+
+// rank orders levels for Compare: twice their value, leaving room for NoticeLevel right above InfoLevel.
+func (l Level) rank() int {
+	if l == NoticeLevel {
+		return 2*int(InfoLevel) + 1
+	}
+	return 2 * int(l)
+}
+
 // String implements fmt.Stringer, returning the lower-case name of the log level.
 func (l Level) String() string {
 	switch l {
@@ -38,6 +60,8 @@ func (l Level) String() string {
 		return "debug"
 	case InfoLevel:
 		return "info"
+	case NoticeLevel:
+		return NoticeName
 	case WarnLevel:
 		return "warn"
 	case ErrorLevel:
@@ -56,7 +80,7 @@ func (l Level) String() string {
 // Valid reports whether l is one of the defined levels.
 func (l Level) Valid() bool {
 	switch l {
-	case DebugLevel, InfoLevel, WarnLevel, ErrorLevel, DPanicLevel, PanicLevel, FatalLevel:
+	case DebugLevel, InfoLevel, NoticeLevel, WarnLevel, ErrorLevel, DPanicLevel, PanicLevel, FatalLevel:
 		return true
 	default:
 		return false
@@ -70,6 +94,8 @@ func ParseLevel(s string) (Level, error) {
 		return DebugLevel, nil
 	case "info":
 		return InfoLevel, nil
+	case NoticeName:
+		return NoticeLevel, nil
 	case "warn", "warning":
 		return WarnLevel, nil
 	case "error":
@@ -103,9 +129,50 @@ func (l *Level) UnmarshalText(text []byte) error {
 	return nil
 }
 
+// Set implements flag.Value, parsing s with ParseLevel.
+func (l *Level) Set(s string) error {
+	return l.UnmarshalText([]byte(s))
+}
+
+// Type implements pflag.Value, naming the flag's type in usage output.
+func (l *Level) Type() string {
+	return "level"
+}
+
+// LevelFlag defines a Level flag on flag.CommandLine with the given name, default value and usage, returning the
+// address of the Level it's stored in:
+//
+//	level := logstox.LevelFlag("log-level", logstox.InfoLevel, "minimum level to log")
+//	flag.Parse()
+//	log := backend.New(logstox.Options[F]{Level: *level})
+//
+// *Level also implements pflag.Value, for use with pflag's Var.
+func LevelFlag(name string, value Level, usage string) *Level {
+	l := new(Level)
+	*l = value
+	flag.Var(l, name, usage)
+	return l
+}
+
+// AtLeast returns a level filter (eg for LevelWriter) enabling min and above.
+func AtLeast(min Level) func(Level) bool {
+	return func(l Level) bool { return l.Compare(min) >= 0 }
+}
+
+// Below returns a level filter enabling levels strictly below max.
+func Below(max Level) func(Level) bool {
+	return func(l Level) bool { return l.Compare(max) < 0 }
+}
+
+// Between returns a level filter enabling levels from min up to and including max.
+func Between(min, max Level) func(Level) bool {
+	return func(l Level) bool { return l.Compare(min) >= 0 && l.Compare(max) <= 0 }
+}
+
 // Interface satisfaction (compile-time assertions).
 var (
 	_ fmt.Stringer             = (*Level)(nil)
+	_ flag.Value               = (*Level)(nil)
 	_ encoding.TextMarshaler   = (*Level)(nil)
 	_ encoding.TextUnmarshaler = (*Level)(nil)
 )
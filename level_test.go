@@ -0,0 +1,60 @@
+package logstox
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestLevelCompare(t *testing.T) {
+	tests := []struct {
+		l, o Level
+		want int
+	}{
+		{InfoLevel, NoticeLevel, -1},
+		{NoticeLevel, NoticeLevel, 0},
+		{NoticeLevel, WarnLevel, -1},
+		{FatalLevel, NoticeLevel, 1},
+		{DebugLevel, InfoLevel, -1},
+		{ErrorLevel, WarnLevel, 1},
+	}
+	for _, tt := range tests {
+		if got := tt.l.Compare(tt.o); got != tt.want {
+			t.Errorf("%v.Compare(%v) = %d, want %d", tt.l, tt.o, got, tt.want)
+		}
+	}
+}
+
+func TestLevelValuesAreStable(t *testing.T) {
+	// levels are persisted and sent over the wire by value
+	for l, want := range map[Level]int{
+		DebugLevel: -1, InfoLevel: 0, WarnLevel: 1, ErrorLevel: 2, DPanicLevel: 3, PanicLevel: 4, FatalLevel: 5,
+	} {
+		if int(l) != want {
+			t.Errorf("%v = %d, want %d", l, l, want)
+		}
+	}
+}
+
+func TestLevelFormatNumber(t *testing.T) {
+	tests := []struct {
+		l            Level
+		syslog, otel int
+	}{
+		{DebugLevel, 7, 5},
+		{InfoLevel, 6, 9},
+		{NoticeLevel, 5, 10},
+		{WarnLevel, 4, 13},
+		{FatalLevel, 0, 21},
+	}
+	for _, tt := range tests {
+		if n, _ := LevelFormatSyslog.Number(tt.l); n != tt.syslog {
+			t.Errorf("syslog number of %v = %d, want %d", tt.l, n, tt.syslog)
+		}
+		if n, _ := LevelFormatOTel.Number(tt.l); n != tt.otel {
+			t.Errorf("OTel number of %v = %d, want %d", tt.l, n, tt.otel)
+		}
+		if l, err := LevelFormatSyslog.Parse(fmt.Sprint(tt.syslog)); err != nil || l != tt.l {
+			t.Errorf("syslog Parse(%d) = %v, %v; want %v", tt.syslog, l, err, tt.l)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package logstox
+
+import (
+	"context"
+	"os"
+)
+
+// LevelSetter is an optional extension for loggers that can derive a child with its own minimum level.
+type LevelSetter[FT any] interface {
+	WithLevel(Level) Logger[FT]
+}
+
+// WithLevel returns a child of l that drops entries below lvl, so a noisy subcomponent can be quieted without a
+// second backend:
+//
+//	db := logstox.WithLevel(log.Named("db"), logstox.WarnLevel)
+//
+// The child's level replaces any level set by an earlier WithLevel, and the parent is unaffected. It can't record
+// entries the backend itself is configured to drop.
+//
+// Loggers that don't implement LevelSetter are wrapped in a filter. Panic and Fatal keep their semantics even if
+// the entry is filtered.
+func WithLevel[FT any](l Logger[FT], lvl Level) Logger[FT] {
+	if ls, ok := l.(LevelSetter[FT]); ok {
+		return ls.WithLevel(lvl)
+	}
+	// skip the filter's own level method so file:line points at the caller
+	return leveled[FT]{base: WithOptions(l, AddCallerSkip(1)), min: lvl}
+}
+
+// leveled is the fallback Logger returned by WithLevel, and the Logger returned by WithLevels.
+type leveled[FT any] struct {
+	base Logger[FT]
+	min  Level
+	// levels, if set, drives the minimum level by name instead of min, v being the cached level for base's name.
+	levels *Levels
+	v      *LevelVar
+}
+
+// level returns the current minimum level.
+func (l leveled[FT]) level() Level {
+	if l.v != nil {
+		return l.v.Level()
+	}
+	return l.min
+}
+
+// Interface satisfaction (compile-time assertions).
+var (
+	_ Logger[any]        = leveled[any]{}
+	_ LevelCheck         = leveled[any]{}
+	_ LevelSetter[any]   = leveled[any]{}
+	_ Introspector[any]  = leveled[any]{}
+	_ Noticer[any]       = leveled[any]{}
+	_ LevelVarHolder     = leveled[any]{}
+	_ Closer             = leveled[any]{}
+	_ ContextBinder[any] = leveled[any]{}
+)
+
+// DEBUG (-1): for recording messages useful for debugging.
+func (l leveled[FT]) Debug(m string, f ...FT) {
+	if l.level().Compare(DebugLevel) <= 0 {
+		l.base.Debug(m, f...)
+	}
+}
+
+// INFO (0): for messages describing normal application operations.
+func (l leveled[FT]) Info(m string, f ...FT) {
+	if l.level().Compare(InfoLevel) <= 0 {
+		l.base.Info(m, f...)
+	}
+}
+
+// Notice logs at NoticeLevel. It logs at Info if the wrapped logger isn't a Noticer.
+func (l leveled[FT]) Notice(m string, f ...FT) {
+	if l.level().Compare(NoticeLevel) > 0 {
+		return
+	}
+	if n, ok := l.base.(Noticer[FT]); ok {
+		n.Notice(m, f...)
+	} else {
+		l.base.Info(m, f...)
+	}
+}
+
+// WARN (1): for recording messages indicating something unusual happened that may need attention before it escalates to a more severe issue.
+func (l leveled[FT]) Warn(m string, f ...FT) {
+	if l.level().Compare(WarnLevel) <= 0 {
+		l.base.Warn(m, f...)
+	}
+}
+
+// ERROR (2): for recording unexpected error conditions in the program.
+func (l leveled[FT]) Error(m string, f ...FT) {
+	if l.level().Compare(ErrorLevel) <= 0 {
+		l.base.Error(m, f...)
+	}
+}
+
+// DPANIC (3): for recording severe error conditions in development. It behaves like PANIC in development and ERROR in production.
+func (l leveled[FT]) DPanic(m string, f ...FT) {
+	if l.level().Compare(DPanicLevel) <= 0 {
+		l.base.DPanic(m, f...)
+	}
+}
+
+// PANIC (4): calls panic() after logging an error condition.
+func (l leveled[FT]) Panic(m string, f ...FT) {
+	if l.level().Compare(PanicLevel) <= 0 {
+		l.base.Panic(m, f...)
+	}
+	panic(m)
+}
+
+// FATAL (5): calls os.Exit(1) after logging an error condition.
+func (l leveled[FT]) Fatal(m string, f ...FT) {
+	if l.level().Compare(FatalLevel) <= 0 {
+		l.base.Fatal(m, f...)
+	}
+	os.Exit(1)
+}
+
+// With returns a child with f added as context and the same minimum level.
+func (l leveled[FT]) With(f ...FT) Logger[FT] {
+	child := l
+	child.base = l.base.With(f...)
+	return child
+}
+
+// Named adds a new path segment to the logger's name. With WithLevels, the child's level is the one for its name.
+func (l leveled[FT]) Named(n string) Logger[FT] {
+	child := l
+	child.base = l.base.Named(n)
+	if l.levels != nil {
+		child.v = l.levels.Var(NameOf(child.base))
+	}
+	return child
+}
+
+// Sync delegates to the underlying logger's Sync.
+func (l leveled[FT]) Sync() error {
+	return l.base.Sync()
+}
+
+// Enabled reports whether lvl is at or above the minimum and recorded by the underlying logger.
+func (l leveled[FT]) Enabled(lvl Level) bool {
+	return lvl.Compare(l.level()) >= 0 && Enabled(l.base, lvl)
+}
+
+// WithLevel replaces the minimum level, including one driven by WithLevels.
+func (l leveled[FT]) WithLevel(lvl Level) Logger[FT] {
+	return leveled[FT]{base: l.base, min: lvl}
+}
+
+// WithContext binds the underlying logger to ctx, keeping the minimum level.
+func (l leveled[FT]) WithContext(ctx context.Context) Logger[FT] {
+	child := l
+	child.base = WithContext(l.base, ctx)
+	return child
+}
+
+// Name returns the underlying logger's name.
+func (l leveled[FT]) Name() string {
+	return NameOf(l.base)
+}
+
+// LevelVar returns the wrapped logger's LevelVar.
+func (l leveled[FT]) LevelVar() *LevelVar {
+	return LevelVarOf(l.base)
+}
+
+// Close closes the wrapped logger.
+func (l leveled[FT]) Close() error {
+	return Close(l.base)
+}
+
+// Fields returns the underlying logger's context fields.
+func (l leveled[FT]) Fields() []FT {
+	return FieldsOf(l.base)
+}
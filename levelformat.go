@@ -0,0 +1,59 @@
+package logstox
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// LevelFormat selects how levels are written: by name, or as a number for ingestion pipelines that expect one. It's
+// set per logger (see Options.LevelFormat); Level's own text form is always the name.
+type LevelFormat uint8
+
+const (
+	// LevelFormatName writes levels by name, eg "info" (default).
+	LevelFormatName LevelFormat = iota
+	// LevelFormatSyslog writes RFC 5424 severities, eg 6 for info. Lower numbers are more severe.
+	LevelFormatSyslog
+	// LevelFormatOTel writes OpenTelemetry severity numbers, eg 9 for info.
+	LevelFormatOTel
+)
+
+// levelsBySeverity are the Valid levels, least severe first.
+var levelsBySeverity = [...]Level{
+	DebugLevel, InfoLevel, NoticeLevel, WarnLevel, ErrorLevel, DPanicLevel, PanicLevel, FatalLevel,
+}
+
+// Number returns the number l is written as in format f. It reports false for LevelFormatName and levels that aren't
+// Valid.
+func (f LevelFormat) Number(l Level) (int, bool) {
+	if !l.Valid() {
+		return 0, false
+	}
+	i := slices.Index(levelsBySeverity[:], l)
+	switch f {
+	case LevelFormatSyslog:
+		// debug, info, notice, warning, err, crit, alert, emerg
+		return [...]int{7, 6, 5, 4, 3, 2, 1, 0}[i], true
+	case LevelFormatOTel:
+		// DEBUG, INFO, INFO2, WARN, ERROR, ERROR2, ERROR3, FATAL
+		return [...]int{5, 9, 10, 13, 17, 18, 19, 21}[i], true
+	default:
+		return 0, false
+	}
+}
+
+// Parse parses a level written in format f: by name (see ParseLevel), or as one of f's numbers if f is numeric.
+func (f LevelFormat) Parse(s string) (Level, error) {
+	n, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || f == LevelFormatName {
+		return ParseLevel(s)
+	}
+	for _, l := range levelsBySeverity {
+		if v, ok := f.Number(l); ok && v == n {
+			return l, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown level number %d", n)
+}
@@ -0,0 +1,274 @@
+package levelpoll
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/khinshankhan/logstox"
+)
+
+// Defaults used for zero Options.
+const (
+	DefaultInterval = 30 * time.Second
+	DefaultJitter   = 0.2
+	DefaultTimeout  = 10 * time.Second
+)
+
+// maxDocumentSize bounds the documents HTTP reads, so a misconfigured endpoint can't exhaust memory.
+const maxDocumentSize = 1 << 20
+
+// Fetcher fetches the level document, see Document.
+type Fetcher interface {
+	Fetch(ctx context.Context) ([]byte, error)
+}
+
+// FetcherFunc adapts a function to a Fetcher, eg to read the document from an etcd client.
+type FetcherFunc func(ctx context.Context) ([]byte, error)
+
+// Fetch calls f.
+func (f FetcherFunc) Fetch(ctx context.Context) ([]byte, error) {
+	return f(ctx)
+}
+
+// HTTP returns a Fetcher GETting the document from url with client (http.DefaultClient if nil). Anything but a 200
+// response is an error. It works with any endpoint serving the document as is, eg Consul's KV store with ?raw:
+//
+//	levelpoll.HTTP(nil, "http://consul:8500/v1/kv/config/api/log-levels?raw")
+func HTTP(client *http.Client, url string) Fetcher {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return FetcherFunc(func(ctx context.Context) ([]byte, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("GET %s: %s", url, resp.Status)
+		}
+		return io.ReadAll(io.LimitReader(resp.Body, maxDocumentSize))
+	})
+}
+
+// Document routes levels by logger name: a JSON object from names to levels, eg
+//
+//	{"": "info", "db": "warn", "http.client": "debug"}
+//
+// A name applies to the logger of that name and its descendants ("db" covers "db.pool"), the longest one matching
+// winning. "" covers every logger.
+type Document map[string]logstox.Level
+
+// ParseDocument parses a JSON level document.
+func ParseDocument(data []byte) (Document, error) {
+	var d Document
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, fmt.Errorf("parsing level document: %w", err)
+	}
+	return d, nil
+}
+
+// Level returns the level d routes name to, and false if no entry covers it.
+func (d Document) Level(name string) (logstox.Level, bool) {
+	for {
+		if lvl, ok := d[name]; ok {
+			return lvl, true
+		}
+		if name == "" {
+			return 0, false
+		}
+		i := strings.LastIndexByte(name, '.')
+		if i < 0 {
+			name = ""
+		} else {
+			name = name[:i]
+		}
+	}
+}
+
+// Options tune a Poller.
+type Options struct {
+	// Interval is the time between polls (DefaultInterval if <= 0).
+	Interval time.Duration
+	// Jitter randomizes every interval by up to this fraction either way, so a fleet of instances doesn't poll in
+	// lockstep: DefaultJitter if 0, none if negative, and capped just below 1 so no interval is cut to nothing.
+	Jitter float64
+	// Timeout bounds each fetch (DefaultTimeout if <= 0).
+	Timeout time.Duration
+	// Default is the level of names the document doesn't cover, and of every name until the first successful poll.
+	Default logstox.Level
+	// ErrorHandler is called with fetch and parse errors; by default they're printed to stderr. The last document
+	// fetched successfully stays in effect.
+	ErrorHandler func(error)
+}
+
+// Poller polls a level Document and applies it to the LevelVars registered with it. Failed polls leave the last
+// known good document in effect. It's also a logstox.LevelProvider notifying its changes, so it can drive
+// logstox.WithLevels:
+//
+//	p := levelpoll.New(levelpoll.HTTP(nil, url), levelpoll.Options{})
+//	go p.Run(ctx)
+//	log = logstox.WithLevels(log, logstox.NewLevels(p))
+//
+// It's safe for concurrent use.
+type Poller struct {
+	f Fetcher
+	o Options
+
+	mu        sync.Mutex
+	doc       Document
+	vars      map[string][]*logstox.LevelVar
+	listeners []func()
+}
+
+// New returns a Poller fetching its document with f. Call Poll or Run to start polling.
+func New(f Fetcher, o Options) *Poller {
+	if o.Interval <= 0 {
+		o.Interval = DefaultInterval
+	}
+	switch {
+	case o.Jitter == 0:
+		o.Jitter = DefaultJitter
+	case o.Jitter < 0 || math.IsNaN(o.Jitter):
+		o.Jitter = 0
+	}
+	o.Jitter = min(o.Jitter, math.Nextafter(1, 0))
+	if o.Timeout <= 0 {
+		o.Timeout = DefaultTimeout
+	}
+	if o.ErrorHandler == nil {
+		o.ErrorHandler = func(err error) { fmt.Fprintf(os.Stderr, "levelpoll: %v\n", err) }
+	}
+	return &Poller{f: f, o: o, vars: make(map[string][]*logstox.LevelVar)}
+}
+
+// Register sets v to the level of name right away, and again after every poll changing it, eg for a backend built
+// with Options.LevelVar and Options.Name. Unregister v once its logger is done with, or the Poller keeps it.
+func (p *Poller) Register(name string, v *logstox.LevelVar) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.vars[name] = append(p.vars[name], v)
+	v.Set(p.level(name))
+}
+
+// Unregister stops setting v to the level of name, undoing a Register. v keeps its current level.
+func (p *Poller) Unregister(name string, v *logstox.LevelVar) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	vs := p.vars[name]
+	i := slices.Index(vs, v)
+	if i < 0 {
+		return
+	}
+	if vs = slices.Delete(vs, i, i+1); len(vs) == 0 {
+		delete(p.vars, name)
+	} else {
+		p.vars[name] = vs
+	}
+}
+
+// Level returns the level of name under the current document, implementing logstox.LevelProvider.
+func (p *Poller) Level(name string) logstox.Level {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.level(name)
+}
+
+// level returns the level of name, p.mu held.
+func (p *Poller) level(name string) logstox.Level {
+	if lvl, ok := p.doc.Level(name); ok {
+		return lvl
+	}
+	return p.o.Default
+}
+
+// OnLevelChange registers fn to be called after every poll changing the document, implementing
+// logstox.LevelNotifier.
+func (p *Poller) OnLevelChange(fn func()) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.listeners = append(p.listeners, fn)
+}
+
+// Poll fetches and applies the document once. On error, which is also handed to Options.ErrorHandler unless ctx is
+// done, the last known good document stays in effect.
+func (p *Poller) Poll(ctx context.Context) error {
+	fctx, cancel := context.WithTimeout(ctx, p.o.Timeout)
+	defer cancel()
+	data, err := p.f.Fetch(fctx)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		err = fmt.Errorf("fetching level document: %w", err)
+		p.o.ErrorHandler(err)
+		return err
+	}
+	doc, err := ParseDocument(data)
+	if err != nil {
+		p.o.ErrorHandler(err)
+		return err
+	}
+
+	p.mu.Lock()
+	if maps.Equal(doc, p.doc) {
+		p.mu.Unlock()
+		return nil
+	}
+	p.doc = doc
+	for name, vs := range p.vars {
+		lvl := p.level(name)
+		for _, v := range vs {
+			v.Set(lvl)
+		}
+	}
+	listeners := p.listeners
+	p.mu.Unlock()
+
+	// outside the lock, listeners may call back into Level
+	for _, fn := range listeners {
+		fn()
+	}
+	return nil
+}
+
+// Run polls right away, then every jittered Options.Interval until ctx is done, returning ctx's error.
+func (p *Poller) Run(ctx context.Context) error {
+	for {
+		_ = p.Poll(ctx) // handed to the ErrorHandler
+		t := time.NewTimer(p.next())
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// next returns the interval until the next poll, randomized by up to Options.Jitter either way.
+func (p *Poller) next() time.Duration {
+	f := 1 + p.o.Jitter*(2*rand.Float64()-1)
+	return time.Duration(float64(p.o.Interval) * f)
+}
+
+// Interface satisfaction (compile-time assertions).
+var (
+	_ logstox.LevelProvider = (*Poller)(nil)
+	_ logstox.LevelNotifier = (*Poller)(nil)
+)
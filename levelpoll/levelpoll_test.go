@@ -0,0 +1,49 @@
+package levelpoll
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/khinshankhan/logstox"
+)
+
+func TestNewJitter(t *testing.T) {
+	tests := []struct {
+		name   string
+		jitter float64
+		want   float64
+	}{
+		{"zero defaults", 0, DefaultJitter},
+		{"negative disables", -1, 0},
+		{"kept", 0.5, 0.5},
+		{"capped below 1", 3, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := New(nil, Options{Jitter: tt.jitter})
+			if got := p.o.Jitter; got > tt.want || got < tt.want-1e-9 || got >= 1 {
+				t.Errorf("Jitter = %v, want %v within [0,1)", got, tt.want)
+			}
+			if tt.jitter < 0 && p.next() != DefaultInterval {
+				t.Errorf("next = %v, want %v without jitter", p.next(), DefaultInterval)
+			}
+		})
+	}
+}
+
+func TestUnregister(t *testing.T) {
+	doc := `{"": "warn"}`
+	p := New(FetcherFunc(func(context.Context) ([]byte, error) { return []byte(doc), nil }), Options{Timeout: time.Second})
+	kept, dropped := new(logstox.LevelVar), new(logstox.LevelVar)
+	p.Register("db", kept)
+	p.Register("db", dropped)
+	p.Unregister("db", dropped)
+
+	if err := p.Poll(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if kept.Level() != logstox.WarnLevel || dropped.Level() != logstox.InfoLevel {
+		t.Errorf("kept at %v, dropped at %v; want warn and the untouched info", kept.Level(), dropped.Level())
+	}
+}
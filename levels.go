@@ -0,0 +1,137 @@
+package logstox
+
+import (
+	"runtime"
+	"sync"
+	"weak"
+)
+
+// LevelProvider decides the minimum level of loggers by name, eg from a feature-flag system or a config service
+// rather than static Options. Names are full logger names, segments joined by periods ("" for the root logger); how
+// they're matched (exactly, by prefix, ...) is up to the provider. See WithLevels.
+type LevelProvider interface {
+	Level(name string) Level
+}
+
+// LevelProviderFunc adapts a function to a LevelProvider.
+type LevelProviderFunc func(name string) Level
+
+// Level calls f.
+func (f LevelProviderFunc) Level(name string) Level {
+	return f(name)
+}
+
+// LevelNotifier is an optional extension for LevelProviders that know when their levels change, eg through a flag
+// SDK's update callback. NewLevels registers with it to refresh its cached levels.
+type LevelNotifier interface {
+	OnLevelChange(func())
+}
+
+// Levels caches a LevelProvider's levels per logger name, so the provider is consulted once per name rather than on
+// every entry. Cached levels are held in LevelVars: Refresh consults the provider again and updates them, which
+// changes the level of every logger using them at once. A name is dropped from the cache once no logger uses its
+// LevelVar anymore, so names derived from requests or tenants don't pile up. The provider is never called with the
+// cache locked, so it may take its time. Levels is safe for concurrent use.
+type Levels struct {
+	p LevelProvider
+
+	mu   sync.Mutex
+	vars map[string]weak.Pointer[levelsEntry]
+}
+
+// levelsEntry holds a cached LevelVar. Its name makes it an allocation of its own, rather than one of the tiny
+// pointer-free ones the runtime batches together and only frees all at once.
+type levelsEntry struct {
+	v    LevelVar
+	name string
+}
+
+// NewLevels returns a Levels caching p's levels, refreshed whenever p notifies a change if it's a LevelNotifier.
+func NewLevels(p LevelProvider) *Levels {
+	ls := &Levels{p: p, vars: make(map[string]weak.Pointer[levelsEntry])}
+	if n, ok := p.(LevelNotifier); ok {
+		n.OnLevelChange(ls.Refresh)
+	}
+	return ls
+}
+
+// Var returns the LevelVar holding the level for name, consulting the provider if name isn't cached.
+func (ls *Levels) Var(name string) *LevelVar {
+	if v := ls.cached(name); v != nil {
+		return v
+	}
+	lvl := ls.p.Level(name)
+
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	if e := ls.vars[name].Value(); e != nil {
+		return &e.v // cached by another goroutine meanwhile
+	}
+	e := &levelsEntry{name: name}
+	e.v.Set(lvl)
+	wp := weak.Make(e)
+	ls.vars[name] = wp
+	runtime.AddCleanup(e, ls.drop, levelsKey{name, wp})
+	return &e.v
+}
+
+// Refresh consults the provider again for every cached name, updating their levels.
+func (ls *Levels) Refresh() {
+	ls.mu.Lock()
+	names := make([]string, 0, len(ls.vars))
+	for name := range ls.vars {
+		names = append(names, name)
+	}
+	ls.mu.Unlock()
+
+	for _, name := range names {
+		lvl := ls.p.Level(name)
+		if v := ls.cached(name); v != nil {
+			v.Set(lvl)
+		}
+	}
+}
+
+// cached returns the LevelVar cached for name, or nil.
+func (ls *Levels) cached(name string) *LevelVar {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	if e := ls.vars[name].Value(); e != nil {
+		return &e.v
+	}
+	return nil
+}
+
+// levelsKey identifies a cached LevelVar for drop.
+type levelsKey struct {
+	name string
+	wp   weak.Pointer[levelsEntry]
+}
+
+// drop removes the cached LevelVar k once it's unreachable, unless name was cached again since.
+func (ls *Levels) drop(k levelsKey) {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	if ls.vars[k.name] == k.wp {
+		delete(ls.vars, k.name)
+	}
+}
+
+// WithLevels returns a logger whose minimum level is the one ls holds for its name, as are those of the children
+// Named derives from it, so levels can be tuned per subsystem at runtime:
+//
+//	levels := logstox.NewLevels(logstox.LevelProviderFunc(func(name string) logstox.Level {
+//		if strings.HasPrefix(name, "db") {
+//			return flags.Level("log-level-db")
+//		}
+//		return logstox.InfoLevel
+//	}))
+//	log = logstox.WithLevels(log, levels)
+//	db := log.Named("db") // at the level the provider gives "db"
+//
+// Like WithLevel, it can't record entries the backend itself is configured to drop, and Panic and Fatal keep their
+// semantics even if the entry is filtered. A later WithLevel replaces the provided level with a fixed one.
+func WithLevels[FT any](l Logger[FT], ls *Levels) Logger[FT] {
+	// skip the filter's own level method so file:line points at the caller
+	return leveled[FT]{base: WithOptions(l, AddCallerSkip(1)), levels: ls, v: ls.Var(NameOf(l))}
+}
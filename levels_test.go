@@ -0,0 +1,53 @@
+package logstox
+
+import (
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestLevelsDropsUnusedNames(t *testing.T) {
+	ls := NewLevels(LevelProviderFunc(func(string) Level { return WarnLevel }))
+	kept := ls.Var("kept")
+	for range 100 {
+		ls.Var("request") // unreferenced right away
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for n := ls.len(); n != 1; n = ls.len() {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d names cached, want only the one in use", n)
+		}
+		runtime.GC()
+		time.Sleep(time.Millisecond)
+	}
+	if v := ls.Var("kept"); v != kept || v.Level() != WarnLevel {
+		t.Errorf("Var(kept) = %p at %v, want the cached %p at warn", v, v.Level(), kept)
+	}
+}
+
+func TestLevelsProviderRunsUnlocked(t *testing.T) {
+	var ls *Levels
+	ls = NewLevels(LevelProviderFunc(func(name string) Level {
+		if name == "child" {
+			return ls.Var("parent").Level() // would deadlock if the provider ran with the cache locked
+		}
+		return ErrorLevel
+	}))
+	v := ls.Var("child")
+	if v.Level() != ErrorLevel {
+		t.Errorf("child at %v, want error", v.Level())
+	}
+	ls.Refresh()
+	if v.Level() != ErrorLevel {
+		t.Errorf("child at %v after Refresh, want error", v.Level())
+	}
+	runtime.KeepAlive(v)
+}
+
+// len returns how many names ls caches.
+func (ls *Levels) len() int {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	return len(ls.vars)
+}
@@ -0,0 +1,66 @@
+package logstox
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// LevelVar is a Level that can be changed at runtime, like slog.LevelVar. Loggers built with it in Options.LevelVar
+// check it on every entry, so one Set changes the level of the root logger and every child derived from it, including
+// children created before the change:
+//
+//	lv := new(logstox.LevelVar)
+//	log := backend.New(logstox.Options[F]{LevelVar: lv})
+//	db := log.Named("db")
+//	...
+//	lv.Set(logstox.DebugLevel) // log and db now record debug entries
+//
+// The zero value is InfoLevel. It's safe for concurrent use.
+type LevelVar struct {
+	v atomic.Int32
+}
+
+// Level returns the current level.
+func (v *LevelVar) Level() Level {
+	return Level(v.v.Load())
+}
+
+// Set sets the level.
+func (v *LevelVar) Set(l Level) {
+	v.v.Store(int32(l))
+}
+
+// String implements fmt.Stringer.
+func (v *LevelVar) String() string {
+	return fmt.Sprintf("LevelVar(%s)", v.Level())
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (v *LevelVar) MarshalText() ([]byte, error) {
+	return v.Level().MarshalText()
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (v *LevelVar) UnmarshalText(text []byte) error {
+	var l Level
+	if err := l.UnmarshalText(text); err != nil {
+		return err
+	}
+	v.Set(l)
+	return nil
+}
+
+// LevelVarHolder is an optional extension for loggers that can report the LevelVar they were built with.
+type LevelVarHolder interface {
+	LevelVar() *LevelVar
+}
+
+// LevelVarOf returns the LevelVar l was built with (see Options.LevelVar), so the level of a logger family can be
+// changed from anywhere one of its loggers is at hand. It returns nil if l wasn't built with one or doesn't implement
+// LevelVarHolder.
+func LevelVarOf[FT any](l Logger[FT]) *LevelVar {
+	if h, ok := l.(LevelVarHolder); ok {
+		return h.LevelVar()
+	}
+	return nil
+}
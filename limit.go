@@ -0,0 +1,146 @@
+package logstox
+
+import (
+	"slices"
+	"unicode/utf8"
+
+	"github.com/khinshankhan/logstox/fields"
+)
+
+// Keys of the fields added to the entries Limits cut down.
+const (
+	TruncatedKey     = "truncated"      // true, if a message or value was truncated
+	OverflowCountKey = "overflow_count" // how many fields were dropped past Limits.MaxFields
+)
+
+// Limits bound the size of entries, so an accidental megabyte payload or a runaway loop adding fields can't clog the
+// pipeline, see Options.Limits. Zero values don't limit.
+//
+// Backends bound the message and the fields of each entry: those given at the call site and those
+// Options.ContextExtractors derive for it. Context fields added via With or Options.Fields are neither bounded nor
+// counted, as backends like zap encode them once, when they're added.
+type Limits struct {
+	// MaxMessageBytes bounds the message.
+	MaxMessageBytes int
+	// MaxFieldBytes bounds string values, including those of string slices and inside Dicts, and the bytes of RawJSON
+	// and Hex fields. RawJSON values over it become strings, as cutting them would leave invalid JSON.
+	MaxFieldBytes int
+	// MaxFields bounds the number of fields, the first ones being kept. It applies to the fields inside each Dict too,
+	// a Dict counting for one field of its parent.
+	MaxFields int
+}
+
+// Enabled reports whether l limits anything.
+func (l Limits) Enabled() bool {
+	return l.MaxMessageBytes > 0 || l.MaxFieldBytes > 0 || l.MaxFields > 0
+}
+
+// Apply returns msg and fs cut down to l, with TruncatedKey added if anything was truncated and OverflowCountKey if
+// fields were dropped, unless a field of fs already has the key, so the entry never holds it twice. Strings are cut
+// on a UTF-8 boundary.
+//
+// fs should be resolved (see fields.Resolve): lazy fields and precomputed bundles count for one field and their
+// values aren't checked, and neither are Any and Error values. fs is copied rather than modified.
+func (l Limits) Apply(msg string, fs []fields.Field) (string, []fields.Field) {
+	msg, truncated := l.Message(msg)
+	fs, cut, overflow := l.Fields(fs)
+	if !truncated && !cut && overflow == 0 {
+		return msg, fs
+	}
+	fs = slices.Clip(fs)
+	if (truncated || cut) && !hasKey(fs, TruncatedKey) {
+		fs = append(fs, fields.Bool(TruncatedKey, true))
+	}
+	if overflow > 0 && !hasKey(fs, OverflowCountKey) {
+		fs = append(fs, fields.Int(OverflowCountKey, overflow))
+	}
+	return msg, fs
+}
+
+// Message returns msg cut to MaxMessageBytes, reporting whether it was.
+func (l Limits) Message(msg string) (string, bool) {
+	if l.MaxMessageBytes > 0 && len(msg) > l.MaxMessageBytes {
+		return Truncate(msg, l.MaxMessageBytes), true
+	}
+	return msg, false
+}
+
+// Fields returns fs cut down to MaxFields and MaxFieldBytes, reporting whether any value was truncated and how many
+// fields were dropped, Dicts included, for backends adding the markers themselves. fs is copied rather than modified,
+// as Dicts may share it.
+func (l Limits) Fields(fs []fields.Field) (out []fields.Field, truncated bool, overflow int) {
+	if l.MaxFields > 0 && len(fs) > l.MaxFields {
+		overflow = len(fs) - l.MaxFields
+		fs = fs[:l.MaxFields:l.MaxFields]
+	}
+	if l.MaxFieldBytes <= 0 && l.MaxFields <= 0 {
+		return fs, false, overflow
+	}
+	for i, f := range fs {
+		g, cut, dropped := l.field(f)
+		if !cut && dropped == 0 {
+			continue
+		}
+		if out == nil {
+			out = slices.Clone(fs)
+		}
+		out[i] = g
+		truncated = truncated || cut
+		overflow += dropped
+	}
+	if out == nil {
+		return fs, false, overflow
+	}
+	return out, truncated, overflow
+}
+
+// field returns f cut down to l, reporting whether its value was truncated and how many fields were dropped from it.
+func (l Limits) field(f fields.Field) (fields.Field, bool, int) {
+	limit := l.MaxFieldBytes
+	switch f.Kind() {
+	case fields.FieldKindDict:
+		if sub, truncated, overflow := l.Fields(f.Fields()); truncated || overflow > 0 {
+			return fields.Dict(f.Key, sub...), truncated, overflow
+		}
+	case fields.FieldKindString:
+		if limit > 0 && len(f.Str()) > limit {
+			return fields.String(f.Key, Truncate(f.Str(), limit)), true, 0
+		}
+	case fields.FieldKindStrings:
+		ss, _ := f.Interface().([]string)
+		if limit <= 0 || !slices.ContainsFunc(ss, func(s string) bool { return len(s) > limit }) {
+			break
+		}
+		out := make([]string, len(ss))
+		for i, s := range ss {
+			out[i] = Truncate(s, limit)
+		}
+		return fields.Strings(f.Key, out), true, 0
+	case fields.FieldKindRawJSON:
+		if b := f.Bytes(); limit > 0 && len(b) > limit {
+			return fields.String(f.Key, Truncate(string(b), limit)), true, 0
+		}
+	case fields.FieldKindHexBytes:
+		if b := f.Bytes(); limit > 0 && len(b) > limit {
+			return fields.Hex(f.Key, b[:limit:limit]), true, 0
+		}
+	}
+	return f, false, 0
+}
+
+// Truncate returns s cut to at most limit bytes on a UTF-8 boundary.
+func Truncate(s string, limit int) string {
+	if len(s) <= limit {
+		return s
+	}
+	n := limit
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
+// hasKey reports whether a field of fs has the key k.
+func hasKey(fs []fields.Field, k string) bool {
+	return slices.ContainsFunc(fs, func(f fields.Field) bool { return f.Key == k })
+}
@@ -0,0 +1,87 @@
+package logstox
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/khinshankhan/logstox/fields"
+)
+
+func TestLimitsApply(t *testing.T) {
+	tests := []struct {
+		name     string
+		limits   Limits
+		msg      string
+		fields   []fields.Field
+		wantMsg  string
+		wantKeys []string
+	}{
+		{
+			name:     "within limits",
+			limits:   Limits{MaxMessageBytes: 8, MaxFieldBytes: 8, MaxFields: 2},
+			msg:      "short",
+			fields:   []fields.Field{fields.String("a", "x"), fields.Int("b", 1)},
+			wantMsg:  "short",
+			wantKeys: []string{"a", "b"},
+		},
+		{
+			name:     "message cut on a rune boundary",
+			limits:   Limits{MaxMessageBytes: 4},
+			msg:      "héllo",
+			wantMsg:  "hél",
+			wantKeys: []string{TruncatedKey},
+		},
+		{
+			name:     "fields dropped",
+			limits:   Limits{MaxFields: 1},
+			fields:   []fields.Field{fields.String("a", "x"), fields.Int("b", 1), fields.Int("c", 2)},
+			wantKeys: []string{"a", OverflowCountKey},
+		},
+		{
+			name:     "dict capped",
+			limits:   Limits{MaxFields: 2},
+			fields:   []fields.Field{fields.Dict("d", fields.Int("a", 1), fields.Int("b", 2), fields.Int("c", 3))},
+			wantKeys: []string{"d", OverflowCountKey},
+		},
+		{
+			name:     "user key kept",
+			limits:   Limits{MaxFieldBytes: 1},
+			fields:   []fields.Field{fields.String(TruncatedKey, "mine"), fields.String("b", "long")},
+			wantKeys: []string{TruncatedKey, "b"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, fs := tt.limits.Apply(tt.msg, tt.fields)
+			if msg != tt.wantMsg {
+				t.Errorf("message = %q, want %q", msg, tt.wantMsg)
+			}
+			var keys []string
+			for _, f := range fs {
+				keys = append(keys, f.Key)
+			}
+			if !slices.Equal(keys, tt.wantKeys) {
+				t.Errorf("keys = %v, want %v", keys, tt.wantKeys)
+			}
+		})
+	}
+}
+
+func TestLimitsFields(t *testing.T) {
+	in := []fields.Field{
+		fields.String("s", "abcdef"),
+		fields.Strings("ss", []string{"ab", "abcdef"}),
+		fields.RawJSON("j", []byte(`{"a":"bcdef"}`)),
+		fields.Dict("d", fields.String("s", "abcdef"), fields.Int("n", 1), fields.Int("m", 2)),
+	}
+	out, truncated, overflow := Limits{MaxFieldBytes: 3, MaxFields: 2}.Fields(in)
+	if !truncated || overflow != 2 {
+		t.Fatalf("truncated, overflow = %v, %d, want true, 2", truncated, overflow)
+	}
+	if len(out) != 2 || out[0].Str() != "abc" || !slices.Equal(out[1].Interface().([]string), []string{"ab", "abc"}) {
+		t.Errorf("got %v", out)
+	}
+	if in[0].Str() != "abcdef" {
+		t.Error("input fields were modified")
+	}
+}
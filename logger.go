@@ -1,7 +1,9 @@
 package logstox
 
 import (
+	"context"
 	"io"
+	"os"
 )
 
 // Logger is the small, portable logging interface parameterized by the field type FT.
@@ -36,11 +38,25 @@ type Logger[FT any] interface {
 }
 
 // LevelCheck is an optional extension that reports if a level is enabled.
-// Backends that can answer cheaply may implement this.
+// Backends that can answer cheaply may implement this; all first-party loggers do.
 type LevelCheck interface {
 	Enabled(Level) bool
 }
 
+// Enabled reports whether l records lvl, so expensive field preparation can be skipped:
+//
+//	if logstox.Enabled(log, logstox.DebugLevel) {
+//		log.Debug("state", fields.Any("dump", expensiveDump()))
+//	}
+//
+// Loggers that don't implement LevelCheck are assumed to record everything.
+func Enabled[FT any](l Logger[FT], lvl Level) bool {
+	if lc, ok := l.(LevelCheck); ok {
+		return lc.Enabled(lvl)
+	}
+	return true
+}
+
 // Options are hints used by a Backend when constructing a Logger.
 // Backends may choose to ignore some fields.
 type Options[FT any] struct {
@@ -50,10 +66,69 @@ type Options[FT any] struct {
 	Writer     io.Writer // preferred sink (backend may ignore)
 	TimeLayout string    // eg time.RFC3339Nano (backend may ignore)
 	Fields     []FT      // default fields for the base logger
+	// CallerSkip is the number of extra stack frames between the call site and the Logger, so file:line points at
+	// the real call site when logstox is wrapped by another facade. Backends add it to whatever they need to skip
+	// their own frames.
+	CallerSkip int
+	// AddStacktrace attaches stack traces to entries at or above StacktraceLevel (default: disabled).
+	AddStacktrace   bool
+	StacktraceLevel Level
+	// Writers are additional sinks, each with its own level filter (backend may ignore), eg stdout below Error,
+	// stderr for Error and above, and a file for everything. Options.Level still applies to all of them.
+	Writers []LevelWriter
+	// Development turns on development mode, consistently across first-party backends: DPanic panics after logging,
+	// like Panic (otherwise it logs like Error), output defaults to a console-friendly encoding, and field keys are
+	// validated strictly (see ValidateKeys).
+	Development bool
+	// LevelVar, if set, replaces Level with a level that can be changed at runtime for the logger and all its
+	// children. Loggers built with it report it via LevelVarOf.
+	LevelVar *LevelVar
+	// LevelFormat writes levels as syslog or OTel severity numbers instead of names (backend may ignore).
+	LevelFormat LevelFormat
+	// SplitStdStreams adds StdStreams to Writers: Warn and below go to stdout, Error and above to stderr, as many
+	// container platforms expect. Set Writer too to also write everything there.
+	SplitStdStreams bool
+	// Multiline selects how messages with line breaks are written: as they are (default), escaped so entries stay on
+	// one line, or folded into a one-line message and a DetailKey field (backend may ignore).
+	Multiline Multiline
+	// Limits bound the size of entries, enforced by the backend after its level check, once lazy fields are
+	// evaluated; context fields added via With or Fields aren't bounded (backend may ignore).
+	Limits Limits
+	// ContextExtractors derive fields from the context a logger is bound to with WithContext, eg trace IDs, the
+	// authenticated subject or feature flags, so they flow into every entry without per-call boilerplate. They run in
+	// the backend, so the middleware of WithMiddleware never see their fields, redaction and schema checks included;
+	// extract them with middleware.EnrichContext instead for those to apply.
+	ContextExtractors []func(context.Context) []FT
+}
+
+// WithCallerSkip returns a copy of o skipping n more frames, for wrapper libraries that add their own layer(s)
+// between the call site and the Logger:
+//
+//	func NewWrapper(b logstox.Backend[F], o logstox.Options[F]) *Wrapper {
+//		return &Wrapper{l: b.New(o.WithCallerSkip(1))}
+//	}
+func (o Options[FT]) WithCallerSkip(n int) Options[FT] {
+	o.CallerSkip += n
+	return o
+}
+
+// LevelWriter is a sink that only receives entries at levels Enabled reports true for.
+type LevelWriter struct {
+	Writer  io.Writer
+	Enabled func(Level) bool // nil receives every level
+}
+
+// StdStreams returns level writers sending Warn and below to stdout and Error and above to stderr, see
+// Options.SplitStdStreams.
+func StdStreams() []LevelWriter {
+	return []LevelWriter{
+		{Writer: os.Stdout, Enabled: Below(ErrorLevel)},
+		{Writer: os.Stderr, Enabled: AtLeast(ErrorLevel)},
+	}
 }
 
 // Backend builds a Logger from Options all parameterized by the field type FT.
-// Backends live in subpackages (eg backend/zapx, backend/slogx).
+// Backends live in subpackages (eg backend/zapx, backend/streamx).
 // Or consumers roll out their custom backend.
 type Backend[FT any] interface {
 	New(Options[FT]) Logger[FT]
@@ -0,0 +1,22 @@
+package logstoxtest
+
+import (
+	"testing"
+
+	"github.com/khinshankhan/logstox"
+	"github.com/khinshankhan/logstox/fields"
+)
+
+// AssertFields fails t if the fields of e don't exactly match want, reporting the missing, extra and mismatched ones
+// (see fields.Diff):
+//
+//	log, logs := logstoxtest.Observe()
+//	svc.Handle(log, req)
+//	logstoxtest.AssertFields(t, logs.FilterMessage("request handled").All()[0],
+//		fields.String("method", "GET"), fields.Int("status", 200))
+func AssertFields(t testing.TB, e logstox.Entry, want ...fields.Field) {
+	t.Helper()
+	if d := fields.Diff(want, e.Fields); d != "" {
+		t.Errorf("logstoxtest: fields of %q don't match:\n%s", e.Message, d)
+	}
+}
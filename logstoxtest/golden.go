@@ -0,0 +1,174 @@
+package logstoxtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/khinshankhan/logstox/parse"
+)
+
+// UpdateEnv is the environment variable making Golden write golden files with the output at hand instead of
+// comparing against them, when it's set to a non-empty value: LOGSTOX_UPDATE_GOLDEN=1 go test ./...
+const UpdateEnv = "LOGSTOX_UPDATE_GOLDEN"
+
+// Scrubbed replaces the values removed by normalizers.
+const Scrubbed = "<scrubbed>"
+
+// Normalizer rewrites a decoded NDJSON entry in place before it's compared, to remove volatile values.
+type Normalizer func(entry map[string]any)
+
+// DefaultNormalizers scrub the values that change between runs with the first-party backends' default keys:
+// timestamps, callers, stacks and durations.
+var DefaultNormalizers = []Normalizer{
+	Scrub("ts", "time", "caller", "source", "stacktrace"),
+	ScrubSuffix("duration", "elapsed", "latency", "_ms", "_ns"),
+}
+
+// Scrub replaces the values at keys, which may be dotted paths into nested objects (eg "http.duration"). A dotted
+// path also matches keys that have dots themselves, at any level: "http.request.method" matches the flat key
+// "http.request.method" as well as "method" nested in "request" nested in "http". Missing keys are left alone.
+func Scrub(keys ...string) Normalizer {
+	return func(entry map[string]any) {
+		for _, k := range keys {
+			scrubPath(entry, k)
+		}
+	}
+}
+
+// scrubPath scrubs the values at path in m, trying every split of path into nested keys.
+func scrubPath(m map[string]any, path string) {
+	if _, ok := m[path]; ok {
+		m[path] = Scrubbed
+	}
+	for i := range len(path) {
+		if path[i] != '.' {
+			continue
+		}
+		if next, ok := m[path[:i]].(map[string]any); ok {
+			scrubPath(next, path[i+1:])
+		}
+	}
+}
+
+// ScrubSuffix replaces the values of keys ending in any of suffixes, at any depth.
+func ScrubSuffix(suffixes ...string) Normalizer {
+	var walk func(map[string]any)
+	walk = func(m map[string]any) {
+		for k, v := range m {
+			if nested, ok := v.(map[string]any); ok {
+				walk(nested)
+				continue
+			}
+			for _, s := range suffixes {
+				if strings.HasSuffix(k, s) {
+					m[k] = Scrubbed
+					break
+				}
+			}
+		}
+	}
+	return walk
+}
+
+// Golden compares NDJSON log output against the golden file at path, failing t on mismatch:
+//
+//	var buf bytes.Buffer
+//	log := zapx.Adapt(zapx.Backend{}.New(logstox.Options[zapx.ZapField]{Writer: &buf}))
+//	svc.Run(log)
+//	logstoxtest.Golden(t, "testdata/run.golden", buf.Bytes())
+//
+// Every line is decoded, passed through ns (DefaultNormalizers if none are given) and re-encoded with sorted keys
+// before comparing, so key order doesn't matter. A mismatch is reported with the first differing line, named after
+// the entry parse.Line reads from it. Run the tests with UpdateEnv set to write the normalized output to path
+// instead.
+func Golden(t testing.TB, path string, output []byte, ns ...Normalizer) {
+	t.Helper()
+
+	if len(ns) == 0 {
+		ns = DefaultNormalizers
+	}
+	got, err := normalize(output, ns)
+	if err != nil {
+		t.Fatalf("logstoxtest: normalizing output: %v", err)
+	}
+
+	if os.Getenv(UpdateEnv) != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("logstoxtest: %v", err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatalf("logstoxtest: %v", err)
+		}
+		t.Logf("logstoxtest: updated %s", path)
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("logstoxtest: %v (run with %s=1 to create it)", err, UpdateEnv)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("logstoxtest: output doesn't match %s%s", path, firstDiff(got, want))
+	}
+}
+
+// normalize decodes every NDJSON line of output, applies ns and re-encodes it.
+func normalize(output []byte, ns []Normalizer) ([]byte, error) {
+	var out bytes.Buffer
+	enc := json.NewEncoder(&out) // maps encode with sorted keys
+	enc.SetEscapeHTML(false)
+	for _, line := range bytes.Split(output, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		var entry map[string]any
+		dec := json.NewDecoder(bytes.NewReader(line))
+		dec.UseNumber() // keep numbers as written
+		if err := dec.Decode(&entry); err != nil {
+			return nil, err
+		}
+		for _, n := range ns {
+			n(entry)
+		}
+		if err := enc.Encode(entry); err != nil {
+			return nil, err
+		}
+	}
+	return out.Bytes(), nil
+}
+
+// firstDiff describes the first line where got and want differ.
+func firstDiff(got, want []byte) string {
+	gl := strings.Split(string(got), "\n")
+	wl := strings.Split(string(want), "\n")
+	for i := 0; i < max(len(gl), len(wl)); i++ {
+		var g, w string
+		if i < len(gl) {
+			g = gl[i]
+		}
+		if i < len(wl) {
+			w = wl[i]
+		}
+		if g != w {
+			return fmt.Sprintf("\nline %d%s:\n got: %s\nwant: %s", i+1, describe(g, w), g, w)
+		}
+	}
+	return ""
+}
+
+// describe names the entry of the golden line want, or else got, eg ` (info "request handled")`, or returns "" if
+// neither parses.
+func describe(got, want string) string {
+	for _, line := range [...]string{want, got} {
+		if e, err := parse.Line([]byte(line)); err == nil {
+			return fmt.Sprintf(" (%s %q)", e.Level, e.Message)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,84 @@
+package logstoxtest
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestScrub(t *testing.T) {
+	tests := []struct {
+		name  string
+		key   string
+		entry map[string]any
+		want  map[string]any
+	}{
+		{
+			name:  "top level",
+			key:   "ts",
+			entry: map[string]any{"ts": 1, "msg": "m"},
+			want:  map[string]any{"ts": Scrubbed, "msg": "m"},
+		},
+		{
+			name:  "nested",
+			key:   "http.duration",
+			entry: map[string]any{"http": map[string]any{"duration": 1, "status": 200}},
+			want:  map[string]any{"http": map[string]any{"duration": Scrubbed, "status": 200}},
+		},
+		{
+			name:  "flat dotted key",
+			key:   "http.request.method",
+			entry: map[string]any{"http.request.method": "GET"},
+			want:  map[string]any{"http.request.method": Scrubbed},
+		},
+		{
+			name:  "partly flat",
+			key:   "http.request.method",
+			entry: map[string]any{"http": map[string]any{"request.method": "GET"}},
+			want:  map[string]any{"http": map[string]any{"request.method": Scrubbed}},
+		},
+		{
+			name:  "missing",
+			key:   "http.missing",
+			entry: map[string]any{"http": "flat"},
+			want:  map[string]any{"http": "flat"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			Scrub(tt.key)(tt.entry)
+			if !reflect.DeepEqual(tt.entry, tt.want) {
+				t.Errorf("got %v, want %v", tt.entry, tt.want)
+			}
+		})
+	}
+}
+
+func TestGoldenUpdate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.golden")
+	output := []byte(`{"msg":"m","ts":"2024-01-01T00:00:00Z","level":"info"}` + "\n")
+
+	t.Setenv(UpdateEnv, "1")
+	Golden(t, path, output)
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"level":"info","msg":"m","ts":"<scrubbed>"}` + "\n"; string(got) != want {
+		t.Errorf("golden file = %s, want %s", got, want)
+	}
+
+	t.Setenv(UpdateEnv, "")
+	Golden(t, path, output)
+}
+
+func TestFirstDiffNamesTheEntry(t *testing.T) {
+	got := []byte(`{"level":"info","msg":"a"}` + "\n" + `{"level":"error","msg":"b","n":1}` + "\n")
+	want := []byte(`{"level":"info","msg":"a"}` + "\n" + `{"level":"error","msg":"b","n":2}` + "\n")
+	d := firstDiff(got, want)
+	if !strings.HasPrefix(d, "\nline 2 (error \"b\"):") {
+		t.Errorf("firstDiff = %q, want it to start with the line and entry", d)
+	}
+}
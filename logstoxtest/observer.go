@@ -0,0 +1,242 @@
+package logstoxtest
+
+import (
+	"bytes"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/khinshankhan/logstox"
+	"github.com/khinshankhan/logstox/fields"
+	"github.com/khinshankhan/logstox/parse"
+)
+
+// Observe returns a Logger recording every entry, at every level, and the ObservedLogs to query them with:
+//
+//	log, logs := logstoxtest.Observe()
+//	svc := NewService(log)
+//	svc.Handle(req)
+//	if logs.FilterMessage("request handled").Len() != 1 { ... }
+//
+// Entries hold the logger's name and its context fields followed by the call-site fields. Panic panics after
+// recording like any logger, while Fatal records and then ends the calling goroutine via runtime.Goexit instead of
+// exiting the test binary.
+func Observe() (logstox.Logger[fields.Field], *ObservedLogs) {
+	logs := &ObservedLogs{}
+	return observer{logs: logs}, logs
+}
+
+// ParseOutput parses the NDJSON output of a real backend (see parse.All) into ObservedLogs, failing t on lines that
+// can't be parsed, so assertions read the same for observed and written entries:
+//
+//	var buf bytes.Buffer
+//	log := zapx.Adapt(zapx.Backend{}.New(logstox.Options[zapx.ZapField]{Writer: &buf}))
+//	svc.Run(log)
+//	if logstoxtest.ParseOutput(t, buf.Bytes()).FilterLevelAtLeast(logstox.ErrorLevel).Len() > 0 { ... }
+func ParseOutput(t testing.TB, output []byte) *ObservedLogs {
+	t.Helper()
+	es, err := parse.All(bytes.NewReader(output), true)
+	if err != nil {
+		t.Fatalf("logstoxtest: %v", err)
+	}
+	return &ObservedLogs{entries: es}
+}
+
+// ObservedLogs is a concurrency-safe collection of observed entries.
+type ObservedLogs struct {
+	mu      sync.RWMutex
+	entries []logstox.Entry
+}
+
+// Len returns the number of entries observed so far.
+func (o *ObservedLogs) Len() int {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return len(o.entries)
+}
+
+// All returns a copy of the entries observed so far, oldest first.
+func (o *ObservedLogs) All() []logstox.Entry {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return append([]logstox.Entry(nil), o.entries...)
+}
+
+// AllUntimed returns All with the Time of every entry zeroed, so entries can be compared directly.
+func (o *ObservedLogs) AllUntimed() []logstox.Entry {
+	es := o.All()
+	for i := range es {
+		es[i].Time = time.Time{}
+	}
+	return es
+}
+
+// TakeAll returns the entries observed so far and forgets them.
+func (o *ObservedLogs) TakeAll() []logstox.Entry {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	es := o.entries
+	o.entries = nil
+	return es
+}
+
+// Filter returns a new ObservedLogs holding the entries matching keep. Any middleware.Predicate can be passed.
+func (o *ObservedLogs) Filter(keep func(logstox.Entry) bool) *ObservedLogs {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	var out []logstox.Entry
+	for _, e := range o.entries {
+		if keep(e) {
+			out = append(out, e)
+		}
+	}
+	return &ObservedLogs{entries: out}
+}
+
+// FilterMessage filters entries with the exact message msg.
+func (o *ObservedLogs) FilterMessage(msg string) *ObservedLogs {
+	return o.Filter(func(e logstox.Entry) bool { return e.Message == msg })
+}
+
+// FilterMessageSnippet filters entries whose message contains snippet.
+func (o *ObservedLogs) FilterMessageSnippet(snippet string) *ObservedLogs {
+	return o.Filter(func(e logstox.Entry) bool { return strings.Contains(e.Message, snippet) })
+}
+
+// FilterFieldKey filters entries with a top-level field keyed k.
+func (o *ObservedLogs) FilterFieldKey(k string) *ObservedLogs {
+	return o.Filter(func(e logstox.Entry) bool {
+		for _, f := range e.Fields {
+			if f.Key == k {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// FilterField filters entries with a top-level field equal to f, comparing keys, kinds and values.
+func (o *ObservedLogs) FilterField(f fields.Field) *ObservedLogs {
+	return o.Filter(func(e logstox.Entry) bool {
+		for _, ef := range e.Fields {
+			if ef.Key == f.Key && ef.Kind() == f.Kind() && reflect.DeepEqual(ef.Boxed(), f.Boxed()) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// FilterLevelAtLeast filters entries at lvl or above.
+func (o *ObservedLogs) FilterLevelAtLeast(lvl logstox.Level) *ObservedLogs {
+	return o.Filter(func(e logstox.Entry) bool { return e.Level.Compare(lvl) >= 0 })
+}
+
+// FilterLevelExact filters entries at exactly lvl.
+func (o *ObservedLogs) FilterLevelExact(lvl logstox.Level) *ObservedLogs {
+	return o.Filter(func(e logstox.Entry) bool { return e.Level == lvl })
+}
+
+// FilterLoggerName filters entries from loggers named exactly name.
+func (o *ObservedLogs) FilterLoggerName(name string) *ObservedLogs {
+	return o.Filter(func(e logstox.Entry) bool { return e.Name == name })
+}
+
+// add records e.
+func (o *ObservedLogs) add(e logstox.Entry) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.entries = append(o.entries, e)
+}
+
+// observer is the Logger returned by Observe.
+type observer struct {
+	logs    *ObservedLogs
+	name    string
+	context []fields.Field
+}
+
+// Interface satisfaction (compile-time assertions).
+var (
+	_ logstox.Logger[fields.Field]       = observer{}
+	_ logstox.Noticer[fields.Field]      = observer{}
+	_ logstox.LevelCheck                 = observer{}
+	_ logstox.Introspector[fields.Field] = observer{}
+)
+
+// log records an entry carrying the context fields followed by fs.
+func (l observer) log(lvl logstox.Level, msg string, fs []fields.Field) {
+	all := make([]fields.Field, 0, len(l.context)+len(fs))
+	all = append(all, l.context...)
+	all = append(all, fs...)
+	l.logs.add(logstox.Entry{
+		Time:    time.Now(),
+		Level:   lvl,
+		Name:    l.name,
+		Message: msg,
+		Fields:  all,
+	})
+}
+
+// DEBUG (-1): for recording messages useful for debugging.
+func (l observer) Debug(msg string, fs ...fields.Field) { l.log(logstox.DebugLevel, msg, fs) }
+
+// INFO (0): for messages describing normal application operations.
+func (l observer) Info(msg string, fs ...fields.Field) { l.log(logstox.InfoLevel, msg, fs) }
+
+// Notice logs at logstox.NoticeLevel, see logstox.Noticer.
+func (l observer) Notice(msg string, fs ...fields.Field) { l.log(logstox.NoticeLevel, msg, fs) }
+
+// WARN (1): for recording messages indicating something unusual happened that may need attention before it escalates to a more severe issue.
+func (l observer) Warn(msg string, fs ...fields.Field) { l.log(logstox.WarnLevel, msg, fs) }
+
+// ERROR (2): for recording unexpected error conditions in the program.
+func (l observer) Error(msg string, fs ...fields.Field) { l.log(logstox.ErrorLevel, msg, fs) }
+
+// DPANIC (3): for recording severe error conditions in development. It's only recorded here, never panics.
+func (l observer) DPanic(msg string, fs ...fields.Field) { l.log(logstox.DPanicLevel, msg, fs) }
+
+// PANIC (4): calls panic() after logging an error condition.
+func (l observer) Panic(msg string, fs ...fields.Field) {
+	l.log(logstox.PanicLevel, msg, fs)
+	panic(msg)
+}
+
+// FATAL (5): records the entry and ends the calling goroutine, rather than the process.
+func (l observer) Fatal(msg string, fs ...fields.Field) {
+	l.log(logstox.FatalLevel, msg, fs)
+	runtime.Goexit()
+}
+
+// With returns a child observer carrying fs as context.
+func (l observer) With(fs ...fields.Field) logstox.Logger[fields.Field] {
+	child := l
+	child.context = append(l.context[:len(l.context):len(l.context)], fs...)
+	return child
+}
+
+// Named adds a new path segment to the logger's name.
+func (l observer) Named(n string) logstox.Logger[fields.Field] {
+	child := l
+	if l.name == "" {
+		child.name = n
+	} else if n != "" {
+		child.name = l.name + "." + n
+	}
+	return child
+}
+
+// Sync is a no-op.
+func (l observer) Sync() error { return nil }
+
+// Enabled reports true, every level is observed.
+func (l observer) Enabled(logstox.Level) bool { return true }
+
+// Name returns the logger's name.
+func (l observer) Name() string { return l.name }
+
+// Fields returns the context fields added via With.
+func (l observer) Fields() []fields.Field { return l.context }
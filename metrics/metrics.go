@@ -0,0 +1,111 @@
+package metrics
+
+import (
+	"sync"
+
+	"github.com/khinshankhan/logstox"
+	"github.com/khinshankhan/logstox/fields"
+)
+
+// Recorder receives the metric updates carried by fields.Counter and fields.Gauge. Implementations bridge to a
+// metrics library, eg for Prometheus:
+//
+//	type promRecorder struct{ counters *prometheus.CounterVec; gauges *prometheus.GaugeVec }
+//
+//	func (r promRecorder) Add(name string, delta float64) { r.counters.WithLabelValues(name).Add(delta) }
+//	func (r promRecorder) Set(name string, value float64) { r.gauges.WithLabelValues(name).Set(value) }
+//
+// Recorders are called from logging goroutines and must be safe for concurrent use.
+type Recorder interface {
+	// Add adds delta to the counter name.
+	Add(name string, delta float64)
+	// Set sets the gauge name to value.
+	Set(name string, value float64)
+}
+
+// Mode is what Middleware does with metric fields once recorded.
+type Mode uint8
+
+const (
+	// Record keeps metric fields on the entry, logged as plain float fields.
+	Record Mode = iota
+	// Extract removes metric fields from the entry, so they only reach the Recorder.
+	Extract
+)
+
+// Middleware returns a middleware reporting the entry's top-level Counter and Gauge fields to r, so log lines double as
+// metric updates without a second instrumentation pass:
+//
+//	log = logstox.WithMiddleware(log, metrics.Middleware(rec, metrics.Record))
+//	log.Info("order placed", fields.Counter("orders_total", 1), fields.Gauge("cart_items", 3))
+//
+// Only entries that reach it are counted, so put it after any sampling or filtering meant to apply to metrics too.
+func Middleware(r Recorder, mode Mode) logstox.Middleware {
+	return func(e logstox.Entry) (logstox.Entry, bool) {
+		kept := e.Fields[:0]
+		for _, f := range e.Fields {
+			switch f.Metric() {
+			case fields.CounterMetric:
+				r.Add(f.Key, f.Float64())
+			case fields.GaugeMetric:
+				r.Set(f.Key, f.Float64())
+			default:
+				kept = append(kept, f)
+				continue
+			}
+			if mode == Record {
+				kept = append(kept, f)
+			}
+		}
+		e.Fields = kept
+		return e, true
+	}
+}
+
+// Memory is a Recorder keeping counters and gauges in memory, eg for tests or to publish through expvar. The zero
+// value is ready to use.
+type Memory struct {
+	mu       sync.Mutex
+	counters map[string]float64
+	gauges   map[string]float64
+}
+
+// Add implements Recorder.
+func (m *Memory) Add(name string, delta float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.counters == nil {
+		m.counters = make(map[string]float64)
+	}
+	m.counters[name] += delta
+}
+
+// Set implements Recorder.
+func (m *Memory) Set(name string, value float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.gauges == nil {
+		m.gauges = make(map[string]float64)
+	}
+	m.gauges[name] = value
+}
+
+// Counter returns the value of the counter name, 0 if it was never added to.
+func (m *Memory) Counter(name string) float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.counters[name]
+}
+
+// Gauge returns the value of the gauge name and whether it was ever set.
+func (m *Memory) Gauge(name string) (float64, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	v, ok := m.gauges[name]
+	return v, ok
+}
+
+// Interface satisfaction (compile-time assertions).
+var (
+	_ Recorder = (*Memory)(nil)
+)
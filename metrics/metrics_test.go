@@ -0,0 +1,100 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/khinshankhan/logstox"
+	"github.com/khinshankhan/logstox/backend/memx"
+	"github.com/khinshankhan/logstox/fields"
+)
+
+// keys returns the keys of fs, space separated.
+func keys(fs []fields.Field) string {
+	out := make([]string, len(fs))
+	for i, f := range fs {
+		out[i] = f.Key
+	}
+	return strings.Join(out, " ")
+}
+
+func TestMiddleware(t *testing.T) {
+	entry := func() logstox.Entry {
+		return logstox.Entry{Fields: []fields.Field{
+			fields.Counter("orders_total", 1),
+			fields.String("user", "ada"),
+			fields.Gauge("cart_items", 3),
+			fields.Float64("ratio", 0.5),
+			fields.Dict("nested", fields.Counter("ignored_total", 1)),
+			fields.Counter("orders_total", 2),
+			fields.Gauge("cart_items", 0),
+		}}
+	}
+	tests := []struct {
+		mode Mode
+		keys string
+	}{
+		{Record, "orders_total user cart_items ratio nested orders_total cart_items"},
+		{Extract, "user ratio nested"},
+	}
+	for _, tt := range tests {
+		m := &Memory{}
+		e, ok := Middleware(m, tt.mode)(entry())
+		if !ok {
+			t.Fatalf("mode %d: entry dropped", tt.mode)
+		}
+		if got := keys(e.Fields); got != tt.keys {
+			t.Errorf("mode %d: fields %s, want %s", tt.mode, got, tt.keys)
+		}
+		if n := m.Counter("orders_total"); n != 3 {
+			t.Errorf("mode %d: orders_total = %v, want 3", tt.mode, n)
+		}
+		if v, ok := m.Gauge("cart_items"); !ok || v != 0 {
+			t.Errorf("mode %d: cart_items = %v, %v, want the last value, 0", tt.mode, v, ok)
+		}
+		if n := m.Counter("ignored_total"); n != 0 {
+			t.Errorf("mode %d: recorded a nested counter", tt.mode)
+		}
+		if _, ok := m.Gauge("ratio"); ok || m.Counter("ratio") != 0 {
+			t.Errorf("mode %d: recorded a plain float field", tt.mode)
+		}
+	}
+}
+
+func TestMiddlewareLogged(t *testing.T) {
+	rec := memx.NewRecorder(2)
+	m := &Memory{}
+	base := memx.Backend{Recorder: rec}.New(logstox.Options[fields.Field]{})
+	logstox.WithMiddleware(base, Middleware(m, Record)).Info("order placed", fields.Counter("orders_total", 1))
+	logstox.WithMiddleware(base, Middleware(m, Extract)).Info("order placed", fields.Counter("orders_total", 1))
+
+	es := rec.Entries()
+	if f, ok := fields.Fields(es[0].Fields).Get("orders_total"); !ok || f.Float64() != 1 {
+		t.Errorf("recorded entry has orders_total %v, want it logged as 1", f)
+	}
+	if len(es[1].Fields) != 0 {
+		t.Errorf("extracted entry has fields %v, want none", es[1].Fields)
+	}
+	if n := m.Counter("orders_total"); n != 2 {
+		t.Errorf("orders_total = %v, want 2", n)
+	}
+}
+
+func TestMemory(t *testing.T) {
+	var m Memory
+	if n := m.Counter("c"); n != 0 {
+		t.Errorf("unknown counter = %v, want 0", n)
+	}
+	if _, ok := m.Gauge("g"); ok {
+		t.Error("unknown gauge reported set")
+	}
+	m.Add("c", 1.5)
+	m.Add("c", -0.5)
+	m.Set("g", 0)
+	if n := m.Counter("c"); n != 1 {
+		t.Errorf("c = %v, want 1", n)
+	}
+	if v, ok := m.Gauge("g"); !ok || v != 0 {
+		t.Errorf("g = %v, %v, want 0 set", v, ok)
+	}
+}
@@ -0,0 +1,229 @@
+package logstox
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/khinshankhan/logstox/fields"
+)
+
+// Middleware inspects and optionally rewrites an Entry before it's emitted.
+// Returning false drops the entry; nothing after it in a Chain will see it.
+// The Entry's Fields slice is owned by the entry, so middleware may modify it in place.
+type Middleware func(Entry) (Entry, bool)
+
+// Chain composes middleware into a single Middleware.
+//
+// Middleware run in the order given: the first receives the original entry and each following one receives the
+// entry returned by the one before it. The first middleware to return false short-circuits the chain and the entry
+// is dropped. Nil middleware are skipped, and an empty chain passes entries through unchanged.
+//
+// As a rule of thumb, order them cheapest-to-drop first: sampling and filtering, then redaction, then enrichment,
+// then routing.
+func Chain(mws ...Middleware) Middleware {
+	// copy so later mutation of the caller's slice doesn't change the chain
+	chain := make([]Middleware, 0, len(mws))
+	for _, mw := range mws {
+		if mw != nil {
+			chain = append(chain, mw)
+		}
+	}
+
+	return func(e Entry) (Entry, bool) {
+		for _, mw := range chain {
+			var ok bool
+			if e, ok = mw(e); !ok {
+				return e, false
+			}
+		}
+		return e, true
+	}
+}
+
+// WithMiddleware returns a Logger that runs every entry through mws (composed via Chain) before handing it to base.
+//
+// Context fields added via With are held by the returned logger rather than pushed down to base, so middleware see
+// (and may rewrite) them alongside the call-site fields. Entries are dispatched to base using their final level, but
+// Panic and Fatal keep their call-site semantics: they still panic/ exit even if middleware dropped or downgraded
+// the entry. Entries below the base logger's level, and entries matching an active Mute, are dropped before the
+// middleware see them, so they don't pay for (or count) entries that are never written.
+func WithMiddleware(base Logger[fields.Field], mws ...Middleware) Logger[fields.Field] {
+	// skip the level method, log, emit and LogAt so file:line still points at the call site
+	return pipeline{base: WithOptions(base, AddCallerSkip(4)), mw: Chain(mws...), name: NameOf(base)}
+}
+
+// pipeline is the Logger returned by WithMiddleware.
+type pipeline struct {
+	base    Logger[fields.Field]
+	mw      Middleware
+	name    string
+	context []fields.Field
+	ctx     context.Context
+}
+
+// Interface satisfaction (compile-time assertions).
+var (
+	_ Logger[fields.Field]         = pipeline{}
+	_ LevelCheck                   = pipeline{}
+	_ Noticer[fields.Field]        = pipeline{}
+	_ OptionsApplier[fields.Field] = pipeline{}
+	_ LevelSetter[fields.Field]    = pipeline{}
+	_ Introspector[fields.Field]   = pipeline{}
+	_ LazyWither[fields.Field]     = pipeline{}
+	_ LevelVarHolder               = pipeline{}
+	_ Closer                       = pipeline{}
+	_ ContextBinder[fields.Field]  = pipeline{}
+)
+
+// log builds the Entry, runs the middleware unless it's disabled or muted (see Mute), and dispatches whatever
+// survives.
+func (p pipeline) log(lvl Level, msg string, fs []fields.Field) {
+	if lvl.Compare(PanicLevel) < 0 && !Enabled(p.base, lvl) {
+		return
+	}
+
+	all := make([]fields.Field, 0, len(p.context)+len(fs))
+	all = append(all, p.context...)
+	all = append(all, fs...)
+
+	e := Entry{
+		Time:    time.Now(),
+		Level:   lvl,
+		Name:    p.name,
+		Message: msg,
+		Fields:  all,
+		Context: p.ctx,
+	}
+	ok := !muted(e)
+	if ok {
+		e, ok = p.mw(e)
+	}
+	if ok {
+		p.emit(e)
+	}
+
+	// uphold the call-site contract if the entry was dropped or downgraded
+	if !ok || e.Level.Compare(lvl) < 0 {
+		switch lvl {
+		case PanicLevel:
+			panic(msg)
+		case FatalLevel:
+			os.Exit(1)
+		}
+	}
+}
+
+// emit hands e to the base logger at e.Level, see LogAt.
+func (p pipeline) emit(e Entry) {
+	LogAt(p.base, e.Level, e.Message, e.Fields...)
+}
+
+// DEBUG (-1): for recording messages useful for debugging.
+func (p pipeline) Debug(msg string, fs ...fields.Field) { p.log(DebugLevel, msg, fs) }
+
+// INFO (0): for messages describing normal application operations.
+func (p pipeline) Info(msg string, fs ...fields.Field) { p.log(InfoLevel, msg, fs) }
+
+// Notice logs at NoticeLevel, see Noticer.
+func (p pipeline) Notice(msg string, fs ...fields.Field) { p.log(NoticeLevel, msg, fs) }
+
+// WARN (1): for recording messages indicating something unusual happened that may need attention before it escalates to a more severe issue.
+func (p pipeline) Warn(msg string, fs ...fields.Field) { p.log(WarnLevel, msg, fs) }
+
+// ERROR (2): for recording unexpected error conditions in the program.
+func (p pipeline) Error(msg string, fs ...fields.Field) { p.log(ErrorLevel, msg, fs) }
+
+// DPANIC (3): for recording severe error conditions in development. It behaves like PANIC in development and ERROR in production.
+func (p pipeline) DPanic(msg string, fs ...fields.Field) { p.log(DPanicLevel, msg, fs) }
+
+// PANIC (4): calls panic() after logging an error condition.
+func (p pipeline) Panic(msg string, fs ...fields.Field) { p.log(PanicLevel, msg, fs) }
+
+// FATAL (5): calls os.Exit(1) after logging an error condition.
+func (p pipeline) Fatal(msg string, fs ...fields.Field) { p.log(FatalLevel, msg, fs) }
+
+// With returns a child pipeline carrying fs as context. The fields are kept on the pipeline (not the base) so that
+// middleware see them on every entry.
+func (p pipeline) With(fs ...fields.Field) Logger[fields.Field] {
+	context := make([]fields.Field, 0, len(p.context)+len(fs))
+	context = append(context, p.context...)
+	context = append(context, fs...)
+
+	child := p
+	child.context = context
+	return child
+}
+
+// WithLazy is With: the pipeline only holds on to context fields, the base logger handles them per entry.
+func (p pipeline) WithLazy(fs ...fields.Field) Logger[fields.Field] {
+	return p.With(fs...)
+}
+
+// Named returns a child pipeline with name appended to both its own and the base logger's name.
+func (p pipeline) Named(name string) Logger[fields.Field] {
+	child := p
+	child.base = p.base.Named(name)
+	if p.name == "" {
+		child.name = name
+	} else if name != "" {
+		child.name = p.name + "." + name
+	}
+	return child
+}
+
+// Sync delegates to the base logger's Sync.
+func (p pipeline) Sync() error {
+	return p.base.Sync()
+}
+
+// Enabled reports whether the base logger records lvl.
+func (p pipeline) Enabled(lvl Level) bool {
+	return Enabled(p.base, lvl)
+}
+
+// WithOptions applies opts to the base logger.
+func (p pipeline) WithOptions(opts ...Option) Logger[fields.Field] {
+	child := p
+	child.base = WithOptions(p.base, opts...)
+	return child
+}
+
+// WithLevel sets the base logger's minimum level. Entries below it never reach the middleware.
+func (p pipeline) WithLevel(lvl Level) Logger[fields.Field] {
+	child := p
+	child.base = WithLevel(p.base, lvl)
+	return child
+}
+
+// WithContext binds the pipeline and its base logger to ctx, so middleware see it as Entry.Context.
+func (p pipeline) WithContext(ctx context.Context) Logger[fields.Field] {
+	child := p
+	child.ctx = ctx
+	child.base = WithContext(p.base, ctx)
+	return child
+}
+
+// LevelVar returns the base logger's LevelVar.
+func (p pipeline) LevelVar() *LevelVar {
+	return LevelVarOf(p.base)
+}
+
+// Close closes the base logger.
+func (p pipeline) Close() error {
+	return Close(p.base)
+}
+
+// Name returns the pipeline's name.
+func (p pipeline) Name() string {
+	return p.name
+}
+
+// Fields returns the base logger's context fields followed by the pipeline's own.
+func (p pipeline) Fields() []fields.Field {
+	base := FieldsOf(p.base)
+	if len(base) == 0 {
+		return p.context
+	}
+	return append(base, p.context...)
+}
@@ -0,0 +1,116 @@
+package middleware
+
+import (
+	"sync"
+
+	"github.com/khinshankhan/logstox"
+	"github.com/khinshankhan/logstox/fields"
+)
+
+// Keys of the summary entry logged by Budget when a request runs out of budget.
+const (
+	BudgetKey        = "log_budget"
+	BudgetDroppedKey = "log_budget_dropped"
+)
+
+// DefaultBudgetTracked is how many requests a Budget tracks when its Tracked is <= 0.
+const DefaultBudgetTracked = 10000
+
+// Budget caps how many entries each request logs, protecting the pipeline from pathological request-level log
+// storms. Requests are told apart by the value of a string field, eg the correlation ID set by httpx.Correlation:
+//
+//	budget := &middleware.Budget{Key: httpx.CorrelationIDKey, Max: 500}
+//	log = logstox.WithMiddleware(log, budget.Middleware())
+//
+// The entry exceeding the budget is replaced by a WarnLevel summary saying so, and the request's later entries are
+// dropped; call End when the request completes to get how many were. Entries without the field aren't counted, and
+// like with SortKeys, the field must be added to the middleware logger rather than baked into the base logger.
+// Budget is safe for concurrent use.
+type Budget struct {
+	Key string // field identifying the request
+	Max int    // entries allowed per request
+	// Tracked bounds the memory used when End isn't called (DefaultBudgetTracked if <= 0): past that many requests,
+	// those that went quiet the longest are forgotten, and their budget starts over if they log again.
+	Tracked int
+
+	mu       sync.Mutex
+	current  map[string]int // request -> entries seen
+	previous map[string]int // the generation before current, see Tracked
+}
+
+// Middleware returns the middleware enforcing the budget.
+func (b *Budget) Middleware() logstox.Middleware {
+	return func(e logstox.Entry) (logstox.Entry, bool) {
+		id, ok := stringField(e.Fields, b.Key)
+		if !ok {
+			return e, true
+		}
+		n := b.count(id)
+		switch {
+		case n <= b.Max:
+			return e, true
+		case n == b.Max+1:
+			return logstox.Entry{
+				Time:    e.Time,
+				Level:   logstox.WarnLevel,
+				Name:    e.Name,
+				Message: "log budget exceeded, dropping further entries",
+				Fields:  []fields.Field{fields.String(b.Key, id), fields.Int(BudgetKey, b.Max)},
+				Context: e.Context,
+			}, true
+		default:
+			return e, false
+		}
+	}
+}
+
+// End forgets the request id, returning how many of its entries were dropped, eg to log them in a request summary:
+//
+//	if n := budget.End(id); n > 0 {
+//		log.Warn("request logs dropped", fields.Int(middleware.BudgetDroppedKey, n))
+//	}
+func (b *Budget) End(id string) (dropped int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	n, ok := b.current[id]
+	if ok {
+		delete(b.current, id)
+	} else {
+		n = b.previous[id]
+		delete(b.previous, id)
+	}
+	// the summary replaced an entry, so it counts as dropped
+	return max(n-b.Max, 0)
+}
+
+// count records an entry for the request id, returning how many it has logged.
+func (b *Budget) count(id string) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	n, ok := b.current[id]
+	if !ok {
+		// move it forward so it survives the next rotation
+		n = b.previous[id]
+		delete(b.previous, id)
+		tracked := b.Tracked
+		if tracked <= 0 {
+			tracked = DefaultBudgetTracked
+		}
+		if b.current == nil || len(b.current) >= (tracked+1)/2 {
+			b.previous, b.current = b.current, make(map[string]int)
+		}
+	}
+	n++
+	b.current[id] = n
+	return n
+}
+
+// stringField returns the value of the first top-level string field with key k.
+func stringField(fs []fields.Field, k string) (string, bool) {
+	for _, f := range fs {
+		if f.Key == k && f.Kind() == fields.FieldKindString {
+			return f.Str(), true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/khinshankhan/logstox"
+	"github.com/khinshankhan/logstox/fields"
+)
+
+// request returns an entry logged by the request id.
+func request(id string) logstox.Entry {
+	return logstox.Entry{Level: logstox.InfoLevel, Message: "m", Fields: []fields.Field{fields.String("req", id)}}
+}
+
+func TestBudget(t *testing.T) {
+	b := &Budget{Key: "req", Max: 2}
+	mw := b.Middleware()
+
+	var passed []string
+	for range 5 {
+		if e, ok := mw(request("a")); ok {
+			passed = append(passed, e.Level.String())
+		}
+	}
+	if want := fmt.Sprint([]string{"info", "info", "warn"}); fmt.Sprint(passed) != want {
+		t.Errorf("passed %v, want %v: the budget, then the summary", passed, want)
+	}
+	if _, ok := mw(request("b")); !ok {
+		t.Error("another request's entry was dropped")
+	}
+	if _, ok := mw(logstox.Entry{}); !ok {
+		t.Error("an entry without the key was dropped")
+	}
+
+	if n := b.End("a"); n != 3 {
+		t.Errorf("End = %d, want the summarized and 2 dropped entries", n)
+	}
+	if _, ok := mw(request("a")); !ok {
+		t.Error("the budget didn't start over after End")
+	}
+	if n := b.End("b"); n != 0 {
+		t.Errorf("End = %d for a request within budget, want 0", n)
+	}
+}
+
+func TestBudgetSummary(t *testing.T) {
+	mw := (&Budget{Key: "req", Max: 0}).Middleware()
+	e := request("a")
+	e.Name = "http"
+	got, ok := mw(e)
+
+	id, _ := stringField(got.Fields, "req")
+	if !ok || got.Level != logstox.WarnLevel || got.Name != "http" || id != "a" {
+		t.Errorf("got %v, want a warn summary for request a from the same logger", got)
+	}
+	if n, _ := fields.Fields(got.Fields).Get(BudgetKey); n.Int64() != 0 {
+		t.Errorf("%s = %v, want the budget", BudgetKey, n)
+	}
+}
+
+func TestBudgetForgetsQuietRequests(t *testing.T) {
+	b := &Budget{Key: "req", Max: 1, Tracked: 2}
+	mw := b.Middleware()
+	mw(request("quiet"))
+	// two generations of one request each rotate quiet out
+	mw(request("b"))
+	mw(request("c"))
+
+	if e, _ := mw(request("quiet")); e.Level != logstox.InfoLevel {
+		t.Error("a forgotten request's budget didn't start over")
+	}
+	mw(request("c"))
+	if _, ok := mw(request("c")); ok {
+		t.Error("a tracked request logged past its budget")
+	}
+}
@@ -0,0 +1,168 @@
+package middleware
+
+import (
+	"sync"
+	"time"
+
+	"github.com/khinshankhan/logstox"
+	"github.com/khinshankhan/logstox/fields"
+	"github.com/khinshankhan/logstox/metrics"
+)
+
+// Keys of the warnings logged by Cardinality.
+const (
+	NewKeyKey       = "new_key"        // the key first seen after the warm-up
+	NewKeyLoggerKey = "new_key_logger" // the name of the logger of the entry carrying it, if it has one
+	KeysSeenKey     = "keys_seen"      // how many distinct keys were seen so far
+)
+
+// Metric names Cardinality reports.
+const (
+	KeysMetric    = "log_keys"           // gauge of the distinct keys seen
+	NewKeysMetric = "log_new_keys_total" // counter of the keys first seen after the warm-up
+)
+
+// DefaultCardinalityKeys is how many keys a Cardinality tracks when its MaxKeys is <= 0.
+const DefaultCardinalityKeys = 10000
+
+// Cardinality tracks the distinct field keys entries carry and reports the keys first seen after a warm-up period,
+// catching accidental unbounded key generation, eg keys with IDs in them:
+//
+//	card := &middleware.Cardinality{WarmUp: 10 * time.Minute, Log: base, Metrics: rec}
+//	log = logstox.WithMiddleware(base, card.Middleware())
+//
+// Keys inside Dicts are tracked by their dotted path, eg "http.status", and precomputed bundles are looked into,
+// while lazy fields aren't evaluated. Like with SortKeys, context fields only take part when they're added to the
+// middleware logger rather than baked into the base logger. Entries pass through unchanged. Cardinality is safe for
+// concurrent use, and entries carrying only known keys, the bulk of them past the warm-up, share a read lock.
+type Cardinality struct {
+	// WarmUp is how long after the first entry keys are learned without being reported.
+	WarmUp time.Duration
+	// MaxKeys bounds the keys tracked (DefaultCardinalityKeys if <= 0). Reaching it is reported once, with a
+	// WarnLevel entry, and later keys aren't tracked.
+	MaxKeys int
+	// Log, if set, receives a WarnLevel entry per new key, with the name of the logger carrying it under
+	// NewKeyLoggerKey. Give it a logger that bypasses the middleware.
+	Log logstox.Logger[fields.Field]
+	// Metrics, if set, receives KeysMetric and NewKeysMetric.
+	Metrics metrics.Recorder
+	// OnNewKey, if set, is called with every new key and the entry carrying it.
+	OnNewKey func(key string, e logstox.Entry)
+
+	mu    sync.RWMutex
+	start time.Time
+	keys  map[string]struct{}
+	full  bool // whether MaxKeys was reached
+}
+
+// Middleware returns the middleware tracking the keys.
+func (c *Cardinality) Middleware() logstox.Middleware {
+	return func(e logstox.Entry) (logstox.Entry, bool) {
+		c.mu.RLock()
+		known := c.keys != nil && (c.full || c.known(e.Fields, ""))
+		c.mu.RUnlock()
+		if known {
+			return e, true
+		}
+
+		var fresh []string // keys first seen after the warm-up
+		c.mu.Lock()
+		if c.keys == nil {
+			c.keys = make(map[string]struct{})
+			c.start = time.Now()
+		}
+		seen := len(c.keys)
+		warm := time.Since(c.start) >= c.WarmUp
+		full := c.full
+		c.track(e.Fields, "", warm, &fresh)
+		n, filled := len(c.keys), c.full && !full
+		c.mu.Unlock()
+
+		if n > seen && c.Metrics != nil {
+			c.Metrics.Set(KeysMetric, float64(n))
+		}
+		for _, k := range fresh {
+			c.report(k, n, e)
+		}
+		if filled && c.Log != nil {
+			c.Log.Warn("log field key limit reached, new keys are no longer tracked", fields.Int(KeysSeenKey, n))
+		}
+		return e, true
+	}
+}
+
+// known reports whether every key of fs, prefixed by prefix, is tracked already. c.mu must be held for reading.
+func (c *Cardinality) known(fs []fields.Field, prefix string) bool {
+	for _, f := range fs {
+		switch f.Kind() {
+		case fields.FieldKindInvalid, fields.FieldKindLazyFields, fields.FieldKindLazyValue:
+			continue
+		case fields.FieldKindPrecomputed:
+			if !c.known(f.Precomputed().Fields(), prefix) {
+				return false
+			}
+			continue
+		}
+		k := f.Key
+		if prefix != "" {
+			k = prefix + k
+		}
+		if _, ok := c.keys[k]; !ok {
+			return false
+		}
+		if f.Kind() == fields.FieldKindDict && !c.known(f.Fields(), k+".") {
+			return false
+		}
+	}
+	return true
+}
+
+// track records the keys of fs, prefixed by prefix, appending those first seen after the warm-up to fresh. c.mu must
+// be held.
+func (c *Cardinality) track(fs []fields.Field, prefix string, warm bool, fresh *[]string) {
+	limit := c.MaxKeys
+	if limit <= 0 {
+		limit = DefaultCardinalityKeys
+	}
+	for _, f := range fs {
+		switch f.Kind() {
+		case fields.FieldKindInvalid, fields.FieldKindLazyFields, fields.FieldKindLazyValue:
+			continue
+		case fields.FieldKindPrecomputed:
+			c.track(f.Precomputed().Fields(), prefix, warm, fresh)
+			continue
+		}
+		k := prefix + f.Key
+		if _, ok := c.keys[k]; !ok && !c.full {
+			if len(c.keys) >= limit {
+				c.full = true
+			} else {
+				c.keys[k] = struct{}{}
+				if warm {
+					*fresh = append(*fresh, k)
+				}
+			}
+		}
+		if f.Kind() == fields.FieldKindDict {
+			c.track(f.Fields(), k+".", warm, fresh)
+		}
+	}
+}
+
+// report reports the new key k, n keys having been seen, carried by e.
+func (c *Cardinality) report(k string, n int, e logstox.Entry) {
+	if c.Metrics != nil {
+		c.Metrics.Add(NewKeysMetric, 1)
+	}
+	if c.Log != nil {
+		fs := []fields.Field{fields.String(NewKeyKey, k), fields.Int(KeysSeenKey, n)}
+		if e.Name != "" {
+			// not c.Log.Named(e.Name): e.Name is a full name, often starting with c.Log's own
+			fs = append(fs, fields.String(NewKeyLoggerKey, e.Name))
+		}
+		c.Log.Warn("new log field key", fs...)
+	}
+	if c.OnNewKey != nil {
+		c.OnNewKey(k, e)
+	}
+}
@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/khinshankhan/logstox"
+	"github.com/khinshankhan/logstox/backend/memx"
+	"github.com/khinshankhan/logstox/fields"
+	"github.com/khinshankhan/logstox/metrics"
+)
+
+func TestCardinality(t *testing.T) {
+	var fresh []string
+	rec := memx.NewRecorder(8)
+	m := &metrics.Memory{}
+	c := &Cardinality{
+		Log:      memx.Backend{Recorder: rec}.New(logstox.Options[fields.Field]{}),
+		Metrics:  m,
+		OnNewKey: func(k string, _ logstox.Entry) { fresh = append(fresh, k) },
+	}
+	mw := c.Middleware()
+
+	lazy := fields.LazyFields(func(context.Context) []fields.Field { return []fields.Field{fields.Int("lazy", 1)} })
+	mw(logstox.Entry{Name: "http", Fields: []fields.Field{
+		fields.String("path", "/"),
+		fields.Dict("http", fields.Int("status", 200)),
+		fields.Precompute(fields.String("service", "api")),
+		lazy,
+	}})
+	mw(logstox.Entry{Fields: []fields.Field{fields.String("path", "/users")}}) // known
+
+	if want := []string{"path", "http", "http.status", "service"}; !reflect.DeepEqual(fresh, want) {
+		t.Errorf("reported %v, want %v", fresh, want)
+	}
+	if n := m.Counter(NewKeysMetric); n != 4 {
+		t.Errorf("%s = %v, want 4", NewKeysMetric, n)
+	}
+	if n, _ := m.Gauge(KeysMetric); n != 4 {
+		t.Errorf("%s = %v, want 4", KeysMetric, n)
+	}
+	es := rec.Entries()
+	if len(es) != 4 || es[0].Level != logstox.WarnLevel {
+		t.Fatalf("logged %v, want a warning per new key", es)
+	}
+	if name, _ := fields.Fields(es[0].Fields).Get(NewKeyLoggerKey); name.Str() != "http" {
+		t.Errorf("%s = %v, want the carrying entry's logger", NewKeyLoggerKey, name)
+	}
+}
+
+func TestCardinalityWarmUp(t *testing.T) {
+	var fresh []string
+	c := &Cardinality{WarmUp: time.Hour, OnNewKey: func(k string, _ logstox.Entry) { fresh = append(fresh, k) }}
+	mw := c.Middleware()
+	mw(logstox.Entry{Fields: []fields.Field{fields.String("a", "a")}})
+	mw(logstox.Entry{Fields: []fields.Field{fields.String("b", "b")}})
+
+	if len(fresh) != 0 {
+		t.Errorf("reported %v during the warm-up, want nothing", fresh)
+	}
+}
+
+func TestCardinalityMaxKeys(t *testing.T) {
+	var fresh []string
+	rec := memx.NewRecorder(8)
+	c := &Cardinality{
+		MaxKeys:  2,
+		Log:      memx.Backend{Recorder: rec}.New(logstox.Options[fields.Field]{}),
+		OnNewKey: func(k string, _ logstox.Entry) { fresh = append(fresh, k) },
+	}
+	mw := c.Middleware()
+	mw(logstox.Entry{Fields: []fields.Field{fields.Int("a", 1), fields.Int("b", 2), fields.Int("c", 3)}})
+	mw(logstox.Entry{Fields: []fields.Field{fields.Int("d", 4)}})
+
+	if want := []string{"a", "b"}; !reflect.DeepEqual(fresh, want) {
+		t.Errorf("reported %v, want %v", fresh, want)
+	}
+	var limits int
+	for _, e := range rec.Entries() {
+		if _, ok := fields.Fields(e.Fields).Get(NewKeyKey); !ok {
+			limits++
+		}
+	}
+	if limits != 1 {
+		t.Errorf("reported reaching the limit %d times, want once", limits)
+	}
+}
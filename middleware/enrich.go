@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"context"
+	"os"
+	"runtime"
+
+	"github.com/khinshankhan/logstox"
+	"github.com/khinshankhan/logstox/fields"
+)
+
+// Keys for runtime and service metadata, following OpenTelemetry semantic conventions (which ECS has converged on).
+const (
+	HostNameKey       = "host.name"
+	ProcessPIDKey     = "process.pid"
+	RuntimeVersionKey = "process.runtime.version"
+	ServiceNameKey    = "service.name"
+	ServiceVersionKey = "service.version"
+	EnvironmentKey    = "deployment.environment"
+	RegionKey         = "cloud.region"
+)
+
+// Service describes the running service. Empty values are omitted from the fields.
+type Service struct {
+	Name        string
+	Version     string
+	Environment string // eg prod, staging
+	Region      string // eg us-east-1
+}
+
+// RuntimeFields returns hostname, pid, Go version and svc metadata as fields.
+// They're computed when called, so pass the result to Options.Fields or Enrich rather than calling it per entry.
+func RuntimeFields(svc Service) []fields.Field {
+	fs := []fields.Field{
+		fields.Int(ProcessPIDKey, os.Getpid()),
+		fields.String(RuntimeVersionKey, runtime.Version()),
+	}
+	if host, err := os.Hostname(); err == nil {
+		fs = append(fs, fields.String(HostNameKey, host))
+	}
+
+	for _, kv := range [...]struct{ k, v string }{
+		{ServiceNameKey, svc.Name},
+		{ServiceVersionKey, svc.Version},
+		{EnvironmentKey, svc.Environment},
+		{RegionKey, svc.Region},
+	} {
+		if kv.v != "" {
+			fs = append(fs, fields.String(kv.k, kv.v))
+		}
+	}
+	return fs
+}
+
+// Enrich returns a middleware appending fs to every entry.
+// NOTE: this does not copy fs; don't mutate it afterwards.
+func Enrich(fs ...fields.Field) logstox.Middleware {
+	return func(e logstox.Entry) (logstox.Entry, bool) {
+		e.Fields = append(e.Fields, fs...)
+		return e, true
+	}
+}
+
+// EnrichContext returns a middleware appending the fields extractors derive from the context the entry's logger is
+// bound to (see Entry.Context), like Options.ContextExtractors but within the chain, so the middleware after it, eg
+// redaction or schema.Allow, see them too:
+//
+//	log = logstox.WithMiddleware(base, middleware.EnrichContext(traceFields), redact.Middleware(redact.Email))
+//
+// Entries of loggers bound to no context pass through unchanged.
+func EnrichContext(extractors ...func(context.Context) []fields.Field) logstox.Middleware {
+	return func(e logstox.Entry) (logstox.Entry, bool) {
+		if e.Context != nil {
+			e.Fields = append(e.Fields, logstox.ExtractContext(e.Context, extractors)...)
+		}
+		return e, true
+	}
+}
+
+// Runtime returns a middleware appending RuntimeFields(svc) to every entry.
+// The fields are computed once, when Runtime is called.
+func Runtime(svc Service) logstox.Middleware {
+	return Enrich(RuntimeFields(svc)...)
+}
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"context"
+	"testing"
+
+	"github.com/khinshankhan/logstox"
+	"github.com/khinshankhan/logstox/backend/memx"
+	"github.com/khinshankhan/logstox/fields"
+)
+
+type userKey struct{}
+
+func TestEnrichContextRunsWithinTheChain(t *testing.T) {
+	user := func(ctx context.Context) []fields.Field {
+		if u, ok := ctx.Value(userKey{}).(string); ok {
+			return []fields.Field{fields.String("user", u)}
+		}
+		return nil
+	}
+	var seen []string // the keys the middleware after EnrichContext got
+	rec := memx.NewRecorder(2)
+	log := logstox.WithMiddleware(
+		memx.Backend{Recorder: rec}.New(logstox.Options[fields.Field]{}),
+		EnrichContext(user),
+		func(e logstox.Entry) (logstox.Entry, bool) {
+			for _, f := range e.Fields {
+				seen = append(seen, f.Key)
+			}
+			return e, true
+		},
+	)
+
+	log.Info("unbound")
+	logstox.WithContext(log, context.WithValue(context.Background(), userKey{}, "ada")).Info("bound")
+
+	if len(seen) != 1 || seen[0] != "user" {
+		t.Errorf("middleware saw %v, want only the bound entry's user", seen)
+	}
+	if es := rec.Entries(); len(es) != 2 || len(es[1].Fields) != 1 || es[1].Fields[0].Str() != "ada" {
+		t.Errorf("recorded %v, want the bound entry with user=ada", es)
+	}
+}
@@ -0,0 +1,140 @@
+package middleware
+
+import (
+	"reflect"
+	"regexp"
+
+	"github.com/khinshankhan/logstox"
+	"github.com/khinshankhan/logstox/fields"
+)
+
+// Predicate reports whether an entry matches some condition.
+type Predicate func(logstox.Entry) bool
+
+// MessageMatches matches entries whose message matches re.
+func MessageMatches(re *regexp.Regexp) Predicate {
+	return func(e logstox.Entry) bool {
+		return re.MatchString(e.Message)
+	}
+}
+
+// HasKey matches entries carrying a (non no-op) field with key k.
+func HasKey(k string) Predicate {
+	return func(e logstox.Entry) bool {
+		_, ok := fields.Fields(e.Fields).Get(k)
+		return ok
+	}
+}
+
+// FieldMatches matches entries carrying a field with key k for which fn returns true.
+func FieldMatches(k string, fn func(fields.Field) bool) Predicate {
+	return func(e logstox.Entry) bool {
+		f, ok := fields.Fields(e.Fields).Get(k)
+		return ok && fn(f)
+	}
+}
+
+// FieldEquals matches entries carrying a field with key k whose value equals v.
+// Values are compared after the same normalization the constructors apply (eg int becomes int64), so
+// FieldEquals("status", 200) matches fields.Int("status", 200).
+func FieldEquals(k string, v any) Predicate {
+	want := fields.From(k, v).Boxed()
+	return FieldMatches(k, func(f fields.Field) bool {
+		return reflect.DeepEqual(f.Boxed(), want)
+	})
+}
+
+// NumberAtLeast matches entries carrying a numeric field (int64, uint64 or float64) with key k whose value is >= n.
+func NumberAtLeast(k string, n float64) Predicate {
+	return FieldMatches(k, func(f fields.Field) bool {
+		v, ok := number(f)
+		return ok && v >= n
+	})
+}
+
+// LevelAtLeast matches entries at or above lvl.
+func LevelAtLeast(lvl logstox.Level) Predicate {
+	return func(e logstox.Entry) bool {
+		return e.Level.Compare(lvl) >= 0
+	}
+}
+
+// LevelAtMost matches entries at or below lvl.
+func LevelAtMost(lvl logstox.Level) Predicate {
+	return func(e logstox.Entry) bool {
+		return e.Level.Compare(lvl) <= 0
+	}
+}
+
+// Named matches entries from the logger named exactly name.
+func Named(name string) Predicate {
+	return func(e logstox.Entry) bool {
+		return e.Name == name
+	}
+}
+
+// AnyOf matches when at least one of ps matches. No predicates never matches.
+func AnyOf(ps ...Predicate) Predicate {
+	return func(e logstox.Entry) bool {
+		for _, p := range ps {
+			if p(e) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// AllOf matches when every one of ps matches. No predicates always matches.
+func AllOf(ps ...Predicate) Predicate {
+	return func(e logstox.Entry) bool {
+		for _, p := range ps {
+			if !p(e) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Not inverts p.
+func Not(p Predicate) Predicate {
+	return func(e logstox.Entry) bool {
+		return !p(e)
+	}
+}
+
+// Drop returns a middleware dropping entries that match any of ps, eg known-noisy health check access logs:
+//
+//	middleware.Drop(middleware.AllOf(
+//		middleware.LevelAtMost(logstox.InfoLevel),
+//		middleware.FieldEquals("path", "/healthz"),
+//	))
+func Drop(ps ...Predicate) logstox.Middleware {
+	match := AnyOf(ps...)
+	return func(e logstox.Entry) (logstox.Entry, bool) {
+		return e, !match(e)
+	}
+}
+
+// Keep returns a middleware that only lets through entries matching any of ps.
+func Keep(ps ...Predicate) logstox.Middleware {
+	match := AnyOf(ps...)
+	return func(e logstox.Entry) (logstox.Entry, bool) {
+		return e, match(e)
+	}
+}
+
+// number returns the value of a numeric field as a float64.
+func number(f fields.Field) (float64, bool) {
+	switch f.Kind() {
+	case fields.FieldKindInt64:
+		return float64(f.Int64()), true
+	case fields.FieldKindUint64:
+		return float64(f.Uint64()), true
+	case fields.FieldKindFloat64:
+		return f.Float64(), true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/khinshankhan/logstox"
+	"github.com/khinshankhan/logstox/fields"
+)
+
+func TestPredicates(t *testing.T) {
+	e := logstox.Entry{
+		Level:   logstox.NoticeLevel,
+		Name:    "http",
+		Message: "request served",
+		Fields: []fields.Field{
+			fields.Int("status", 503),
+			fields.Float64("ratio", 0.5),
+			fields.String("path", "/healthz"),
+			fields.Nop(),
+		},
+	}
+	never := func(logstox.Entry) bool { return false }
+	tests := []struct {
+		name string
+		p    Predicate
+		want bool
+	}{
+		{"message matches", MessageMatches(regexp.MustCompile(`^request`)), true},
+		{"message doesn't match", MessageMatches(regexp.MustCompile(`failed`)), false},
+		{"has key", HasKey("status"), true},
+		{"missing key", HasKey("user"), false},
+		{"nop isn't a key", HasKey(""), false},
+		{"field matches", FieldMatches("path", func(f fields.Field) bool { return f.Str() == "/healthz" }), true},
+		{"field equals normalized int", FieldEquals("status", 503), true},
+		{"field equals other value", FieldEquals("status", 200), false},
+		{"field equals other type", FieldEquals("status", "503"), false},
+		{"number at least int", NumberAtLeast("status", 500), true},
+		{"number below", NumberAtLeast("status", 504), false},
+		{"number at least float", NumberAtLeast("ratio", 0.5), true},
+		{"number of a string", NumberAtLeast("path", 0), false},
+		{"level at least info", LevelAtLeast(logstox.InfoLevel), true},
+		{"level at least warn", LevelAtLeast(logstox.WarnLevel), false},
+		{"level at most info", LevelAtMost(logstox.InfoLevel), false},
+		{"level at most notice", LevelAtMost(logstox.NoticeLevel), true},
+		{"named", Named("http"), true},
+		{"named is exact", Named("ht"), false},
+		{"any of", AnyOf(never, HasKey("status")), true},
+		{"any of none", AnyOf(), false},
+		{"all of", AllOf(HasKey("status"), never), false},
+		{"all of none", AllOf(), true},
+		{"not", Not(never), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.p(e); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDropAndKeep(t *testing.T) {
+	health := FieldEquals("path", "/healthz")
+	tests := []struct {
+		name     string
+		mw       logstox.Middleware
+		path     string
+		wantPass bool
+	}{
+		{"drop matching", Drop(health), "/healthz", false},
+		{"drop passes others", Drop(health), "/users", true},
+		{"keep matching", Keep(health), "/healthz", true},
+		{"keep drops others", Keep(health), "/users", false},
+		{"drop nothing", Drop(), "/healthz", true},
+		{"keep nothing", Keep(), "/healthz", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := logstox.Entry{Fields: []fields.Field{fields.String("path", tt.path)}}
+			if _, ok := tt.mw(e); ok != tt.wantPass {
+				t.Errorf("passed %v, want %v", ok, tt.wantPass)
+			}
+		})
+	}
+}
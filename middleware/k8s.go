@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"os"
+
+	"github.com/khinshankhan/logstox"
+	"github.com/khinshankhan/logstox/fields"
+)
+
+// KubernetesKey is the key of the Dict added by KubernetesFields.
+const KubernetesKey = "k8s"
+
+// Environment variables read by KubernetesFields. Kubernetes doesn't set these itself; expose them on the container
+// through the downward API, eg:
+//
+//	env:
+//	  - name: POD_NAME
+//	    valueFrom: {fieldRef: {fieldPath: metadata.name}}
+//	  - name: POD_NAMESPACE
+//	    valueFrom: {fieldRef: {fieldPath: metadata.namespace}}
+//	  - name: NODE_NAME
+//	    valueFrom: {fieldRef: {fieldPath: spec.nodeName}}
+//	  - name: CONTAINER_NAME
+//	    value: app
+const (
+	PodNameEnv       = "POD_NAME"
+	PodNamespaceEnv  = "POD_NAMESPACE"
+	NodeNameEnv      = "NODE_NAME"
+	ContainerNameEnv = "CONTAINER_NAME"
+)
+
+// KubernetesFields groups pod, namespace, node and container names read from the downward API environment variables
+// under KubernetesKey ("k8s"), using OpenTelemetry's k8s.* naming within the Dict.
+// Unset variables are omitted; if none are set (eg not running in a cluster), returns a no-op.
+func KubernetesFields() fields.Field {
+	var fs []fields.Field
+	for _, kv := range [...]struct{ k, env string }{
+		{"pod.name", PodNameEnv},
+		{"namespace.name", PodNamespaceEnv},
+		{"node.name", NodeNameEnv},
+		{"container.name", ContainerNameEnv},
+	} {
+		if v := os.Getenv(kv.env); v != "" {
+			fs = append(fs, fields.String(kv.k, v))
+		}
+	}
+	if len(fs) == 0 {
+		return fields.Nop()
+	}
+	return fields.Dict(KubernetesKey, fs...)
+}
+
+// Kubernetes returns a middleware attaching KubernetesFields to every entry.
+// The environment is read once, when Kubernetes is called.
+func Kubernetes() logstox.Middleware {
+	if f := KubernetesFields(); !f.IsZero() {
+		return Enrich(f)
+	}
+	return Enrich()
+}
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/khinshankhan/logstox"
+	"github.com/khinshankhan/logstox/fields"
+)
+
+func TestKubernetesFields(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want map[string]string // nil for a no-op
+	}{
+		{"outside a cluster", nil, nil},
+		{
+			"every variable",
+			map[string]string{
+				PodNameEnv: "api-7d9f", PodNamespaceEnv: "prod", NodeNameEnv: "node-1", ContainerNameEnv: "app",
+			},
+			map[string]string{
+				"pod.name": "api-7d9f", "namespace.name": "prod", "node.name": "node-1", "container.name": "app",
+			},
+		},
+		{
+			"unset omitted",
+			map[string]string{PodNameEnv: "api-7d9f"},
+			map[string]string{"pod.name": "api-7d9f"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, env := range []string{PodNameEnv, PodNamespaceEnv, NodeNameEnv, ContainerNameEnv} {
+				t.Setenv(env, tt.env[env])
+			}
+
+			f := KubernetesFields()
+			if tt.want == nil {
+				if !f.IsZero() {
+					t.Errorf("got %v, want a no-op", f)
+				}
+				return
+			}
+			got := make(map[string]string)
+			for _, sub := range f.Fields() {
+				got[sub.Key] = sub.Str()
+			}
+			if f.Key != KubernetesKey || f.Kind() != fields.FieldKindDict || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %s %v with %v, want a %s Dict with %v", f.Key, f.Kind(), got, KubernetesKey, tt.want)
+			}
+		})
+	}
+}
+
+func TestKubernetesReadsTheEnvironmentOnce(t *testing.T) {
+	t.Setenv(PodNameEnv, "before")
+	mw := Kubernetes()
+	t.Setenv(PodNameEnv, "after")
+
+	e, _ := mw(logstox.Entry{})
+	if len(e.Fields) != 1 || e.Fields[0].Fields()[0].Str() != "before" {
+		t.Errorf("got %v, want the pod name read when Kubernetes was called", e.Fields)
+	}
+}
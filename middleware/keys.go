@@ -0,0 +1,183 @@
+package middleware
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/khinshankhan/logstox"
+	"github.com/khinshankhan/logstox/fields"
+)
+
+// SortKeys returns a middleware sorting fields by key, recursively inside Dicts, so output is deterministic for
+// diff-based tests and parsers that need it. Fields with the same key keep their order. Precomputed bundles are
+// expanded in place to be sorted with the rest, while lazy fields (which can't be looked into without evaluating
+// them) are moved after the sorted ones.
+//
+// Context fields only take part when they're added to the middleware logger (see logstox.WithMiddleware) rather than
+// baked into the base logger.
+func SortKeys() logstox.Middleware {
+	return func(e logstox.Entry) (logstox.Entry, bool) {
+		e.Fields = sortFields(e.Fields)
+		return e, true
+	}
+}
+
+// sortFields returns fs sorted by key, with lazy fields last.
+func sortFields(fs []fields.Field) []fields.Field {
+	out := make([]fields.Field, 0, len(fs))
+	var lazy []fields.Field
+	var expand func([]fields.Field)
+	expand = func(fs []fields.Field) {
+		for _, f := range fs {
+			switch f.Kind() {
+			case fields.FieldKindInvalid:
+			case fields.FieldKindLazyFields, fields.FieldKindLazyValue:
+				lazy = append(lazy, f)
+			case fields.FieldKindPrecomputed:
+				expand(f.Precomputed().Fields())
+			case fields.FieldKindDict:
+				out = append(out, fields.Dict(f.Key, sortFields(f.Fields())...))
+			default:
+				out = append(out, f)
+			}
+		}
+	}
+	expand(fs)
+
+	slices.SortStableFunc(out, func(a, b fields.Field) int {
+		return cmp.Compare(a.Key, b.Key)
+	})
+	return append(out, lazy...)
+}
+
+// KeyCase is a key naming convention NormalizeKeys enforces.
+type KeyCase uint8
+
+const (
+	// SnakeCase rewrites keys as lower-case words joined by underscores, eg "httpStatus" becomes "http_status".
+	SnakeCase KeyCase = iota
+	// CamelCase rewrites keys as words joined with upper-case initials, eg "http_status" becomes "httpStatus".
+	CamelCase
+	// LowerCase only lower-cases keys, eg "httpStatus" becomes "httpstatus".
+	LowerCase
+)
+
+// maxCachedKeys bounds NormalizeKeys' cache, so unbounded key sets (which are a problem of their own) can't grow it
+// forever.
+const maxCachedKeys = 4096
+
+// NormalizeKeys returns a middleware rewriting field keys to c, recursively inside Dicts, so teams mixing conventions
+// still produce a uniform schema. Words are split on underscores, hyphens, spaces and case changes ("HTTPStatus" is
+// "HTTP" and "Status"); dots are kept as namespace separators, each segment being normalized on its own. Rewritten
+// keys are cached.
+//
+// Precomputed bundles are expanded so their keys can be rewritten, and lazy fields are normalized when evaluated.
+func NormalizeKeys(c KeyCase) logstox.Middleware {
+	n := &normalizer{c: c}
+	return func(e logstox.Entry) (logstox.Entry, bool) {
+		e.Fields = n.fields(e.Fields)
+		return e, true
+	}
+}
+
+// normalizer rewrites keys to c, caching the results.
+type normalizer struct {
+	c     KeyCase
+	cache sync.Map // key -> normalized key
+	size  atomic.Int64
+}
+
+// fields returns fs with normalized keys.
+func (n *normalizer) fields(fs []fields.Field) []fields.Field {
+	out := make([]fields.Field, 0, len(fs))
+	for _, f := range fs {
+		switch f.Kind() {
+		case fields.FieldKindInvalid:
+		case fields.FieldKindLazyFields, fields.FieldKindLazyValue:
+			fn := f.LazyFunc()
+			out = append(out, fields.LazyFields(func(ctx context.Context) []fields.Field { return n.fields(fn(ctx)) }))
+		case fields.FieldKindPrecomputed:
+			out = append(out, n.fields(f.Precomputed().Fields())...)
+		case fields.FieldKindDict:
+			out = append(out, fields.Dict(n.key(f.Key), n.fields(f.Fields())...))
+		default:
+			f.Key = n.key(f.Key)
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// key returns k normalized, from the cache if possible.
+func (n *normalizer) key(k string) string {
+	if v, ok := n.cache.Load(k); ok {
+		return v.(string)
+	}
+	v := normalizeKey(k, n.c)
+	if n.size.Load() < maxCachedKeys {
+		if _, loaded := n.cache.LoadOrStore(k, v); !loaded {
+			n.size.Add(1)
+		}
+	}
+	return v
+}
+
+// normalizeKey rewrites k to c, segment by dot-separated segment.
+func normalizeKey(k string, c KeyCase) string {
+	if c == LowerCase {
+		return strings.ToLower(k)
+	}
+	segments := strings.Split(k, ".")
+	for i, s := range segments {
+		words := splitWords(s)
+		for j, w := range words {
+			w = strings.ToLower(w)
+			if c == CamelCase && j > 0 {
+				r, size := utf8.DecodeRuneInString(w)
+				w = string(unicode.ToUpper(r)) + w[size:]
+			}
+			words[j] = w
+		}
+		if c == CamelCase {
+			segments[i] = strings.Join(words, "")
+		} else {
+			segments[i] = strings.Join(words, "_")
+		}
+	}
+	return strings.Join(segments, ".")
+}
+
+// splitWords splits s on underscores, hyphens, spaces and case changes. Acronyms stay whole: "HTTPStatus" splits into
+// "HTTP" and "Status".
+func splitWords(s string) []string {
+	var words []string
+	rs := []rune(s)
+	start := 0
+	flush := func(end int) {
+		if end > start {
+			words = append(words, string(rs[start:end]))
+		}
+		start = end
+	}
+	for i, r := range rs {
+		switch {
+		case r == '_' || r == '-' || r == ' ':
+			flush(i)
+			start = i + 1
+		case i > start && unicode.IsUpper(r):
+			prev := rs[i-1]
+			nextLower := i+1 < len(rs) && unicode.IsLower(rs[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				flush(i)
+			}
+		}
+	}
+	flush(len(rs))
+	return words
+}
@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/khinshankhan/logstox"
+	"github.com/khinshankhan/logstox/fields"
+)
+
+// keys returns the keys of fs, Dicts' as "key{sub keys}".
+func keys(fs []fields.Field) []string {
+	out := make([]string, 0, len(fs))
+	for _, f := range fs {
+		k := f.Key
+		if f.Kind() == fields.FieldKindDict {
+			k += "{" + strings.Join(keys(f.Fields()), " ") + "}"
+		}
+		out = append(out, k)
+	}
+	return out
+}
+
+func TestSortKeys(t *testing.T) {
+	lazy := fields.LazyFields(func(context.Context) []fields.Field { return nil })
+	e, ok := SortKeys()(logstox.Entry{Fields: []fields.Field{
+		lazy,
+		fields.String("b", "first"),
+		fields.Dict("d", fields.Int("z", 1), fields.Int("y", 2)),
+		fields.Nop(),
+		fields.Precompute(fields.String("c", "c"), fields.String("a", "a")),
+		fields.String("b", "second"),
+	}})
+
+	if want := []string{"a", "b", "b", "c", "d{y z}", ""}; !ok || !reflect.DeepEqual(keys(e.Fields), want) {
+		t.Fatalf("sorted into %v, want %v", keys(e.Fields), want)
+	}
+	if e.Fields[1].Str() != "first" || e.Fields[2].Str() != "second" {
+		t.Error("fields with the same key were reordered")
+	}
+	if e.Fields[5].Kind() != fields.FieldKindLazyFields {
+		t.Errorf("last field is %v, want the lazy one", e.Fields[5].Kind())
+	}
+}
+
+func TestNormalizeKeys(t *testing.T) {
+	tests := []struct {
+		key                 string
+		snake, camel, lower string
+	}{
+		{"httpStatus", "http_status", "httpStatus", "httpstatus"},
+		{"http_status", "http_status", "httpStatus", "http_status"},
+		{"HTTPStatus", "http_status", "httpStatus", "httpstatus"},
+		{"user-id", "user_id", "userId", "user-id"},
+		{"request id", "request_id", "requestId", "request id"},
+		{"retry2Count", "retry2_count", "retry2Count", "retry2count"},
+		{"http.requestMethod", "http.request_method", "http.requestMethod", "http.requestmethod"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			for c, want := range map[KeyCase]string{SnakeCase: tt.snake, CamelCase: tt.camel, LowerCase: tt.lower} {
+				if got := normalizeKey(tt.key, c); got != want {
+					t.Errorf("case %d: got %q, want %q", c, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestNormalizeKeysMiddleware(t *testing.T) {
+	mw := NormalizeKeys(SnakeCase)
+	lazy := fields.LazyFields(func(context.Context) []fields.Field { return []fields.Field{fields.Int("lazyKey", 1)} })
+	e, _ := mw(logstox.Entry{Fields: []fields.Field{
+		fields.Dict("httpRequest", fields.String("userAgent", "curl")),
+		fields.Precompute(fields.String("traceID", "t")),
+		fields.Nop(),
+		lazy,
+	}})
+
+	if want := []string{"http_request{user_agent}", "trace_id", ""}; !reflect.DeepEqual(keys(e.Fields), want) {
+		t.Fatalf("normalized into %v, want %v", keys(e.Fields), want)
+	}
+	if got := e.Fields[2].LazyFunc()(context.Background()); len(got) != 1 || got[0].Key != "lazy_key" {
+		t.Errorf("lazy fields evaluated to %v, want lazy_key", got)
+	}
+}
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"github.com/khinshankhan/logstox"
+)
+
+// Rule rewrites an entry's level to Level when When matches.
+type Rule struct {
+	When  Predicate
+	Level logstox.Level
+}
+
+// Relevel returns a middleware that upgrades or downgrades entries based on rules, useful when upstream libraries
+// log at the wrong level. Rules are checked in order and the first match wins; entries matching no rule pass
+// through untouched. For example:
+//
+//	middleware.Relevel(
+//		middleware.Rule{When: middleware.NumberAtLeast("status", 500), Level: logstox.ErrorLevel},
+//		middleware.Rule{When: middleware.AllOf(
+//			middleware.LevelAtMost(logstox.InfoLevel),
+//			middleware.HasKey(fields.ErrorKey),
+//		), Level: logstox.WarnLevel},
+//	)
+//
+// NOTE: escalating into PanicLevel or FatalLevel makes the backend panic/ exit, so prefer DPanicLevel at most.
+func Relevel(rules ...Rule) logstox.Middleware {
+	return func(e logstox.Entry) (logstox.Entry, bool) {
+		for _, r := range rules {
+			if r.When(e) {
+				e.Level = r.Level
+				break
+			}
+		}
+		return e, true
+	}
+}
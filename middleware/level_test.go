@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"testing"
+
+	"github.com/khinshankhan/logstox"
+	"github.com/khinshankhan/logstox/fields"
+)
+
+func TestRelevel(t *testing.T) {
+	mw := Relevel(
+		Rule{When: NumberAtLeast("status", 500), Level: logstox.ErrorLevel},
+		Rule{When: NumberAtLeast("status", 400), Level: logstox.WarnLevel},
+		Rule{When: HasKey("status"), Level: logstox.DebugLevel},
+	)
+	tests := []struct {
+		name string
+		fs   []fields.Field
+		want logstox.Level
+	}{
+		{"first match wins", []fields.Field{fields.Int("status", 503)}, logstox.ErrorLevel},
+		{"later rule", []fields.Field{fields.Int("status", 404)}, logstox.WarnLevel},
+		{"downgrade", []fields.Field{fields.Int("status", 200)}, logstox.DebugLevel},
+		{"no match", nil, logstox.NoticeLevel},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, ok := mw(logstox.Entry{Level: logstox.NoticeLevel, Fields: tt.fs})
+			if !ok || e.Level != tt.want {
+				t.Errorf("got %v (passed %v), want %v", e.Level, ok, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"github.com/khinshankhan/logstox"
+)
+
+// Keys of the fields Limit adds to the entries it cut down.
+const (
+	TruncatedKey     = logstox.TruncatedKey     // true, if a message or value was truncated
+	OverflowCountKey = logstox.OverflowCountKey // how many fields were dropped past LimitOptions.MaxFields
+)
+
+// LimitOptions bound the size of entries, see Limit. Zero values don't limit.
+type LimitOptions = logstox.Limits
+
+// Limit returns a middleware truncating oversized messages and field values, adding TruncatedKey to the entries it
+// truncated, and dropping fields past a maximum, adding OverflowCountKey, so an accidental megabyte payload or a
+// runaway loop adding fields can't clog the pipeline:
+//
+//	log = logstox.WithMiddleware(base, middleware.Limit(middleware.LimitOptions{
+//		MaxMessageBytes: 4 << 10,
+//		MaxFieldBytes:   16 << 10,
+//		MaxFields:       256,
+//	}))
+//
+// Lazy fields and precomputed bundles are left for the backend to evaluate, so they count for one field and their
+// values aren't checked; set the same limits on the backend's Options.Limits, which sees them evaluated, to bound
+// them too. See logstox.Limits.Apply for the rest.
+func Limit(o LimitOptions) logstox.Middleware {
+	return func(e logstox.Entry) (logstox.Entry, bool) {
+		e.Message, e.Fields = o.Apply(e.Message, e.Fields)
+		return e, true
+	}
+}
@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"github.com/khinshankhan/logstox"
+	"github.com/khinshankhan/logstox/fields"
+)
+
+// RouteMode is what Route does with the entries it sends to a route.
+type RouteMode uint8
+
+const (
+	// RouteOnly sends matching entries to their route only, dropping them from the main logger.
+	RouteOnly RouteMode = iota
+	// RouteCopy sends matching entries to their route and lets them through to the main logger too.
+	RouteCopy
+)
+
+// Route returns a middleware sending entries to the logger routes maps the value of their string field k to, so one
+// logger can feed destinations with different retention, eg audit entries to a dedicated sink:
+//
+//	log = logstox.WithMiddleware(log, middleware.Route("channel", map[string]logstox.Logger[fields.Field]{
+//		"audit": auditLog,
+//	}, middleware.RouteOnly))
+//
+// Entries without the field, or with a value routes doesn't have, go through to the main logger. Routed entries keep
+// their message, fields and logger name; the ones above ErrorLevel are logged at ErrorLevel on their route, leaving
+// the call site's panic or exit to the main logger. The field must be added to the middleware logger rather than
+// baked into the base logger, like with SortKeys.
+func Route(k string, routes map[string]logstox.Logger[fields.Field], mode RouteMode) logstox.Middleware {
+	// skip LogAt, emit, the middleware, the chain and the pipeline's log and level method, so file:line still points
+	// at the call site when used through logstox.WithMiddleware
+	skipped := make(map[string]logstox.Logger[fields.Field], len(routes))
+	for v, l := range routes {
+		skipped[v] = logstox.WithOptions(l, logstox.AddCallerSkip(6))
+	}
+	return func(e logstox.Entry) (logstox.Entry, bool) {
+		v, ok := stringField(e.Fields, k)
+		if !ok {
+			return e, true
+		}
+		l, ok := skipped[v]
+		if !ok {
+			return e, true
+		}
+		emit(l, e)
+		return e, mode == RouteCopy
+	}
+}
+
+// emit logs e to l, at ErrorLevel at most.
+func emit(l logstox.Logger[fields.Field], e logstox.Entry) {
+	if e.Name != "" {
+		l = l.Named(e.Name)
+	}
+	lvl := e.Level
+	if lvl.Compare(logstox.ErrorLevel) > 0 {
+		lvl = logstox.ErrorLevel
+	}
+	logstox.LogAt(l, lvl, e.Message, e.Fields...)
+}
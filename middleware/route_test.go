@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"testing"
+
+	"github.com/khinshankhan/logstox"
+	"github.com/khinshankhan/logstox/backend/memx"
+	"github.com/khinshankhan/logstox/fields"
+)
+
+func TestRoute(t *testing.T) {
+	cases := []struct {
+		name      string
+		mode      RouteMode
+		channel   []fields.Field
+		wantMain  int
+		wantAudit int
+	}{
+		{"only", RouteOnly, []fields.Field{fields.String("channel", "audit")}, 0, 1},
+		{"copy", RouteCopy, []fields.Field{fields.String("channel", "audit")}, 1, 1},
+		{"unknown route", RouteOnly, []fields.Field{fields.String("channel", "billing")}, 1, 0},
+		{"no field", RouteOnly, nil, 1, 0},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			main, audit := memx.NewRecorder(4), memx.NewRecorder(4)
+			log := logstox.WithMiddleware(
+				memx.Backend{Recorder: main}.New(logstox.Options[fields.Field]{}),
+				Route("channel", map[string]logstox.Logger[fields.Field]{
+					"audit": memx.Backend{Recorder: audit}.New(logstox.Options[fields.Field]{}),
+				}, c.mode),
+			)
+			log.Info("m", c.channel...)
+
+			if main.Len() != c.wantMain || audit.Len() != c.wantAudit {
+				t.Errorf("main got %d entries and audit %d, want %d and %d", main.Len(), audit.Len(), c.wantMain,
+					c.wantAudit)
+			}
+		})
+	}
+}
+
+func TestRouteLevels(t *testing.T) {
+	audit := memx.NewRecorder(8)
+	log := logstox.WithMiddleware(
+		memx.Backend{Recorder: memx.NewRecorder(8)}.New(logstox.Options[fields.Field]{}),
+		Route("channel", map[string]logstox.Logger[fields.Field]{
+			"audit": memx.Backend{Recorder: audit}.New(logstox.Options[fields.Field]{}),
+		}, RouteOnly),
+	).With(fields.String("channel", "audit")).Named("billing")
+
+	logstox.NoticerOf(log).Notice("notice")
+	log.DPanic("dpanic")
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("Panic didn't panic with its entry routed away")
+			}
+		}()
+		log.Panic("panic")
+	}()
+
+	want := []logstox.Level{logstox.NoticeLevel, logstox.ErrorLevel, logstox.ErrorLevel}
+	es := audit.Entries()
+	if len(es) != len(want) {
+		t.Fatalf("audit got %d entries, want %d", len(es), len(want))
+	}
+	for i, e := range es {
+		if e.Level != want[i] || e.Name != "billing" {
+			t.Errorf("%s routed at %v named %q, want %v named %q", e.Message, e.Level, e.Name, want[i], "billing")
+		}
+	}
+}
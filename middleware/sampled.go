@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/khinshankhan/logstox"
+	"github.com/khinshankhan/logstox/contextx"
+)
+
+// SampledDebug returns a middleware keeping Debug entries only when sampled reports the trace active in their
+// context is sampled, or the context was flagged by contextx.WithForceDebug, so debug detail exists for exactly the
+// requests whose traces get looked at. Entries above Debug always pass, and Debug entries of loggers not bound to a
+// context (see logstox.WithContext) are dropped. For OpenTelemetry, otelx.Sampled reports the sampling decision:
+//
+//	log = logstox.WithMiddleware(base, middleware.SampledDebug(otelx.Sampled))
+//	...
+//	logstox.WithContext(log, r.Context()).Debug("cache lookup", fields.String("key", k))
+//
+// The base logger must record Debug entries for there to be any to keep.
+func SampledDebug(sampled func(context.Context) bool) logstox.Middleware {
+	return func(e logstox.Entry) (logstox.Entry, bool) {
+		if e.Level.Compare(logstox.DebugLevel) > 0 {
+			return e, true
+		}
+		if e.Context == nil {
+			return e, false
+		}
+		return e, contextx.ForceDebug(e.Context) || sampled(e.Context)
+	}
+}
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"context"
+	"testing"
+
+	"github.com/khinshankhan/logstox"
+	"github.com/khinshankhan/logstox/contextx"
+)
+
+type sampledKey struct{}
+
+func TestSampledDebug(t *testing.T) {
+	mw := SampledDebug(func(ctx context.Context) bool { return ctx.Value(sampledKey{}) != nil })
+	sampled := context.WithValue(context.Background(), sampledKey{}, true)
+	tests := []struct {
+		name  string
+		level logstox.Level
+		ctx   context.Context
+		want  bool
+	}{
+		{"info unbound", logstox.InfoLevel, nil, true},
+		{"notice unsampled", logstox.NoticeLevel, context.Background(), true},
+		{"debug unbound", logstox.DebugLevel, nil, false},
+		{"debug unsampled", logstox.DebugLevel, context.Background(), false},
+		{"debug sampled", logstox.DebugLevel, sampled, true},
+		{"debug forced", logstox.DebugLevel, contextx.WithForceDebug(context.Background()), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, ok := mw(logstox.Entry{Level: tt.level, Context: tt.ctx}); ok != tt.want {
+				t.Errorf("passed %v, want %v", ok, tt.want)
+			}
+		})
+	}
+}
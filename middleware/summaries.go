@@ -0,0 +1,196 @@
+package middleware
+
+import (
+	"sync"
+	"time"
+
+	"github.com/khinshankhan/logstox"
+	"github.com/khinshankhan/logstox/fields"
+)
+
+// Keys of the roll-up entries logged by Summaries.
+const (
+	SuppressedKey        = "suppressed"         // how many entries were suppressed
+	SuppressedMessageKey = "suppressed_message" // their message
+	SuppressedLoggerKey  = "suppressed_logger"  // the name of their logger, if they have one
+	SuppressedFirstKey   = "suppressed_first"   // when the first one was
+	SuppressedLastKey    = "suppressed_last"    // when the last one was
+)
+
+// Defaults used for zero SummaryOptions.
+const (
+	DefaultSummaryInterval = time.Minute
+	DefaultSummaryKeys     = 1000
+)
+
+// SummaryOptions tune Summaries.
+type SummaryOptions struct {
+	// Interval is how often roll-ups are logged (DefaultSummaryInterval if <= 0).
+	Interval time.Duration
+	// MaxKeys bounds how many (name, message) pairs are tracked per interval (DefaultSummaryKeys if <= 0). Entries
+	// past that are rolled up together, without a message.
+	MaxKeys int
+}
+
+// Summaries counts entries that sampling, rate limiting, deduplication or filtering suppress, and periodically logs a
+// roll-up per (logger name, message) with how many were suppressed and when the first and last were, so operators
+// know suppression happened and how much:
+//
+//	sums := middleware.NewSummaries(base, middleware.SummaryOptions{})
+//	defer sums.Close()
+//	log = logstox.WithMiddleware(base, sums.Wrap(budget.Middleware()))
+//
+// Wrap counts the entries a middleware drops; Record takes them from anything else. Of the bundled backends, only zapx
+// samples, so zapx.Backend.OnSampled is the only backend hook to hand to Record; memx, streamx and apexx drop nothing
+// past the level check. Roll-ups are logged to the logger given to NewSummaries as is, with the name of the suppressed
+// entries' logger under SuppressedLoggerKey, at the highest level among them (capped at Error, so a roll-up can't
+// panic or exit). Give it a logger that bypasses the suppression, or roll-ups may be suppressed themselves.
+// Summaries is safe for concurrent use.
+type Summaries struct {
+	log     logstox.Logger[fields.Field]
+	maxKeys int
+
+	mu       sync.Mutex
+	counts   map[summaryKey]*summary
+	overflow summary // entries past MaxKeys
+
+	stop chan struct{}
+	done chan struct{}
+	once sync.Once
+}
+
+// summaryKey identifies the entries rolled up together.
+type summaryKey struct {
+	name, message string
+}
+
+// summary is the roll-up of suppressed entries.
+type summary struct {
+	n           int
+	first, last time.Time
+	level       logstox.Level
+}
+
+// add counts e into the summary.
+func (s *summary) add(e logstox.Entry) {
+	t := e.Time
+	if t.IsZero() {
+		t = time.Now()
+	}
+	if s.n == 0 || t.Before(s.first) {
+		s.first = t
+	}
+	if s.n == 0 || t.After(s.last) {
+		s.last = t
+	}
+	if s.n == 0 || e.Level.Compare(s.level) > 0 {
+		s.level = e.Level
+	}
+	s.n++
+}
+
+// NewSummaries returns Summaries logging roll-ups to log. Close it to stop the background logging and log what's
+// pending.
+func NewSummaries(log logstox.Logger[fields.Field], o SummaryOptions) *Summaries {
+	if o.Interval <= 0 {
+		o.Interval = DefaultSummaryInterval
+	}
+	if o.MaxKeys <= 0 {
+		o.MaxKeys = DefaultSummaryKeys
+	}
+	s := &Summaries{
+		log:     log,
+		maxKeys: o.MaxKeys,
+		counts:  make(map[summaryKey]*summary),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go s.loop(o.Interval)
+	return s
+}
+
+// loop flushes every interval until Close.
+func (s *Summaries) loop(interval time.Duration) {
+	defer close(s.done)
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			s.Flush()
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+// Wrap returns mw counting the entries it drops.
+func (s *Summaries) Wrap(mw logstox.Middleware) logstox.Middleware {
+	return func(e logstox.Entry) (logstox.Entry, bool) {
+		out, ok := mw(e)
+		if !ok {
+			s.Record(e)
+		}
+		return out, ok
+	}
+}
+
+// Record counts e as suppressed.
+func (s *Summaries) Record(e logstox.Entry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	k := summaryKey{e.Name, e.Message}
+	sum, ok := s.counts[k]
+	if !ok {
+		if len(s.counts) >= s.maxKeys {
+			s.overflow.add(e)
+			return
+		}
+		sum = &summary{}
+		s.counts[k] = sum
+	}
+	sum.add(e)
+}
+
+// Flush logs the roll-ups of the entries suppressed since the last flush, and starts counting over.
+func (s *Summaries) Flush() {
+	s.mu.Lock()
+	counts, overflow := s.counts, s.overflow
+	s.counts, s.overflow = make(map[summaryKey]*summary), summary{}
+	s.mu.Unlock()
+
+	for k, sum := range counts {
+		s.emit(k.name, sum, fields.String(SuppressedMessageKey, k.message))
+	}
+	if overflow.n > 0 {
+		s.emit("", &overflow)
+	}
+}
+
+// emit logs the roll-up sum for the logger name.
+func (s *Summaries) emit(name string, sum *summary, extra ...fields.Field) {
+	// not s.log.Named(name): name is a full name, often starting with s.log's own
+	log := s.log
+	if name != "" {
+		extra = append(extra, fields.String(SuppressedLoggerKey, name))
+	}
+	fs := append(extra,
+		fields.Int(SuppressedKey, sum.n),
+		fields.TimeField(SuppressedFirstKey, sum.first),
+		fields.TimeField(SuppressedLastKey, sum.last),
+	)
+	lvl := sum.level
+	if lvl.Compare(logstox.ErrorLevel) > 0 {
+		lvl = logstox.ErrorLevel
+	}
+	logstox.LogAt(log, lvl, "log entries suppressed", fs...)
+}
+
+// Close stops the background logging and logs the pending roll-ups. Entries recorded after Close are only logged by
+// Flush.
+func (s *Summaries) Close() error {
+	s.once.Do(func() { close(s.stop) })
+	<-s.done
+	s.Flush()
+	return nil
+}
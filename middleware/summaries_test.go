@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"testing"
+	"time"
+
+	"github.com/khinshankhan/logstox"
+	"github.com/khinshankhan/logstox/backend/memx"
+	"github.com/khinshankhan/logstox/fields"
+)
+
+// field returns the field of e keyed k.
+func field(e logstox.Entry, k string) (fields.Field, bool) {
+	for _, f := range e.Fields {
+		if f.Key == k {
+			return f, true
+		}
+	}
+	return fields.Field{}, false
+}
+
+func TestSummaries(t *testing.T) {
+	rec := memx.NewRecorder(8)
+	sums := NewSummaries(memx.Backend{Recorder: rec}.New(logstox.Options[fields.Field]{Level: logstox.DebugLevel}),
+		SummaryOptions{Interval: time.Hour})
+	defer sums.Close()
+	dropAll := sums.Wrap(func(e logstox.Entry) (logstox.Entry, bool) { return e, false })
+	base := memx.Backend{Recorder: memx.NewRecorder(8)}.New(logstox.Options[fields.Field]{Level: logstox.DebugLevel})
+	log := logstox.WithMiddleware(base, dropAll).Named("db")
+
+	log.Info("slow query")
+	log.Warn("slow query")
+	log.DPanic("slow query")
+	log.Debug("connected")
+	sums.Flush()
+
+	cases := map[string]struct {
+		n     int64
+		level logstox.Level
+	}{
+		"slow query": {3, logstox.ErrorLevel}, // DPanic, capped
+		"connected":  {1, logstox.DebugLevel},
+	}
+	es := rec.Entries()
+	if len(es) != len(cases) {
+		t.Fatalf("got %d roll-ups, want %d", len(es), len(cases))
+	}
+	for _, e := range es {
+		msg, _ := field(e, SuppressedMessageKey)
+		want, ok := cases[msg.Str()]
+		if !ok {
+			t.Errorf("roll-up for unexpected message %q", msg.Str())
+			continue
+		}
+		n, _ := field(e, SuppressedKey)
+		name, _ := field(e, SuppressedLoggerKey)
+		if n.Int64() != want.n || e.Level != want.level || name.Str() != "db" {
+			t.Errorf("%q rolled up %d at %v for %q, want %d at %v for %q", msg.Str(), n.Int64(), e.Level, name.Str(),
+				want.n, want.level, "db")
+		}
+	}
+
+	rec.Reset()
+	sums.Flush()
+	if rec.Len() != 0 {
+		t.Errorf("a second Flush logged %d roll-ups, want 0", rec.Len())
+	}
+}
+
+func TestSummariesOverflow(t *testing.T) {
+	rec := memx.NewRecorder(8)
+	sums := NewSummaries(memx.Backend{Recorder: rec}.New(logstox.Options[fields.Field]{}),
+		SummaryOptions{Interval: time.Hour, MaxKeys: 1})
+	sums.Record(logstox.Entry{Message: "a", Level: logstox.InfoLevel})
+	sums.Record(logstox.Entry{Message: "b", Level: logstox.NoticeLevel})
+	sums.Record(logstox.Entry{Message: "c", Level: logstox.InfoLevel})
+	if err := sums.Close(); err != nil { // flushes what's pending
+		t.Fatal(err)
+	}
+
+	es := rec.Entries()
+	if len(es) != 2 {
+		t.Fatalf("got %d roll-ups, want the tracked key's and the overflow's", len(es))
+	}
+	var overflow logstox.Entry
+	for _, e := range es {
+		if _, ok := field(e, SuppressedMessageKey); !ok {
+			overflow = e
+		}
+	}
+	if n, _ := field(overflow, SuppressedKey); n.Int64() != 2 || overflow.Level != logstox.NoticeLevel {
+		t.Errorf("overflow rolled up %d at %v, want 2 at notice", n.Int64(), overflow.Level)
+	}
+}
@@ -0,0 +1,152 @@
+package logstox_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/khinshankhan/logstox"
+	"github.com/khinshankhan/logstox/backend/memx"
+	"github.com/khinshankhan/logstox/fields"
+)
+
+// tag returns a middleware appending name to *order and passing entries through if pass is set.
+func tag(order *[]string, name string, pass bool) logstox.Middleware {
+	return func(e logstox.Entry) (logstox.Entry, bool) {
+		*order = append(*order, name)
+		return e, pass
+	}
+}
+
+func TestChain(t *testing.T) {
+	tests := []struct {
+		name      string
+		mws       func(order *[]string) []logstox.Middleware
+		wantOrder []string
+		wantOK    bool
+	}{
+		{"empty", func(*[]string) []logstox.Middleware { return nil }, nil, true},
+		{
+			"in order",
+			func(o *[]string) []logstox.Middleware {
+				return []logstox.Middleware{tag(o, "a", true), tag(o, "b", true)}
+			},
+			[]string{"a", "b"}, true,
+		},
+		{
+			"nil skipped",
+			func(o *[]string) []logstox.Middleware { return []logstox.Middleware{nil, tag(o, "a", true), nil} },
+			[]string{"a"}, true,
+		},
+		{
+			"short-circuits on drop",
+			func(o *[]string) []logstox.Middleware {
+				return []logstox.Middleware{tag(o, "a", true), tag(o, "b", false), tag(o, "c", true)}
+			},
+			[]string{"a", "b"}, false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var order []string
+			_, ok := logstox.Chain(tt.mws(&order)...)(logstox.Entry{Message: "m"})
+			if ok != tt.wantOK || !reflect.DeepEqual(order, tt.wantOrder) {
+				t.Errorf("ran %v and passed %v, want %v and %v", order, ok, tt.wantOrder, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestChainCopiesMiddleware(t *testing.T) {
+	var order []string
+	mws := []logstox.Middleware{tag(&order, "a", true)}
+	chain := logstox.Chain(mws...)
+	mws[0] = tag(&order, "b", true)
+	chain(logstox.Entry{})
+	if !reflect.DeepEqual(order, []string{"a"}) {
+		t.Errorf("ran %v, want the middleware given to Chain", order)
+	}
+}
+
+func TestWithMiddleware(t *testing.T) {
+	rec := memx.NewRecorder(4)
+	base := memx.Backend{Recorder: rec}.New(logstox.Options[fields.Field]{})
+	var seen []string // context keys the middleware saw
+	log := logstox.WithMiddleware(base,
+		func(e logstox.Entry) (logstox.Entry, bool) {
+			for _, f := range e.Fields {
+				seen = append(seen, f.Key)
+			}
+			return e, e.Message != "drop"
+		},
+		func(e logstox.Entry) (logstox.Entry, bool) {
+			e.Message = "rewritten " + e.Message
+			e.Fields = append(e.Fields, fields.Bool("enriched", true))
+			if e.Message == "rewritten downgrade" {
+				e.Level = logstox.InfoLevel
+			}
+			return e, true
+		},
+	).With(fields.String("request", "r1"))
+
+	log.Info("kept", fields.Int("n", 1))
+	log.Info("drop")
+	log.Error("downgrade")
+	log.Debug("disabled")
+
+	if want := []string{"request", "n", "request", "request"}; !reflect.DeepEqual(seen, want) {
+		t.Errorf("middleware saw keys %v, want %v: context first, disabled entries skipped", seen, want)
+	}
+	es := rec.Entries()
+	if len(es) != 2 {
+		t.Fatalf("recorded %d entries, want 2", len(es))
+	}
+	if e := es[0]; e.Message != "rewritten kept" || len(e.Fields) != 3 || e.Fields[2].Key != "enriched" {
+		t.Errorf("recorded %q with %v, want the rewritten message and fields", e.Message, e.Fields)
+	}
+	if e := es[1]; e.Level != logstox.InfoLevel {
+		t.Errorf("downgraded entry recorded at %v, want info", e.Level)
+	}
+	if fs := logstox.FieldsOf(base); len(fs) != 0 {
+		t.Errorf("With pushed %v down to the base logger, want it held by the pipeline", fs)
+	}
+}
+
+func TestWithMiddlewarePropagates(t *testing.T) {
+	rec := memx.NewRecorder(4)
+	var names []string
+	parent := logstox.WithMiddleware(memx.Backend{Recorder: rec}.New(logstox.Options[fields.Field]{}),
+		func(e logstox.Entry) (logstox.Entry, bool) {
+			names = append(names, e.Name)
+			return e, true
+		},
+	)
+	child := parent.Named("db").With(fields.String("a", "a"))
+	child.With(fields.String("b", "b")).Info("grandchild")
+	parent.Info("parent")
+
+	if want := []string{"db", ""}; !reflect.DeepEqual(names, want) {
+		t.Errorf("middleware saw names %v, want %v", names, want)
+	}
+	es := rec.Entries()
+	if len(es) != 2 || len(es[0].Fields) != 2 || len(es[1].Fields) != 0 {
+		t.Fatalf("recorded %v, want the grandchild with both context fields and the parent with none", es)
+	}
+	if es[0].Name != "db" {
+		t.Errorf("grandchild recorded as %q, want the base logger named too", es[0].Name)
+	}
+}
+
+func TestWithMiddlewareKeepsPanic(t *testing.T) {
+	rec := memx.NewRecorder(1)
+	log := logstox.WithMiddleware(memx.Backend{Recorder: rec}.New(logstox.Options[fields.Field]{}),
+		func(e logstox.Entry) (logstox.Entry, bool) { return e, false })
+	defer func() {
+		if recover() == nil {
+			t.Error("Panic didn't panic with its entry dropped")
+		}
+		if rec.Len() != 0 {
+			t.Errorf("recorded %d entries, want the dropped one not written", rec.Len())
+		}
+	}()
+	log.Panic("dropped")
+}
@@ -0,0 +1,42 @@
+package logstox
+
+import "strings"
+
+// Multiline selects how messages spanning several lines are written, see Options.Multiline.
+type Multiline uint8
+
+const (
+	// MultilineKeep leaves messages as they are (default): console encodings write them verbatim, for people reading
+	// a terminal, and JSON encodings escape line breaks, as they must.
+	MultilineKeep Multiline = iota
+	// MultilineEscape writes line breaks in messages as the escapes \n and \r, so every entry stays on a single line
+	// whatever the encoding. JSON encodings escape them anyway.
+	MultilineEscape
+	// MultilineFold writes the first line as the message and the rest as a string field under DetailKey, so messages
+	// stay short summaries that group and search well, eg for panics or SQL statements.
+	MultilineFold
+)
+
+// DetailKey is the key of the field MultilineFold moves all but the first line of a message to.
+const DetailKey = "detail"
+
+// Apply applies m to the message msg, console telling whether it's written by a console encoding that doesn't escape
+// line breaks itself. It returns the message to write and, when folding, the detail to write under DetailKey, empty
+// if there's none.
+func (m Multiline) Apply(msg string, console bool) (message, detail string) {
+	if m == MultilineKeep || m == MultilineEscape && !console {
+		return msg, ""
+	}
+	i := strings.IndexAny(msg, "\r\n")
+	if i < 0 {
+		return msg, ""
+	}
+	if m == MultilineFold {
+		rest := strings.TrimLeft(msg[i:], "\r\n")
+		return msg[:i], strings.TrimRight(rest, "\r\n")
+	}
+	return lineEscaper.Replace(msg), ""
+}
+
+// lineEscaper escapes line breaks for MultilineEscape.
+var lineEscaper = strings.NewReplacer("\n", `\n`, "\r", `\r`)
@@ -0,0 +1,28 @@
+package logstox
+
+import "testing"
+
+func TestMultilineApply(t *testing.T) {
+	tests := []struct {
+		name        string
+		m           Multiline
+		console     bool
+		msg         string
+		wantMessage string
+		wantDetail  string
+	}{
+		{"zero value keeps", 0, true, "a\nb", "a\nb", ""},
+		{"escape console", MultilineEscape, true, "a\r\nb", `a\r\nb`, ""},
+		{"escape json", MultilineEscape, false, "a\nb", "a\nb", ""},
+		{"fold", MultilineFold, false, "a\n\nb\nc\n", "a", "b\nc"},
+		{"fold single line", MultilineFold, true, "a", "a", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message, detail := tt.m.Apply(tt.msg, tt.console)
+			if message != tt.wantMessage || detail != tt.wantDetail {
+				t.Errorf("Apply(%q) = %q, %q, want %q, %q", tt.msg, message, detail, tt.wantMessage, tt.wantDetail)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package logstox
+
+import (
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/khinshankhan/logstox/fields"
+)
+
+// Keys of the audit entries logged when a mute starts and ends.
+const (
+	MuteIDKey         = "mute_id"         // identifies the mute across its entries
+	MuteUntilKey      = "mute_until"      // when the mute expires
+	MuteSuppressedKey = "mute_suppressed" // how many entries it suppressed, on the ending entry
+)
+
+// mute is a matcher registered by Mute.
+type mute struct {
+	id         uint64
+	match      func(Entry) bool
+	until      time.Time
+	suppressed atomic.Int64
+	timer      *time.Timer
+	once       sync.Once
+}
+
+// mutes holds the active mutes, copied on write as they're read for every entry. mu also orders the audit entries.
+var mutes struct {
+	mu     sync.Mutex
+	active atomic.Pointer[[]*mute]
+	lastID uint64
+	log    atomic.Pointer[Logger[fields.Field]]
+}
+
+// SetMuteLogger sets where the audit entries of Mute go, process-wide. Until it's called mutes aren't audited. Give
+// it a logger that isn't subject to the mutes, eg one not built with WithMiddleware.
+func SetMuteLogger(l Logger[fields.Field]) {
+	mutes.log.Store(&l)
+}
+
+// Mute suppresses entries that match reports true for until the given time, in every logger built with
+// WithMiddleware, eg expected errors during a planned failover:
+//
+//	unmute := logstox.Mute(func(e logstox.Entry) bool {
+//		return e.Name == "db" && e.Level.Compare(logstox.ErrorLevel) <= 0
+//	}, time.Now().Add(15*time.Minute))
+//	defer unmute()
+//
+// Entries are matched before any middleware runs, and Panic and Fatal keep their semantics even if the entry is
+// muted. The returned function ends the mute early; calling it again, or after the mute expired, does nothing.
+//
+// A WarnLevel audit entry is logged to the logger set by SetMuteLogger when muting starts and when it ends, with the
+// mute's MuteIDKey, and MuteUntilKey or MuteSuppressedKey respectively. match must be safe for concurrent use.
+func Mute(match func(Entry) bool, until time.Time) (unmute func()) {
+	mutes.mu.Lock()
+	mutes.lastID++
+	m := &mute{id: mutes.lastID, match: match, until: until}
+	var active []*mute
+	if p := mutes.active.Load(); p != nil {
+		active = *p
+	}
+	active = append(slices.Clip(active), m)
+	mutes.active.Store(&active)
+	auditMute("muting entries", fields.Uint64(MuteIDKey, m.id), fields.TimeField(MuteUntilKey, until))
+	m.timer = time.AfterFunc(time.Until(until), m.end) // end waits for the lock, so it sees the timer
+	mutes.mu.Unlock()
+	return m.end
+}
+
+// end unregisters the mute and audits it, once.
+func (m *mute) end() {
+	m.once.Do(func() {
+		mutes.mu.Lock()
+		m.timer.Stop()
+		if p := mutes.active.Load(); p != nil {
+			active := slices.DeleteFunc(slices.Clone(*p), func(o *mute) bool { return o == m })
+			if len(active) == 0 {
+				mutes.active.Store(nil)
+			} else {
+				mutes.active.Store(&active)
+			}
+		}
+		auditMute("unmuting entries", fields.Uint64(MuteIDKey, m.id), fields.Int64(MuteSuppressedKey, m.suppressed.Load()))
+		mutes.mu.Unlock()
+	})
+}
+
+// auditMute logs an audit entry to the mute logger, if there's one.
+func auditMute(msg string, fs ...fields.Field) {
+	if l := mutes.log.Load(); l != nil && *l != nil {
+		(*l).Warn(msg, fs...)
+	}
+}
+
+// muted reports whether an active mute matches e, counting e as suppressed by it.
+func muted(e Entry) bool {
+	p := mutes.active.Load()
+	if p == nil {
+		return false
+	}
+	for _, m := range *p {
+		if e.Time.Before(m.until) && m.match(e) {
+			m.suppressed.Add(1)
+			return true
+		}
+	}
+	return false
+}
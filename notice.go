@@ -0,0 +1,43 @@
+package logstox
+
+// NoticeName is the name NoticeLevel is written and parsed as.
+const NoticeName = "notice"
+
+// NoticeKey is the field backends without a notice level of their own tag notices with: they write them at Info, with
+// NoticeKey set to true so they can still be told apart.
+const NoticeKey = "notice"
+
+// Noticer is an optional extension for loggers that can write entries at NoticeLevel. Logger has no Notice method, so
+// loggers predating NoticeLevel still implement it; the first-party backends and wrappers implement Noticer too.
+type Noticer[FT any] interface {
+	Notice(string, ...FT)
+}
+
+// NoticerOf returns l as a Noticer:
+//
+//	logstox.NoticerOf(log).Notice("config reloaded", fields.String("path", path))
+//
+// Loggers that don't implement Noticer are wrapped so Notice logs at Info instead, when NoticeLevel is enabled. Keep the
+// result around rather than calling NoticerOf per entry, the wrapper is built on every call.
+func NoticerOf[FT any](l Logger[FT]) Noticer[FT] {
+	if n, ok := l.(Noticer[FT]); ok {
+		return n
+	}
+	// skip the wrapper's own Notice so file:line points at the caller
+	return noticeInfo[FT]{WithOptions(l, AddCallerSkip(1))}
+}
+
+// noticeInfo is the fallback Noticer returned by NoticerOf, logging notices at Info.
+type noticeInfo[FT any] struct {
+	base Logger[FT]
+}
+
+// Interface satisfaction (compile-time assertions).
+var _ Noticer[any] = noticeInfo[any]{}
+
+// Notice logs m at Info if NoticeLevel is enabled.
+func (n noticeInfo[FT]) Notice(m string, f ...FT) {
+	if Enabled(n.base, NoticeLevel) {
+		n.base.Info(m, f...)
+	}
+}
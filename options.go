@@ -0,0 +1,62 @@
+package logstox
+
+// Option is a post-construction adjustment applied via WithOptions.
+type Option func(*Adjustments)
+
+// Adjustments collect the Options passed to WithOptions. Backends implementing OptionsApplier read them; zero values
+// leave the corresponding setting unchanged.
+type Adjustments struct {
+	AddSource  *bool // toggle file:line, nil leaves it as is
+	CallerSkip int   // extra frames to skip, added to the current skip
+	// ErrorHandler replaces where the backend reports its own failures (eg a write error), which by default go to
+	// stderr or are dropped.
+	ErrorHandler func(error)
+	// Hooks are called with every entry that's written. Fields aren't populated: hooks are for counting and alerting,
+	// not re-encoding.
+	Hooks []func(Entry) error
+}
+
+// AddSource toggles including file:line in entries.
+func AddSource(enabled bool) Option {
+	return func(a *Adjustments) { a.AddSource = &enabled }
+}
+
+// AddCallerSkip skips n more stack frames when reporting the caller, for libraries wrapping a logger they were given.
+func AddCallerSkip(n int) Option {
+	return func(a *Adjustments) { a.CallerSkip += n }
+}
+
+// OnError swaps the handler for the backend's own failures.
+func OnError(fn func(error)) Option {
+	return func(a *Adjustments) { a.ErrorHandler = fn }
+}
+
+// Hooks attaches per-entry callbacks (eg metrics), in addition to any already attached.
+func Hooks(fns ...func(Entry) error) Option {
+	return func(a *Adjustments) { a.Hooks = append(a.Hooks, fns...) }
+}
+
+// Collect applies opts to a fresh Adjustments, for backends implementing OptionsApplier.
+func Collect(opts ...Option) Adjustments {
+	var a Adjustments
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&a)
+		}
+	}
+	return a
+}
+
+// OptionsApplier is an optional extension for loggers that can be adjusted after construction.
+type OptionsApplier[FT any] interface {
+	WithOptions(...Option) Logger[FT]
+}
+
+// WithOptions returns a child of l with opts applied, so libraries receiving a logger can adapt it without access
+// to the Backend that built it. Loggers that don't implement OptionsApplier are returned unchanged.
+func WithOptions[FT any](l Logger[FT], opts ...Option) Logger[FT] {
+	if oa, ok := l.(OptionsApplier[FT]); ok {
+		return oa.WithOptions(opts...)
+	}
+	return l
+}
@@ -0,0 +1,75 @@
+package otelx
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/khinshankhan/logstox"
+	"github.com/khinshankhan/logstox/fields"
+)
+
+// ResourceFields converts the attributes of an OpenTelemetry Resource into fields under their attribute keys
+// (service.name, deployment.environment, host.name, ...), so logs and traces from the same process carry the same
+// attributes:
+//
+//	res, err := resource.New(ctx, resource.WithFromEnv(), resource.WithHost())
+//	...
+//	log = log.With(fields.Precompute(otelx.ResourceFields(res)...))
+//
+// Attributes come in the Resource's (sorted) key order. A nil Resource has none.
+func ResourceFields(r *resource.Resource) []fields.Field {
+	if r == nil {
+		return nil
+	}
+	fs := make([]fields.Field, 0, r.Len())
+	for iter := r.Iter(); iter.Next(); {
+		fs = append(fs, Field(iter.Attribute()))
+	}
+	return fs
+}
+
+// WithResource returns a copy of o with r's attributes added to its default fields, converted by to (eg zapx.ToZap,
+// or nothing for fields.Field backends), so every logger the Backend builds carries them.
+func WithResource[FT any](o logstox.Options[FT], r *resource.Resource, to func(fields.Field) FT) logstox.Options[FT] {
+	rfs := ResourceFields(r)
+	out := make([]FT, 0, len(o.Fields)+len(rfs))
+	out = append(out, o.Fields...)
+	for _, f := range rfs {
+		out = append(out, to(f))
+	}
+	o.Fields = out
+	return o
+}
+
+// Field converts an OpenTelemetry attribute into a field.
+func Field(kv attribute.KeyValue) fields.Field {
+	k := string(kv.Key)
+	switch kv.Value.Type() {
+	case attribute.BOOL:
+		return fields.Bool(k, kv.Value.AsBool())
+	case attribute.INT64:
+		return fields.Int64(k, kv.Value.AsInt64())
+	case attribute.FLOAT64:
+		return fields.Float64(k, kv.Value.AsFloat64())
+	case attribute.STRING:
+		return fields.String(k, kv.Value.AsString())
+	case attribute.BOOLSLICE:
+		return fields.Bools(k, kv.Value.AsBoolSlice())
+	case attribute.INT64SLICE:
+		return fields.Int64s(k, kv.Value.AsInt64Slice())
+	case attribute.FLOAT64SLICE:
+		return fields.Float64s(k, kv.Value.AsFloat64Slice())
+	case attribute.STRINGSLICE:
+		return fields.Strings(k, kv.Value.AsStringSlice())
+	default:
+		return fields.String(k, kv.Value.Emit())
+	}
+}
+
+// Sampled reports whether the span context active in ctx is sampled, eg for middleware.SampledDebug.
+func Sampled(ctx context.Context) bool {
+	return trace.SpanContextFromContext(ctx).IsSampled()
+}
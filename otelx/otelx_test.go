@@ -0,0 +1,120 @@
+package otelx
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/khinshankhan/logstox"
+	"github.com/khinshankhan/logstox/fields"
+)
+
+func TestField(t *testing.T) {
+	tests := []struct {
+		kv   attribute.KeyValue
+		kind fields.FieldKind
+		want any
+	}{
+		{attribute.Bool("b", true), fields.FieldKindBool, true},
+		{attribute.Int64("i", -3), fields.FieldKindInt64, int64(-3)},
+		{attribute.Int("int", 7), fields.FieldKindInt64, int64(7)},
+		{attribute.Float64("f", 1.5), fields.FieldKindFloat64, 1.5},
+		{attribute.String("s", "api"), fields.FieldKindString, "api"},
+		{attribute.BoolSlice("bs", []bool{true, false}), fields.FieldKindBools, []bool{true, false}},
+		{attribute.Int64Slice("is", []int64{1, 2}), fields.FieldKindInt64s, []int64{1, 2}},
+		{attribute.Float64Slice("fs", []float64{0.5}), fields.FieldKindFloat64s, []float64{0.5}},
+		{attribute.StringSlice("ss", []string{"a", "b"}), fields.FieldKindStrings, []string{"a", "b"}},
+		{attribute.KeyValue{Key: "invalid"}, fields.FieldKindString, "unknown"}, // as Value.Emit writes it
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.kv.Key), func(t *testing.T) {
+			f := Field(tt.kv)
+			if f.Key != string(tt.kv.Key) || f.Kind() != tt.kind {
+				t.Fatalf("Field = %s %v, want %s %v", f.Key, f.Kind(), tt.kv.Key, tt.kind)
+			}
+			if got := f.Boxed(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("value %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResourceFields(t *testing.T) {
+	res := resource.NewSchemaless(
+		attribute.String("service.name", "api"),
+		attribute.String("deployment.environment", "prod"),
+		attribute.Int("service.instance.count", 3),
+	)
+	fs := ResourceFields(res)
+	var got []string
+	for _, f := range fs {
+		got = append(got, fmt.Sprintf("%s=%v", f.Key, f.Boxed()))
+	}
+	want := []string{"deployment.environment=prod", "service.instance.count=3", "service.name=api"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ResourceFields = %v, want %v in key order", got, want)
+	}
+	if fs := ResourceFields(nil); fs != nil {
+		t.Errorf("ResourceFields(nil) = %v, want nil", fs)
+	}
+}
+
+func TestWithResource(t *testing.T) {
+	res := resource.NewSchemaless(attribute.String("service.name", "api"))
+	o := logstox.Options[string]{Fields: []string{"default"}}
+	got := WithResource(o, res, func(f fields.Field) string { return f.Key + "=" + f.Str() })
+
+	if want := []string{"default", "service.name=api"}; !reflect.DeepEqual(got.Fields, want) {
+		t.Errorf("Fields = %v, want %v", got.Fields, want)
+	}
+	if len(o.Fields) != 1 {
+		t.Errorf("WithResource changed the original Options: %v", o.Fields)
+	}
+}
+
+// TestSeverityNumbers checks the levels written with logstox.LevelFormatOTel are the severity numbers of the
+// OpenTelemetry log data model, so logs and the OTel SDK agree on them.
+func TestSeverityNumbers(t *testing.T) {
+	tests := []struct {
+		level    logstox.Level
+		severity int // SeverityNumber, see https://opentelemetry.io/docs/specs/otel/logs/data-model/#field-severitynumber
+	}{
+		{logstox.DebugLevel, 5},   // DEBUG
+		{logstox.InfoLevel, 9},    // INFO
+		{logstox.NoticeLevel, 10}, // INFO2
+		{logstox.WarnLevel, 13},   // WARN
+		{logstox.ErrorLevel, 17},  // ERROR
+		{logstox.DPanicLevel, 18}, // ERROR2
+		{logstox.PanicLevel, 19},  // ERROR3
+		{logstox.FatalLevel, 21},  // FATAL
+	}
+	for _, tt := range tests {
+		n, ok := logstox.LevelFormatOTel.Number(tt.level)
+		if !ok || n != tt.severity {
+			t.Errorf("%v is written as %d, %v, want %d", tt.level, n, ok, tt.severity)
+		}
+		if lvl, err := logstox.LevelFormatOTel.Parse(fmt.Sprint(tt.severity)); err != nil || lvl != tt.level {
+			t.Errorf("severity %d parses as %v, %v, want %v", tt.severity, lvl, err, tt.level)
+		}
+	}
+}
+
+func TestSampled(t *testing.T) {
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{1}, SpanID: trace.SpanID{1}, TraceFlags: trace.FlagsSampled,
+	})
+	if !Sampled(trace.ContextWithSpanContext(context.Background(), sc)) {
+		t.Error("Sampled = false for a sampled span")
+	}
+	if Sampled(trace.ContextWithSpanContext(context.Background(), sc.WithTraceFlags(0))) {
+		t.Error("Sampled = true for a span that isn't sampled")
+	}
+	if Sampled(context.Background()) {
+		t.Error("Sampled = true without a span")
+	}
+}
@@ -0,0 +1,301 @@
+package parse
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/khinshankhan/logstox"
+	"github.com/khinshankhan/logstox/fields"
+	"github.com/khinshankhan/logstox/pretty"
+)
+
+// CallerKey is the field the caller of an entry is kept under, the first-party backends writing one.
+const CallerKey = "caller"
+
+// maxLineSize bounds the lines a Scanner reads; entries with stack traces get long.
+const maxLineSize = 16 * 1024 * 1024
+
+// Line parses a single NDJSON line, as written by the first-party backends, into an Entry. The time, level, logger
+// name and message are found under the keys pretty.Parse knows, the caller is kept under CallerKey, and the other
+// members become fields in their original order:
+//
+//   - strings become String fields, or Time fields when they're RFC 3339 timestamps, and the conventional error key
+//     (fields.ErrorKey) becomes an Error field
+//   - numbers become Int64 fields when integral and Float64 fields otherwise
+//   - arrays of strings, bools or numbers become the matching slice fields, other arrays Any fields
+//   - objects become Dicts, and null an Any field holding nil
+//
+// Levels are parsed by name, unknown levels becoming InfoLevel; see LineFormat for levels written as numbers.
+func Line(line []byte) (logstox.Entry, error) {
+	return LineFormat(line, logstox.LevelFormatName)
+}
+
+// LineFormat is Line for output written with Options.LevelFormat f, parsing levels by name or as numbers of f.
+func LineFormat(line []byte, f logstox.LevelFormat) (logstox.Entry, error) {
+	pe, err := pretty.Parse(line)
+	if err != nil {
+		return logstox.Entry{}, err
+	}
+
+	e := logstox.Entry{
+		Time:    Time(pe.Time),
+		Level:   Level(pe.Level, f),
+		Name:    pe.Name,
+		Message: pe.Message,
+		Fields:  make([]fields.Field, 0, len(pe.Fields)+1),
+	}
+	for _, m := range pe.Fields {
+		f, err := Field(m.Key, m.Value)
+		if err != nil {
+			return logstox.Entry{}, fmt.Errorf("parse: field %q: %w", m.Key, err)
+		}
+		e.Fields = append(e.Fields, f)
+	}
+	if pe.Caller != "" {
+		e.Fields = append(e.Fields, fields.String(CallerKey, pe.Caller))
+	}
+	return e, nil
+}
+
+// All parses every NDJSON line read from r, skipping blank lines. With strict set, lines that can't be parsed are an
+// error; they're skipped otherwise.
+func All(r io.Reader, strict bool) ([]logstox.Entry, error) {
+	var out []logstox.Entry
+	sc := NewScanner(r)
+	sc.Strict = strict
+	for sc.Scan() {
+		out = append(out, sc.Entry())
+	}
+	return out, sc.Err()
+}
+
+// Scanner reads Entries from NDJSON one line at a time, like bufio.Scanner:
+//
+//	sc := parse.NewScanner(f)
+//	for sc.Scan() {
+//		e := sc.Entry()
+//		...
+//	}
+//	if err := sc.Err(); err != nil { ... }
+type Scanner struct {
+	// Strict stops the scan at lines that can't be parsed (eg aren't JSON objects) instead of skipping them.
+	Strict bool
+	// LevelFormat is the Options.LevelFormat the output was written with, see LineFormat.
+	LevelFormat logstox.LevelFormat
+
+	sc   *bufio.Scanner
+	e    logstox.Entry
+	line int
+	err  error
+}
+
+// NewScanner returns a Scanner reading from r.
+func NewScanner(r io.Reader) *Scanner {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), maxLineSize)
+	return &Scanner{sc: sc}
+}
+
+// Scan advances to the next entry, reporting false at the end of the input or on an error.
+func (s *Scanner) Scan() bool {
+	if s.err != nil {
+		return false
+	}
+	for s.sc.Scan() {
+		s.line++
+		if len(bytes.TrimSpace(s.sc.Bytes())) == 0 {
+			continue
+		}
+		e, err := LineFormat(s.sc.Bytes(), s.LevelFormat)
+		if err != nil {
+			if s.Strict {
+				s.err = fmt.Errorf("parse: line %d: %w", s.line, err)
+				return false
+			}
+			continue
+		}
+		s.e = e
+		return true
+	}
+	s.err = s.sc.Err()
+	return false
+}
+
+// Entry returns the entry read by the last Scan.
+func (s *Scanner) Entry() logstox.Entry {
+	return s.e
+}
+
+// Line returns the line number of the entry read by the last Scan, counting from 1.
+func (s *Scanner) Line() int {
+	return s.line
+}
+
+// Err returns the error that stopped the scan, nil at the end of the input.
+func (s *Scanner) Err() error {
+	return s.err
+}
+
+// Time parses RFC 3339 or epoch-seconds timestamps, returning the zero time otherwise.
+func Time(s string) time.Time {
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return t
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		sec := int64(f)
+		return time.Unix(sec, int64((f-float64(sec))*1e9))
+	}
+	return time.Time{}
+}
+
+// Level parses a level written in format f (see logstox.LevelFormat.Parse), returning InfoLevel for unknown levels.
+func Level(s string, f logstox.LevelFormat) logstox.Level {
+	if lvl, err := f.Parse(s); err == nil {
+		return lvl
+	}
+	return logstox.InfoLevel
+}
+
+// Field converts a raw JSON value into a field keyed k, see Line.
+func Field(k string, raw json.RawMessage) (fields.Field, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 {
+		return fields.Field{}, errors.New("empty value")
+	}
+
+	switch raw[0] {
+	case '{':
+		fs, err := object(raw)
+		if err != nil {
+			return fields.Field{}, err
+		}
+		return fields.Dict(k, fs...), nil
+	case '[':
+		return array(k, raw)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return fields.Field{}, err
+	}
+	switch v := v.(type) {
+	case string:
+		return str(k, v), nil
+	case bool:
+		return fields.Bool(k, v), nil
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return fields.Int64(k, i), nil
+		}
+		f, err := v.Float64()
+		return fields.Float64(k, f), err
+	default:
+		return fields.Any(k, v), nil // null
+	}
+}
+
+// str converts a string value into an Error, Time or String field.
+func str(k, v string) fields.Field {
+	if k == fields.ErrorKey && v != "" {
+		return fields.Error(errors.New(v))
+	}
+	// cheap check before parsing: RFC 3339 starts with a 4 digit year and a dash
+	if len(v) >= len("2006-01-02T15:04:05Z") && v[4] == '-' && v[10] == 'T' {
+		if t, err := time.Parse(time.RFC3339Nano, v); err == nil {
+			return fields.TimeField(k, t)
+		}
+	}
+	return fields.String(k, v)
+}
+
+// object converts a JSON object into fields, keeping key order.
+func object(raw json.RawMessage) ([]fields.Field, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	if _, err := dec.Token(); err != nil { // {
+		return nil, err
+	}
+	var fs []fields.Field
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		var v json.RawMessage
+		if err := dec.Decode(&v); err != nil {
+			return nil, err
+		}
+		f, err := Field(tok.(string), v)
+		if err != nil {
+			return nil, err
+		}
+		fs = append(fs, f)
+	}
+	return fs, nil
+}
+
+// array converts a JSON array into a typed slice field when its elements share a type, and Any otherwise.
+func array(k string, raw json.RawMessage) (fields.Field, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var vs []any
+	if err := dec.Decode(&vs); err != nil {
+		return fields.Field{}, err
+	}
+	if len(vs) == 0 {
+		return fields.Any(k, vs), nil
+	}
+
+	switch vs[0].(type) {
+	case string:
+		if out, ok := all[string](vs); ok {
+			return fields.Strings(k, out), nil
+		}
+	case bool:
+		if out, ok := all[bool](vs); ok {
+			return fields.Bools(k, out), nil
+		}
+	case json.Number:
+		if ns, ok := all[json.Number](vs); ok {
+			return numbers(k, ns), nil
+		}
+	}
+	return fields.Any(k, vs), nil
+}
+
+// all asserts every element of vs is a T.
+func all[T any](vs []any) ([]T, bool) {
+	out := make([]T, len(vs))
+	for i, v := range vs {
+		t, ok := v.(T)
+		if !ok {
+			return nil, false
+		}
+		out[i] = t
+	}
+	return out, true
+}
+
+// numbers converts ns into Int64s if they're all integral, Float64s otherwise.
+func numbers(k string, ns []json.Number) fields.Field {
+	ints := make([]int64, len(ns))
+	for i, n := range ns {
+		v, err := n.Int64()
+		if err != nil {
+			floats := make([]float64, len(ns))
+			for j, n := range ns {
+				floats[j], _ = n.Float64()
+			}
+			return fields.Float64s(k, floats)
+		}
+		ints[i] = v
+	}
+	return fields.Int64s(k, ints)
+}
@@ -0,0 +1,189 @@
+package parse
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/khinshankhan/logstox"
+	"github.com/khinshankhan/logstox/fields"
+	"github.com/khinshankhan/logstox/pretty"
+)
+
+// kinds describes fs as "key:kind", Dicts as "key{sub fields}".
+func kinds(fs []fields.Field) string {
+	out := make([]string, len(fs))
+	for i, f := range fs {
+		if f.Kind() == fields.FieldKindDict {
+			out[i] = f.Key + "{" + kinds(f.Fields()) + "}"
+			continue
+		}
+		out[i] = f.Key + ":" + f.Kind().String()
+	}
+	return strings.Join(out, " ")
+}
+
+func TestLine(t *testing.T) {
+	tests := []struct {
+		name   string
+		line   string
+		level  logstox.Level
+		msg    string
+		fields string
+	}{
+		{"minimal", `{"level":"warn","msg":"m"}`, logstox.WarnLevel, "m", ""},
+		{"scalars", `{"level":"info","msg":"m","s":"x","b":true,"i":-3,"f":1.5,"n":null}`,
+			logstox.InfoLevel, "m", "s:string b:bool i:int64 f:float64 n:any"},
+		{"time and error", `{"msg":"m","at":"2024-05-01T10:00:00.5Z","error":"boom","date":"2024-05-01"}`,
+			logstox.InfoLevel, "m", "at:time error:error date:string"},
+		{"arrays", `{"msg":"m","ss":["a"],"bs":[true],"is":[1,2],"fs":[1,2.5],"mixed":[1,"a"],"none":[]}`,
+			logstox.InfoLevel, "m", "ss:strings bs:bools is:int64s fs:float64s mixed:any none:any"},
+		{"objects", `{"msg":"m","http":{"status":200,"req":{"path":"/"}}}`,
+			logstox.InfoLevel, "m", "http{status:int64 req{path:string}}"},
+		{"caller", `{"msg":"m","caller":{"file":"main.go","line":12},"k":"v"}`,
+			logstox.InfoLevel, "m", "k:string caller:string"},
+		{"unknown level", `{"level":"loud","msg":"m"}`, logstox.InfoLevel, "m", ""},
+		{"alternative keys", `{"severity":"error","message":"m","@timestamp":"2024-05-01T10:00:00Z"}`,
+			logstox.ErrorLevel, "m", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := Line([]byte(tt.line))
+			if err != nil {
+				t.Fatalf("Line: %v", err)
+			}
+			if e.Level != tt.level || e.Message != tt.msg {
+				t.Errorf("got %v %q, want %v %q", e.Level, e.Message, tt.level, tt.msg)
+			}
+			if got := kinds(e.Fields); got != tt.fields {
+				t.Errorf("fields %s, want %s", got, tt.fields)
+			}
+		})
+	}
+}
+
+func TestLineValues(t *testing.T) {
+	e, err := Line([]byte(`{"ts":"2024-05-01T10:00:00Z","logger":"http","msg":"m","caller":{"file":"a.go","line":3},` +
+		`"error":"boom","big":18446744073709551615}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC); !e.Time.Equal(want) || e.Name != "http" {
+		t.Errorf("got time %v, name %q, want %v, http", e.Time, e.Name, want)
+	}
+	fs := fields.Fields(e.Fields)
+	if f, _ := fs.Get(fields.ErrorKey); f.Err() == nil || f.Err().Error() != "boom" {
+		t.Errorf("%s = %v, want boom", fields.ErrorKey, f.Err())
+	}
+	if f, _ := fs.Get("big"); f.Kind() != fields.FieldKindFloat64 {
+		t.Errorf("big is a %v, want a float64 out of the int64 range", f.Kind())
+	}
+	if f, _ := fs.Get(CallerKey); f.Str() != "a.go:3" {
+		t.Errorf("%s = %q, want a.go:3", CallerKey, f.Str())
+	}
+}
+
+func TestLineMalformed(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		not  bool // not an object at all
+	}{
+		{"empty", ``, true},
+		{"array", `["a"]`, true},
+		{"string", `"msg"`, true},
+		{"text", `starting server on :8080`, true},
+		{"truncated", `{"level":"info","msg":"m"`, false},
+		{"truncated value", `{"level":"info","k":{"a":`, false},
+		{"bad value", `{"level":"info","k":tru}`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Line([]byte(tt.line))
+			if err == nil {
+				t.Fatal("Line succeeded")
+			}
+			if errors.Is(err, pretty.ErrNotObject) != tt.not {
+				t.Errorf("Line = %v, want ErrNotObject %v", err, tt.not)
+			}
+		})
+	}
+}
+
+func TestLineFormat(t *testing.T) {
+	tests := []struct {
+		f     logstox.LevelFormat
+		level string
+		want  logstox.Level
+	}{
+		{logstox.LevelFormatName, `"warn"`, logstox.WarnLevel},
+		{logstox.LevelFormatSyslog, `3`, logstox.ErrorLevel},
+		{logstox.LevelFormatSyslog, `"warn"`, logstox.WarnLevel},
+		{logstox.LevelFormatOTel, `9`, logstox.InfoLevel},
+		{logstox.LevelFormatOTel, `99`, logstox.InfoLevel},
+	}
+	for _, tt := range tests {
+		e, err := LineFormat([]byte(`{"level":`+tt.level+`,"msg":"m"}`), tt.f)
+		if err != nil || e.Level != tt.want {
+			t.Errorf("LineFormat(%s, %d) = %v, %v, want %v", tt.level, tt.f, e.Level, err, tt.want)
+		}
+	}
+}
+
+func TestTime(t *testing.T) {
+	tests := []struct {
+		s    string
+		want time.Time
+	}{
+		{"2024-05-01T10:00:00.25+02:00", time.Date(2024, 5, 1, 8, 0, 0, 250e6, time.UTC)},
+		{"1714557600", time.Unix(1714557600, 0)},
+		{"1714557600.5", time.Unix(1714557600, 5e8)},
+		{"yesterday", time.Time{}},
+		{"", time.Time{}},
+	}
+	for _, tt := range tests {
+		if got := Time(tt.s); !got.Equal(tt.want) {
+			t.Errorf("Time(%q) = %v, want %v", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestScanner(t *testing.T) {
+	input := "{\"msg\":\"a\"}\n\n  \nnot json\n{\"msg\":\"b\"}\n"
+	tests := []struct {
+		strict bool
+		want   string // messages and their line numbers
+		err    bool
+	}{
+		{false, "[a@1 b@5]", false},
+		{true, "[a@1]", true},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprint("strict=", tt.strict), func(t *testing.T) {
+			sc := NewScanner(strings.NewReader(input))
+			sc.Strict = tt.strict
+			var got []string
+			for sc.Scan() {
+				got = append(got, fmt.Sprintf("%s@%d", sc.Entry().Message, sc.Line()))
+			}
+			if fmt.Sprint(got) != tt.want {
+				t.Errorf("scanned %v, want %s", got, tt.want)
+			}
+			if err := sc.Err(); (err != nil) != tt.err || (err != nil && !strings.Contains(err.Error(), "line 4")) {
+				t.Errorf("Err = %v, want an error on line 4: %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestAll(t *testing.T) {
+	es, err := All(strings.NewReader("{\"msg\":\"a\"}\nnope\n{\"msg\":\"b\"}"), false)
+	if err != nil || len(es) != 2 || es[1].Message != "b" {
+		t.Errorf("All = %v, %v, want a and b", es, err)
+	}
+	if _, err := All(strings.NewReader("nope\n"), true); err == nil {
+		t.Error("strict All succeeded on a malformed line")
+	}
+}
@@ -0,0 +1,224 @@
+package pretty
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/khinshankhan/logstox"
+)
+
+// ANSI escapes used when Renderer.Color is set.
+const (
+	reset   = "\x1b[0m"
+	dim     = "\x1b[2m"
+	bold    = "\x1b[1m"
+	red     = "\x1b[31m"
+	yellow  = "\x1b[33m"
+	blue    = "\x1b[34m"
+	magenta = "\x1b[35m"
+	cyan    = "\x1b[36m"
+)
+
+// Keys the first-party backends (and most JSON loggers) write the well-known parts of an entry under, in the order
+// they're looked up.
+var (
+	TimeKeys    = []string{"ts", "time", "timestamp", "@timestamp"}
+	LevelKeys   = []string{"level", "lvl", "severity"}
+	NameKeys    = []string{"logger", "name"}
+	MessageKeys = []string{"msg", "message"}
+	CallerKeys  = []string{"caller", "source"}
+)
+
+// ErrNotObject is returned by Parse for lines that aren't a JSON object.
+var ErrNotObject = errors.New("pretty: not a JSON object")
+
+// Member is a key of a decoded entry with its raw JSON value.
+type Member struct {
+	Key   string
+	Value json.RawMessage
+}
+
+// Entry is an NDJSON entry split into its well-known parts and remaining fields, in their original order.
+type Entry struct {
+	Time    string
+	Level   string
+	Name    string
+	Message string
+	Caller  string
+	Fields  []Member
+}
+
+// Parse decodes a single NDJSON line.
+func Parse(line []byte) (Entry, error) {
+	dec := json.NewDecoder(bytes.NewReader(line))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return Entry{}, ErrNotObject
+	}
+
+	var e Entry
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return Entry{}, err
+		}
+		key := tok.(string) // object keys are always strings
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return Entry{}, err
+		}
+
+		switch {
+		case e.Time == "" && slices.Contains(TimeKeys, key):
+			e.Time = text(raw)
+		case e.Level == "" && slices.Contains(LevelKeys, key):
+			e.Level = text(raw)
+		case e.Name == "" && slices.Contains(NameKeys, key):
+			e.Name = text(raw)
+		case e.Message == "" && slices.Contains(MessageKeys, key):
+			e.Message = text(raw)
+		case e.Caller == "" && slices.Contains(CallerKeys, key):
+			e.Caller = caller(raw)
+		default:
+			e.Fields = append(e.Fields, Member{Key: key, Value: raw})
+		}
+	}
+	return e, nil
+}
+
+// Lookup returns the text of the top-level field k.
+func (e Entry) Lookup(k string) (string, bool) {
+	for _, m := range e.Fields {
+		if m.Key == k {
+			return text(m.Value), true
+		}
+	}
+	return "", false
+}
+
+// Renderer formats entries as human-readable lines:
+//
+//	15:04:05.000 INFO  http  request handled  method=GET status=200  (server.go:42)
+type Renderer struct {
+	// Color adds ANSI colors, levels colored by severity.
+	Color bool
+	// TimeLayout reformats parseable timestamps. Empty keeps them as written.
+	TimeLayout string
+}
+
+// Render writes e to w as a single line.
+func (r Renderer) Render(w io.Writer, e Entry) error {
+	var b strings.Builder
+
+	if e.Time != "" {
+		r.paint(&b, dim, r.formatTime(e.Time))
+		b.WriteByte(' ')
+	}
+	r.paint(&b, levelColor(e.Level), fmt.Sprintf("%-5s", strings.ToUpper(e.Level)))
+	if e.Name != "" {
+		b.WriteByte(' ')
+		r.paint(&b, cyan, e.Name)
+	}
+	b.WriteByte(' ')
+	r.paint(&b, bold, e.Message)
+
+	for _, m := range e.Fields {
+		b.WriteString("  ")
+		r.paint(&b, dim, m.Key+"=")
+		b.WriteString(value(m.Value))
+	}
+	if e.Caller != "" {
+		b.WriteString("  ")
+		r.paint(&b, dim, "("+e.Caller+")")
+	}
+	b.WriteByte('\n')
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// paint writes s in color c when coloring is on.
+func (r Renderer) paint(b *strings.Builder, c, s string) {
+	if !r.Color || s == "" {
+		b.WriteString(s)
+		return
+	}
+	b.WriteString(c)
+	b.WriteString(s)
+	b.WriteString(reset)
+}
+
+// formatTime reformats RFC 3339 or epoch-seconds timestamps with r.TimeLayout.
+func (r Renderer) formatTime(s string) string {
+	if r.TimeLayout == "" {
+		return s
+	}
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return t.Format(r.TimeLayout)
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		sec := int64(f)
+		return time.Unix(sec, int64((f-float64(sec))*1e9)).Format(r.TimeLayout)
+	}
+	return s
+}
+
+// levelColor picks the color for a level name, red for error and above.
+func levelColor(s string) string {
+	lvl, err := logstox.ParseLevel(s)
+	switch {
+	case err != nil:
+		return ""
+	case lvl <= logstox.DebugLevel:
+		return magenta
+	case lvl == logstox.InfoLevel, lvl == logstox.NoticeLevel:
+		return blue
+	case lvl == logstox.WarnLevel:
+		return yellow
+	default:
+		return red
+	}
+}
+
+// text returns a JSON string's contents, or the raw JSON of other values.
+func text(raw json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	return string(raw)
+}
+
+// value renders a field value: strings unquoted unless they need quoting, everything else as compact JSON.
+func value(raw json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil && string(raw) != "null" { // null unmarshals into "" too
+		if s == "" || strings.ContainsAny(s, " \t\n\"=") {
+			return strconv.Quote(s)
+		}
+		return s
+	}
+	var b bytes.Buffer
+	if err := json.Compact(&b, raw); err != nil {
+		return string(raw)
+	}
+	return b.String()
+}
+
+// caller renders zap's "file:line" string or slog's source object.
+func caller(raw json.RawMessage) string {
+	var src struct {
+		File string `json:"file"`
+		Line int    `json:"line"`
+	}
+	if err := json.Unmarshal(raw, &src); err == nil && src.File != "" {
+		return src.File + ":" + strconv.Itoa(src.Line)
+	}
+	return text(raw)
+}
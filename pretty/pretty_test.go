@@ -0,0 +1,92 @@
+package pretty
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name string
+		r    Renderer
+		line string
+		want string
+	}{
+		{"minimal", Renderer{}, `{"level":"info","msg":"started"}`, "INFO  started\n"},
+		{"all parts", Renderer{},
+			`{"ts":"2024-05-01T10:00:00.123Z","level":"warn","logger":"http","msg":"slow","ms":812,"caller":"server.go:42"}`,
+			"2024-05-01T10:00:00.123Z WARN  http slow  ms=812  (server.go:42)\n"},
+		{"time layout", Renderer{TimeLayout: "15:04:05.000"}, `{"time":"2024-05-01T10:00:00.123Z","level":"debug","msg":"m"}`,
+			"10:00:00.123 DEBUG m\n"},
+		{"epoch time", Renderer{TimeLayout: "2006"}, `{"ts":1714557600.5,"level":"info","msg":"m"}`, "2024 INFO  m\n"},
+		{"unparseable time", Renderer{TimeLayout: "15:04"}, `{"ts":"yesterday","level":"info","msg":"m"}`,
+			"yesterday INFO  m\n"},
+		{"values", Renderer{},
+			`{"level":"error","msg":"m","s":"plain","q":"two words","e":"","eq":"a=b","n":null,"obj":{ "a": [1, 2] }}`,
+			`ERROR m  s=plain  q="two words"  e=""  eq="a=b"  n=null  obj={"a":[1,2]}` + "\n"},
+		{"slog keys", Renderer{}, `{"time":"t","level":"INFO","msg":"m","source":{"file":"main.go","line":7}}`,
+			"t INFO  m  (main.go:7)\n"},
+		{"first key wins", Renderer{}, `{"msg":"first","message":"second"}`, "      first  message=second\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := Parse([]byte(tt.line))
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			var b strings.Builder
+			if err := tt.r.Render(&b, e); err != nil {
+				t.Fatal(err)
+			}
+			if b.String() != tt.want {
+				t.Errorf("rendered\n%q\nwant\n%q", b.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderColor(t *testing.T) {
+	tests := []struct {
+		level, color string
+	}{
+		{"debug", magenta},
+		{"info", blue},
+		{"notice", blue},
+		{"warn", yellow},
+		{"error", red},
+		{"fatal", red},
+		{"custom", ""},
+	}
+	for _, tt := range tests {
+		e, _ := Parse([]byte(`{"level":"` + tt.level + `","msg":"m","k":"v"}`))
+		var b strings.Builder
+		Renderer{Color: true}.Render(&b, e)
+		if !strings.HasPrefix(b.String(), tt.color+strings.ToUpper(tt.level)) {
+			t.Errorf("%s rendered as %q, want it in color %q", tt.level, b.String(), tt.color)
+		}
+		if want := bold + "m" + reset + "  " + dim + "k=" + reset + "v\n"; !strings.HasSuffix(b.String(), want) {
+			t.Errorf("%s rendered as %q, want it ending in %q", tt.level, b.String(), want)
+		}
+	}
+}
+
+func TestParseNotObject(t *testing.T) {
+	for _, line := range []string{``, `plain text`, `[1]`, `"s"`} {
+		if _, err := Parse([]byte(line)); err != ErrNotObject {
+			t.Errorf("Parse(%q) = %v, want %v", line, err, ErrNotObject)
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	e, _ := Parse([]byte(`{"msg":"m","status":500,"path":"/a"}`))
+	if v, ok := e.Lookup("status"); !ok || v != "500" {
+		t.Errorf("Lookup(status) = %q, %v, want 500", v, ok)
+	}
+	if v, ok := e.Lookup("path"); !ok || v != "/a" {
+		t.Errorf("Lookup(path) = %q, %v, want /a", v, ok)
+	}
+	if _, ok := e.Lookup("msg"); ok {
+		t.Error("Lookup found the message among the fields")
+	}
+}
@@ -0,0 +1,137 @@
+package logstox
+
+import (
+	"sync"
+	"time"
+
+	"github.com/khinshankhan/logstox/fields"
+)
+
+// Keys of the fields ProgressLogger logs.
+const (
+	ProgressKey        = "progress"         // how much is done
+	ProgressTotalKey   = "progress_total"   // out of how much, left out if it isn't known
+	ProgressPercentKey = "progress_percent" // how much is done in percent, if the total is known
+	ProgressRateKey    = "progress_rate"    // how much is done per second, since the start
+	ProgressETAKey     = "progress_eta"     // how long until it's all done at that rate, if the total is known
+)
+
+// Defaults used for zero ProgressLogger settings.
+const (
+	DefaultProgressInterval = 10 * time.Second
+	DefaultProgressMessage  = "progress"
+)
+
+// ProgressLogger logs the progress of a long-running loop without flooding the logs, see Progress.
+//
+// The settings must be changed before the first update. ProgressLogger is safe for concurrent use.
+type ProgressLogger struct {
+	// Interval is the most often progress is logged (DefaultProgressInterval if <= 0).
+	Interval time.Duration
+	// Percent, if > 0, also logs progress whenever it advanced that many percentage points since it was last logged,
+	// even before Interval elapsed. It needs a total.
+	Percent float64
+	// Message is the message progress is logged with (DefaultProgressMessage if empty).
+	Message string
+
+	own   Logger[fields.Field] // skipping ProgressLogger's own methods for file:line
+	total int64
+	start time.Time
+
+	mu          sync.Mutex
+	done        int64
+	lastLogged  time.Time
+	lastPercent float64
+	finished    bool // whether completion was logged
+}
+
+// Progress returns a ProgressLogger logging to l at InfoLevel, total being how much there is to do (<= 0 if it isn't
+// known). Progress is logged at most once per Interval (or Percent points), with the rate and, given a total, the
+// percentage done and the ETA; reaching the total is always logged:
+//
+//	p := logstox.Progress(log.With(fields.String("job", "reindex")), int64(len(docs)))
+//	for i, doc := range docs {
+//		index(doc)
+//		p.Update(int64(i + 1))
+//	}
+func Progress(l Logger[fields.Field], total int64) *ProgressLogger {
+	now := time.Now()
+	return &ProgressLogger{
+		own:        WithOptions(l, AddCallerSkip(1)),
+		total:      total,
+		start:      now,
+		lastLogged: now,
+	}
+}
+
+// Update sets how much is done to n, logging progress if it's due.
+func (p *ProgressLogger) Update(n int64) {
+	if fs, ok := p.update(n, false); ok {
+		p.own.Info(p.message(), fs...)
+	}
+}
+
+// Add adds delta to how much is done, logging progress if it's due.
+func (p *ProgressLogger) Add(delta int64) {
+	if fs, ok := p.update(delta, true); ok {
+		p.own.Info(p.message(), fs...)
+	}
+}
+
+// Done returns how much is done.
+func (p *ProgressLogger) Done() int64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.done
+}
+
+// message returns the message progress is logged with.
+func (p *ProgressLogger) message() string {
+	if p.Message == "" {
+		return DefaultProgressMessage
+	}
+	return p.Message
+}
+
+// update records progress, returning the fields to log if it's due.
+func (p *ProgressLogger) update(n int64, add bool) ([]fields.Field, bool) {
+	now := time.Now()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if add {
+		n += p.done
+	}
+	p.done = n
+
+	interval := p.Interval
+	if interval <= 0 {
+		interval = DefaultProgressInterval
+	}
+	var percent float64
+	if p.total > 0 {
+		percent = float64(n) / float64(p.total) * 100
+	}
+	complete := p.total > 0 && n >= p.total && !p.finished
+	due := now.Sub(p.lastLogged) >= interval || p.Percent > 0 && percent-p.lastPercent >= p.Percent
+	if p.finished || !complete && !due {
+		return nil, false
+	}
+	p.finished = complete
+	p.lastLogged, p.lastPercent = now, percent
+
+	fs := make([]fields.Field, 0, 5)
+	fs = append(fs, fields.Int64(ProgressKey, n))
+	elapsed := now.Sub(p.start).Seconds()
+	var rate float64
+	if elapsed > 0 {
+		rate = float64(n) / elapsed
+	}
+	if p.total > 0 {
+		fs = append(fs, fields.Int64(ProgressTotalKey, p.total), fields.Float64(ProgressPercentKey, percent))
+	}
+	fs = append(fs, fields.Float64(ProgressRateKey, rate))
+	if p.total > 0 && rate > 0 {
+		fs = append(fs, fields.Duration(ProgressETAKey, time.Duration(float64(max(p.total-n, 0))/rate*float64(time.Second))))
+	}
+	return fs, true
+}
@@ -0,0 +1,504 @@
+package protox
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	"github.com/khinshankhan/logstox"
+	"github.com/khinshankhan/logstox/backend/streamx"
+	"github.com/khinshankhan/logstox/fields"
+)
+
+// MaxFrameSize is the largest frame a Reader accepts, so a corrupt length prefix can't make it allocate unbounded
+// memory.
+const MaxFrameSize = 16 << 20
+
+// Protobuf wire types.
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+// Marshal encodes e as an Entry message of entry.proto, for high-throughput shipping where JSON's overhead matters.
+// Lazy fields are evaluated (handed e.Context, or context.Background if it's nil) and precomputed bundles expanded;
+// the Context itself isn't written.
+//
+// The encoding is written against entry.proto by hand, keeping logstox free of dependencies; code generated from
+// entry.proto in any language reads it. Times are written as unix nanoseconds, so they're read back in UTC, and the
+// values of fields.Any fields are encoded by encoding/json and read back as generic JSON values.
+func Marshal(e logstox.Entry) ([]byte, error) {
+	return AppendEntry(nil, e)
+}
+
+// AppendEntry appends e, encoded as by Marshal, to dst.
+func AppendEntry(dst []byte, e logstox.Entry) ([]byte, error) {
+	ctx := e.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if !e.Time.IsZero() {
+		dst = appendVarint(dst, 1, uint64(e.Time.UnixNano()))
+	}
+	if e.Level != 0 {
+		dst = appendVarint(dst, 2, zigzag(int64(e.Level)))
+	}
+	if e.Name != "" {
+		dst = appendString(dst, 3, e.Name)
+	}
+	if e.Message != "" {
+		dst = appendString(dst, 4, e.Message)
+	}
+	return appendFields(ctx, dst, 5, e.Fields)
+}
+
+// Encoder encodes entries as length-prefixed frames (see entry.proto) for streamx.Backend, making it a sink for
+// internal log shipping:
+//
+//	log := streamx.Backend{Encoder: protox.Encoder{}}.New(logstox.Options[fields.Field]{Writer: conn})
+//
+// Read the frames back with a Reader.
+type Encoder struct{}
+
+// Interface satisfaction (compile-time assertions).
+var _ streamx.Encoder = Encoder{}
+
+// AppendEntry appends e to dst as a frame: its length as a varint followed by e encoded as by Marshal.
+func (Encoder) AppendEntry(dst []byte, e logstox.Entry) ([]byte, error) {
+	msg, err := Marshal(e)
+	if err != nil {
+		return dst, err
+	}
+	dst = binary.AppendUvarint(dst, uint64(len(msg)))
+	return append(dst, msg...), nil
+}
+
+// Unmarshal decodes an Entry message written by Marshal. Unknown fields are skipped, so messages from newer schema
+// versions can be read.
+func Unmarshal(b []byte) (logstox.Entry, error) {
+	var e logstox.Entry
+	err := each(b, func(num, _ int, v uint64, data []byte) error {
+		switch num {
+		case 1:
+			e.Time = unixNano(int64(v))
+		case 2:
+			e.Level = logstox.Level(unzigzag(v))
+		case 3:
+			e.Name = string(data)
+		case 4:
+			e.Message = string(data)
+		case 5:
+			f, err := unmarshalField(data)
+			if err != nil {
+				return err
+			}
+			e.Fields = append(e.Fields, f)
+		}
+		return nil
+	})
+	return e, err
+}
+
+// Reader reads entries from a stream of frames written by Encoder.
+type Reader struct {
+	r   *bufio.Reader
+	buf []byte
+}
+
+// NewReader returns a Reader reading frames from r.
+func NewReader(r io.Reader) *Reader {
+	return &Reader{r: bufio.NewReader(r)}
+}
+
+// Read returns the next entry. It returns io.EOF at the end of the stream, and io.ErrUnexpectedEOF if the stream ends
+// in the middle of a frame.
+func (r *Reader) Read() (logstox.Entry, error) {
+	n, err := binary.ReadUvarint(r.r)
+	if err != nil {
+		return logstox.Entry{}, err
+	}
+	if n > MaxFrameSize {
+		return logstox.Entry{}, fmt.Errorf("protox: frame of %d bytes exceeds MaxFrameSize", n)
+	}
+	if uint64(cap(r.buf)) < n {
+		r.buf = make([]byte, n)
+	}
+	r.buf = r.buf[:n]
+	if _, err := io.ReadFull(r.r, r.buf); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return logstox.Entry{}, err
+	}
+	return Unmarshal(r.buf)
+}
+
+// appendFields appends fs as Field messages under field number num, evaluating lazy fields with ctx and expanding
+// bundles.
+func appendFields(ctx context.Context, dst []byte, num int, fs []fields.Field) ([]byte, error) {
+	for _, f := range fields.Resolve(ctx, nil, fs) {
+		msg, err := appendField(ctx, nil, f)
+		if err != nil {
+			return dst, fmt.Errorf("marshaling field %q: %w", f.Key, err)
+		}
+		dst = appendBytes(dst, num, msg)
+	}
+	return dst, nil
+}
+
+// appendField appends the Field message of f, which holds a plain value.
+func appendField(ctx context.Context, dst []byte, f fields.Field) ([]byte, error) {
+	if f.Key != "" {
+		dst = appendString(dst, 1, f.Key)
+	}
+	switch f.Kind() {
+	case fields.FieldKindString:
+		dst = appendString(dst, 2, f.Str())
+	case fields.FieldKindBool:
+		dst = appendVarint(dst, 3, boolBits(f.Bool()))
+	case fields.FieldKindInt64:
+		dst = appendVarint(dst, 4, zigzag(f.Int64()))
+	case fields.FieldKindUint64:
+		dst = appendVarint(dst, 5, f.Uint64())
+	case fields.FieldKindFloat64:
+		dst = appendFixed64(dst, 6, math.Float64bits(f.Float64()))
+	case fields.FieldKindTime:
+		dst = appendVarint(dst, 7, uint64(timeNano(f.Time())))
+	case fields.FieldKindDuration:
+		dst = appendVarint(dst, 8, uint64(f.Duration()))
+	case fields.FieldKindError:
+		dst = appendBytes(dst, 9, appendError(nil, f.Err()))
+	case fields.FieldKindStrings:
+		var msg []byte
+		for _, s := range f.Interface().([]string) {
+			msg = appendString(msg, 1, s)
+		}
+		dst = appendBytes(dst, 10, msg)
+	case fields.FieldKindBools:
+		var packed []byte
+		for _, b := range f.Interface().([]bool) {
+			packed = binary.AppendUvarint(packed, boolBits(b))
+		}
+		dst = appendBytes(dst, 11, appendPacked(nil, packed))
+	case fields.FieldKindInt64s:
+		var packed []byte
+		for _, n := range f.Interface().([]int64) {
+			packed = binary.AppendUvarint(packed, zigzag(n))
+		}
+		dst = appendBytes(dst, 12, appendPacked(nil, packed))
+	case fields.FieldKindUint64s:
+		var packed []byte
+		for _, n := range f.Interface().([]uint64) {
+			packed = binary.AppendUvarint(packed, n)
+		}
+		dst = appendBytes(dst, 13, appendPacked(nil, packed))
+	case fields.FieldKindFloat64s:
+		var packed []byte
+		for _, x := range f.Interface().([]float64) {
+			packed = binary.LittleEndian.AppendUint64(packed, math.Float64bits(x))
+		}
+		dst = appendBytes(dst, 14, appendPacked(nil, packed))
+	case fields.FieldKindErrors:
+		var msg []byte
+		for _, err := range f.Interface().([]error) {
+			msg = appendBytes(msg, 1, appendError(nil, err))
+		}
+		dst = appendBytes(dst, 15, msg)
+	case fields.FieldKindDict:
+		msg, err := appendFields(ctx, nil, 1, f.Fields())
+		if err != nil {
+			return dst, err
+		}
+		dst = appendBytes(dst, 16, msg)
+	case fields.FieldKindRawJSON:
+		dst = appendBytes(dst, 17, f.Bytes())
+	case fields.FieldKindHexBytes:
+		dst = appendBytes(dst, 18, f.Bytes())
+	case fields.FieldKindTimestamp:
+		dst = appendVarint(dst, 19, uint64(timeNano(f.Time())))
+	default:
+		b, err := json.Marshal(f.Boxed())
+		if err != nil {
+			return dst, err
+		}
+		dst = appendBytes(dst, 20, b)
+	}
+	return dst, nil
+}
+
+// appendError appends the Error message of err, the message being unset for a nil error.
+func appendError(dst []byte, err error) []byte {
+	if err == nil {
+		return dst
+	}
+	return appendString(dst, 1, err.Error())
+}
+
+// appendPacked returns the Strings/Bools/... message holding packed as its packed repeated values.
+func appendPacked(dst, packed []byte) []byte {
+	if len(packed) == 0 {
+		return dst
+	}
+	return appendBytes(dst, 1, packed)
+}
+
+// unmarshalField decodes a Field message.
+func unmarshalField(b []byte) (fields.Field, error) {
+	var (
+		k    string
+		num  int // number of the value field, the last one set winning as for any oneof
+		v    uint64
+		data []byte
+	)
+	err := each(b, func(n, _ int, nv uint64, ndata []byte) error {
+		if n == 1 {
+			k = string(ndata)
+		} else {
+			num, v, data = n, nv, ndata
+		}
+		return nil
+	})
+	if err != nil {
+		return fields.Nop(), err
+	}
+
+	switch num {
+	case 2:
+		return fields.String(k, string(data)), nil
+	case 3:
+		return fields.Bool(k, v != 0), nil
+	case 4:
+		return fields.Int64(k, unzigzag(v)), nil
+	case 5:
+		return fields.Uint64(k, v), nil
+	case 6:
+		return fields.Float64(k, math.Float64frombits(v)), nil
+	case 7:
+		return fields.TimeField(k, unixNano(int64(v))), nil
+	case 8:
+		return fields.Duration(k, time.Duration(v)), nil
+	case 9:
+		e, err := unmarshalError(data)
+		if err != nil || e == nil {
+			return fields.Nop(), err
+		}
+		return fields.NamedError(k, e), nil
+	case 10:
+		var ss []string
+		err := each(data, func(num, _ int, _ uint64, data []byte) error {
+			if num == 1 {
+				ss = append(ss, string(data))
+			}
+			return nil
+		})
+		return fields.Strings(k, ss), err
+	case 11:
+		var bs []bool
+		err := repeatedVarints(data, func(v uint64) { bs = append(bs, v != 0) })
+		return fields.Bools(k, bs), err
+	case 12:
+		var ns []int64
+		err := repeatedVarints(data, func(v uint64) { ns = append(ns, unzigzag(v)) })
+		return fields.Int64s(k, ns), err
+	case 13:
+		var ns []uint64
+		err := repeatedVarints(data, func(v uint64) { ns = append(ns, v) })
+		return fields.Uint64s(k, ns), err
+	case 14:
+		var xs []float64
+		err := repeatedFixed64s(data, func(v uint64) { xs = append(xs, math.Float64frombits(v)) })
+		return fields.Float64s(k, xs), err
+	case 15:
+		var errs []error
+		err := each(data, func(num, _ int, _ uint64, data []byte) error {
+			if num != 1 {
+				return nil
+			}
+			e, err := unmarshalError(data)
+			errs = append(errs, e)
+			return err
+		})
+		return fields.Errors(k, errs), err
+	case 16:
+		var fs []fields.Field
+		err := each(data, func(num, _ int, _ uint64, data []byte) error {
+			if num != 1 {
+				return nil
+			}
+			f, err := unmarshalField(data)
+			fs = append(fs, f)
+			return err
+		})
+		return fields.Dict(k, fs...), err
+	case 17:
+		return fields.RawJSON(k, bytes.Clone(data)), nil
+	case 18:
+		return fields.Hex(k, bytes.Clone(data)), nil
+	case 19:
+		return fields.TimestampAt(k, unixNano(int64(v))), nil
+	case 20:
+		var value any
+		err := json.Unmarshal(data, &value)
+		return fields.Any(k, value), err
+	default:
+		return fields.Nop(), nil // no value, or one from a newer schema
+	}
+}
+
+// unmarshalError decodes an Error message into the error it describes, nil if its message is unset.
+func unmarshalError(b []byte) (e error, err error) {
+	err = each(b, func(num, _ int, _ uint64, data []byte) error {
+		if num == 1 {
+			e = errors.New(string(data))
+		}
+		return nil
+	})
+	return e, err
+}
+
+// repeatedVarints calls fn with every value of field 1 of msg, packed or not.
+func repeatedVarints(msg []byte, fn func(uint64)) error {
+	return each(msg, func(num, typ int, v uint64, data []byte) error {
+		switch {
+		case num != 1:
+		case typ == wireBytes:
+			for len(data) > 0 {
+				v, n := binary.Uvarint(data)
+				if n <= 0 {
+					return errors.New("protox: invalid packed varint")
+				}
+				fn(v)
+				data = data[n:]
+			}
+		default:
+			fn(v)
+		}
+		return nil
+	})
+}
+
+// repeatedFixed64s calls fn with every value of field 1 of msg, packed or not.
+func repeatedFixed64s(msg []byte, fn func(uint64)) error {
+	return each(msg, func(num, typ int, v uint64, data []byte) error {
+		switch {
+		case num != 1:
+		case typ == wireBytes:
+			if len(data)%8 != 0 {
+				return errors.New("protox: invalid packed fixed64")
+			}
+			for ; len(data) > 0; data = data[8:] {
+				fn(binary.LittleEndian.Uint64(data))
+			}
+		default:
+			fn(v)
+		}
+		return nil
+	})
+}
+
+// each calls fn with every field of the message b: its number, wire type, and either its integer value (varint and
+// fixed types) or its data (length-delimited types). It stops at the first error.
+func each(b []byte, fn func(num, typ int, v uint64, data []byte) error) error {
+	for len(b) > 0 {
+		tag, n := binary.Uvarint(b)
+		if n <= 0 {
+			return errors.New("protox: invalid tag")
+		}
+		b = b[n:]
+		num, typ := int(tag>>3), int(tag&7)
+
+		var (
+			v    uint64
+			data []byte
+		)
+		switch typ {
+		case wireVarint:
+			if v, n = binary.Uvarint(b); n <= 0 {
+				return errors.New("protox: invalid varint")
+			}
+		case wireFixed64:
+			if n = 8; len(b) < n {
+				return io.ErrUnexpectedEOF
+			}
+			v = binary.LittleEndian.Uint64(b)
+		case wireFixed32:
+			if n = 4; len(b) < n {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(binary.LittleEndian.Uint32(b))
+		case wireBytes:
+			l, ln := binary.Uvarint(b)
+			if ln <= 0 || l > uint64(len(b)-ln) {
+				return errors.New("protox: invalid length")
+			}
+			data = b[ln : ln+int(l)]
+			n = ln + int(l)
+		default:
+			return fmt.Errorf("protox: unsupported wire type %d", typ)
+		}
+		b = b[n:]
+		if err := fn(num, typ, v, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func appendTag(dst []byte, num, typ int) []byte {
+	return binary.AppendUvarint(dst, uint64(num)<<3|uint64(typ))
+}
+
+func appendVarint(dst []byte, num int, v uint64) []byte {
+	return binary.AppendUvarint(appendTag(dst, num, wireVarint), v)
+}
+
+func appendFixed64(dst []byte, num int, v uint64) []byte {
+	return binary.LittleEndian.AppendUint64(appendTag(dst, num, wireFixed64), v)
+}
+
+func appendBytes(dst []byte, num int, b []byte) []byte {
+	dst = binary.AppendUvarint(appendTag(dst, num, wireBytes), uint64(len(b)))
+	return append(dst, b...)
+}
+
+func appendString(dst []byte, num int, s string) []byte {
+	dst = binary.AppendUvarint(appendTag(dst, num, wireBytes), uint64(len(s)))
+	return append(dst, s...)
+}
+
+// zigzag maps signed integers to unsigned ones so small magnitudes encode small, as sint32/sint64 do.
+func zigzag(v int64) uint64 { return uint64(v<<1) ^ uint64(v>>63) }
+
+func unzigzag(v uint64) int64 { return int64(v>>1) ^ -int64(v&1) }
+
+func boolBits(b bool) uint64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// timeNano returns t as unix nanoseconds, 0 for the zero time.
+func timeNano(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano()
+}
+
+// unixNano is the inverse of timeNano, returning times in UTC.
+func unixNano(n int64) time.Time {
+	if n == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, n).UTC()
+}
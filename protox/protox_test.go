@@ -0,0 +1,90 @@
+package protox
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/khinshankhan/logstox"
+	"github.com/khinshankhan/logstox/fields"
+)
+
+func TestRoundTrip(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 30, 0, 123456789, time.UTC) // read back in UTC
+	tests := []struct {
+		name string
+		f    fields.Field
+	}{
+		{"string", fields.String("k", "héllo")},
+		{"bool", fields.Bool("k", true)},
+		{"int64", fields.Int64("k", math.MinInt64)},
+		{"uint64", fields.Uint64("k", math.MaxUint64)},
+		{"float64", fields.Float64("k", -3.25)},
+		{"time", fields.TimeField("k", now)},
+		{"timestamp", fields.TimestampAt("k", now)},
+		{"duration", fields.Duration("k", -time.Minute)},
+		{"error", fields.NamedError("k", errors.New("boom"))},
+		{"strings", fields.Strings("k", []string{"a", ""})},
+		{"bools", fields.Bools("k", []bool{true, false})},
+		{"int64s", fields.Int64s("k", []int64{-1, 1 << 40})},
+		{"uint64s", fields.Uint64s("k", []uint64{0, math.MaxUint64})},
+		{"float64s", fields.Float64s("k", []float64{0.5, 1e300})},
+		{"errors", fields.Errors("k", []error{errors.New("a"), nil})},
+		{"dict", fields.Dict("k", fields.String("a", "b"), fields.Dict("c", fields.Int64("d", 1)))},
+		{"rawjson", fields.RawJSON("k", []byte(`{"a":[1,2]}`))},
+		{"hexbytes", fields.Hex("k", []byte{0, 0xff})},
+		{"any", fields.Any("k", map[string]any{"a": []any{1.0, "b"}})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := logstox.Entry{
+				Time: now, Level: logstox.WarnLevel, Name: "api.db", Message: "query", Fields: []fields.Field{tt.f},
+			}
+			b, err := Marshal(e)
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+			got, err := Unmarshal(b)
+			if err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+			if gj, wj := marshalJSON(t, got), marshalJSON(t, e); gj != wj {
+				t.Errorf("round trip = %s, want %s", gj, wj)
+			}
+		})
+	}
+}
+
+func TestReader(t *testing.T) {
+	var stream []byte
+	for _, msg := range []string{"a", "b"} {
+		var err error
+		if stream, err = (Encoder{}).AppendEntry(stream, logstox.Entry{Message: msg}); err != nil {
+			t.Fatalf("AppendEntry: %v", err)
+		}
+	}
+
+	r := NewReader(bytes.NewReader(stream[:len(stream)-1]))
+	if e, err := r.Read(); err != nil || e.Message != "a" {
+		t.Fatalf("Read = %q, %v; want a", e.Message, err)
+	}
+	if _, err := r.Read(); err != io.ErrUnexpectedEOF {
+		t.Errorf("Read of a truncated frame = %v, want io.ErrUnexpectedEOF", err)
+	}
+	if _, err := NewReader(bytes.NewReader(nil)).Read(); err != io.EOF {
+		t.Errorf("Read of an empty stream = %v, want io.EOF", err)
+	}
+}
+
+// marshalJSON returns e in the canonical JSON schema.
+func marshalJSON(t *testing.T, e logstox.Entry) string {
+	t.Helper()
+	b, err := e.MarshalJSON()
+	if err != nil {
+		t.Fatalf("MarshalJSON: %v", err)
+	}
+	return string(b)
+}
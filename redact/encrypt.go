@@ -0,0 +1,116 @@
+package redact
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/khinshankhan/logstox"
+	"github.com/khinshankhan/logstox/fields"
+)
+
+// encryptedPrefix marks encrypted values (and their format version) so they're recognizable in logs.
+const encryptedPrefix = "enc:v1:"
+
+// ErrNotEncrypted is returned by Decrypt for values that weren't produced by Encrypt.
+var ErrNotEncrypted = errors.New("redact: value is not encrypted")
+
+// Encrypt returns a middleware encrypting the values of fields whose key matches pattern (including inside Dicts,
+// precomputed bundles and lazy fields) with aead, so regulated data can be logged for later authorized decryption
+// instead of being dropped.
+//
+// Values are replaced by strings of the form "enc:v1:" + base64(nonce || ciphertext). Strings are encrypted as is,
+// other values as their JSON encoding and errors as their message. The field key is used as additional data, so a
+// value can't be moved under another key undetected. If a value can't be encrypted it's replaced by
+// DefaultReplacement: this fails closed, never emitting plaintext.
+func Encrypt(aead cipher.AEAD, pattern *regexp.Regexp) logstox.Middleware {
+	return func(e logstox.Entry) (logstox.Entry, bool) {
+		e.Fields = encryptFields(e.Fields, aead, pattern)
+		return e, true
+	}
+}
+
+// Decrypt reverses Encrypt for a value logged under key k.
+func Decrypt(aead cipher.AEAD, k, v string) ([]byte, error) {
+	if !strings.HasPrefix(v, encryptedPrefix) {
+		return nil, ErrNotEncrypted
+	}
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(v, encryptedPrefix))
+	if err != nil {
+		return nil, err
+	}
+	n := aead.NonceSize()
+	if len(raw) < n {
+		return nil, ErrNotEncrypted
+	}
+	return aead.Open(nil, raw[:n], raw[n:], []byte(k))
+}
+
+// encryptFields returns a copy of fs with matching values encrypted.
+func encryptFields(fs []fields.Field, aead cipher.AEAD, pattern *regexp.Regexp) []fields.Field {
+	if len(fs) == 0 {
+		return fs
+	}
+	out := make([]fields.Field, 0, len(fs))
+	for _, f := range fs {
+		if sub, ok := within(f, func(fs []fields.Field) []fields.Field { return encryptFields(fs, aead, pattern) }); ok {
+			out = append(out, sub...)
+			continue
+		}
+		switch {
+		case f.IsZero():
+			out = append(out, f)
+		case pattern.MatchString(f.Key):
+			out = append(out, fields.String(f.Key, encryptValue(aead, f)))
+		case f.Kind() == fields.FieldKindDict:
+			out = append(out, fields.Dict(f.Key, encryptFields(f.Fields(), aead, pattern)...))
+		default:
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// encryptValue seals f's value, falling back to DefaultReplacement on any failure.
+func encryptValue(aead cipher.AEAD, f fields.Field) string {
+	var plain []byte
+	switch v := plainValue(f).(type) {
+	case string:
+		plain = []byte(v)
+	case error:
+		plain = []byte(v.Error())
+	case []byte:
+		plain = v
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return DefaultReplacement
+		}
+		plain = b
+	}
+
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plain)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return DefaultReplacement
+	}
+	sealed := aead.Seal(nonce, nonce, plain, []byte(f.Key))
+	return encryptedPrefix + base64.StdEncoding.EncodeToString(sealed)
+}
+
+// plainValue returns f's value, with Dicts turned into maps so they JSON encode as objects.
+func plainValue(f fields.Field) any {
+	if f.Kind() != fields.FieldKindDict {
+		return f.Boxed()
+	}
+	m := make(map[string]any)
+	for _, sub := range f.Fields() {
+		if !sub.IsZero() {
+			m[sub.Key] = plainValue(sub)
+		}
+	}
+	return m
+}
@@ -0,0 +1,43 @@
+package redact
+
+import (
+	"github.com/khinshankhan/logstox"
+	"github.com/khinshankhan/logstox/fields"
+)
+
+// Hash returns a middleware replacing the string values of fields with any of keys (including inside Dicts,
+// precomputed bundles and lazy fields) by h of the value, so the hash function is configured once per logger rather
+// than at every call site.
+func Hash(h fields.Hasher, keys ...string) logstox.Middleware {
+	set := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		set[k] = true
+	}
+	return func(e logstox.Entry) (logstox.Entry, bool) {
+		e.Fields = hashFields(e.Fields, h, set)
+		return e, true
+	}
+}
+
+// hashFields returns a copy of fs with the string values of keys hashed.
+func hashFields(fs []fields.Field, h fields.Hasher, keys map[string]bool) []fields.Field {
+	if len(fs) == 0 {
+		return fs
+	}
+	out := make([]fields.Field, 0, len(fs))
+	for _, f := range fs {
+		if sub, ok := within(f, func(fs []fields.Field) []fields.Field { return hashFields(fs, h, keys) }); ok {
+			out = append(out, sub...)
+			continue
+		}
+		switch {
+		case f.Kind() == fields.FieldKindDict:
+			out = append(out, fields.Dict(f.Key, hashFields(f.Fields(), h, keys)...))
+		case f.Kind() == fields.FieldKindString && keys[f.Key]:
+			out = append(out, fields.HashedWith(f.Key, f.Str(), h))
+		default:
+			out = append(out, f)
+		}
+	}
+	return out
+}
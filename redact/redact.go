@@ -0,0 +1,221 @@
+package redact
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"regexp"
+
+	"github.com/khinshankhan/logstox"
+	"github.com/khinshankhan/logstox/fields"
+)
+
+// DefaultReplacement is what matches are replaced with when a Detector doesn't set its own.
+const DefaultReplacement = "[REDACTED]"
+
+// Detector finds one kind of sensitive data in strings.
+type Detector struct {
+	Name    string
+	Pattern *regexp.Regexp
+	// Validate, if set, filters regex matches, eg a Luhn check to cut credit card false positives.
+	Validate func(match string) bool
+	// Replacement for matches. If empty, defaults to DefaultReplacement.
+	Replacement string
+}
+
+// Built-in detectors.
+var (
+	// Email matches email addresses.
+	Email = Detector{
+		Name:    "email",
+		Pattern: regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`),
+	}
+	// CreditCard matches 13-19 digit card numbers (optionally space or dash separated) that pass a Luhn check.
+	CreditCard = Detector{
+		Name:     "credit_card",
+		Pattern:  regexp.MustCompile(`\b\d(?:[ \-]?\d){12,18}\b`),
+		Validate: luhn,
+	}
+	// BearerToken matches bearer credentials, as found in Authorization headers.
+	BearerToken = Detector{
+		Name:        "bearer_token",
+		Pattern:     regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9\-._~+/]+=*`),
+		Replacement: "Bearer " + DefaultReplacement,
+	}
+)
+
+// Custom returns a detector for the regular expression expr. It panics if expr doesn't compile.
+func Custom(name, expr string) Detector {
+	return Detector{Name: name, Pattern: regexp.MustCompile(expr)}
+}
+
+// mask replaces every (validated) match of d in s.
+func (d Detector) mask(s string) string {
+	repl := d.Replacement
+	if repl == "" {
+		repl = DefaultReplacement
+	}
+	return d.Pattern.ReplaceAllStringFunc(s, func(m string) string {
+		if d.Validate != nil && !d.Validate(m) {
+			return m
+		}
+		return repl
+	})
+}
+
+// Masker applies a set of detectors.
+type Masker struct {
+	Detectors []Detector
+}
+
+// String masks every detector's matches in s.
+func (m Masker) String(s string) string {
+	for _, d := range m.Detectors {
+		s = d.mask(s)
+	}
+	return s
+}
+
+// matches reports whether any detector matches s.
+func (m Masker) matches(s string) bool {
+	for _, d := range m.Detectors {
+		if d.Pattern.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// Fields returns a copy of fs with string values masked, recursing into Dicts, precomputed bundles (which are
+// expanded) and the string values of RawJSON. Lazy fields are masked once they're evaluated. fs itself is never
+// modified.
+func (m Masker) Fields(fs []fields.Field) []fields.Field {
+	if len(fs) == 0 {
+		return fs
+	}
+	out := make([]fields.Field, 0, len(fs))
+	for _, f := range fs {
+		if sub, ok := within(f, m.Fields); ok {
+			out = append(out, sub...)
+			continue
+		}
+		out = append(out, m.field(f))
+	}
+	return out
+}
+
+// within applies fn to the fields f holds if it's a lazy field, once it's evaluated, or a precomputed bundle, which
+// is expanded, so the middleware rewriting values don't miss them. It reports false for other fields.
+func within(f fields.Field, fn func([]fields.Field) []fields.Field) ([]fields.Field, bool) {
+	switch f.Kind() {
+	case fields.FieldKindLazyFields, fields.FieldKindLazyValue:
+		lazy := f.LazyFunc()
+		return []fields.Field{fields.LazyFields(func(ctx context.Context) []fields.Field { return fn(lazy(ctx)) })}, true
+	case fields.FieldKindPrecomputed:
+		return fn(f.Precomputed().Fields()), true
+	default:
+		return nil, false
+	}
+}
+
+// field masks a single field, rebuilding it through its constructor to keep the kind.
+func (m Masker) field(f fields.Field) fields.Field {
+	switch f.Kind() {
+	case fields.FieldKindString:
+		return fields.String(f.Key, m.String(f.Str()))
+	case fields.FieldKindStrings:
+		in := f.Interface().([]string)
+		out := make([]string, len(in))
+		for i, s := range in {
+			out[i] = m.String(s)
+		}
+		return fields.Strings(f.Key, out)
+	case fields.FieldKindDict:
+		return fields.Dict(f.Key, m.Fields(f.Fields())...)
+	case fields.FieldKindRawJSON:
+		return fields.RawJSON(f.Key, m.json(f.Bytes()))
+	case fields.FieldKindAny:
+		if s, ok := f.Interface().(string); ok {
+			return fields.String(f.Key, m.String(s))
+		}
+	}
+	return f
+}
+
+// json masks the string values in raw, leaving object keys alone. Invalid JSON is masked as plain text. raw is only
+// decoded and re-encoded if it holds a value to mask, and returned as is otherwise.
+func (m Masker) json(raw []byte) []byte {
+	// UseNumber so numbers round-trip exactly rather than through float64
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return []byte(m.String(string(raw)))
+	}
+	v, changed := m.walk(v)
+	if !changed {
+		return raw
+	}
+	out, err := json.Marshal(v)
+	if err != nil {
+		return []byte(m.String(string(raw)))
+	}
+	return out
+}
+
+// walk masks the strings within a decoded JSON value, reporting whether any was.
+func (m Masker) walk(v any) (any, bool) {
+	var changed bool
+	switch t := v.(type) {
+	case string:
+		if !m.matches(t) {
+			return t, false
+		}
+		s := m.String(t)
+		return s, s != t
+	case []any:
+		for i := range t {
+			var c bool
+			t[i], c = m.walk(t[i])
+			changed = changed || c
+		}
+	case map[string]any:
+		for k := range t {
+			var c bool
+			t[k], c = m.walk(t[k])
+			changed = changed || c
+		}
+	}
+	return v, changed
+}
+
+// Middleware returns a middleware masking the message and string fields of every entry with ds.
+// Errors are left untouched; log sensitive errors through a string field if they need masking.
+func Middleware(ds ...Detector) logstox.Middleware {
+	m := Masker{Detectors: ds}
+	return func(e logstox.Entry) (logstox.Entry, bool) {
+		e.Message = m.String(e.Message)
+		e.Fields = m.Fields(e.Fields)
+		return e, true
+	}
+}
+
+// luhn reports whether the digits in s pass the Luhn checksum.
+func luhn(s string) bool {
+	sum, n := 0, 0
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if n%2 == 1 {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		n++
+	}
+	return n > 0 && sum%10 == 0
+}
@@ -0,0 +1,60 @@
+package redact
+
+import (
+	"context"
+	"testing"
+
+	"github.com/khinshankhan/logstox/fields"
+)
+
+func TestMaskerFields(t *testing.T) {
+	m := Masker{Detectors: []Detector{Email}}
+	tests := []struct {
+		name string
+		in   fields.Field
+		want map[string]string
+	}{
+		{"string", fields.String("to", "a@b.io"), map[string]string{"to": DefaultReplacement}},
+		{"dict", fields.Dict("d", fields.String("to", "a@b.io")), map[string]string{"d.to": DefaultReplacement}},
+		{
+			"lazy",
+			fields.Lazy(func() []fields.Field { return []fields.Field{fields.String("to", "a@b.io")} }),
+			map[string]string{"to": DefaultReplacement},
+		},
+		{
+			"precomputed",
+			fields.Precompute(fields.String("to", "a@b.io"), fields.String("id", "1")),
+			map[string]string{"to": DefaultReplacement, "id": "1"},
+		},
+		{"raw json", fields.RawJSON("j", []byte(`{"to":"a@b.io"}`)), map[string]string{"j": `{"to":"[REDACTED]"}`}},
+		{"raw json kept as is", fields.RawJSON("j", []byte(`{ "n": 1.50 }`)), map[string]string{"j": `{ "n": 1.50 }`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := make(map[string]string)
+			flatten(got, "", fields.Resolve(context.Background(), nil, m.Fields([]fields.Field{tt.in})))
+			for k, want := range tt.want {
+				if got[k] != want {
+					t.Errorf("%s = %q, want %q", k, got[k], want)
+				}
+			}
+			if len(got) != len(tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// flatten collects the string and RawJSON values of fs into dst, keyed by their dotted path.
+func flatten(dst map[string]string, prefix string, fs []fields.Field) {
+	for _, f := range fs {
+		switch f.Kind() {
+		case fields.FieldKindDict:
+			flatten(dst, prefix+f.Key+".", f.Fields())
+		case fields.FieldKindRawJSON:
+			dst[prefix+f.Key] = string(f.Bytes())
+		default:
+			dst[prefix+f.Key] = f.Str()
+		}
+	}
+}
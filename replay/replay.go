@@ -0,0 +1,105 @@
+package replay
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/khinshankhan/logstox"
+	"github.com/khinshankhan/logstox/fields"
+	"github.com/khinshankhan/logstox/parse"
+)
+
+// Keys ToLogger keeps the original time and level under, since loggers stamp their own.
+const (
+	OriginalTimeKey  = "original_ts"
+	OriginalLevelKey = "original_level"
+)
+
+// Emitter receives replayed entries, eg a logger via ToLogger or a memx Recorder via EmitterFunc.
+type Emitter interface {
+	Emit(logstox.Entry) error
+}
+
+// EmitterFunc adapts a function to an Emitter.
+type EmitterFunc func(logstox.Entry) error
+
+// Emit calls f(e).
+func (f EmitterFunc) Emit(e logstox.Entry) error { return f(e) }
+
+// ToLogger returns an Emitter logging entries through l, named after the entry's logger. Entries keep their message,
+// level and fields; the original time is added under OriginalTimeKey. Entries at DPanic and above are logged at Error
+// with the original level under OriginalLevelKey, so replaying can't panic or exit.
+func ToLogger(l logstox.Logger[fields.Field]) Emitter {
+	return EmitterFunc(func(e logstox.Entry) error {
+		target := l
+		if e.Name != "" {
+			target = l.Named(e.Name)
+		}
+		// copied, so appending the original time and level never writes into the caller's backing array
+		fs := make([]fields.Field, len(e.Fields), len(e.Fields)+2)
+		copy(fs, e.Fields)
+		if !e.Time.IsZero() {
+			fs = append(fs, fields.TimestampAt(OriginalTimeKey, e.Time))
+		}
+
+		lvl := e.Level
+		if lvl.Compare(logstox.ErrorLevel) > 0 {
+			lvl = logstox.ErrorLevel
+			fs = append(fs, fields.String(OriginalLevelKey, e.Level.String()))
+		}
+		logstox.LogAt(target, lvl, e.Message, fs...)
+		return nil
+	})
+}
+
+// Options tune Replay.
+type Options struct {
+	// Rate caps the number of entries emitted per second, for load-testing sinks. 0 replays as fast as possible, and
+	// so do rates over one entry per nanosecond, which no ticker can pace.
+	Rate float64
+	// Strict fails on lines that can't be parsed (eg aren't JSON objects) instead of skipping them.
+	Strict bool
+}
+
+// Replay decodes the NDJSON entries read from r and emits them to e in order, returning the number emitted. It stops
+// at the first emit error, or when ctx is done.
+func Replay(ctx context.Context, r io.Reader, e Emitter, o Options) (int, error) {
+	var tick *time.Ticker
+	if o.Rate > 0 {
+		// rates over 1e9 round the interval down to 0, which NewTicker panics on
+		if every := time.Duration(float64(time.Second) / o.Rate); every > 0 {
+			tick = time.NewTicker(every)
+			defer tick.Stop()
+		}
+	}
+
+	sc := parse.NewScanner(r)
+	sc.Strict = o.Strict
+	n := 0
+	for sc.Scan() {
+		if tick != nil {
+			select {
+			case <-tick.C:
+			case <-ctx.Done():
+				return n, ctx.Err()
+			}
+		} else if err := ctx.Err(); err != nil {
+			return n, err
+		}
+		if err := e.Emit(sc.Entry()); err != nil {
+			return n, err
+		}
+		n++
+	}
+	if err := sc.Err(); err != nil {
+		return n, fmt.Errorf("replay: %w", err)
+	}
+	return n, nil
+}
+
+// Decode parses a single NDJSON line, as written by the first-party backends, back into an Entry. It's parse.Line.
+func Decode(line []byte) (logstox.Entry, error) {
+	return parse.Line(line)
+}
@@ -0,0 +1,49 @@
+package replay
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/khinshankhan/logstox"
+	"github.com/khinshankhan/logstox/backend/memx"
+	"github.com/khinshankhan/logstox/fields"
+)
+
+func TestReplayRates(t *testing.T) {
+	const in = `{"level":"info","msg":"a","fields":[]}` + "\n" + `{"level":"warn","msg":"b","fields":[]}` + "\n"
+	for _, rate := range []float64{0, 1e6, 1e9, 5e9, 1e12} {
+		var got []string
+		n, err := Replay(context.Background(), strings.NewReader(in), EmitterFunc(func(e logstox.Entry) error {
+			got = append(got, e.Message)
+			return nil
+		}), Options{Rate: rate})
+		if err != nil || n != 2 || strings.Join(got, "") != "ab" {
+			t.Errorf("rate %g: replayed %d %q, %v; want 2 \"ab\"", rate, n, got, err)
+		}
+	}
+}
+
+func TestToLoggerKeepsCallerFields(t *testing.T) {
+	rec := memx.NewRecorder(4)
+	emit := ToLogger(memx.Backend{Recorder: rec}.New(logstox.Options[fields.Field]{}))
+
+	fs := make([]fields.Field, 1, 4) // room to append into
+	fs[0] = fields.String("k", "v")
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	if err := emit.Emit(logstox.Entry{Time: at, Level: logstox.PanicLevel, Message: "m", Fields: fs}); err != nil {
+		t.Fatal(err)
+	}
+	if spare := fs[:cap(fs)]; spare[1].Key != "" || spare[2].Key != "" {
+		t.Errorf("ToLogger wrote into the caller's fields: %v", spare)
+	}
+
+	es := rec.Entries()
+	if len(es) != 1 {
+		t.Fatalf("recorded %d entries, want 1", len(es))
+	}
+	if e := es[0]; e.Level != logstox.ErrorLevel || len(e.Fields) != 3 || e.Fields[2].Key != OriginalLevelKey {
+		t.Errorf("recorded %v %v, want Error with k, %s and %s", e.Level, e.Fields, OriginalTimeKey, OriginalLevelKey)
+	}
+}
@@ -0,0 +1,257 @@
+package logstox
+
+import (
+	"context"
+	"errors"
+	"os"
+)
+
+// LevelRoute sends the entries from Min up to and including Max to Logger, see RouteLevels.
+type LevelRoute[FT any] struct {
+	Min, Max Level
+	Logger   Logger[FT]
+}
+
+// RouteLevels returns a Logger sending every entry to the loggers of all the routes its level falls in, eg debug to
+// a local file, Info and above to stdout, and Error and above to an alerting sink as well:
+//
+//	log := logstox.RouteLevels(
+//		logstox.LevelRoute[F]{Min: logstox.DebugLevel, Max: logstox.DebugLevel, Logger: fileLog},
+//		logstox.LevelRoute[F]{Min: logstox.InfoLevel, Max: logstox.FatalLevel, Logger: stdoutLog},
+//		logstox.LevelRoute[F]{Min: logstox.ErrorLevel, Max: logstox.FatalLevel, Logger: alertLog},
+//	)
+//
+// The route loggers still apply their own levels. Panic entries are written to every matching route before the
+// router panics; Fatal entries are written at ErrorLevel to all matching routes but the last, which gets the Fatal
+// entry and exits. Sinks that only differ in writer are cheaper as Options.Writers with Between.
+func RouteLevels[FT any](routes ...LevelRoute[FT]) Logger[FT] {
+	r := router[FT]{routes: make([]LevelRoute[FT], len(routes))}
+	for i, route := range routes {
+		// skip the router's level method so file:line points at the caller
+		route.Logger = WithOptions(route.Logger, AddCallerSkip(1))
+		r.routes[i] = route
+	}
+	return r
+}
+
+// router is the Logger returned by RouteLevels.
+type router[FT any] struct {
+	routes []LevelRoute[FT]
+}
+
+// Interface satisfaction (compile-time assertions).
+var (
+	_ Logger[any]         = router[any]{}
+	_ LevelCheck          = router[any]{}
+	_ OptionsApplier[any] = router[any]{}
+	_ LevelSetter[any]    = router[any]{}
+	_ Noticer[any]        = router[any]{}
+	_ Closer              = router[any]{}
+	_ ContextBinder[any]  = router[any]{}
+	_ LevelVarHolder      = router[any]{}
+	_ Introspector[any]   = router[any]{}
+)
+
+// in reports whether lvl falls in the route.
+func (route LevelRoute[FT]) in(lvl Level) bool {
+	return lvl.Compare(route.Min) >= 0 && lvl.Compare(route.Max) <= 0
+}
+
+// DEBUG (-1): for recording messages useful for debugging.
+func (r router[FT]) Debug(m string, f ...FT) {
+	for _, route := range r.routes {
+		if route.in(DebugLevel) {
+			route.Logger.Debug(m, f...)
+		}
+	}
+}
+
+// INFO (0): for messages describing normal application operations.
+func (r router[FT]) Info(m string, f ...FT) {
+	for _, route := range r.routes {
+		if route.in(InfoLevel) {
+			route.Logger.Info(m, f...)
+		}
+	}
+}
+
+// Notice logs at NoticeLevel. It logs at Info on routes whose logger isn't a Noticer.
+func (r router[FT]) Notice(m string, f ...FT) {
+	for _, route := range r.routes {
+		if !route.in(NoticeLevel) {
+			continue
+		}
+		if n, ok := route.Logger.(Noticer[FT]); ok {
+			n.Notice(m, f...)
+		} else {
+			route.Logger.Info(m, f...)
+		}
+	}
+}
+
+// WARN (1): for recording messages indicating something unusual happened that may need attention before it escalates to a more severe issue.
+func (r router[FT]) Warn(m string, f ...FT) {
+	for _, route := range r.routes {
+		if route.in(WarnLevel) {
+			route.Logger.Warn(m, f...)
+		}
+	}
+}
+
+// ERROR (2): for recording unexpected error conditions in the program.
+func (r router[FT]) Error(m string, f ...FT) {
+	for _, route := range r.routes {
+		if route.in(ErrorLevel) {
+			route.Logger.Error(m, f...)
+		}
+	}
+}
+
+// DPANIC (3): for recording severe error conditions in development. It behaves like PANIC in development and ERROR in production.
+func (r router[FT]) DPanic(m string, f ...FT) {
+	for _, route := range r.routes {
+		if route.in(DPanicLevel) {
+			route.Logger.DPanic(m, f...)
+		}
+	}
+}
+
+// PANIC (4): calls panic() after logging an error condition.
+func (r router[FT]) Panic(m string, f ...FT) {
+	for _, route := range r.routes {
+		if route.in(PanicLevel) {
+			// skip panicRecovered too
+			panicRecovered(WithOptions(route.Logger, AddCallerSkip(1)), m, f)
+		}
+	}
+	panic(m)
+}
+
+// panicRecovered logs a panic entry to l, recovering the panic so the router can reach every route first. Only the
+// logger's own panic is recovered: backends panic with the entry's message, possibly rewritten by middleware, so any
+// string is taken for it, while other values, eg a runtime error from a broken writer, carry on.
+func panicRecovered[FT any](l Logger[FT], m string, f []FT) {
+	defer func() {
+		if v := recover(); v != nil {
+			if _, ok := v.(string); !ok {
+				panic(v)
+			}
+		}
+	}()
+	l.Panic(m, f...)
+}
+
+// FATAL (5): calls os.Exit(1) after logging an error condition.
+func (r router[FT]) Fatal(m string, f ...FT) {
+	last := -1
+	for i, route := range r.routes {
+		if route.in(FatalLevel) {
+			last = i
+		}
+	}
+	for i, route := range r.routes {
+		switch {
+		case i == last:
+			route.Logger.Fatal(m, f...)
+		case route.in(FatalLevel):
+			// the last route exits, so the others get an entry that doesn't
+			route.Logger.Error(m, f...)
+			_ = route.Logger.Sync()
+		}
+	}
+	os.Exit(1)
+}
+
+// mapLoggers returns a router with fn applied to every route's logger.
+func (r router[FT]) mapLoggers(fn func(Logger[FT]) Logger[FT]) router[FT] {
+	child := router[FT]{routes: make([]LevelRoute[FT], len(r.routes))}
+	for i, route := range r.routes {
+		route.Logger = fn(route.Logger)
+		child.routes[i] = route
+	}
+	return child
+}
+
+// With returns a child router with f added as context on every route.
+func (r router[FT]) With(f ...FT) Logger[FT] {
+	return r.mapLoggers(func(l Logger[FT]) Logger[FT] { return l.With(f...) })
+}
+
+// Named adds a new path segment to every route logger's name.
+func (r router[FT]) Named(n string) Logger[FT] {
+	return r.mapLoggers(func(l Logger[FT]) Logger[FT] { return l.Named(n) })
+}
+
+// WithOptions applies opts to every route logger.
+func (r router[FT]) WithOptions(opts ...Option) Logger[FT] {
+	return r.mapLoggers(func(l Logger[FT]) Logger[FT] { return WithOptions(l, opts...) })
+}
+
+// WithLevel sets the minimum level of every route logger.
+func (r router[FT]) WithLevel(lvl Level) Logger[FT] {
+	return r.mapLoggers(func(l Logger[FT]) Logger[FT] { return WithLevel(l, lvl) })
+}
+
+// WithContext binds every route logger to ctx.
+func (r router[FT]) WithContext(ctx context.Context) Logger[FT] {
+	return r.mapLoggers(func(l Logger[FT]) Logger[FT] { return WithContext(l, ctx) })
+}
+
+// Sync syncs every route logger, returning their errors joined.
+func (r router[FT]) Sync() error {
+	errs := make([]error, 0, len(r.routes))
+	for _, route := range r.routes {
+		errs = append(errs, route.Logger.Sync())
+	}
+	return errors.Join(errs...)
+}
+
+// Close closes every route logger, returning their errors joined.
+func (r router[FT]) Close() error {
+	errs := make([]error, 0, len(r.routes))
+	for _, route := range r.routes {
+		errs = append(errs, Close(route.Logger))
+	}
+	return errors.Join(errs...)
+}
+
+// Enabled reports whether any route lvl falls in records it.
+func (r router[FT]) Enabled(lvl Level) bool {
+	for _, route := range r.routes {
+		if route.in(lvl) && Enabled(route.Logger, lvl) {
+			return true
+		}
+	}
+	return false
+}
+
+// LevelVar returns the LevelVar the route loggers share, nil if they hold different ones or none.
+func (r router[FT]) LevelVar() *LevelVar {
+	var v *LevelVar
+	for _, route := range r.routes {
+		switch lv := LevelVarOf(route.Logger); {
+		case lv == nil:
+		case v == nil:
+			v = lv
+		case lv != v:
+			return nil
+		}
+	}
+	return v
+}
+
+// Name returns the name of the first route logger; Named names them all alike.
+func (r router[FT]) Name() string {
+	if len(r.routes) == 0 {
+		return ""
+	}
+	return NameOf(r.routes[0].Logger)
+}
+
+// Fields returns the context fields of the first route logger; With adds to them all alike.
+func (r router[FT]) Fields() []FT {
+	if len(r.routes) == 0 {
+		return nil
+	}
+	return FieldsOf(r.routes[0].Logger)
+}
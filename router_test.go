@@ -0,0 +1,78 @@
+package logstox_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/khinshankhan/logstox"
+	"github.com/khinshankhan/logstox/backend/memx"
+	"github.com/khinshankhan/logstox/fields"
+)
+
+func TestRouteLevelsPanic(t *testing.T) {
+	errBroken := errors.New("broken sink")
+	tests := []struct {
+		name   string
+		broken bool
+		want   any
+	}{
+		{"logger's own panic reaches every route", false, "boom"},
+		{"foreign panic carries on", true, errBroken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first, last := memx.NewRecorder(1), memx.NewRecorder(1)
+			var firstLog logstox.Logger[fields.Field] = memx.Backend{Recorder: first}.New(logstox.Options[fields.Field]{})
+			if tt.broken {
+				firstLog = logstox.WithMiddleware(firstLog, func(logstox.Entry) (logstox.Entry, bool) { panic(errBroken) })
+			}
+			log := logstox.RouteLevels(
+				logstox.LevelRoute[fields.Field]{Min: logstox.ErrorLevel, Max: logstox.FatalLevel, Logger: firstLog},
+				logstox.LevelRoute[fields.Field]{
+					Min: logstox.ErrorLevel, Max: logstox.FatalLevel,
+					Logger: memx.Backend{Recorder: last}.New(logstox.Options[fields.Field]{}),
+				},
+			)
+
+			func() {
+				defer func() {
+					if v := recover(); v != tt.want {
+						t.Errorf("recovered %v, want %v", v, tt.want)
+					}
+				}()
+				log.Panic("boom")
+			}()
+			if got := last.Len(); !tt.broken && got != 1 {
+				t.Errorf("last route recorded %d entries, want 1", got)
+			}
+		})
+	}
+}
+
+func TestRouteLevelsIntrospection(t *testing.T) {
+	lv := new(logstox.LevelVar)
+	o := logstox.Options[fields.Field]{LevelVar: lv, Name: "app"}
+	log := logstox.RouteLevels(
+		logstox.LevelRoute[fields.Field]{Min: logstox.DebugLevel, Max: logstox.InfoLevel, Logger: memx.Backend{}.New(o)},
+		logstox.LevelRoute[fields.Field]{Min: logstox.WarnLevel, Max: logstox.FatalLevel, Logger: memx.Backend{}.New(o)},
+	).Named("db").With(fields.String("k", "v"))
+
+	if got := logstox.LevelVarOf(log); got != lv {
+		t.Errorf("LevelVarOf = %p, want the routes' %p", got, lv)
+	}
+	if got := logstox.NameOf(log); got != "app.db" {
+		t.Errorf("NameOf = %q, want app.db", got)
+	}
+	if fs := logstox.FieldsOf(log); len(fs) != 1 || fs[0].Key != "k" {
+		t.Errorf("FieldsOf = %v, want k", fs)
+	}
+
+	o.LevelVar = new(logstox.LevelVar)
+	mixed := logstox.RouteLevels(
+		logstox.LevelRoute[fields.Field]{Min: logstox.DebugLevel, Max: logstox.InfoLevel, Logger: memx.Backend{}.New(o)},
+		logstox.LevelRoute[fields.Field]{Min: logstox.WarnLevel, Max: logstox.FatalLevel, Logger: log},
+	)
+	if got := logstox.LevelVarOf(mixed); got != nil {
+		t.Errorf("LevelVarOf = %p, want nil for routes holding different LevelVars", got)
+	}
+}
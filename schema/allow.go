@@ -0,0 +1,95 @@
+package schema
+
+import (
+	"context"
+
+	"github.com/khinshankhan/logstox"
+	"github.com/khinshankhan/logstox/fields"
+)
+
+// ExtraKey is the key of the Dict AllowFold folds the fields that aren't allowed into.
+const ExtraKey = "extra"
+
+// AllowMode is what Allow does with the fields whose key isn't allowed.
+type AllowMode uint8
+
+const (
+	// AllowDrop drops them.
+	AllowDrop AllowMode = iota
+	// AllowFold folds them into a Dict under ExtraKey, so nothing is lost but the top level keeps the reviewed schema.
+	AllowFold
+)
+
+// Keys returns the names of the keys s declares, Required then Optional, eg for Allow.
+func (s Schema) Keys() []string {
+	keys := make([]string, 0, len(s.Required)+len(s.Optional))
+	for _, k := range s.Required {
+		keys = append(keys, k.Name)
+	}
+	for _, k := range s.Optional {
+		keys = append(keys, k.Name)
+	}
+	return keys
+}
+
+// Allow returns a middleware emitting only the top-level fields whose key is in keys, for services with a fixed,
+// reviewed log schema; the others are dropped or folded into an ExtraKey Dict depending on mode:
+//
+//	log = logstox.WithMiddleware(base, schema.Allow(schema.AllowDrop, s.Keys()...))
+//
+// Lazy fields are evaluated, with the entry's context, and precomputed bundles expanded so their keys can be checked.
+// Entries whose fields are all plain and allowed pass through as is.
+//
+// Allow only sees the fields that go through the middleware, so these are written whatever keys allows:
+//   - context fields baked into the base logger (Options.Fields, or With before WithMiddleware), rather than added to
+//     the middleware logger
+//   - fields the backend derives from Options.ContextExtractors; don't combine them with Allow, derive those fields
+//     in the middleware chain instead, before Allow
+//   - fields the backend adds itself: Multiline's logstox.DetailKey, the fields.PanicKey of DPanic and Panic entries,
+//     and Development's logstox.FieldErrorsKey
+func Allow(mode AllowMode, keys ...string) logstox.Middleware {
+	allowed := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		allowed[k] = true
+	}
+	return func(e logstox.Entry) (logstox.Entry, bool) {
+		if allPlainAllowed(e.Fields, allowed) {
+			return e, true
+		}
+		ctx := e.Context
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		fs := fields.Resolve(ctx, make([]fields.Field, 0, len(e.Fields)), e.Fields)
+		out := fs[:0]
+		var extra []fields.Field
+		for _, f := range fs {
+			switch {
+			case allowed[f.Key]:
+				out = append(out, f)
+			case mode == AllowFold:
+				extra = append(extra, f)
+			}
+		}
+		if len(extra) > 0 {
+			out = append(out, fields.Dict(ExtraKey, extra...))
+		}
+		e.Fields = out
+		return e, true
+	}
+}
+
+// allPlainAllowed reports whether fs holds no lazy, precomputed or no-op fields and only allowed keys, so Allow has
+// nothing to do.
+func allPlainAllowed(fs []fields.Field, allowed map[string]bool) bool {
+	for _, f := range fs {
+		switch f.Kind() {
+		case fields.FieldKindInvalid, fields.FieldKindLazyFields, fields.FieldKindLazyValue, fields.FieldKindPrecomputed:
+			return false
+		}
+		if !allowed[f.Key] {
+			return false
+		}
+	}
+	return true
+}
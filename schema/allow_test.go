@@ -0,0 +1,84 @@
+package schema
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/khinshankhan/logstox"
+	"github.com/khinshankhan/logstox/fields"
+)
+
+func keys(fs []fields.Field) []string {
+	ks := make([]string, len(fs))
+	for i, f := range fs {
+		ks[i] = f.Key
+	}
+	return ks
+}
+
+func TestAllow(t *testing.T) {
+	lazy := fields.LazyFields(func(context.Context) []fields.Field {
+		return []fields.Field{fields.String("user", "u"), fields.String("secret", "s")}
+	})
+	tests := []struct {
+		name      string
+		mode      AllowMode
+		fields    []fields.Field
+		want      []string
+		wantExtra []string
+	}{
+		{
+			name:   "all allowed",
+			fields: []fields.Field{fields.String("user", "u"), fields.Int("status", 200)},
+			want:   []string{"user", "status"},
+		},
+		{
+			name:   "drop",
+			fields: []fields.Field{fields.String("user", "u"), fields.String("secret", "s")},
+			want:   []string{"user"},
+		},
+		{
+			name:      "fold",
+			mode:      AllowFold,
+			fields:    []fields.Field{fields.String("secret", "s"), fields.Int("status", 200), fields.String("other", "o")},
+			want:      []string{"status", ExtraKey},
+			wantExtra: []string{"secret", "other"},
+		},
+		{
+			name:   "lazy fields are resolved",
+			fields: []fields.Field{lazy, fields.Nop()},
+			want:   []string{"user"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, ok := Allow(tt.mode, "user", "status")(logstox.Entry{Fields: tt.fields})
+			if !ok {
+				t.Fatal("Allow dropped the entry")
+			}
+			if got := keys(e.Fields); !slices.Equal(got, tt.want) {
+				t.Fatalf("keys = %v, want %v", got, tt.want)
+			}
+			if tt.wantExtra != nil {
+				if got := keys(e.Fields[len(e.Fields)-1].Fields()); !slices.Equal(got, tt.wantExtra) {
+					t.Errorf("%s keys = %v, want %v", ExtraKey, got, tt.wantExtra)
+				}
+			}
+		})
+	}
+}
+
+func TestAllowFastPath(t *testing.T) {
+	fs := []fields.Field{fields.String("user", "u"), fields.Int("status", 200)}
+	mw := Allow(AllowDrop, "user", "status")
+	allocs := testing.AllocsPerRun(100, func() {
+		e, _ := mw(logstox.Entry{Fields: fs})
+		if &e.Fields[0] != &fs[0] {
+			t.Fatal("fields were copied")
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("got %v allocs per entry, want 0", allocs)
+	}
+}
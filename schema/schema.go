@@ -0,0 +1,159 @@
+package schema
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/khinshankhan/logstox"
+	"github.com/khinshankhan/logstox/fields"
+)
+
+// ViolationsKey is the field key violations are attached under in Development mode.
+const ViolationsKey = "schema_violations"
+
+// Key declares a field key and, optionally, the kind its value must have.
+// A Kind of fields.FieldKindInvalid (the zero value) accepts any kind.
+type Key struct {
+	Name string
+	Kind fields.FieldKind
+}
+
+// Schema declares which top-level keys entries may carry.
+type Schema struct {
+	Required  []Key    // keys every entry must carry
+	Optional  []Key    // keys entries may carry, kind-checked when present
+	Forbidden []string // keys entries must never carry
+	// Strict also reports keys that are neither Required nor Optional.
+	Strict bool
+}
+
+// Violation describes a single way an entry broke the schema.
+type Violation struct {
+	Key    string
+	Reason string
+}
+
+// String implements fmt.Stringer.
+func (v Violation) String() string {
+	return v.Key + ": " + v.Reason
+}
+
+// Validate checks fs against s, returning all violations found (nil if there are none).
+// Only top-level keys are checked, including those of precomputed bundles; no-op fields are ignored, and so are lazy
+// fields, which aren't evaluated.
+//
+// Validate indexes s on every call; Validator does it once.
+func (s Schema) Validate(fs []fields.Field) []Violation {
+	return s.index().validate(fs)
+}
+
+// index is a Schema with its keys indexed for validation.
+type index struct {
+	s         Schema
+	declared  map[string]fields.FieldKind
+	forbidden map[string]bool
+}
+
+// index indexes s's keys.
+func (s Schema) index() index {
+	declared := make(map[string]fields.FieldKind, len(s.Required)+len(s.Optional))
+	for _, k := range s.Required {
+		declared[k.Name] = k.Kind
+	}
+	for _, k := range s.Optional {
+		declared[k.Name] = k.Kind
+	}
+	forbidden := make(map[string]bool, len(s.Forbidden))
+	for _, k := range s.Forbidden {
+		forbidden[k] = true
+	}
+	return index{s: s, declared: declared, forbidden: forbidden}
+}
+
+// validate implements Schema.Validate.
+func (x index) validate(fs []fields.Field) []Violation {
+	var vs []Violation
+	seen := make(map[string]bool, len(fs))
+	for _, f := range expand(nil, fs) {
+		if f.IsZero() {
+			continue
+		}
+		seen[f.Key] = true
+
+		kind, ok := x.declared[f.Key]
+		switch {
+		case x.forbidden[f.Key]:
+			vs = append(vs, Violation{Key: f.Key, Reason: "forbidden"})
+		case !ok && x.s.Strict:
+			vs = append(vs, Violation{Key: f.Key, Reason: "undeclared"})
+		case ok && kind != fields.FieldKindInvalid && f.Kind() != kind:
+			vs = append(vs, Violation{Key: f.Key, Reason: fmt.Sprintf("want kind %s, got %s", kind, f.Kind())})
+		}
+	}
+
+	for _, k := range x.s.Required {
+		if !seen[k.Name] {
+			vs = append(vs, Violation{Key: k.Name, Reason: "missing"})
+		}
+	}
+	return vs
+}
+
+// expand appends fs to dst with precomputed bundles expanded and lazy fields left out.
+func expand(dst, fs []fields.Field) []fields.Field {
+	for _, f := range fs {
+		switch f.Kind() {
+		case fields.FieldKindLazyFields, fields.FieldKindLazyValue:
+		case fields.FieldKindPrecomputed:
+			dst = expand(dst, f.Precomputed().Fields())
+		default:
+			dst = append(dst, f)
+		}
+	}
+	return dst
+}
+
+// Validator enforces a Schema as middleware.
+type Validator struct {
+	Schema Schema
+	// Development escalates violating entries to DPanicLevel (with the violations attached under ViolationsKey), so
+	// they panic in development backends. Otherwise violating entries pass through unchanged.
+	Development bool
+	// OnViolation, if set, is called with every violating entry and its violations.
+	OnViolation func(logstox.Entry, []Violation)
+
+	violations atomic.Uint64
+}
+
+// Violations returns the number of violating entries seen so far.
+func (v *Validator) Violations() uint64 {
+	return v.violations.Load()
+}
+
+// Middleware returns a middleware validating every entry against v.Schema, as it is when Middleware is called.
+func (v *Validator) Middleware() logstox.Middleware {
+	x := v.Schema.index()
+	return func(e logstox.Entry) (logstox.Entry, bool) {
+		vs := x.validate(e.Fields)
+		if len(vs) == 0 {
+			return e, true
+		}
+
+		v.violations.Add(1)
+		if v.OnViolation != nil {
+			v.OnViolation(e, vs)
+		}
+
+		if v.Development {
+			reasons := make([]string, len(vs))
+			for i, violation := range vs {
+				reasons[i] = violation.String()
+			}
+			e.Fields = append(e.Fields, fields.Strings(ViolationsKey, reasons))
+			if e.Level.Compare(logstox.DPanicLevel) < 0 {
+				e.Level = logstox.DPanicLevel
+			}
+		}
+		return e, true
+	}
+}
@@ -0,0 +1,47 @@
+package schema
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/khinshankhan/logstox/fields"
+)
+
+func TestValidate(t *testing.T) {
+	s := Schema{
+		Required:  []Key{{Name: "service", Kind: fields.FieldKindString}},
+		Optional:  []Key{{Name: "status", Kind: fields.FieldKindInt64}},
+		Forbidden: []string{"password"},
+		Strict:    true,
+	}
+	lazy := fields.Lazy(func() []fields.Field { return []fields.Field{fields.String("password", "p")} })
+	tests := []struct {
+		name   string
+		fields []fields.Field
+		want   []string
+	}{
+		{"valid", []fields.Field{fields.String("service", "api"), fields.Int("status", 200)}, nil},
+		{"missing", []fields.Field{fields.Int("status", 200)}, []string{"service: missing"}},
+		{"wrong kind", []fields.Field{fields.String("service", "api"), fields.String("status", "ok")}, []string{
+			"status: want kind int64, got string",
+		}},
+		{"forbidden and undeclared", []fields.Field{
+			fields.String("service", "api"), fields.String("password", "p"), fields.Bool("extra", true),
+		}, []string{"password: forbidden", "extra: undeclared"}},
+		{"precomputed", []fields.Field{fields.Precompute(fields.String("service", "api"), fields.Bool("extra", true))}, []string{
+			"extra: undeclared",
+		}},
+		{"lazy and no-op ignored", []fields.Field{fields.String("service", "api"), lazy, fields.Nop()}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, v := range s.Validate(tt.fields) {
+				got = append(got, v.String())
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("violations = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package logstox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"reflect"
+	"slices"
+	"syscall"
+	"time"
+)
+
+// DefaultSyncTimeout is how long SyncOnShutdown and SyncOnSignal wait for loggers to sync.
+const DefaultSyncTimeout = 5 * time.Second
+
+// Syncer is anything that can flush buffered entries. Every Logger is one, whatever its field type, as are
+// buffering sinks like bufx.Writer.
+type Syncer interface {
+	Sync() error
+}
+
+// SyncWithin syncs ss concurrently, waiting at most timeout for them. It returns their errors joined, plus an error
+// for every Syncer still syncing when timeout ran out.
+func SyncWithin(timeout time.Duration, ss ...Syncer) error {
+	errs := make(chan error, len(ss)) // buffered so stragglers don't leak blocked on send
+	for _, s := range ss {
+		go func() { errs <- s.Sync() }()
+	}
+
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	var all []error
+	for pending := len(ss); pending > 0; pending-- {
+		select {
+		case err := <-errs:
+			all = append(all, err)
+		case <-deadline.C:
+			all = append(all, fmt.Errorf("logstox: %d of %d syncs still running after %s", pending, len(ss), timeout))
+			return errors.Join(all...)
+		}
+	}
+	return errors.Join(all...)
+}
+
+// SyncOnShutdown blocks until ctx is done, then syncs ss within DefaultSyncTimeout, returning the aggregated errors.
+// Run it in its own goroutine, tied to the context that cancels the application:
+//
+//	go func() { errc <- logstox.SyncOnShutdown(ctx, log, auditLog) }()
+func SyncOnShutdown(ctx context.Context, ss ...Syncer) error {
+	<-ctx.Done()
+	return SyncWithin(DefaultSyncTimeout, ss...)
+}
+
+// SyncOnSignal blocks until the process receives SIGINT or SIGTERM, then syncs ss like SyncOnShutdown. The signal is
+// consumed, so the caller decides how to exit; a second signal gets the default behavior again.
+func SyncOnSignal(ss ...Syncer) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	<-ctx.Done()
+	stop()
+	return SyncWithin(DefaultSyncTimeout, ss...)
+}
+
+// Closer is an optional extension for loggers that hold resources beyond buffered entries, eg file descriptors or
+// network connections of their sinks. Close flushes like Sync, then releases them; the logger and every logger
+// sharing its sinks (parents, children) must not be used afterwards. The zapx, apexx and streamx loggers implement
+// it for the writers given in Options.
+type Closer interface {
+	Close() error
+}
+
+// Close closes l if it implements Closer, and only syncs it otherwise.
+func Close[FT any](l Logger[FT]) error {
+	if c, ok := l.(Closer); ok {
+		return c.Close()
+	}
+	return l.Sync()
+}
+
+// CloseWriters closes the writers that implement io.Closer, once each, leaving the standard streams open. It's meant
+// for backends implementing Closer.
+func CloseWriters(ws ...io.Writer) error {
+	var errs []error
+	var closed []io.Closer
+	for _, w := range ws {
+		c, ok := w.(io.Closer)
+		if !ok || w == os.Stdout || w == os.Stderr {
+			continue
+		}
+		if reflect.TypeOf(c).Comparable() {
+			if slices.Contains(closed, c) {
+				continue
+			}
+			closed = append(closed, c)
+		}
+		errs = append(errs, c.Close())
+	}
+	return errors.Join(errs...)
+}
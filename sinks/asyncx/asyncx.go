@@ -0,0 +1,272 @@
+package asyncx
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/khinshankhan/logstox"
+)
+
+// DefaultSize is the queue size used for zero Options.
+const DefaultSize = 1024
+
+// ErrClosed is returned by writes to a closed Writer.
+var ErrClosed = errors.New("asyncx: writer closed")
+
+// Policy is what a Writer does with a write when its queue is full.
+type Policy uint8
+
+const (
+	// Block waits for room in the queue, up to Options.Timeout, then drops the entry.
+	Block Policy = iota
+	// DropNewest drops the entry being written.
+	DropNewest
+	// DropOldest drops the oldest queued entry to make room.
+	DropOldest
+	// DropDebugFirst drops the oldest queued debug entry to make room, or the entry being written if it's a debug
+	// entry or none is queued, so the queue degrades by losing the least important entries.
+	DropDebugFirst
+)
+
+// Options tune a Writer.
+type Options struct {
+	// Size is how many entries the queue holds (DefaultSize if <= 0).
+	Size int
+	// Policy applies when the queue is full.
+	Policy Policy
+	// Timeout is how long Block waits for room, 0 waiting as long as it takes.
+	Timeout time.Duration
+	// Level returns the level of an encoded entry, for DropDebugFirst (JSONLevel if nil).
+	Level func(entry []byte) logstox.Level
+	// ErrorHandler is called with the underlying writer's errors, which are dropped if it's nil.
+	ErrorHandler func(error)
+}
+
+// Stats counts what a Writer did with its entries.
+type Stats struct {
+	Written       int64 // entries handed to the underlying writer, including failed writes
+	TimedOut      int64 // entries dropped by Block after waiting Timeout
+	DroppedNewest int64 // entries dropped by DropNewest, or by DropDebugFirst when no debug entry was queued
+	DroppedOldest int64 // queued entries dropped by DropOldest
+	DroppedDebug  int64 // debug entries dropped by DropDebugFirst
+}
+
+// Writer moves writes to an underlying io.Writer off the logging goroutines: entries are queued and written in the
+// background, and a slow sink only costs callers when the queue is full, depending on Options.Policy:
+//
+//	w := asyncx.New(conn, asyncx.Options{Policy: asyncx.DropDebugFirst})
+//	defer w.Close()
+//	log := backend.New(logstox.Options[F]{Writer: w})
+//
+// Every Write is one entry, as backends write them. Sync (which backends call when the logger is synced) waits for
+// the queue to be written out.
+type Writer struct {
+	w     io.Writer
+	o     Options
+	stats struct{ written, timedOut, droppedNewest, droppedOldest, droppedDebug atomic.Int64 }
+
+	mu      sync.Mutex
+	changed *sync.Cond // signaled when entries are queued or written, or the Writer is closed
+	queue   []entry
+	writing bool // the background goroutine holds an entry out of the queue
+	closed  bool
+	done    chan struct{}
+}
+
+// entry is a queued write.
+type entry struct {
+	p     []byte
+	debug bool
+}
+
+// New returns a Writer writing to w in the background. Close it to write out the queue and stop.
+func New(w io.Writer, o Options) *Writer {
+	if o.Size <= 0 {
+		o.Size = DefaultSize
+	}
+	if o.Level == nil {
+		o.Level = JSONLevel
+	}
+	aw := &Writer{w: w, o: o, queue: make([]entry, 0, o.Size), done: make(chan struct{})}
+	aw.changed = sync.NewCond(&aw.mu)
+	go aw.loop()
+	return aw
+}
+
+// loop writes queued entries until the Writer is closed and the queue is empty.
+func (w *Writer) loop() {
+	defer close(w.done)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for {
+		for len(w.queue) == 0 && !w.closed {
+			w.changed.Wait()
+		}
+		if len(w.queue) == 0 {
+			return
+		}
+		e := w.queue[0]
+		w.pop()
+		w.writing = true
+		w.mu.Unlock()
+
+		_, err := w.w.Write(e.p)
+		w.stats.written.Add(1)
+		if err != nil && w.o.ErrorHandler != nil {
+			w.o.ErrorHandler(err)
+		}
+
+		w.mu.Lock()
+		w.writing = false
+		w.changed.Broadcast()
+	}
+}
+
+// Write implements io.Writer, queueing a copy of p as one entry. It only fails once the Writer is closed, including
+// while Block waits for room; entries dropped by the policy are counted in Stats.
+func (w *Writer) Write(p []byte) (int, error) {
+	e := entry{p: append([]byte(nil), p...)}
+	if w.o.Policy == DropDebugFirst {
+		e.debug = w.o.Level(p) <= logstox.DebugLevel
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return 0, ErrClosed
+	}
+	if len(w.queue) >= w.o.Size && !w.makeRoom(e) {
+		if w.closed { // while Block waited
+			return 0, ErrClosed
+		}
+		return len(p), nil
+	}
+	w.queue = append(w.queue, e)
+	w.changed.Broadcast()
+	return len(p), nil
+}
+
+// pop removes the oldest queued entry. w.mu must be held.
+func (w *Writer) pop() {
+	w.queue[0] = entry{}
+	w.queue = w.queue[1:]
+}
+
+// makeRoom applies the policy to a full queue, reporting whether e can be queued. w.mu must be held.
+func (w *Writer) makeRoom(e entry) bool {
+	switch w.o.Policy {
+	case DropNewest:
+		w.stats.droppedNewest.Add(1)
+		return false
+	case DropOldest:
+		w.pop()
+		w.stats.droppedOldest.Add(1)
+		return true
+	case DropDebugFirst:
+		if e.debug {
+			w.stats.droppedDebug.Add(1)
+			return false
+		}
+		for i, queued := range w.queue {
+			if queued.debug {
+				w.queue = append(w.queue[:i], w.queue[i+1:]...)
+				w.stats.droppedDebug.Add(1)
+				return true
+			}
+		}
+		w.stats.droppedNewest.Add(1)
+		return false
+	default:
+		return w.wait()
+	}
+}
+
+// wait blocks until the queue has room, the Writer is closed or Timeout runs out, reporting whether there's room.
+// w.mu must be held.
+func (w *Writer) wait() bool {
+	if w.o.Timeout > 0 {
+		// wake the waiters up when the timeout runs out, the condition variable can't time out on its own
+		t := time.AfterFunc(w.o.Timeout, func() {
+			w.mu.Lock()
+			w.changed.Broadcast()
+			w.mu.Unlock()
+		})
+		defer t.Stop()
+	}
+	deadline := time.Now().Add(w.o.Timeout)
+	for len(w.queue) >= w.o.Size && !w.closed {
+		if w.o.Timeout > 0 && !time.Now().Before(deadline) {
+			w.stats.timedOut.Add(1)
+			return false
+		}
+		w.changed.Wait()
+	}
+	return !w.closed
+}
+
+// Sync waits until every queued entry is written, then syncs the underlying writer if it supports it (eg *os.File).
+func (w *Writer) Sync() error {
+	w.mu.Lock()
+	for len(w.queue) > 0 || w.writing {
+		w.changed.Wait()
+	}
+	w.mu.Unlock()
+	if s, ok := w.w.(interface{ Sync() error }); ok {
+		return s.Sync()
+	}
+	return nil
+}
+
+// Close writes out the queue and stops the background goroutine. It doesn't close the underlying writer. Writes after
+// Close fail with ErrClosed.
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	w.closed = true
+	w.changed.Broadcast()
+	w.mu.Unlock()
+	<-w.done
+	return nil
+}
+
+// Stats returns the Writer's counters.
+func (w *Writer) Stats() Stats {
+	return Stats{
+		Written:       w.stats.written.Load(),
+		TimedOut:      w.stats.timedOut.Load(),
+		DroppedNewest: w.stats.droppedNewest.Load(),
+		DroppedOldest: w.stats.droppedOldest.Load(),
+		DroppedDebug:  w.stats.droppedDebug.Load(),
+	}
+}
+
+// levelKey precedes the level in JSON entries written by the first-party backends.
+var levelKey = []byte(`"level":"`)
+
+// JSONLevel returns the level of a JSON entry from its "level" string, InfoLevel if it has none or it isn't a level
+// name.
+func JSONLevel(entry []byte) logstox.Level {
+	i := bytes.Index(entry, levelKey)
+	if i < 0 {
+		return logstox.InfoLevel
+	}
+	rest := entry[i+len(levelKey):]
+	end := bytes.IndexByte(rest, '"')
+	if end < 0 {
+		return logstox.InfoLevel
+	}
+	lvl, err := logstox.ParseLevel(string(rest[:end]))
+	if err != nil {
+		return logstox.InfoLevel
+	}
+	return lvl
+}
+
+// Interface satisfaction (compile-time assertions).
+var (
+	_ io.Writer = (*Writer)(nil)
+	_ io.Closer = (*Writer)(nil)
+)
@@ -0,0 +1,152 @@
+package asyncx
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// gateWriter holds every write until release is closed, signaling entered when it first holds one.
+type gateWriter struct {
+	entered chan struct{}
+	release chan struct{}
+
+	mu  sync.Mutex
+	got []string
+}
+
+func newGateWriter() *gateWriter {
+	return &gateWriter{entered: make(chan struct{}, 1), release: make(chan struct{})}
+}
+
+func (g *gateWriter) Write(p []byte) (int, error) {
+	select {
+	case g.entered <- struct{}{}:
+	default:
+	}
+	<-g.release
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.got = append(g.got, string(p))
+	return len(p), nil
+}
+
+// line returns a JSON entry at level.
+func line(level, msg string) string {
+	return `{"level":"` + level + `","msg":"` + msg + `"}`
+}
+
+// fill returns a Writer of size 2 to g whose background goroutine holds held, so later writes queue up.
+func fill(t *testing.T, g *gateWriter, o Options, held string) *Writer {
+	t.Helper()
+	o.Size = 2
+	w := New(g, o)
+	if _, err := w.Write([]byte(held)); err != nil {
+		t.Fatal(err)
+	}
+	<-g.entered
+	return w
+}
+
+func TestPolicies(t *testing.T) {
+	held, a, b := line("info", "held"), line("info", "a"), line("debug", "b")
+	tests := []struct {
+		name   string
+		o      Options
+		writes []string // after held, the first two fill the queue
+		want   []string
+		stats  Stats
+	}{
+		{"block", Options{Policy: Block, Timeout: 10 * time.Millisecond},
+			[]string{a, b, line("info", "c")},
+			[]string{held, a, b}, Stats{Written: 3, TimedOut: 1}},
+		{"drop newest", Options{Policy: DropNewest},
+			[]string{a, b, line("info", "c")},
+			[]string{held, a, b}, Stats{Written: 3, DroppedNewest: 1}},
+		{"drop oldest", Options{Policy: DropOldest},
+			[]string{a, b, line("info", "c")},
+			[]string{held, b, line("info", "c")}, Stats{Written: 3, DroppedOldest: 1}},
+		{"drop debug first", Options{Policy: DropDebugFirst},
+			[]string{a, b, line("info", "c")},
+			[]string{held, a, line("info", "c")}, Stats{Written: 3, DroppedDebug: 1}},
+		{"drop debug first, new debug entry", Options{Policy: DropDebugFirst},
+			[]string{a, line("info", "c"), b},
+			[]string{held, a, line("info", "c")}, Stats{Written: 3, DroppedDebug: 1}},
+		{"drop debug first, no debug entry", Options{Policy: DropDebugFirst},
+			[]string{a, line("info", "c"), line("warn", "d")},
+			[]string{held, a, line("info", "c")}, Stats{Written: 3, DroppedNewest: 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newGateWriter()
+			w := fill(t, g, tt.o, held)
+			for _, s := range tt.writes {
+				if n, err := w.Write([]byte(s)); n != len(s) || err != nil {
+					t.Fatalf("Write = %d, %v, want %d, nil", n, err, len(s))
+				}
+			}
+			close(g.release)
+			if err := w.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			if !slices.Equal(g.got, tt.want) {
+				t.Errorf("wrote %v, want %v", g.got, tt.want)
+			}
+			if got := w.Stats(); got != tt.stats {
+				t.Errorf("Stats = %+v, want %+v", got, tt.stats)
+			}
+		})
+	}
+}
+
+func TestBlockWaitsForRoom(t *testing.T) {
+	g := newGateWriter()
+	w := fill(t, g, Options{Policy: Block}, "held")
+	w.Write([]byte("a"))
+	w.Write([]byte("b"))
+
+	written := make(chan error)
+	go func() {
+		_, err := w.Write([]byte("c"))
+		written <- err
+	}()
+	close(g.release)
+	if err := <-written; err != nil {
+		t.Fatalf("Write = %v, want it queued once there's room", err)
+	}
+	w.Close()
+	if want := []string{"held", "a", "b", "c"}; !slices.Equal(g.got, want) {
+		t.Errorf("wrote %v, want %v", g.got, want)
+	}
+}
+
+func TestCloseWhileBlocked(t *testing.T) {
+	g := newGateWriter()
+	w := fill(t, g, Options{Policy: Block}, "held")
+	w.Write([]byte("a"))
+	w.Write([]byte("b"))
+
+	written := make(chan error)
+	go func() {
+		_, err := w.Write([]byte("c"))
+		written <- err
+	}()
+	time.Sleep(10 * time.Millisecond) // for the write to block
+	closed := make(chan error)
+	go func() { closed <- w.Close() }()
+
+	if err := <-written; !errors.Is(err, ErrClosed) {
+		t.Errorf("blocked Write = %v, want %v", err, ErrClosed)
+	}
+	close(g.release)
+	<-closed
+	if want := []string{"held", "a", "b"}; !slices.Equal(g.got, want) {
+		t.Errorf("wrote %v, want %v", g.got, want)
+	}
+	if _, err := w.Write([]byte("d")); !errors.Is(err, ErrClosed) {
+		t.Errorf("Write after Close = %v, want %v", err, ErrClosed)
+	}
+}
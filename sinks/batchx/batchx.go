@@ -0,0 +1,216 @@
+package batchx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// Defaults used for zero Options.
+const (
+	DefaultMaxEntries = 1000
+	DefaultMaxBytes   = 1 << 20
+	DefaultMaxLatency = time.Second
+	DefaultTimeout    = 10 * time.Second
+)
+
+// ErrClosed is returned by writes to a closed Writer.
+var ErrClosed = errors.New("batchx: writer closed")
+
+// Sender delivers a batch of encoded entries to a remote sink, eg as one HTTP request or Kafka produce call. Entries
+// are in write order, one per element, and belong to the batch: they aren't reused once Send returns.
+type Sender interface {
+	Send(ctx context.Context, batch [][]byte) error
+}
+
+// SenderFunc adapts a function to a Sender.
+type SenderFunc func(ctx context.Context, batch [][]byte) error
+
+// Send calls f(ctx, batch).
+func (f SenderFunc) Send(ctx context.Context, batch [][]byte) error { return f(ctx, batch) }
+
+// Options tune a Writer.
+type Options struct {
+	// MaxEntries is how many entries a batch holds at most (DefaultMaxEntries if <= 0).
+	MaxEntries int
+	// MaxBytes is how many bytes of entries a batch holds at most (DefaultMaxBytes if <= 0). A single larger entry
+	// is sent in a batch of its own.
+	MaxBytes int
+	// MaxLatency is how long an entry waits for its batch to fill up before it's sent anyway (DefaultMaxLatency if
+	// <= 0).
+	MaxLatency time.Duration
+	// Flushers is how many batches are sent concurrently (1 if <= 0, which keeps batches in order). Writes block
+	// while every flusher is busy and another batch is already waiting.
+	Flushers int
+	// Timeout bounds every Send (DefaultTimeout if <= 0).
+	Timeout time.Duration
+	// ErrorHandler is called with Send's errors; by default they're printed to stderr. The batch is lost, wrap the
+	// Sender to retry.
+	ErrorHandler func(error)
+}
+
+// Writer batches entries in front of a remote sink, so sink implementations only deal with sending:
+//
+//	w := batchx.New(sender, batchx.Options{MaxLatency: 500 * time.Millisecond})
+//	defer w.Close()
+//	log := backend.New(logstox.Options[F]{Writer: w})
+//
+// Every Write is one entry, as backends write them. A batch is sent when it reaches MaxEntries or MaxBytes, when its
+// oldest entry has waited MaxLatency, and on Sync (which backends call when the logger is synced) or Close, both of
+// which wait for the batches in flight.
+type Writer struct {
+	s Sender
+	o Options
+
+	mu     sync.Mutex
+	batch  [][]byte
+	size   int
+	timer  *time.Timer // sends the batch after MaxLatency, armed by its first entry
+	gen    uint64      // counts the batches handed off, so a timer that fired late can tell its batch is gone
+	closed bool
+
+	batches  chan [][]byte
+	flushers sync.WaitGroup
+
+	pendingMu sync.Mutex
+	pending   int // batches handed off and not sent yet
+	idle      *sync.Cond
+}
+
+// New returns a Writer batching entries to s. Close it to send the last batch and stop the flushers.
+func New(s Sender, o Options) *Writer {
+	if o.MaxEntries <= 0 {
+		o.MaxEntries = DefaultMaxEntries
+	}
+	if o.MaxBytes <= 0 {
+		o.MaxBytes = DefaultMaxBytes
+	}
+	if o.MaxLatency <= 0 {
+		o.MaxLatency = DefaultMaxLatency
+	}
+	if o.Flushers <= 0 {
+		o.Flushers = 1
+	}
+	if o.Timeout <= 0 {
+		o.Timeout = DefaultTimeout
+	}
+	if o.ErrorHandler == nil {
+		o.ErrorHandler = func(err error) { fmt.Fprintf(os.Stderr, "batchx: %v\n", err) }
+	}
+
+	w := &Writer{s: s, o: o, batches: make(chan [][]byte, 1)}
+	w.idle = sync.NewCond(&w.pendingMu)
+	w.flushers.Add(o.Flushers)
+	for range o.Flushers {
+		go w.flush()
+	}
+	return w
+}
+
+// flush sends batches until the Writer is closed.
+func (w *Writer) flush() {
+	defer w.flushers.Done()
+	for batch := range w.batches {
+		ctx, cancel := context.WithTimeout(context.Background(), w.o.Timeout)
+		if err := w.s.Send(ctx, batch); err != nil {
+			w.o.ErrorHandler(fmt.Errorf("sending %d entries: %w", len(batch), err))
+		}
+		cancel()
+
+		w.pendingMu.Lock()
+		w.pending--
+		if w.pending == 0 {
+			w.idle.Broadcast()
+		}
+		w.pendingMu.Unlock()
+	}
+}
+
+// Write implements io.Writer, adding a copy of p to the current batch as one entry.
+func (w *Writer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return 0, ErrClosed
+	}
+	if len(w.batch) > 0 && w.size+len(p) > w.o.MaxBytes {
+		w.handOff()
+	}
+	w.batch = append(w.batch, append([]byte(nil), p...))
+	w.size += len(p)
+	switch {
+	case len(w.batch) >= w.o.MaxEntries || w.size >= w.o.MaxBytes:
+		w.handOff()
+	case len(w.batch) == 1:
+		gen := w.gen
+		w.timer = time.AfterFunc(w.o.MaxLatency, func() { w.expire(gen) })
+	}
+	return len(p), nil
+}
+
+// expire sends batch gen once its first entry has waited MaxLatency. Stopping the timer doesn't stop a callback
+// that's already waiting for w.mu, so the batch may have been handed off since, and the current one is left alone.
+func (w *Writer) expire(gen uint64) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.closed && w.gen == gen {
+		w.handOff()
+	}
+}
+
+// handOff queues the current batch for the flushers, blocking while they're all busy. w.mu must be held.
+func (w *Writer) handOff() {
+	if len(w.batch) == 0 {
+		return
+	}
+	if w.timer != nil {
+		w.timer.Stop()
+		w.timer = nil
+	}
+	w.pendingMu.Lock()
+	w.pending++
+	w.pendingMu.Unlock()
+
+	w.batches <- w.batch
+	w.batch, w.size = nil, 0
+	w.gen++
+}
+
+// Sync sends the current batch and waits until no batch is left in flight, sent or failed.
+func (w *Writer) Sync() error {
+	w.mu.Lock()
+	w.handOff()
+	w.mu.Unlock()
+
+	w.pendingMu.Lock()
+	defer w.pendingMu.Unlock()
+	for w.pending > 0 {
+		w.idle.Wait()
+	}
+	return nil
+}
+
+// Close sends the current batch, waits for the batches in flight and stops the flushers. Writes after Close fail with
+// ErrClosed.
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return nil
+	}
+	w.handOff()
+	w.closed = true
+	close(w.batches)
+	w.flushers.Wait()
+	return nil
+}
+
+// Interface satisfaction (compile-time assertions).
+var (
+	_ io.Writer = (*Writer)(nil)
+	_ io.Closer = (*Writer)(nil)
+)
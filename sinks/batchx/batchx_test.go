@@ -0,0 +1,135 @@
+package batchx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recorder is a Sender keeping the batches it's sent, as strings, and signaling each on sent.
+type recorder struct {
+	mu      sync.Mutex
+	batches [][]string
+	sent    chan struct{}
+}
+
+func newRecorder() *recorder { return &recorder{sent: make(chan struct{}, 16)} }
+
+func (r *recorder) Send(_ context.Context, batch [][]byte) error {
+	b := make([]string, len(batch))
+	for i, p := range batch {
+		b[i] = string(p)
+	}
+	r.mu.Lock()
+	r.batches = append(r.batches, b)
+	r.mu.Unlock()
+	r.sent <- struct{}{}
+	return nil
+}
+
+func (r *recorder) String() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return fmt.Sprint(r.batches)
+}
+
+func write(t *testing.T, w *Writer, entries ...string) {
+	t.Helper()
+	for _, e := range entries {
+		if _, err := w.Write([]byte(e)); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestWriterFlushesFullBatches(t *testing.T) {
+	tests := []struct {
+		name    string
+		o       Options
+		entries []string
+		sent    int    // how many batches are sent right away
+		want    string // those batches
+	}{
+		{"max entries", Options{MaxEntries: 2}, []string{"a", "b", "c"}, 1, "[[a b]]"},
+		{"max bytes", Options{MaxBytes: 4}, []string{"ab", "cd", "e"}, 1, "[[ab cd]]"},
+		{"larger entry", Options{MaxBytes: 4}, []string{"ab", "cdefg", "h"}, 2, "[[ab] [cdefg]]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newRecorder()
+			tt.o.MaxLatency = time.Hour
+			w := New(r, tt.o)
+			defer w.Close()
+			write(t, w, tt.entries...)
+
+			for range tt.sent {
+				<-r.sent
+			}
+			if got := r.String(); got != tt.want {
+				t.Errorf("sent %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriterFlushesAfterMaxLatency(t *testing.T) {
+	r := newRecorder()
+	w := New(r, Options{MaxLatency: 10 * time.Millisecond})
+	defer w.Close()
+	write(t, w, "a", "b")
+
+	select {
+	case <-r.sent:
+	case <-time.After(time.Second):
+		t.Fatal("the batch wasn't sent after MaxLatency")
+	}
+	if got := r.String(); got != "[[a b]]" {
+		t.Errorf("sent %s, want [[a b]]", got)
+	}
+}
+
+func TestWriterIgnoresStaleTimers(t *testing.T) {
+	r := newRecorder()
+	w := New(r, Options{MaxEntries: 2, MaxLatency: time.Hour})
+	defer w.Close()
+	write(t, w, "a")
+	stale := w.gen // the generation a's timer was armed with
+	write(t, w, "b", "c")
+
+	// a's timer firing now, after its batch was handed off, mustn't send c's batch early
+	w.expire(stale)
+	w.mu.Lock()
+	n := len(w.batch)
+	w.mu.Unlock()
+	if n != 1 {
+		t.Errorf("stale timer handed off the current batch, %d entries left, want 1", n)
+	}
+}
+
+func TestWriterCloseSendsEverything(t *testing.T) {
+	r := newRecorder()
+	w := New(r, Options{MaxEntries: 2, MaxLatency: time.Hour, Flushers: 2})
+	write(t, w, "a", "b", "c", "d", "e")
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, b := range r.batches {
+		got = append(got, b...)
+	}
+	slices.Sort(got)
+	if want := []string{"a", "b", "c", "d", "e"}; !slices.Equal(got, want) {
+		t.Errorf("sent %v, want %v", got, want)
+	}
+	if _, err := w.Write([]byte("f")); !errors.Is(err, ErrClosed) {
+		t.Errorf("Write after Close = %v, want %v", err, ErrClosed)
+	}
+	if err := w.Close(); err != nil {
+		t.Errorf("second Close = %v, want nil", err)
+	}
+}
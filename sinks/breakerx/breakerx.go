@@ -0,0 +1,172 @@
+package breakerx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/khinshankhan/logstox/sinks/batchx"
+)
+
+// Defaults used for zero Options.
+const (
+	DefaultFailures = 5
+	DefaultCooldown = 30 * time.Second
+)
+
+// ErrOpen is returned for batches rejected while the breaker is open and there's no fallback.
+var ErrOpen = errors.New("breakerx: circuit open")
+
+// State is the state of a Breaker.
+type State uint8
+
+const (
+	// Closed lets batches through to the sink.
+	Closed State = iota
+	// Open rejects batches (or sends them to the fallback) until the cooldown is over.
+	Open
+	// HalfOpen lets a single trial batch through to find out whether the sink recovered.
+	HalfOpen
+)
+
+// String implements fmt.Stringer, returning the lower-case name of the state.
+func (s State) String() string {
+	switch s {
+	case Closed:
+		return "closed"
+	case Open:
+		return "open"
+	case HalfOpen:
+		return "half-open"
+	default:
+		return fmt.Sprintf("State(%d)", uint8(s))
+	}
+}
+
+// Options tune a Breaker.
+type Options struct {
+	// Failures is how many consecutive failures open the breaker (DefaultFailures if <= 0).
+	Failures int
+	// Cooldown is how long the breaker stays open before trying the sink again (DefaultCooldown if <= 0).
+	Cooldown time.Duration
+	// Fallback, if set, receives the batches rejected while the breaker is open, eg a local file.
+	Fallback batchx.Sender
+	// OnStateChange, if set, is called on every transition, eg to log or alert on it. It must not call the Breaker.
+	OnStateChange func(from, to State)
+}
+
+// Stats is a snapshot of a Breaker's state and counters, for diagnostics endpoints and metrics.
+type Stats struct {
+	State    State
+	Failures int       // consecutive failures so far
+	Trips    int64     // times the breaker opened
+	Rejected int64     // batches not sent to the sink because the breaker was open
+	OpenedAt time.Time // when the breaker last opened, zero if it never did
+}
+
+// Breaker stops sending to a persistently failing sink for a cooldown period, redirecting batches to a fallback
+// meanwhile, so a dead remote sink doesn't slow every flush down with timeouts:
+//
+//	w := batchx.New(breakerx.New(sender, breakerx.Options{Fallback: fileSender}), batchx.Options{})
+//
+// Once the cooldown is over, the next batch is sent to the sink as a trial: success closes the breaker, failure
+// opens it for another cooldown.
+type Breaker struct {
+	s batchx.Sender
+	o Options
+
+	mu    sync.Mutex
+	stats Stats
+	trial bool // a half-open trial is in flight
+}
+
+// New returns a Breaker in front of s.
+func New(s batchx.Sender, o Options) *Breaker {
+	if o.Failures <= 0 {
+		o.Failures = DefaultFailures
+	}
+	if o.Cooldown <= 0 {
+		o.Cooldown = DefaultCooldown
+	}
+	return &Breaker{s: s, o: o}
+}
+
+// Send implements batchx.Sender, sending batch to the sink unless the breaker is open.
+func (b *Breaker) Send(ctx context.Context, batch [][]byte) error {
+	if !b.allow() {
+		if b.o.Fallback != nil {
+			return b.o.Fallback.Send(ctx, batch)
+		}
+		return ErrOpen
+	}
+	err := b.s.Send(ctx, batch)
+	b.record(err)
+	return err
+}
+
+// allow reports whether a batch may go to the sink, moving from Open to HalfOpen once the cooldown is over.
+func (b *Breaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.stats.State {
+	case Open:
+		if time.Since(b.stats.OpenedAt) < b.o.Cooldown {
+			b.stats.Rejected++
+			return false
+		}
+		b.set(HalfOpen)
+		fallthrough
+	case HalfOpen:
+		if b.trial {
+			b.stats.Rejected++
+			return false
+		}
+		b.trial = true
+	}
+	return true
+}
+
+// record updates the state with the outcome of a send to the sink.
+func (b *Breaker) record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.trial = false
+	if err == nil {
+		b.stats.Failures = 0
+		b.set(Closed)
+		return
+	}
+	b.stats.Failures++
+	if b.stats.State == HalfOpen || (b.stats.State == Closed && b.stats.Failures >= b.o.Failures) {
+		b.stats.Trips++
+		b.stats.OpenedAt = time.Now()
+		b.set(Open)
+	}
+}
+
+// set moves to state s. b.mu must be held.
+func (b *Breaker) set(s State) {
+	from := b.stats.State
+	if from == s {
+		return
+	}
+	b.stats.State = s
+	if b.o.OnStateChange != nil {
+		b.o.OnStateChange(from, s)
+	}
+}
+
+// Stats returns a snapshot of the breaker's state and counters.
+func (b *Breaker) Stats() Stats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.stats
+}
+
+// Interface satisfaction (compile-time assertions).
+var (
+	_ batchx.Sender = (*Breaker)(nil)
+	_ fmt.Stringer  = State(0)
+)
@@ -0,0 +1,127 @@
+package breakerx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+var errDown = errors.New("down")
+
+// sink is a batchx.Sender failing with err, counting its sends. If entered is set, sends signal it and wait for
+// release.
+type sink struct {
+	mu    sync.Mutex
+	err   error
+	sends int
+
+	entered, release chan struct{}
+}
+
+func (s *sink) Send(context.Context, [][]byte) error {
+	if s.entered != nil {
+		s.entered <- struct{}{}
+		<-s.release
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sends++
+	return s.err
+}
+
+// cool ends b's cooldown.
+func cool(b *Breaker) {
+	b.mu.Lock()
+	b.stats.OpenedAt = time.Now().Add(-b.o.Cooldown)
+	b.mu.Unlock()
+}
+
+var batch = [][]byte{[]byte("entry")}
+
+func TestBreakerOpensAfterFailures(t *testing.T) {
+	var changes []string
+	s := &sink{err: errDown}
+	b := New(s, Options{Failures: 3, OnStateChange: func(from, to State) {
+		changes = append(changes, fmt.Sprintf("%v>%v", from, to))
+	}})
+
+	for i := range 3 {
+		if err := b.Send(context.Background(), batch); !errors.Is(err, errDown) {
+			t.Fatalf("Send %d = %v, want %v", i, err, errDown)
+		}
+	}
+	if err := b.Send(context.Background(), batch); !errors.Is(err, ErrOpen) {
+		t.Fatalf("Send = %v, want %v", err, ErrOpen)
+	}
+
+	st := b.Stats()
+	if st.State != Open || st.Failures != 3 || st.Trips != 1 || st.Rejected != 1 || st.OpenedAt.IsZero() {
+		t.Errorf("Stats = %+v, want open after 3 failures, with 1 trip and 1 rejection", st)
+	}
+	if s.sends != 3 {
+		t.Errorf("sink got %d sends, want 3", s.sends)
+	}
+	if fmt.Sprint(changes) != "[closed>open]" {
+		t.Errorf("state changes %v, want [closed>open]", changes)
+	}
+}
+
+func TestBreakerFallback(t *testing.T) {
+	s, fallback := &sink{err: errDown}, &sink{}
+	b := New(s, Options{Failures: 1, Fallback: fallback})
+	b.Send(context.Background(), batch)
+
+	if err := b.Send(context.Background(), batch); err != nil {
+		t.Fatalf("Send = %v, want the fallback's nil", err)
+	}
+	if s.sends != 1 || fallback.sends != 1 {
+		t.Errorf("sink got %d sends and the fallback %d, want 1 each", s.sends, fallback.sends)
+	}
+}
+
+func TestBreakerHalfOpen(t *testing.T) {
+	tests := []struct {
+		name  string
+		err   error // the trial's
+		state State
+		trips int64
+	}{
+		{"trial fails", errDown, Open, 2},
+		{"trial succeeds", nil, Closed, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &sink{err: errDown}
+			b := New(s, Options{Failures: 1, Cooldown: time.Hour})
+			b.Send(context.Background(), batch)
+			cool(b)
+
+			s.err = tt.err
+			s.entered, s.release = make(chan struct{}), make(chan struct{})
+			trial := make(chan error)
+			go func() { trial <- b.Send(context.Background(), batch) }()
+			<-s.entered
+			if st := b.Stats().State; st != HalfOpen {
+				t.Errorf("state during the trial = %v, want %v", st, HalfOpen)
+			}
+			if err := b.Send(context.Background(), batch); !errors.Is(err, ErrOpen) {
+				t.Errorf("Send during the trial = %v, want %v", err, ErrOpen)
+			}
+			close(s.release)
+			if err := <-trial; !errors.Is(err, tt.err) {
+				t.Errorf("trial = %v, want %v", err, tt.err)
+			}
+
+			st := b.Stats()
+			if st.State != tt.state || st.Trips != tt.trips || st.Rejected != 1 {
+				t.Errorf("Stats = %+v, want %v with %d trips and 1 rejection", st, tt.state, tt.trips)
+			}
+			if tt.state == Closed && st.Failures != 0 {
+				t.Errorf("Failures = %d after closing, want 0", st.Failures)
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package bufx
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/khinshankhan/logstox"
+)
+
+// Defaults used for zero Options.
+const (
+	DefaultSize     = 256 * 1024
+	DefaultInterval = 30 * time.Second
+)
+
+// Options tune a Writer.
+type Options struct {
+	// Size is the buffer size in bytes (DefaultSize if <= 0). Writes that don't fit flush the buffer first, so entries
+	// aren't split across flushes, and writes larger than the buffer go straight through.
+	Size int
+	// Interval is how often buffered output is flushed in the background (DefaultInterval if <= 0).
+	Interval time.Duration
+	// ErrorHandler is called with the errors of background flushes; by default they're printed to stderr. Other
+	// flushes return their errors.
+	ErrorHandler func(error)
+}
+
+// Writer buffers writes to an underlying io.Writer, flushing when the buffer is full, every Options.Interval, on
+// Sync, and after entries that reach the level given to Hook. It cuts syscalls for high-volume file and network
+// logging:
+//
+//	w := bufx.New(file, bufx.Options{})
+//	defer w.Close()
+//	log := backend.New(logstox.Options[F]{Writer: w})
+//	log = logstox.WithOptions(log, logstox.Hooks(w.Hook(logstox.ErrorLevel)))
+//
+// Backends call Sync on their writers when the logger is synced, which flushes the buffer. A flush failing loses the
+// buffered output: bufio.Writer keeps failing once it has, so the buffer is reset for later writes to get through
+// when the underlying writer recovers.
+type Writer struct {
+	mu      sync.Mutex
+	w       io.Writer
+	buf     *bufio.Writer
+	onError func(error)
+
+	stop chan struct{}
+	done chan struct{}
+	once sync.Once
+}
+
+// New returns a Writer buffering writes to w. Close it to stop the background flushing.
+func New(w io.Writer, o Options) *Writer {
+	if o.Size <= 0 {
+		o.Size = DefaultSize
+	}
+	if o.Interval <= 0 {
+		o.Interval = DefaultInterval
+	}
+	if o.ErrorHandler == nil {
+		o.ErrorHandler = func(err error) { fmt.Fprintf(os.Stderr, "bufx: %v\n", err) }
+	}
+	bw := &Writer{
+		w:       w,
+		buf:     bufio.NewWriterSize(w, o.Size),
+		onError: o.ErrorHandler,
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go bw.loop(o.Interval)
+	return bw
+}
+
+// loop flushes every interval until Close.
+func (w *Writer) loop(interval time.Duration) {
+	defer close(w.done)
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			if err := w.Flush(); err != nil {
+				w.onError(err)
+			}
+		case <-w.stop:
+			return
+		}
+	}
+}
+
+// Write implements io.Writer, buffering p.
+func (w *Writer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(p) > w.buf.Available() && w.buf.Buffered() > 0 {
+		if err := w.flush(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := w.buf.Write(p)
+	if err != nil {
+		// a write larger than the buffer failed going straight through, or flushing what it didn't fit
+		w.buf.Reset(w.w)
+	}
+	return n, err
+}
+
+// Flush writes buffered output to the underlying writer.
+func (w *Writer) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.flush()
+}
+
+// flush flushes the buffer, resetting it if that fails. w.mu must be held.
+func (w *Writer) flush() error {
+	err := w.buf.Flush()
+	if err != nil {
+		w.buf.Reset(w.w)
+	}
+	return err
+}
+
+// Sync flushes the buffer, then syncs the underlying writer if it supports it (eg *os.File).
+func (w *Writer) Sync() error {
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if s, ok := w.w.(interface{ Sync() error }); ok {
+		return s.Sync()
+	}
+	return nil
+}
+
+// Close stops the background flushing and flushes the buffer. It doesn't close the underlying writer. Writes after
+// Close are still buffered, but only flushed by Flush, Sync or a full buffer.
+func (w *Writer) Close() error {
+	w.once.Do(func() { close(w.stop) })
+	<-w.done
+	return w.Flush()
+}
+
+// Hook returns a hook (see logstox.Hooks) flushing the buffer after every entry at min or above, so errors reach the
+// underlying writer right away.
+func (w *Writer) Hook(min logstox.Level) func(logstox.Entry) error {
+	return func(e logstox.Entry) error {
+		if e.Level.Compare(min) < 0 {
+			return nil
+		}
+		return w.Flush()
+	}
+}
+
+// Interface satisfaction (compile-time assertions).
+var (
+	_ io.Writer = (*Writer)(nil)
+	_ io.Closer = (*Writer)(nil)
+)
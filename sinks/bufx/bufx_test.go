@@ -0,0 +1,63 @@
+package bufx
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+// flakyWriter fails while fail is set.
+type flakyWriter struct {
+	bytes.Buffer
+	fail bool
+}
+
+func (w *flakyWriter) Write(p []byte) (int, error) {
+	if w.fail {
+		return 0, errors.New("down")
+	}
+	return w.Buffer.Write(p)
+}
+
+func TestWriterRecoversAfterFlushError(t *testing.T) {
+	tests := []struct {
+		name  string
+		write func(w *Writer) error // the failing operation
+	}{
+		{"flush", func(w *Writer) error {
+			_, _ = w.Write([]byte("lost\n"))
+			return w.Flush()
+		}},
+		{"full buffer", func(w *Writer) error {
+			_, _ = w.Write([]byte("lost\n"))
+			_, err := w.Write(bytes.Repeat([]byte("x"), 16))
+			return err
+		}},
+		{"large write", func(w *Writer) error {
+			_, err := w.Write(bytes.Repeat([]byte("x"), 64))
+			return err
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fw := &flakyWriter{fail: true}
+			w := New(fw, Options{Size: 16, ErrorHandler: func(error) {}})
+			defer w.Close()
+
+			if err := tt.write(w); err == nil {
+				t.Fatal("got no error from the failing writer")
+			}
+
+			fw.fail = false
+			if _, err := w.Write([]byte("kept\n")); err != nil {
+				t.Fatalf("Write after recovery: %v", err)
+			}
+			if err := w.Flush(); err != nil {
+				t.Fatalf("Flush after recovery: %v", err)
+			}
+			if got := fw.String(); got != "kept\n" {
+				t.Errorf("underlying writer got %q, want %q", got, "kept\n")
+			}
+		})
+	}
+}
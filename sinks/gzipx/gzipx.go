@@ -0,0 +1,119 @@
+package gzipx
+
+import (
+	"compress/gzip"
+	"io"
+	"sync"
+	"time"
+)
+
+// DefaultInterval is the flush interval used for zero Options.
+const DefaultInterval = 10 * time.Second
+
+// Options tune a Writer.
+type Options struct {
+	// Level is the gzip compression level (gzip.DefaultCompression if 0), eg gzip.BestSpeed for busy debug logs.
+	Level int
+	// Interval is how often a flush point is written in the background (DefaultInterval if <= 0), bounding how much
+	// output a crash can leave undecodable.
+	Interval time.Duration
+}
+
+// Writer gzip-compresses writes to an underlying io.Writer, for verbose logs retained on disk:
+//
+//	w, err := gzipx.New(file, gzipx.Options{Level: gzip.BestSpeed})
+//	...
+//	defer w.Close()
+//	log := backend.New(logstox.Options[F]{Writer: w})
+//
+// Compressed output only reaches the underlying writer at flush points: every Options.Interval, on Flush and on Sync
+// (which backends call when the logger is synced). Everything up to the last flush point decompresses even if the
+// stream is never closed, eg with `zcat` (which will complain about the missing end of the stream).
+type Writer struct {
+	mu sync.Mutex
+	w  io.Writer
+	gz *gzip.Writer
+
+	stop chan struct{}
+	done chan struct{}
+	once sync.Once
+}
+
+// New returns a Writer compressing writes to w. It fails on an invalid Options.Level. Close it to end the gzip
+// stream and stop the background flushing.
+func New(w io.Writer, o Options) (*Writer, error) {
+	if o.Level == 0 {
+		o.Level = gzip.DefaultCompression
+	}
+	if o.Interval <= 0 {
+		o.Interval = DefaultInterval
+	}
+	gz, err := gzip.NewWriterLevel(w, o.Level)
+	if err != nil {
+		return nil, err
+	}
+	gw := &Writer{
+		w:    w,
+		gz:   gz,
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	go gw.loop(o.Interval)
+	return gw, nil
+}
+
+// loop flushes every interval until Close.
+func (w *Writer) loop(interval time.Duration) {
+	defer close(w.done)
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			_ = w.Flush() // surfaced by the next Write, gzip keeps the error
+		case <-w.stop:
+			return
+		}
+	}
+}
+
+// Write implements io.Writer, compressing p.
+func (w *Writer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.gz.Write(p)
+}
+
+// Flush writes a flush point: all output so far is compressed and written to the underlying writer.
+func (w *Writer) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.gz.Flush()
+}
+
+// Sync flushes, then syncs the underlying writer if it supports it (eg *os.File).
+func (w *Writer) Sync() error {
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if s, ok := w.w.(interface{ Sync() error }); ok {
+		return s.Sync()
+	}
+	return nil
+}
+
+// Close stops the background flushing and ends the gzip stream. It doesn't close the underlying writer. Writes after
+// Close fail.
+func (w *Writer) Close() error {
+	w.once.Do(func() { close(w.stop) })
+	<-w.done
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.gz.Close()
+}
+
+// Interface satisfaction (compile-time assertions).
+var (
+	_ io.Writer = (*Writer)(nil)
+	_ io.Closer = (*Writer)(nil)
+)
@@ -0,0 +1,136 @@
+package gzipx
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// file is an underlying writer safe for the background flushes, counting its syncs.
+type file struct {
+	mu    sync.Mutex
+	buf   bytes.Buffer
+	syncs int
+}
+
+func (f *file) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.buf.Write(p)
+}
+
+func (f *file) Sync() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.syncs++
+	return nil
+}
+
+// decompress returns what the stream written so far decompresses to, and whether it's complete.
+func (f *file) decompress(t *testing.T) (string, bool) {
+	t.Helper()
+	f.mu.Lock()
+	compressed := bytes.Clone(f.buf.Bytes())
+	f.mu.Unlock()
+	r, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return "", false
+	}
+	out, err := io.ReadAll(r)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("decompressing: %v", err)
+	}
+	return string(out), err == nil
+}
+
+const lines = "{\"msg\":\"a\"}\n{\"msg\":\"b\"}\n"
+
+func write(t *testing.T, w *Writer, s string) {
+	t.Helper()
+	if n, err := w.Write([]byte(s)); n != len(s) || err != nil {
+		t.Fatalf("Write = %d, %v, want %d, nil", n, err, len(s))
+	}
+}
+
+func TestWriterRoundTrip(t *testing.T) {
+	f := &file{}
+	w, err := New(f, Options{Level: gzip.BestSpeed})
+	if err != nil {
+		t.Fatal(err)
+	}
+	write(t, w, lines)
+	write(t, w, strings.Repeat("x", 1<<16))
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, complete := f.decompress(t); !complete || got != lines+strings.Repeat("x", 1<<16) {
+		t.Errorf("decompressed %d bytes, complete %v, want everything written and the end of the stream", len(got),
+			complete)
+	}
+	if err := w.Close(); err != nil {
+		t.Errorf("second Close = %v, want nil", err)
+	}
+	if _, err := w.Write([]byte(lines)); err == nil {
+		t.Error("Write after Close succeeded")
+	}
+}
+
+func TestWriterFlush(t *testing.T) {
+	tests := []struct {
+		name  string
+		flush func(w *Writer) error
+		syncs int
+	}{
+		{"flush", (*Writer).Flush, 0},
+		{"sync", (*Writer).Sync, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &file{}
+			w, _ := New(f, Options{Interval: time.Hour})
+			defer w.Close()
+			write(t, w, lines)
+			if got, _ := f.decompress(t); got != "" {
+				t.Fatalf("decompressed %q before flushing, want nothing", got)
+			}
+
+			if err := tt.flush(w); err != nil {
+				t.Fatal(err)
+			}
+			if got, complete := f.decompress(t); got != lines || complete {
+				t.Errorf("decompressed %q, complete %v, want %q without the end of the stream", got, complete, lines)
+			}
+			if f.syncs != tt.syncs {
+				t.Errorf("synced the underlying writer %d times, want %d", f.syncs, tt.syncs)
+			}
+		})
+	}
+}
+
+func TestWriterFlushesInTheBackground(t *testing.T) {
+	f := &file{}
+	w, _ := New(f, Options{Interval: time.Millisecond})
+	defer w.Close()
+	write(t, w, lines)
+
+	for deadline := time.Now().Add(time.Second); ; time.Sleep(time.Millisecond) {
+		if got, _ := f.decompress(t); got == lines {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("nothing was flushed after the interval")
+		}
+	}
+}
+
+func TestNewFailsOnInvalidLevel(t *testing.T) {
+	if _, err := New(io.Discard, Options{Level: 42}); err == nil {
+		t.Error("New succeeded with level 42")
+	}
+}
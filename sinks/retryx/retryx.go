@@ -0,0 +1,266 @@
+package retryx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/khinshankhan/logstox/sinks/batchx"
+)
+
+// Defaults used for zero Options.
+const (
+	DefaultMinBackoff  = 100 * time.Millisecond
+	DefaultMaxBackoff  = 30 * time.Second
+	DefaultMaxAttempts = 3
+	DefaultMaxQueued   = 100000
+	DefaultTimeout     = 10 * time.Second
+)
+
+// ErrClosed is returned by sends to a closed Sender.
+var ErrClosed = errors.New("retryx: sender closed")
+
+// Options tune a Sender.
+type Options struct {
+	// MinBackoff and MaxBackoff bound the wait between attempts, which doubles from MinBackoff up to MaxBackoff and
+	// is jittered down to half of it so recovering sinks aren't hit in lockstep (DefaultMinBackoff and
+	// DefaultMaxBackoff if <= 0).
+	MinBackoff, MaxBackoff time.Duration
+	// MaxAttempts is how many times Send tries a batch before queueing it (DefaultMaxAttempts if <= 0).
+	MaxAttempts int
+	// MaxQueued bounds how many entries are queued during an outage (DefaultMaxQueued if <= 0). Past it, the oldest
+	// batches are dropped.
+	MaxQueued int
+	// Timeout bounds every attempt made while draining the queue (DefaultTimeout if <= 0).
+	Timeout time.Duration
+	// Transient reports whether an error is worth retrying; by default every error is. Batches failing with other
+	// errors are neither retried nor queued.
+	Transient func(error) bool
+}
+
+// Sender retries the batches a wrapped batchx.Sender fails to send, with exponential backoff and jitter, and queues
+// them in memory during outages:
+//
+//	w := batchx.New(retryx.New(sender, retryx.Options{}), batchx.Options{})
+//
+// Only one batch is retried at a time: the batches sent meanwhile, eg by other batchx flushers, are queued behind it.
+// A batch failing MaxAttempts times is queued ahead of them, and the batches after it are queued while the queue is
+// being drained in the background, so the sink still receives them in order once it recovers. Send succeeds for
+// queued batches, see Queued and Dropped for what's waiting or was lost.
+type Sender struct {
+	s batchx.Sender
+	o Options
+
+	mu       sync.Mutex
+	queue    [][][]byte
+	queued   int    // entries in queue
+	popped   uint64 // batches removed from the head of queue, sent or dropped
+	draining bool   // a batch is being retried or the queue drained, so batches sent meanwhile are queued
+	closed   bool
+	dropped  atomic.Int64
+
+	stop chan struct{}
+	once sync.Once
+}
+
+// New returns a Sender retrying s. Close it to stop draining.
+func New(s batchx.Sender, o Options) *Sender {
+	if o.MinBackoff <= 0 {
+		o.MinBackoff = DefaultMinBackoff
+	}
+	if o.MaxBackoff <= 0 {
+		o.MaxBackoff = DefaultMaxBackoff
+	}
+	if o.MaxAttempts <= 0 {
+		o.MaxAttempts = DefaultMaxAttempts
+	}
+	if o.MaxQueued <= 0 {
+		o.MaxQueued = DefaultMaxQueued
+	}
+	if o.Timeout <= 0 {
+		o.Timeout = DefaultTimeout
+	}
+	if o.Transient == nil {
+		o.Transient = func(error) bool { return true }
+	}
+	return &Sender{s: s, o: o, stop: make(chan struct{})}
+}
+
+// Send implements batchx.Sender. It sends batch right away unless an earlier batch is being retried or queued,
+// retrying it up to MaxAttempts times while ctx allows, then queues it. It fails for errors that aren't Transient,
+// and with ErrClosed once the Sender is closed.
+func (r *Sender) Send(ctx context.Context, batch [][]byte) error {
+	if queued, err := r.enqueueIfDraining(batch); queued || err != nil {
+		return err
+	}
+	err := r.s.Send(ctx, batch)
+	if err == nil || !r.o.Transient(err) || !r.claim(batch) {
+		return err
+	}
+	for attempt := 1; attempt < r.o.MaxAttempts; attempt++ {
+		select {
+		case <-time.After(r.backoff(attempt - 1)):
+		case <-ctx.Done():
+			r.release(batch)
+			return nil
+		case <-r.stop:
+			return err
+		}
+		if err = r.s.Send(ctx, batch); err == nil || !r.o.Transient(err) {
+			r.release(nil)
+			return err
+		}
+	}
+	r.release(batch)
+	return nil
+}
+
+// backoff returns the jittered wait after the given failed attempt.
+func (r *Sender) backoff(attempt int) time.Duration {
+	d := r.o.MaxBackoff
+	if attempt < 32 {
+		d = min(r.o.MinBackoff<<attempt, r.o.MaxBackoff)
+	}
+	return d/2 + rand.N(d/2+1)
+}
+
+// enqueueIfDraining queues batch if a batch is being retried or queued, failing once the Sender is closed.
+func (r *Sender) enqueueIfDraining(batch [][]byte) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return false, ErrClosed
+	}
+	if !r.draining {
+		return false, nil
+	}
+	r.push(batch)
+	return true, nil
+}
+
+// claim reports whether the failed batch is the one to retry, queueing it if another batch got there first.
+func (r *Sender) claim(batch [][]byte) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.draining {
+		r.push(batch)
+		return false
+	}
+	r.draining = true
+	return true
+}
+
+// release ends the retries of the claimed batch, queueing it ahead of the batches sent meanwhile if it's not nil, and
+// drains the queue if it isn't empty.
+func (r *Sender) release(batch [][]byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if batch != nil {
+		r.queue = append([][][]byte{batch}, r.queue...)
+		r.queued += len(batch)
+		r.trim()
+	}
+	switch {
+	case len(r.queue) == 0:
+		r.draining = false
+	case !r.closed:
+		go r.drain()
+	}
+}
+
+// push appends batch to the queue, dropping the oldest batches past MaxQueued. r.mu must be held.
+func (r *Sender) push(batch [][]byte) {
+	r.queue = append(r.queue, batch)
+	r.queued += len(batch)
+	r.trim()
+}
+
+// trim drops the oldest batches past MaxQueued. r.mu must be held.
+func (r *Sender) trim() {
+	for r.queued > r.o.MaxQueued && len(r.queue) > 0 {
+		r.dropped.Add(int64(len(r.queue[0])))
+		r.pop()
+	}
+}
+
+// pop removes the batch at the head of the queue. r.mu must be held.
+func (r *Sender) pop() {
+	r.queued -= len(r.queue[0])
+	r.queue[0] = nil
+	r.queue = r.queue[1:]
+	r.popped++
+}
+
+// drain sends the queued batches in order, backing off while the sink keeps failing, until the queue is empty or the
+// Sender is closed.
+func (r *Sender) drain() {
+	attempt := 0
+	for {
+		r.mu.Lock()
+		if len(r.queue) == 0 {
+			r.draining = false
+			r.mu.Unlock()
+			return
+		}
+		batch, head := r.queue[0], r.popped
+		r.mu.Unlock()
+
+		ctx, cancel := context.WithTimeout(context.Background(), r.o.Timeout)
+		err := r.s.Send(ctx, batch)
+		cancel()
+
+		if err == nil || !r.o.Transient(err) {
+			attempt = 0
+			r.mu.Lock()
+			if r.popped == head { // unless it was dropped meanwhile
+				r.pop()
+			}
+			r.mu.Unlock()
+			if err != nil {
+				r.dropped.Add(int64(len(batch)))
+			}
+			continue
+		}
+
+		select {
+		case <-time.After(r.backoff(attempt)):
+			attempt++
+		case <-r.stop:
+			return
+		}
+	}
+}
+
+// Queued returns how many entries are queued, waiting for the sink to recover.
+func (r *Sender) Queued() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.queued
+}
+
+// Dropped returns how many entries were lost so far, because the queue was full or their batch failed with an error
+// that isn't Transient while draining.
+func (r *Sender) Dropped() int64 {
+	return r.dropped.Load()
+}
+
+// Close stops retrying, failing if entries were still queued: they're lost. Sends after Close fail with ErrClosed.
+func (r *Sender) Close() error {
+	r.mu.Lock()
+	r.closed = true
+	r.mu.Unlock()
+	r.once.Do(func() { close(r.stop) })
+	if n := r.Queued(); n > 0 {
+		return fmt.Errorf("retryx: %d queued entries lost", n)
+	}
+	return nil
+}
+
+// Interface satisfaction (compile-time assertions).
+var (
+	_ batchx.Sender = (*Sender)(nil)
+)
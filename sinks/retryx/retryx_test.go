@@ -0,0 +1,157 @@
+package retryx
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+var errDown = errors.New("down")
+
+// sink is a batchx.Sender failing its first fails sends, recording every send of a batch by its first entry, "!"
+// appended to the failed ones.
+type sink struct {
+	mu     sync.Mutex
+	fails  int
+	err    error // errDown if nil
+	got    []string
+	failed chan struct{} // signaled on failed sends
+}
+
+func newSink(fails int) *sink { return &sink{fails: fails, failed: make(chan struct{}, 16)} }
+
+func (s *sink) Send(_ context.Context, batch [][]byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.fails > 0 {
+		s.fails--
+		s.got = append(s.got, string(batch[0])+"!")
+		s.failed <- struct{}{}
+		if s.err != nil {
+			return s.err
+		}
+		return errDown
+	}
+	s.got = append(s.got, string(batch[0]))
+	return nil
+}
+
+func (s *sink) sent() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.got)
+}
+
+func batch(e string) [][]byte { return [][]byte{[]byte(e)} }
+
+// fast returns Options backing off by a millisecond.
+func fast(attempts int) Options {
+	return Options{MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond, MaxAttempts: attempts}
+}
+
+// drained waits for r's queue to be drained.
+func drained(t *testing.T, r *Sender) {
+	t.Helper()
+	for deadline := time.Now().Add(time.Second); r.Queued() > 0; time.Sleep(time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d entries still queued", r.Queued())
+		}
+	}
+}
+
+func TestSenderRetries(t *testing.T) {
+	tests := []struct {
+		name     string
+		fails    int
+		attempts int
+		want     []string
+	}{
+		{"no failure", 0, 3, []string{"a"}},
+		{"within attempts", 2, 3, []string{"a!", "a!", "a"}},
+		{"queued then drained", 3, 2, []string{"a!", "a!", "a!", "a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newSink(tt.fails)
+			r := New(s, fast(tt.attempts))
+			if err := r.Send(context.Background(), batch("a")); err != nil {
+				t.Fatalf("Send = %v, want nil", err)
+			}
+			drained(t, r)
+			if err := r.Close(); err != nil {
+				t.Fatal(err)
+			}
+			if got := s.sent(); !slices.Equal(got, tt.want) {
+				t.Errorf("sent %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSenderKeepsOrderWhileQueued(t *testing.T) {
+	s := newSink(2)
+	r := New(s, fast(2))
+	defer r.Close()
+	for _, e := range []string{"a", "b", "c"} {
+		if err := r.Send(context.Background(), batch(e)); err != nil {
+			t.Fatalf("Send(%s) = %v, want nil", e, err)
+		}
+	}
+	drained(t, r)
+	if want := []string{"a!", "a!", "a", "b", "c"}; !slices.Equal(s.sent(), want) {
+		t.Errorf("sent %v, want %v", s.sent(), want)
+	}
+}
+
+func TestSenderRetriesOneBatchAtATime(t *testing.T) {
+	s := newSink(1)
+	r := New(s, Options{MinBackoff: 50 * time.Millisecond, MaxBackoff: 50 * time.Millisecond})
+	defer r.Close()
+
+	done := make(chan error)
+	go func() { done <- r.Send(context.Background(), batch("a")) }()
+	<-s.failed
+	// another flusher's batch, while a backs off
+	if err := r.Send(context.Background(), batch("b")); err != nil {
+		t.Fatalf("Send(b) = %v, want nil", err)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("Send(a) = %v, want nil", err)
+	}
+	drained(t, r)
+	if want := []string{"a!", "a", "b"}; !slices.Equal(s.sent(), want) {
+		t.Errorf("sent %v, want %v", s.sent(), want)
+	}
+}
+
+func TestSenderFailsForPermanentErrors(t *testing.T) {
+	errBad := errors.New("bad request")
+	s := newSink(1)
+	s.err = errBad
+	r := New(s, Options{Transient: func(err error) bool { return !errors.Is(err, errBad) }})
+	defer r.Close()
+
+	if err := r.Send(context.Background(), batch("a")); !errors.Is(err, errBad) {
+		t.Errorf("Send = %v, want %v", err, errBad)
+	}
+	if want := []string{"a!"}; !slices.Equal(s.sent(), want) || r.Queued() != 0 {
+		t.Errorf("sent %v with %d queued, want %v without retries", s.sent(), r.Queued(), want)
+	}
+}
+
+func TestSenderClose(t *testing.T) {
+	s := newSink(2) // the queue's drain fails too
+	r := New(s, Options{MinBackoff: time.Hour, MaxBackoff: time.Hour, MaxAttempts: 1})
+	if err := r.Send(context.Background(), batch("a")); err != nil {
+		t.Fatalf("Send = %v, want it queued", err)
+	}
+	if err := r.Close(); err == nil {
+		t.Error("Close = nil, want the queued entry reported lost")
+	}
+	if err := r.Send(context.Background(), batch("b")); !errors.Is(err, ErrClosed) {
+		t.Errorf("Send after Close = %v, want %v", err, ErrClosed)
+	}
+}
@@ -0,0 +1,356 @@
+package sqllog
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"reflect"
+	"time"
+)
+
+// conn logs the statements run on a connection.
+type conn struct {
+	c driver.Conn
+	o *Options
+}
+
+// Interface satisfaction (compile-time assertions).
+var (
+	_ driver.Conn               = (*conn)(nil)
+	_ driver.ConnBeginTx        = (*conn)(nil)
+	_ driver.ConnPrepareContext = (*conn)(nil)
+	_ driver.ExecerContext      = (*conn)(nil)
+	_ driver.QueryerContext     = (*conn)(nil)
+	_ driver.Pinger             = (*conn)(nil)
+	_ driver.SessionResetter    = (*conn)(nil)
+	_ driver.Validator          = (*conn)(nil)
+	_ driver.NamedValueChecker  = (*conn)(nil)
+	_ driver.Stmt               = (*stmt)(nil)
+	_ driver.StmtExecContext    = (*stmt)(nil)
+	_ driver.StmtQueryContext   = (*stmt)(nil)
+	_ driver.NamedValueChecker  = (*stmt)(nil)
+	_ driver.Tx                 = tx{}
+	_ driver.RowsNextResultSet  = (*rows)(nil)
+)
+
+func wrapConn(c driver.Conn, o *Options) *conn {
+	return &conn{c: c, o: o}
+}
+
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c *conn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	start := time.Now()
+	var s driver.Stmt
+	var err error
+	if pc, ok := c.c.(driver.ConnPrepareContext); ok {
+		s, err = pc.PrepareContext(ctx, query)
+	} else if err = ctx.Err(); err == nil {
+		s, err = c.c.Prepare(query)
+	}
+	if err != nil {
+		c.o.log(ctx, "prepare", query, nil, start, -1, err)
+		return nil, err
+	}
+	return &stmt{s: s, query: query, o: c.o}, nil
+}
+
+func (c *conn) Close() error { return c.c.Close() }
+
+func (c *conn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *conn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	start := time.Now()
+	var t driver.Tx
+	var err error
+	if bt, ok := c.c.(driver.ConnBeginTx); ok {
+		t, err = bt.BeginTx(ctx, opts)
+	} else {
+		// what database/sql does for drivers without BeginTx
+		switch {
+		case opts.Isolation != 0:
+			err = errors.New("sqllog: driver does not support non-default isolation level")
+		case opts.ReadOnly:
+			err = errors.New("sqllog: driver does not support read-only transactions")
+		default:
+			if err = ctx.Err(); err == nil {
+				t, err = c.c.Begin()
+			}
+		}
+	}
+	if err != nil {
+		c.o.log(ctx, "begin", "", nil, start, -1, err)
+		return nil, err
+	}
+	return tx{t: t, ctx: ctx, o: c.o}, nil
+}
+
+func (c *conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	ec, ok := c.c.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip // database/sql prepares a statement instead
+	}
+	start := time.Now()
+	res, err := ec.ExecContext(ctx, query, args)
+	c.o.log(ctx, "exec", query, args, start, affected(res, err), err)
+	return res, err
+}
+
+func (c *conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	qc, ok := c.c.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip // database/sql prepares a statement instead
+	}
+	start := time.Now()
+	rs, err := qc.QueryContext(ctx, query, args)
+	if err != nil {
+		c.o.log(ctx, "query", query, args, start, -1, err)
+		return nil, err
+	}
+	return &rows{r: rs, ctx: ctx, query: query, args: args, start: start, o: c.o}, nil
+}
+
+func (c *conn) Ping(ctx context.Context) error {
+	if p, ok := c.c.(driver.Pinger); ok {
+		return p.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *conn) ResetSession(ctx context.Context) error {
+	if sr, ok := c.c.(driver.SessionResetter); ok {
+		return sr.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *conn) IsValid() bool {
+	if v, ok := c.c.(driver.Validator); ok {
+		return v.IsValid()
+	}
+	return true
+}
+
+func (c *conn) CheckNamedValue(nv *driver.NamedValue) error {
+	if nc, ok := c.c.(driver.NamedValueChecker); ok {
+		return nc.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip // database/sql's default conversion
+}
+
+// stmt logs the runs of a prepared statement.
+type stmt struct {
+	s     driver.Stmt
+	query string
+	o     *Options
+}
+
+func (s *stmt) Close() error  { return s.s.Close() }
+func (s *stmt) NumInput() int { return s.s.NumInput() }
+
+func (s *stmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.ExecContext(context.Background(), named(args))
+}
+
+func (s *stmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.QueryContext(context.Background(), named(args))
+}
+
+func (s *stmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	start := time.Now()
+	var res driver.Result
+	var err error
+	if ec, ok := s.s.(driver.StmtExecContext); ok {
+		res, err = ec.ExecContext(ctx, args)
+	} else {
+		var vs []driver.Value
+		if vs, err = values(ctx, args); err == nil {
+			res, err = s.s.Exec(vs)
+		}
+	}
+	s.o.log(ctx, "exec", s.query, args, start, affected(res, err), err)
+	return res, err
+}
+
+func (s *stmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	start := time.Now()
+	var rs driver.Rows
+	var err error
+	if qc, ok := s.s.(driver.StmtQueryContext); ok {
+		rs, err = qc.QueryContext(ctx, args)
+	} else {
+		var vs []driver.Value
+		if vs, err = values(ctx, args); err == nil {
+			rs, err = s.s.Query(vs)
+		}
+	}
+	if err != nil {
+		s.o.log(ctx, "query", s.query, args, start, -1, err)
+		return nil, err
+	}
+	return &rows{r: rs, ctx: ctx, query: s.query, args: args, start: start, o: s.o}, nil
+}
+
+func (s *stmt) CheckNamedValue(nv *driver.NamedValue) error {
+	switch ss := s.s.(type) {
+	case driver.NamedValueChecker:
+		return ss.CheckNamedValue(nv)
+	case driver.ColumnConverter: // honored by database/sql for older drivers
+		v, err := ss.ColumnConverter(nv.Ordinal - 1).ConvertValue(nv.Value)
+		if err != nil {
+			return err
+		}
+		nv.Value = v
+		return nil
+	default:
+		return driver.ErrSkip // database/sql's default conversion
+	}
+}
+
+// tx logs the failures of a transaction.
+type tx struct {
+	t   driver.Tx
+	ctx context.Context // the context it began with
+	o   *Options
+}
+
+func (t tx) Commit() error {
+	start := time.Now()
+	err := t.t.Commit()
+	if err != nil {
+		t.o.log(t.ctx, "commit", "", nil, start, -1, err)
+	}
+	return err
+}
+
+func (t tx) Rollback() error {
+	start := time.Now()
+	err := t.t.Rollback()
+	if err != nil {
+		t.o.log(t.ctx, "rollback", "", nil, start, -1, err)
+	}
+	return err
+}
+
+// rows counts the rows of a query, logging it when they're closed.
+type rows struct {
+	r     driver.Rows
+	ctx   context.Context
+	query string
+	args  []driver.NamedValue
+	start time.Time
+	o     *Options
+	n     int64
+	err   error // the error that ended the rows, other than io.EOF
+}
+
+func (r *rows) Columns() []string { return r.r.Columns() }
+
+func (r *rows) Next(dest []driver.Value) error {
+	err := r.r.Next(dest)
+	switch {
+	case err == nil:
+		r.n++
+	case err != io.EOF:
+		r.err = err
+	}
+	return err
+}
+
+func (r *rows) Close() error {
+	err := r.r.Close()
+	logged := r.err
+	if logged == nil {
+		logged = err
+	}
+	r.o.log(r.ctx, "query", r.query, r.args, r.start, r.n, logged)
+	return err
+}
+
+func (r *rows) HasNextResultSet() bool {
+	rs, ok := r.r.(driver.RowsNextResultSet)
+	return ok && rs.HasNextResultSet()
+}
+
+func (r *rows) NextResultSet() error {
+	if rs, ok := r.r.(driver.RowsNextResultSet); ok {
+		return rs.NextResultSet()
+	}
+	return io.EOF
+}
+
+func (r *rows) ColumnTypeScanType(i int) reflect.Type {
+	if ct, ok := r.r.(driver.RowsColumnTypeScanType); ok {
+		return ct.ColumnTypeScanType(i)
+	}
+	return reflect.TypeFor[any]()
+}
+
+func (r *rows) ColumnTypeDatabaseTypeName(i int) string {
+	if ct, ok := r.r.(driver.RowsColumnTypeDatabaseTypeName); ok {
+		return ct.ColumnTypeDatabaseTypeName(i)
+	}
+	return ""
+}
+
+func (r *rows) ColumnTypeLength(i int) (int64, bool) {
+	if ct, ok := r.r.(driver.RowsColumnTypeLength); ok {
+		return ct.ColumnTypeLength(i)
+	}
+	return 0, false
+}
+
+func (r *rows) ColumnTypeNullable(i int) (nullable, ok bool) {
+	if ct, ok := r.r.(driver.RowsColumnTypeNullable); ok {
+		return ct.ColumnTypeNullable(i)
+	}
+	return false, false
+}
+
+func (r *rows) ColumnTypePrecisionScale(i int) (precision, scale int64, ok bool) {
+	if ct, ok := r.r.(driver.RowsColumnTypePrecisionScale); ok {
+		return ct.ColumnTypePrecisionScale(i)
+	}
+	return 0, 0, false
+}
+
+// affected returns the rows affected by an exec, -1 if it failed or the driver doesn't know.
+func affected(res driver.Result, err error) int64 {
+	if err != nil || res == nil {
+		return -1
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return -1
+	}
+	return n
+}
+
+// named converts positional arguments to named ones.
+func named(args []driver.Value) []driver.NamedValue {
+	nvs := make([]driver.NamedValue, len(args))
+	for i, v := range args {
+		nvs[i] = driver.NamedValue{Ordinal: i + 1, Value: v}
+	}
+	return nvs
+}
+
+// values converts named arguments to positional ones for drivers that don't take names, like database/sql does.
+func values(ctx context.Context, args []driver.NamedValue) ([]driver.Value, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	vs := make([]driver.Value, len(args))
+	for i, a := range args {
+		if a.Name != "" {
+			return nil, errors.New("sqllog: driver does not support the use of Named Parameters")
+		}
+		vs[i] = a.Value
+	}
+	return vs, nil
+}
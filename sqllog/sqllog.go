@@ -0,0 +1,202 @@
+package sqllog
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/khinshankhan/logstox"
+	"github.com/khinshankhan/logstox/contextx"
+	"github.com/khinshankhan/logstox/fields"
+)
+
+// Keys of the fields statements are logged with, following the OpenTelemetry semantic conventions for databases
+// where there's one.
+const (
+	OperationKey    = "db.operation.name"         // the database/sql call: query, exec, prepare, begin, commit or rollback
+	QueryKey        = "db.query.text"             // the statement
+	ArgsKey         = "db.query.args"             // its arguments, see Options.Args
+	RowsKey         = "db.response.returned_rows" // how many rows a query returned
+	RowsAffectedKey = "db.rows_affected"          // how many rows an exec affected, if the driver knows
+	DurationKey     = "event.duration"            // how long it took, until its rows were closed for a query
+	SlowKey         = "db.slow"                   // true if it took at least Options.SlowThreshold
+)
+
+// Options configure the logging of Wrap and WrapConnector.
+type Options struct {
+	// Logger logs the statements whose context carries no logger (see contextx.Logger), those that do are logged to
+	// that one, so request-scoped fields flow in. If nil, statements without one aren't logged.
+	Logger logstox.Logger[fields.Field]
+	// Level is the level statements are logged at, InfoLevel by default. Failed statements are logged at ErrorLevel
+	// and slow ones at WarnLevel, while those whose context was canceled, eg by a client going away, are logged at
+	// Level as canceled rather than failed. Levels above ErrorLevel are logged at ErrorLevel.
+	Level logstox.Level
+	// SlowThreshold, if > 0, logs statements taking at least that long at WarnLevel, with SlowKey.
+	SlowThreshold time.Duration
+	// Args logs the arguments of statements under ArgsKey. They may hold personal data or secrets, see Redact.
+	Args bool
+	// Redact, if set, rewrites every argument before it's logged, eg to mask the ones bound to sensitive columns:
+	//
+	//	Redact: func(arg driver.NamedValue) any {
+	//		if s, ok := arg.Value.(string); ok && len(s) > 64 {
+	//			return s[:64] + "..."
+	//		}
+	//		return arg.Value
+	//	},
+	Redact func(arg driver.NamedValue) any
+}
+
+// Wrap returns d logging every statement run through it (see Options), to be registered under its own name:
+//
+//	sql.Register("postgres+log", sqllog.Wrap(&pq.Driver{}, sqllog.Options{Logger: log, SlowThreshold: time.Second}))
+//	db, err := sql.Open("postgres+log", dsn)
+//
+// Queries are logged once their rows are closed, with how many were read. Transactions are only logged when
+// beginning, committing or rolling them back fails; their statements are logged like any other.
+func Wrap(d driver.Driver, o Options) driver.Driver {
+	return wrappedDriver{d: d, o: &o}
+}
+
+// WrapConnector returns c logging every statement run through it, for drivers providing connectors:
+//
+//	db := sql.OpenDB(sqllog.WrapConnector(connector, sqllog.Options{Logger: log}))
+func WrapConnector(c driver.Connector, o Options) driver.Connector {
+	return connector{c: c, o: &o}
+}
+
+// wrappedDriver is the driver returned by Wrap.
+type wrappedDriver struct {
+	d driver.Driver
+	o *Options
+}
+
+// Interface satisfaction (compile-time assertions).
+var (
+	_ driver.Driver        = wrappedDriver{}
+	_ driver.DriverContext = wrappedDriver{}
+	_ driver.Connector     = connector{}
+	_ io.Closer            = connector{}
+)
+
+func (d wrappedDriver) Open(name string) (driver.Conn, error) {
+	c, err := d.d.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return wrapConn(c, d.o), nil
+}
+
+func (d wrappedDriver) OpenConnector(name string) (driver.Connector, error) {
+	if dc, ok := d.d.(driver.DriverContext); ok {
+		c, err := dc.OpenConnector(name)
+		if err != nil {
+			return nil, err
+		}
+		return connector{c: c, o: d.o}, nil
+	}
+	return connector{c: dsnConnector{name: name, d: d.d}, o: d.o}, nil
+}
+
+// connector is the connector returned by WrapConnector.
+type connector struct {
+	c driver.Connector
+	o *Options
+}
+
+func (c connector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.c.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return wrapConn(conn, c.o), nil
+}
+
+func (c connector) Driver() driver.Driver {
+	return wrappedDriver{d: c.c.Driver(), o: c.o}
+}
+
+// Close closes the wrapped connector if it's an io.Closer, as sql.DB.Close does with its connector.
+func (c connector) Close() error {
+	if cl, ok := c.c.(io.Closer); ok {
+		return cl.Close()
+	}
+	return nil
+}
+
+// dsnConnector connects with a driver that doesn't provide connectors, like database/sql does.
+type dsnConnector struct {
+	name string
+	d    driver.Driver
+}
+
+func (c dsnConnector) Connect(context.Context) (driver.Conn, error) { return c.d.Open(c.name) }
+
+func (c dsnConnector) Driver() driver.Driver { return c.d }
+
+// log logs the operation op of the statement query run with args, which started at start, returned rows rows (or
+// affected them, for an exec; -1 if unknown) and err.
+func (o *Options) log(ctx context.Context, op, query string, args []driver.NamedValue, start time.Time, rows int64, err error) {
+	if errors.Is(err, driver.ErrSkip) {
+		return // database/sql falls back to another path, which is logged
+	}
+	l := contextx.Logger(ctx, o.Logger)
+	if l == nil {
+		return
+	}
+	dur := time.Since(start)
+	lvl := o.Level
+	slow := o.SlowThreshold > 0 && dur >= o.SlowThreshold
+	canceled := errors.Is(err, context.Canceled)
+	switch {
+	case err != nil && !canceled:
+		lvl = logstox.ErrorLevel
+	case slow && lvl.Compare(logstox.WarnLevel) < 0:
+		lvl = logstox.WarnLevel
+	case lvl.Compare(logstox.ErrorLevel) > 0:
+		lvl = logstox.ErrorLevel
+	}
+	if !logstox.Enabled(l, lvl) {
+		return
+	}
+
+	fs := make([]fields.Field, 0, 8)
+	fs = append(fs, fields.String(OperationKey, op))
+	if query != "" {
+		fs = append(fs, fields.String(QueryKey, query))
+	}
+	if o.Args && len(args) > 0 {
+		vs := make([]any, len(args))
+		for i, a := range args {
+			if o.Redact != nil {
+				vs[i] = o.Redact(a)
+			} else {
+				vs[i] = a.Value
+			}
+		}
+		fs = append(fs, fields.Any(ArgsKey, vs))
+	}
+	if rows >= 0 {
+		key := RowsKey
+		if op == "exec" {
+			key = RowsAffectedKey
+		}
+		fs = append(fs, fields.Int64(key, rows))
+	}
+	fs = append(fs, fields.Duration(DurationKey, dur))
+	if slow {
+		fs = append(fs, fields.Bool(SlowKey, true))
+	}
+
+	msg := "sql " + op
+	switch {
+	case canceled:
+		msg += " canceled"
+		fs = append(fs, fields.Error(err))
+	case err != nil:
+		msg += " failed"
+		fs = append(fs, fields.Error(err))
+	}
+	logstox.LogAt(l, lvl, msg, fs...)
+}
@@ -0,0 +1,62 @@
+package sqllog
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/khinshankhan/logstox"
+	"github.com/khinshankhan/logstox/backend/memx"
+	"github.com/khinshankhan/logstox/fields"
+)
+
+// fakeConnector hands out connections whose execs fail with err, and records whether it was closed.
+type fakeConnector struct {
+	err    error
+	closed bool
+}
+
+func (c *fakeConnector) Connect(context.Context) (driver.Conn, error) { return fakeConn{c.err}, nil }
+func (c *fakeConnector) Driver() driver.Driver                        { return nil }
+func (c *fakeConnector) Close() error                                 { c.closed = true; return nil }
+
+type fakeConn struct{ err error }
+
+func (fakeConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (fakeConn) Close() error                        { return nil }
+func (fakeConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+
+func (c fakeConn) ExecContext(context.Context, string, []driver.NamedValue) (driver.Result, error) {
+	return driver.RowsAffected(1), c.err
+}
+
+func TestLogLevels(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		wantLevel logstox.Level
+		wantMsg   string
+	}{
+		{"ok", nil, logstox.InfoLevel, "sql exec"},
+		{"failed", errors.New("syntax error"), logstox.ErrorLevel, "sql exec failed"},
+		{"canceled", context.Canceled, logstox.InfoLevel, "sql exec canceled"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := memx.NewRecorder(4)
+			c := &fakeConnector{err: tt.err}
+			db := sql.OpenDB(WrapConnector(c, Options{Logger: memx.Backend{Recorder: rec}.New(logstox.Options[fields.Field]{})}))
+			_, _ = db.Exec("DELETE FROM t")
+			if err := db.Close(); err != nil || !c.closed {
+				t.Errorf("Close = %v, closed the connector: %v; want it closed", err, c.closed)
+			}
+
+			es := rec.Entries()
+			if len(es) != 1 || es[0].Level != tt.wantLevel || es[0].Message != tt.wantMsg {
+				t.Fatalf("logged %v, want one %v %q entry", es, tt.wantLevel, tt.wantMsg)
+			}
+		})
+	}
+}
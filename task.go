@@ -0,0 +1,145 @@
+package logstox
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/khinshankhan/logstox/fields"
+)
+
+// Keys of the fields TaskLogger adds.
+const (
+	TaskKey         = "task"       // the task's name, on every entry
+	StepKey         = "step"       // the step's number, "3/12" (or "3" if the total isn't known)
+	StepNameKey     = "step_name"  // the step's name
+	StepDurationKey = "step_took"  // how long the step took, on its "step done" entry
+	TaskDurationKey = "task_took"  // how long the task took, on the summary
+	TaskStepsKey    = "task_steps" // Dict of each step's duration by name, on the summary
+)
+
+// TaskLogger logs a task made of sequential steps, for CLI tools and batch jobs: steps are numbered, each step's
+// start and duration are logged, and End logs a summary. See Task.
+//
+// It embeds the task's logger, whose entries carry TaskKey, for entries outside any step. TaskLogger is safe for
+// concurrent use, though steps are meant to follow one another.
+type TaskLogger struct {
+	Logger[fields.Field]
+
+	own   Logger[fields.Field] // the task's logger, skipping TaskLogger's own methods for file:line
+	total int
+	start time.Time
+
+	mu      sync.Mutex
+	steps   []taskStep
+	running bool // whether the last step is running
+	ended   bool
+}
+
+// taskStep is a step of a task.
+type taskStep struct {
+	name  string
+	start time.Time
+	took  time.Duration
+}
+
+// Task starts a task named name on l, steps being how many steps it has (0 if it isn't known):
+//
+//	task := logstox.Task(log, "migrate-db", 3)
+//	step := task.Step("schema") // step started, step=1/3
+//	step.Info("table created", fields.String("table", "users"))
+//	step = task.Step("backfill") // step done (with step_took), then step started, step=2/3
+//	...
+//	task.End(err) // task done (or failed) with task_took and every step's duration
+func Task(l Logger[fields.Field], name string, steps int) *TaskLogger {
+	tl := l.With(fields.String(TaskKey, name))
+	return &TaskLogger{
+		Logger: tl,
+		own:    WithOptions(tl, AddCallerSkip(1)),
+		total:  steps,
+		start:  time.Now(),
+	}
+}
+
+// Step ends the current step, if any, and starts the next one named name, returning its logger: entries carry
+// TaskKey, StepKey and StepNameKey. The end of a step is logged as "step done" with its duration under
+// StepDurationKey, its start as "step started", both at InfoLevel.
+func (t *TaskLogger) Step(name string) Logger[fields.Field] {
+	now := time.Now()
+	t.mu.Lock()
+	done, ok := t.endStep(now)
+	n := len(t.steps) + 1
+	t.steps = append(t.steps, taskStep{name: name, start: now})
+	t.running = true
+	t.mu.Unlock()
+
+	if ok {
+		t.own.Info("step done", done...)
+	}
+	step := t.Logger.With(fields.String(StepKey, t.number(n)), fields.String(StepNameKey, name))
+	WithOptions(step, AddCallerSkip(1)).Info("step started")
+	return step
+}
+
+// End ends the current step, if any, and logs a summary of the task: "task done" at InfoLevel if err is nil, "task
+// failed" at ErrorLevel with err otherwise, with the number of steps run under StepKey, the task's duration under
+// TaskDurationKey and each step's duration under TaskStepsKey. Calls after the first do nothing.
+func (t *TaskLogger) End(err error) {
+	now := time.Now()
+	t.mu.Lock()
+	if t.ended {
+		t.mu.Unlock()
+		return
+	}
+	t.ended = true
+	done, ok := t.endStep(now)
+	steps := make([]fields.Field, len(t.steps))
+	for i, s := range t.steps {
+		name := s.name
+		if name == "" {
+			name = strconv.Itoa(i + 1)
+		}
+		steps[i] = fields.Duration(name, s.took)
+	}
+	n := len(t.steps)
+	t.mu.Unlock()
+
+	if ok {
+		t.own.Info("step done", done...)
+	}
+	summary := []fields.Field{
+		fields.String(StepKey, t.number(n)),
+		fields.Duration(TaskDurationKey, now.Sub(t.start)),
+		fields.Dict(TaskStepsKey, steps...),
+	}
+	if err != nil {
+		t.own.Error("task failed", append(summary, fields.Error(err))...)
+		return
+	}
+	t.own.Info("task done", summary...)
+}
+
+// endStep records the end of the current step, returning the fields of its "step done" entry, or false if there's
+// no step running. t.mu must be held.
+func (t *TaskLogger) endStep(now time.Time) ([]fields.Field, bool) {
+	if !t.running {
+		return nil, false
+	}
+	t.running = false
+	n := len(t.steps)
+	s := &t.steps[n-1]
+	s.took = now.Sub(s.start)
+	return []fields.Field{
+		fields.String(StepKey, t.number(n)),
+		fields.String(StepNameKey, s.name),
+		fields.Duration(StepDurationKey, s.took),
+	}, true
+}
+
+// number formats the step number n, with the total if it's known.
+func (t *TaskLogger) number(n int) string {
+	if t.total <= 0 {
+		return strconv.Itoa(n)
+	}
+	return strconv.Itoa(n) + "/" + strconv.Itoa(t.total)
+}
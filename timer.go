@@ -0,0 +1,59 @@
+package logstox
+
+import (
+	"time"
+
+	"github.com/khinshankhan/logstox/fields"
+)
+
+// Timer measures the time since it was started, see StartTimer.
+type Timer struct {
+	key   string
+	start time.Time
+}
+
+// StartTimer starts a Timer whose elapsed time is reported under k, replacing hand-rolled time.Since math:
+//
+//	t := logstox.StartTimer("took")
+//	...
+//	log.Info("fetched", t.Field())
+//
+// Since deferred calls evaluate their receiver right away, Stop also works deferred, timing the rest of the function:
+//
+//	defer logstox.StartTimer("took").Stop(log, "request handled")
+func StartTimer(k string) Timer {
+	return Timer{key: k, start: time.Now()}
+}
+
+// Elapsed returns the time since the timer was started, measured on the monotonic clock.
+func (t Timer) Elapsed() time.Duration {
+	return time.Since(t.start)
+}
+
+// Field returns the elapsed time as a duration field.
+func (t Timer) Field() fields.Field {
+	return fields.Duration(t.key, t.Elapsed())
+}
+
+// Stop logs msg at InfoLevel to l with fs and the elapsed time.
+func (t Timer) Stop(l Logger[fields.Field], msg string, fs ...fields.Field) {
+	t.stop(l, InfoLevel, msg, fs)
+}
+
+// StopAt is Stop logging at lvl; nothing is built if l doesn't record lvl.
+func (t Timer) StopAt(l Logger[fields.Field], lvl Level, msg string, fs ...fields.Field) {
+	t.stop(l, lvl, msg, fs)
+}
+
+// stop implements Stop and StopAt.
+func (t Timer) stop(l Logger[fields.Field], lvl Level, msg string, fs []fields.Field) {
+	elapsed := t.Elapsed() // before the level check, so it isn't counted
+	// skip Write, stop and Stop/StopAt so file:line points at the caller
+	ce := check(l, lvl, msg, 3)
+	if ce == nil {
+		return
+	}
+	all := make([]fields.Field, 0, len(fs)+1)
+	all = append(all, fs...)
+	ce.Write(append(all, fields.Duration(t.key, elapsed))...)
+}
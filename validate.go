@@ -0,0 +1,32 @@
+package logstox
+
+import (
+	"errors"
+	"fmt"
+)
+
+// FieldErrorsKey is the field key backends attach key validation errors under in Development.
+const FieldErrorsKey = "field_errors"
+
+// ValidateKeys reports the problems strict field validation catches among the keys of a single call's fields: empty
+// keys and keys repeated within the call. It returns nil if there are none.
+//
+// Backends run it on every enabled entry below DPanic when Options.Development is set, and escalate entries that fail
+// to DPanicLevel with the error attached under FieldErrorsKey, so they panic. Fields that don't carry a key by design
+// (no-ops, lazy and inlined fields) should be left out of keys.
+func ValidateKeys(keys []string) error {
+	var errs []error
+	for i, k := range keys {
+		if k == "" {
+			errs = append(errs, fmt.Errorf("field %d has an empty key", i))
+			continue
+		}
+		for _, prev := range keys[:i] {
+			if prev == k {
+				errs = append(errs, fmt.Errorf("duplicate field key %q", k))
+				break
+			}
+		}
+	}
+	return errors.Join(errs...)
+}